@@ -5,6 +5,7 @@ import BookingDetail from "./components/BookingDetail";
 import MenuManagement from "./components/MenuManagement";
 import PackageManagement from "./components/PackageManagement";
 import SignIn from "./components/SignIn";
+import ResetPassword from "./components/ResetPassword";
 import { useAuth } from "./context/AuthContext";
 import { useRef, useEffect } from "react";
 
@@ -38,6 +39,7 @@ export default function AppRoutes() {
     return (
       <Routes>
         <Route path="/signin" element={<SignIn />} />
+        <Route path="/reset-password" element={<ResetPassword />} />
         <Route path="*" element={<Navigate to="/signin" replace />} />
       </Routes>
     );
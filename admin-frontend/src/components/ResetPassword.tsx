@@ -0,0 +1,172 @@
+import { useState } from "react";
+import { Link, useSearchParams } from "react-router-dom";
+
+const API_URL = import.meta.env.VITE_API_URL || "http://localhost:8080";
+
+const inputClass =
+  "w-full px-3 py-2 border border-gray-300 rounded-md focus:outline-none focus:ring-2 focus:ring-terracotta";
+const buttonClass =
+  "w-full py-3 bg-terracotta hover:bg-latte text-parchment hover:text-mocha font-bold rounded-lg transition disabled:opacity-50";
+
+// Requests a reset email, or sets a new password when opened from the
+// emailed link (?token=...)
+export default function ResetPassword() {
+  const [searchParams] = useSearchParams();
+  const token = searchParams.get("token") || "";
+
+  const [username, setUsername] = useState("");
+  const [password, setPassword] = useState("");
+  const [confirmPassword, setConfirmPassword] = useState("");
+  const [message, setMessage] = useState("");
+  const [error, setError] = useState("");
+  const [isDone, setIsDone] = useState(false);
+  const [isSubmitting, setIsSubmitting] = useState(false);
+
+  const post = async (endpoint: string, body: object) => {
+    const response = await fetch(`${API_URL}${endpoint}`, {
+      method: "POST",
+      headers: { "Content-Type": "application/json" },
+      body: JSON.stringify(body),
+    });
+    if (!response.ok) {
+      throw new Error((await response.text()).trim() || "Request failed");
+    }
+  };
+
+  const handleRequest = async (e: React.FormEvent) => {
+    e.preventDefault();
+    setError("");
+    setIsSubmitting(true);
+    try {
+      await post("/api/v1/auth/password/forgot", { username });
+      setMessage(
+        "If the account has an email address, a reset link is on its way. The link expires in one hour."
+      );
+      setIsDone(true);
+    } catch (err) {
+      setError(err instanceof Error ? err.message : "Request failed");
+    } finally {
+      setIsSubmitting(false);
+    }
+  };
+
+  const handleReset = async (e: React.FormEvent) => {
+    e.preventDefault();
+    setError("");
+    if (password !== confirmPassword) {
+      setError("Passwords do not match");
+      return;
+    }
+    setIsSubmitting(true);
+    try {
+      await post("/api/v1/auth/password/reset", { token, password });
+      setMessage("Your password has been changed. You can now sign in.");
+      setIsDone(true);
+    } catch (err) {
+      setError(err instanceof Error ? err.message : "Reset failed");
+    } finally {
+      setIsSubmitting(false);
+    }
+  };
+
+  return (
+    <div className="min-h-screen flex items-center justify-center bg-parchment">
+      <div className="max-w-md w-full bg-white rounded-lg shadow-lg overflow-hidden">
+        <div className="bg-terracotta py-6 px-6 text-center">
+          <h2 className="text-3xl font-bold text-parchment">Reset Password</h2>
+        </div>
+
+        <div className="py-8 px-6 space-y-6">
+          {error && (
+            <div className="bg-red-50 border-l-4 border-red-500 p-4 text-red-700">
+              <p>{error}</p>
+            </div>
+          )}
+
+          {isDone ? (
+            <p className="text-espresso">{message}</p>
+          ) : token ? (
+            <form onSubmit={handleReset} className="space-y-6">
+              <div>
+                <label
+                  htmlFor="password"
+                  className="block text-sm font-medium text-espresso mb-1"
+                >
+                  New password
+                </label>
+                <input
+                  id="password"
+                  type="password"
+                  autoComplete="new-password"
+                  value={password}
+                  onChange={(e) => setPassword(e.target.value)}
+                  className={inputClass}
+                  required
+                />
+              </div>
+              <div>
+                <label
+                  htmlFor="confirm-password"
+                  className="block text-sm font-medium text-espresso mb-1"
+                >
+                  Confirm new password
+                </label>
+                <input
+                  id="confirm-password"
+                  type="password"
+                  autoComplete="new-password"
+                  value={confirmPassword}
+                  onChange={(e) => setConfirmPassword(e.target.value)}
+                  className={inputClass}
+                  required
+                />
+              </div>
+              <p className="text-xs text-gray-500">
+                At least 12 characters, using three of: lowercase, uppercase,
+                digits, symbols.
+              </p>
+              <button
+                type="submit"
+                disabled={isSubmitting}
+                className={buttonClass}
+              >
+                {isSubmitting ? "Saving..." : "Set New Password"}
+              </button>
+            </form>
+          ) : (
+            <form onSubmit={handleRequest} className="space-y-6">
+              <div>
+                <label
+                  htmlFor="user"
+                  className="block text-sm font-medium text-espresso mb-1"
+                >
+                  User
+                </label>
+                <input
+                  id="user"
+                  value={username}
+                  onChange={(e) => setUsername(e.target.value)}
+                  className={inputClass}
+                  required
+                />
+              </div>
+              <button
+                type="submit"
+                disabled={isSubmitting}
+                className={buttonClass}
+              >
+                {isSubmitting ? "Sending..." : "Send Reset Link"}
+              </button>
+            </form>
+          )}
+
+          <div className="text-center text-sm">
+            <Link to="/signin" className="text-terracotta hover:underline">
+              Back to sign in
+            </Link>
+          </div>
+        </div>
+      </div>
+    </div>
+  );
+}
@@ -1,14 +1,28 @@
 import { useState } from "react";
-import { useNavigate, useLocation } from "react-router-dom";
-import { useAuth } from "../context/AuthContext";
+import { Link, useNavigate, useLocation } from "react-router-dom";
+import {
+  useAuth,
+  type ConfirmedTwoFactorSetup,
+  type TwoFactorSetup,
+} from "../context/AuthContext";
+
+type Step = "credentials" | "code" | "enroll" | "recovery";
 
 export default function SignIn() {
   const [username, setUsername] = useState("");
   const [password, setPassword] = useState("");
+  const [code, setCode] = useState("");
+  const [step, setStep] = useState<Step>("credentials");
+  const [challengeToken, setChallengeToken] = useState("");
+  const [setup, setSetup] = useState<TwoFactorSetup | null>(null);
+  const [confirmed, setConfirmed] = useState<ConfirmedTwoFactorSetup | null>(
+    null
+  );
   const [error, setError] = useState("");
   const [isSubmitting, setIsSubmitting] = useState(false);
 
-  const { login } = useAuth();
+  const { login, verifyTwoFactor, startTwoFactorSetup, confirmTwoFactorSetup } =
+    useAuth();
   const navigate = useNavigate();
   const location = useLocation();
 
@@ -23,10 +37,22 @@ export default function SignIn() {
     console.log("Attempting login with API URL:", import.meta.env.VITE_API_URL);
 
     try {
-      const success = await login(username, password);
-      if (success) {
+      const result = await login(username, password);
+      if (result.status === "success") {
         console.log("Login successful, navigating to:", from);
         navigate(from, { replace: true });
+      } else if (result.status === "two_factor") {
+        setChallengeToken(result.challengeToken);
+        setStep("code");
+      } else if (result.status === "enrollment_required") {
+        setChallengeToken(result.challengeToken);
+        const started = await startTwoFactorSetup(result.challengeToken);
+        if (started) {
+          setSetup(started);
+          setStep("enroll");
+        } else {
+          setError("Could not start two-factor setup");
+        }
       } else {
         console.error("Login failed - server returned unsuccessful response");
         setError("Invalid username or password");
@@ -51,6 +77,128 @@ export default function SignIn() {
     }
   };
 
+  const handleCodeSubmit = async (e: React.FormEvent) => {
+    e.preventDefault();
+    setError("");
+    setIsSubmitting(true);
+
+    try {
+      if (step === "code") {
+        if (await verifyTwoFactor(challengeToken, code)) {
+          navigate(from, { replace: true });
+        } else {
+          setError("Invalid code");
+        }
+      } else {
+        const result = await confirmTwoFactorSetup(challengeToken, code);
+        if (result) {
+          setConfirmed(result);
+          setStep("recovery");
+        } else {
+          setError("Invalid code");
+        }
+      }
+    } finally {
+      setCode("");
+      setIsSubmitting(false);
+    }
+  };
+
+  const finishEnrollment = () => {
+    confirmed?.startSession();
+    navigate(from, { replace: true });
+  };
+
+  const inputClass =
+    "w-full px-3 py-2 border border-gray-300 rounded-md focus:outline-none focus:ring-2 focus:ring-terracotta";
+  const buttonClass =
+    "w-full py-3 bg-terracotta hover:bg-latte text-parchment hover:text-mocha font-bold rounded-lg transition disabled:opacity-50";
+
+  if (step !== "credentials") {
+    return (
+      <div className="min-h-screen flex items-center justify-center bg-parchment">
+        <div className="max-w-md w-full bg-white rounded-lg shadow-lg overflow-hidden">
+          <div className="bg-terracotta py-6 px-6 text-center">
+            <h2 className="text-3xl font-bold text-parchment">
+              Two-Factor Authentication
+            </h2>
+          </div>
+
+          {step === "recovery" && confirmed ? (
+            <div className="py-8 px-6 space-y-6">
+              <p className="text-espresso">
+                Save these recovery codes somewhere safe. Each one can be used
+                once if you lose your authenticator.
+              </p>
+              <ul className="grid grid-cols-2 gap-2 font-mono text-sm">
+                {confirmed.recoveryCodes.map((recoveryCode) => (
+                  <li key={recoveryCode}>{recoveryCode}</li>
+                ))}
+              </ul>
+              <button onClick={finishEnrollment} className={buttonClass}>
+                I've saved my codes
+              </button>
+            </div>
+          ) : (
+            <form onSubmit={handleCodeSubmit} className="py-8 px-6 space-y-6">
+              {error && (
+                <div className="bg-red-50 border-l-4 border-red-500 p-4 text-red-700">
+                  <p>{error}</p>
+                </div>
+              )}
+
+              {step === "enroll" && setup && (
+                <div className="space-y-3 text-center">
+                  <p className="text-espresso">
+                    Two-factor authentication is required. Scan this code with
+                    your authenticator app.
+                  </p>
+                  <img
+                    src={`data:image/png;base64,${setup.qrCodePng}`}
+                    alt="Authenticator QR code"
+                    className="mx-auto"
+                  />
+                  <p className="text-xs text-gray-500 break-all">
+                    Or enter this key manually: {setup.secret}
+                  </p>
+                </div>
+              )}
+
+              <div>
+                <label
+                  htmlFor="code"
+                  className="block text-sm font-medium text-espresso mb-1"
+                >
+                  {step === "code"
+                    ? "Authenticator or recovery code"
+                    : "Authenticator code"}
+                </label>
+                <input
+                  id="code"
+                  type="text"
+                  inputMode={step === "code" ? "text" : "numeric"}
+                  autoComplete="one-time-code"
+                  value={code}
+                  onChange={(e) => setCode(e.target.value)}
+                  className={inputClass}
+                  required
+                />
+              </div>
+
+              <button
+                type="submit"
+                disabled={isSubmitting}
+                className={buttonClass}
+              >
+                {isSubmitting ? "Verifying..." : "Verify"}
+              </button>
+            </form>
+          )}
+        </div>
+      </div>
+    );
+  }
+
   return (
     <div className="min-h-screen flex items-center justify-center bg-parchment">
       <div className="max-w-md w-full bg-white rounded-lg shadow-lg overflow-hidden">
@@ -110,6 +258,15 @@ export default function SignIn() {
             {isSubmitting ? "Signing in..." : "Sign In"}
           </button>
 
+          <div className="text-center text-sm">
+            <Link
+              to="/reset-password"
+              className="text-terracotta hover:underline"
+            >
+              Forgot password?
+            </Link>
+          </div>
+
           <div className="text-center text-sm text-gray-500 mt-4">
             <p>For demo: admin / admin</p>
           </div>
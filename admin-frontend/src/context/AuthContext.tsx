@@ -6,10 +6,44 @@ import React, {
   useCallback,
 } from "react";
 
+export type LoginResult =
+  | { status: "success" }
+  | { status: "two_factor"; challengeToken: string }
+  | { status: "enrollment_required"; challengeToken: string }
+  | { status: "failed" };
+
+export interface TwoFactorSetup {
+  secret: string;
+  otpauthUri: string;
+  qrCodePng: string; // base64-encoded PNG
+}
+
+// Recovery codes are shown before signing in, so the session starts on demand
+export interface ConfirmedTwoFactorSetup {
+  recoveryCodes: string[];
+  startSession: () => void;
+}
+
+interface LoginResponse {
+  token?: string;
+  refreshToken?: string;
+  user?: { id: number; role: string };
+  twoFactorRequired?: boolean;
+  enrollmentRequired?: boolean;
+  challengeToken?: string;
+  recoveryCodes?: string[];
+}
+
 interface AuthContextType {
   isAuthenticated: boolean;
   token: string | null;
-  login: (username: string, password: string) => Promise<boolean>;
+  login: (username: string, password: string) => Promise<LoginResult>;
+  verifyTwoFactor: (challengeToken: string, code: string) => Promise<boolean>;
+  startTwoFactorSetup: (challengeToken: string) => Promise<TwoFactorSetup | null>;
+  confirmTwoFactorSetup: (
+    challengeToken: string,
+    code: string
+  ) => Promise<ConfirmedTwoFactorSetup | null>;
   logout: () => void;
   isLoading: boolean;
   user: { id: number; role: string } | undefined;
@@ -91,7 +125,9 @@ export const AuthProvider: React.FC<{ children: React.ReactNode }> = ({
       // For login/refresh endpoints that don't need auth
       const isAuthEndpoint =
         endpoint.includes("/api/v1/auth/login") ||
-        endpoint.includes("/api/v1/auth/refresh");
+        endpoint.includes("/api/v1/auth/refresh") ||
+        endpoint.includes("/api/v1/auth/2fa/verify") ||
+        endpoint.includes("/api/v1/auth/2fa/setup");
 
       if (!token && !isAuthEndpoint) {
         throw new Error("Not authenticated");
@@ -217,6 +253,12 @@ export const AuthProvider: React.FC<{ children: React.ReactNode }> = ({
         const data = await response.json();
         setToken(data.accessToken);
 
+        // Refresh tokens are single use: keep the rotated one for next time
+        if (data.refreshToken) {
+          localStorage.setItem("refresh_token", data.refreshToken);
+          setRefreshToken(data.refreshToken);
+        }
+
         // Update user data if needed
         if (!user) {
           // Also use direct fetch for validation if needed
@@ -280,36 +322,118 @@ export const AuthProvider: React.FC<{ children: React.ReactNode }> = ({
     [API_URL] // Only depend on API_URL
   );
 
+  // Store the session returned once login (and any second factor) succeeds
+  const startSession = useCallback(
+    (data: LoginResponse) => {
+      if (!data.token || !data.refreshToken || !data.user) {
+        throw new Error("Incomplete session response");
+      }
+
+      // Store refresh token in localStorage (more persistent but less sensitive)
+      localStorage.setItem("refresh_token", data.refreshToken);
+
+      // Store access token only in memory
+      setToken(data.token);
+      setRefreshToken(data.refreshToken);
+      setUser({ id: data.user.id, role: data.user.role });
+      setIsAuthenticated(true);
+      updateActivity();
+    },
+    [updateActivity]
+  );
+
   // Login function
   const login = useCallback(
-    async (username: string, password: string): Promise<boolean> => {
+    async (username: string, password: string): Promise<LoginResult> => {
       try {
-        const data = await apiRequest<{
-          token: string;
-          refreshToken: string;
-          user: { id: number; role: string };
-        }>("/api/v1/auth/login", {
+        const data = await apiRequest<LoginResponse>("/api/v1/auth/login", {
           method: "POST",
           headers: { "Content-Type": "application/json" },
           body: JSON.stringify({ username, password }),
         });
 
-        // Store refresh token in localStorage (more persistent but less sensitive)
-        localStorage.setItem("refresh_token", data.refreshToken);
+        // Accounts with 2FA get a challenge instead of a session
+        if (data.challengeToken) {
+          return data.enrollmentRequired
+            ? { status: "enrollment_required", challengeToken: data.challengeToken }
+            : { status: "two_factor", challengeToken: data.challengeToken };
+        }
 
-        // Store access token only in memory
-        setToken(data.token);
-        setRefreshToken(data.refreshToken);
-        setUser({ id: data.user.id, role: data.user.role });
-        setIsAuthenticated(true);
-        updateActivity();
-        return true;
+        startSession(data);
+        return { status: "success" };
       } catch (error) {
         console.error("Login error:", error);
+        return { status: "failed" };
+      }
+    },
+    [apiRequest, startSession]
+  );
+
+  // Second login step: exchange the challenge and a TOTP or recovery code for a session
+  const verifyTwoFactor = useCallback(
+    async (challengeToken: string, code: string): Promise<boolean> => {
+      // Recovery codes look like xxxxx-xxxxx, authenticator codes are 6 digits
+      const isRecoveryCode = !/^\d{6}$/.test(code.trim());
+      try {
+        const data = await apiRequest<LoginResponse>("/api/v1/auth/2fa/verify", {
+          method: "POST",
+          body: JSON.stringify(
+            isRecoveryCode
+              ? { challengeToken, recoveryCode: code }
+              : { challengeToken, code: code.trim() }
+          ),
+        });
+        startSession(data);
+        return true;
+      } catch (error) {
+        console.error("Two-factor verification error:", error);
         return false;
       }
     },
-    [apiRequest, updateActivity]
+    [apiRequest, startSession]
+  );
+
+  // Forced enrollment: fetch a new secret and QR code for the challenged user
+  const startTwoFactorSetup = useCallback(
+    async (challengeToken: string): Promise<TwoFactorSetup | null> => {
+      try {
+        return await apiRequest<TwoFactorSetup>("/api/v1/auth/2fa/setup", {
+          method: "POST",
+          body: JSON.stringify({ challengeToken }),
+        });
+      } catch (error) {
+        console.error("Two-factor setup error:", error);
+        return null;
+      }
+    },
+    [apiRequest]
+  );
+
+  // Confirm forced enrollment; returns the recovery codes and a callback that
+  // starts the session once the user has saved them
+  const confirmTwoFactorSetup = useCallback(
+    async (
+      challengeToken: string,
+      code: string
+    ): Promise<ConfirmedTwoFactorSetup | null> => {
+      try {
+        const data = await apiRequest<LoginResponse>(
+          "/api/v1/auth/2fa/setup/confirm",
+          {
+            method: "POST",
+            body: JSON.stringify({ challengeToken, code: code.trim() }),
+          }
+        );
+        return {
+          recoveryCodes: data.recoveryCodes ?? [],
+          startSession: () => startSession(data),
+        };
+      } catch (error) {
+        console.error("Two-factor confirmation error:", error);
+        return null;
+      }
+    },
+    [apiRequest, startSession]
   );
 
   // Logout function
@@ -442,6 +566,9 @@ export const AuthProvider: React.FC<{ children: React.ReactNode }> = ({
         isAuthenticated,
         token,
         login,
+        verifyTwoFactor,
+        startTwoFactorSetup,
+        confirmTwoFactorSetup,
         logout,
         isLoading,
         user,
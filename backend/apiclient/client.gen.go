@@ -0,0 +1,1631 @@
+// Code generated by cmd/apigen from internal/openapi/openapi.yaml. DO NOT EDIT.
+
+package apiclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+)
+
+type Booking struct {
+	Archived bool `json:"archived"`
+	// User running the event; absent when unassigned
+	AssignedUserID int       `json:"assignedUserId,omitempty"`
+	CoffeeFlavors  []string  `json:"coffeeFlavors"`
+	CreatedAt      time.Time `json:"createdAt"`
+	// Event date, YYYY-MM-DD
+	Date        string        `json:"date"`
+	Email       string        `json:"email"`
+	HasShade    bool          `json:"hasShade"`
+	ID          int           `json:"id"`
+	IsOutdoor   bool          `json:"isOutdoor"`
+	Location    string        `json:"location"`
+	MilkOptions []string      `json:"milkOptions"`
+	Name        string        `json:"name"`
+	Notes       string        `json:"notes"`
+	Package     string        `json:"package"`
+	People      int           `json:"people"`
+	Phone       string        `json:"phone"`
+	Status      BookingStatus `json:"status"`
+	Time        string        `json:"time"`
+}
+
+type BookingCreated struct {
+	ID      int    `json:"id"`
+	Message string `json:"message"`
+}
+
+// Matches bookings on every field that is set; dates are inclusive.
+type BookingFilter struct {
+	Archived       bool `json:"archived,omitempty"`
+	AssignedUserID int  `json:"assignedUserId,omitempty"`
+	// Earliest event date, YYYY-MM-DD
+	DateFrom string `json:"dateFrom,omitempty"`
+	// Latest event date, YYYY-MM-DD
+	DateTo string        `json:"dateTo,omitempty"`
+	Status BookingStatus `json:"status,omitempty"`
+}
+
+type BookingImportRequest struct {
+	// The CSV file, with a header row
+	Csv string `json:"csv"`
+	// Report what would be imported without saving
+	DryRun bool `json:"dryRun,omitempty"`
+	// Booking field to CSV column header, for example
+	// `{"name": "Customer", "date": "Event Date"}`. Fields left out
+	// are read from a column with the same name or export header;
+	// a field mapped to "" is not imported. Fields are name, email,
+	// phone, date, time, people, location, notes, coffeeFlavors,
+	// milkOptions, package, status, archived, isOutdoor and hasShade.
+	Mapping map[string]string `json:"mapping,omitempty"`
+}
+
+type BookingImportResponse struct {
+	// Bookings created, or that would be created
+	Created    int                   `json:"created"`
+	DryRun     bool                  `json:"dryRun"`
+	Duplicates int                   `json:"duplicates"`
+	Invalid    int                   `json:"invalid"`
+	Results    []BookingImportResult `json:"results"`
+	Rows       int                   `json:"rows"`
+}
+
+type BookingImportResult struct {
+	Errors []string `json:"errors,omitempty"`
+	// The booking created, or the existing booking a duplicate
+	// matches. Not set for rows a dry run would create.
+	ID     int    `json:"id,omitempty"`
+	Result string `json:"result"`
+	// Line of the row in the file, counting the header as 1
+	Row int `json:"row"`
+}
+
+// Either email or phone is required.
+type BookingInput struct {
+	Archived      bool     `json:"archived,omitempty"`
+	CoffeeFlavors []string `json:"coffeeFlavors"`
+	// Event date, YYYY-MM-DD
+	Date        string   `json:"date"`
+	Email       string   `json:"email,omitempty"`
+	HasShade    bool     `json:"hasShade,omitempty"`
+	IsOutdoor   bool     `json:"isOutdoor,omitempty"`
+	Location    string   `json:"location"`
+	MilkOptions []string `json:"milkOptions"`
+	Name        string   `json:"name"`
+	Notes       string   `json:"notes,omitempty"`
+	Package     string   `json:"package,omitempty"`
+	People      int      `json:"people"`
+	Phone       string   `json:"phone,omitempty"`
+	Time        string   `json:"time"`
+}
+
+// Where a booking is in its workflow; new bookings are pending
+type BookingStatus string
+
+const (
+	BookingStatusPending   BookingStatus = "pending"
+	BookingStatusConfirmed BookingStatus = "confirmed"
+	BookingStatusCompleted BookingStatus = "completed"
+	BookingStatusCanceled  BookingStatus = "canceled"
+)
+
+type BookingWithoutPII struct {
+	Archived       bool      `json:"archived"`
+	AssignedUserID int       `json:"assignedUserId,omitempty"`
+	CoffeeFlavors  []string  `json:"coffeeFlavors"`
+	CreatedAt      time.Time `json:"createdAt"`
+	// Event date, YYYY-MM-DD
+	Date        string        `json:"date"`
+	HasShade    bool          `json:"hasShade"`
+	ID          int           `json:"id"`
+	IsOutdoor   bool          `json:"isOutdoor"`
+	MilkOptions []string      `json:"milkOptions"`
+	Package     string        `json:"package"`
+	People      int           `json:"people"`
+	Status      BookingStatus `json:"status"`
+	Time        string        `json:"time"`
+}
+
+type BulkAction string
+
+const (
+	BulkActionArchive   BulkAction = "archive"
+	BulkActionUnarchive BulkAction = "unarchive"
+	BulkActionDelete    BulkAction = "delete"
+	BulkActionSetStatus BulkAction = "set_status"
+	BulkActionReassign  BulkAction = "reassign"
+)
+
+// Exactly one of ids and filter is required.
+type BulkBookingRequest struct {
+	Action BulkAction `json:"action"`
+	// New assignee for reassign; omit to unassign
+	AssignedUserID int `json:"assignedUserId,omitempty"`
+	// Report what would change without saving
+	DryRun bool           `json:"dryRun,omitempty"`
+	Filter *BookingFilter `json:"filter,omitempty"`
+	IDs    []int          `json:"ids,omitempty"`
+	Status BookingStatus  `json:"status,omitempty"`
+}
+
+type BulkBookingResponse struct {
+	Action BulkAction `json:"action"`
+	// Bookings the action changed, or would change
+	Changed int  `json:"changed"`
+	DryRun  bool `json:"dryRun"`
+	// Bookings that exist
+	Matched int                 `json:"matched"`
+	Results []BulkBookingResult `json:"results"`
+}
+
+type BulkBookingResult struct {
+	// The booking after the action, or before it when deleted
+	Booking *Booking `json:"booking,omitempty"`
+	ID      int      `json:"id"`
+	Result  string   `json:"result"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"currentPassword"`
+	NewPassword     string `json:"newPassword"`
+}
+
+type CheckResult struct {
+	Duration string `json:"duration"`
+	Error    string `json:"error,omitempty"`
+	Status   string `json:"status"`
+}
+
+// Either email or phone is required.
+type ContactRequest struct {
+	Email   string `json:"email,omitempty"`
+	Message string `json:"message"`
+	Name    string `json:"name"`
+	Phone   string `json:"phone,omitempty"`
+}
+
+type ForgotPasswordRequest struct {
+	Username string `json:"username"`
+}
+
+type Health struct {
+	Status    string    `json:"status"`
+	Timestamp time.Time `json:"timestamp"`
+	// Go duration since the process started
+	Uptime string `json:"uptime"`
+}
+
+type Ingredient struct {
+	CreatedAt time.Time `json:"createdAt"`
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	// Quantity on hand
+	Stock float64 `json:"stock"`
+	// Unit stock and recipe quantities are measured in, such as ml or g
+	Unit      string    `json:"unit"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type IngredientInput struct {
+	Name  string  `json:"name"`
+	Stock float64 `json:"stock,omitempty"`
+	Unit  string  `json:"unit"`
+}
+
+type IngredientNeed struct {
+	// Needed first by bookings from today until the day before
+	Committed    float64 `json:"committed"`
+	IngredientID int     `json:"ingredientId"`
+	Name         string  `json:"name"`
+	// Stock left after committed and required
+	Remaining float64 `json:"remaining"`
+	// Needed by the date's bookings
+	Required float64 `json:"required"`
+	// Set when remaining would go negative
+	Short bool    `json:"short"`
+	Stock float64 `json:"stock"`
+	ToBuy float64 `json:"toBuy"`
+	Unit  string  `json:"unit"`
+}
+
+type JWK struct {
+	Alg string `json:"alg"`
+	Crv string `json:"crv,omitempty"`
+	E   string `json:"e,omitempty"`
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	N   string `json:"n,omitempty"`
+	Use string `json:"use"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+type LeadTime struct {
+	AverageDays float64 `json:"averageDays"`
+	Bookings    int     `json:"bookings"`
+	MaxDays     int     `json:"maxDays"`
+	MedianDays  float64 `json:"medianDays"`
+	MinDays     int     `json:"minDays"`
+	P90Days     float64 `json:"p90Days"`
+}
+
+type LoginRequest struct {
+	Password string `json:"password"`
+	Username string `json:"username"`
+}
+
+// A completed login carries token, refreshToken and user (or csrfToken
+// in cookie session mode). Otherwise twoFactorRequired or
+// enrollmentRequired is set along with a challengeToken for the next step.
+type LoginResponse struct {
+	ChallengeToken     string   `json:"challengeToken,omitempty"`
+	CSRFToken          string   `json:"csrfToken,omitempty"`
+	EnrollmentRequired bool     `json:"enrollmentRequired,omitempty"`
+	RecoveryCodes      []string `json:"recoveryCodes,omitempty"`
+	RefreshToken       string   `json:"refreshToken,omitempty"`
+	Token              string   `json:"token,omitempty"`
+	TwoFactorRequired  bool     `json:"twoFactorRequired,omitempty"`
+	User               *User    `json:"user,omitempty"`
+}
+
+type LogoutRequest struct {
+	RefreshToken string `json:"refreshToken,omitempty"`
+}
+
+type MenuImageUpload struct {
+	Image string `json:"image"`
+}
+
+type MenuItem struct {
+	Active      bool      `json:"active"`
+	CreatedAt   time.Time `json:"createdAt"`
+	Description string    `json:"description,omitempty"`
+	// Position within its type, set by reordering
+	DisplayOrder int `json:"displayOrder,omitempty"`
+	ID           int `json:"id"`
+	// Absent when the item has no image
+	ImageURL string `json:"imageUrl,omitempty"`
+	Label    string `json:"label"`
+	// Price adjustment shown with the item, e.g. 0.5 for an extra charge
+	PriceModifier float64      `json:"priceModifier,omitempty"`
+	Tags          []MenuTag    `json:"tags,omitempty"`
+	Type          MenuItemType `json:"type"`
+	UpdatedAt     time.Time    `json:"updatedAt"`
+	Value         string       `json:"value"`
+}
+
+// Display order and the image have their own endpoints and are ignored here.
+type MenuItemInput struct {
+	Active        bool         `json:"active,omitempty"`
+	Description   string       `json:"description,omitempty"`
+	Label         string       `json:"label"`
+	PriceModifier float64      `json:"priceModifier,omitempty"`
+	Tags          []MenuTag    `json:"tags,omitempty"`
+	Type          MenuItemType `json:"type"`
+	Value         string       `json:"value"`
+}
+
+type MenuItemType string
+
+const (
+	MenuItemTypeCoffeeFlavor MenuItemType = "coffee_flavor"
+	MenuItemTypeMilkOption   MenuItemType = "milk_option"
+)
+
+type MenuOrder struct {
+	IDs  []int        `json:"ids"`
+	Type MenuItemType `json:"type"`
+}
+
+type MenuReport struct {
+	Bookings      int          `json:"bookings"`
+	CoffeeFlavors []Popularity `json:"coffeeFlavors"`
+	From          string       `json:"from"`
+	MilkOptions   []Popularity `json:"milkOptions"`
+	To            string       `json:"to"`
+}
+
+// Allergen and dietary attributes
+type MenuTag string
+
+const (
+	MenuTagDairyFree  MenuTag = "dairy_free"
+	MenuTagNutFree    MenuTag = "nut_free"
+	MenuTagGlutenFree MenuTag = "gluten_free"
+	MenuTagSoyFree    MenuTag = "soy_free"
+	MenuTagVegan      MenuTag = "vegan"
+)
+
+type Message struct {
+	Message string `json:"message"`
+}
+
+type MissingRecipe struct {
+	Label string `json:"label"`
+	// 0 when the value isn't on the menu
+	MenuItemID int          `json:"menuItemId"`
+	Type       MenuItemType `json:"type"`
+	Value      string       `json:"value"`
+}
+
+type Package struct {
+	Active       bool      `json:"active"`
+	CreatedAt    time.Time `json:"createdAt"`
+	Description  string    `json:"description"`
+	DisplayOrder int       `json:"displayOrder"`
+	ID           int       `json:"id"`
+	Name         string    `json:"name"`
+	Points       []string  `json:"points"`
+	// Display price, e.g. "$450"
+	Price     string    `json:"price"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type PackageInput struct {
+	Active       bool     `json:"active,omitempty"`
+	Description  string   `json:"description,omitempty"`
+	DisplayOrder int      `json:"displayOrder,omitempty"`
+	Name         string   `json:"name"`
+	Points       []string `json:"points,omitempty"`
+	Price        string   `json:"price"`
+}
+
+type PackageRevenue struct {
+	Bookings  int `json:"bookings"`
+	Headcount int `json:"headcount"`
+	// Package name as booked; empty for bookings without one
+	Package string `json:"package"`
+	// False when some of the bookings couldn't be priced
+	Priced  bool    `json:"priced"`
+	Revenue float64 `json:"revenue"`
+}
+
+type Permission string
+
+const (
+	PermissionBookingsRead   Permission = "bookings:read"
+	PermissionBookingsWrite  Permission = "bookings:write"
+	PermissionBookingsDelete Permission = "bookings:delete"
+	PermissionMenuWrite      Permission = "menu:write"
+	PermissionPackagesRead   Permission = "packages:read"
+	PermissionPackagesWrite  Permission = "packages:write"
+	PermissionUsersManage    Permission = "users:manage"
+	PermissionConfigRead     Permission = "config:read"
+	PermissionWebhooksManage Permission = "webhooks:manage"
+	PermissionReportsRead    Permission = "reports:read"
+)
+
+type Ping struct {
+	Status    string    `json:"status"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+type Popularity struct {
+	Bookings int `json:"bookings"`
+	// Fraction of the range's bookings that chose the value
+	Share float64 `json:"share"`
+	Value string  `json:"value"`
+}
+
+type PrepEvent struct {
+	BookingID     int      `json:"bookingId"`
+	CoffeeFlavors []string `json:"coffeeFlavors"`
+	Location      string   `json:"location"`
+	MilkOptions   []string `json:"milkOptions"`
+	Package       string   `json:"package"`
+	People        int      `json:"people"`
+	Time          string   `json:"time"`
+}
+
+type PrepItem struct {
+	HasRecipe bool         `json:"hasRecipe"`
+	Label     string       `json:"label"`
+	Servings  float64      `json:"servings"`
+	Type      MenuItemType `json:"type"`
+	Value     string       `json:"value"`
+}
+
+type PrepSheet struct {
+	Bookings    int              `json:"bookings"`
+	Date        string           `json:"date"`
+	Events      []PrepEvent      `json:"events"`
+	Headcount   int              `json:"headcount"`
+	Ingredients []IngredientNeed `json:"ingredients"`
+	// Flavors and milk options booked that have no recipe, so aren't counted in ingredients
+	MissingRecipes []MissingRecipe `json:"missingRecipes"`
+	Prep           []PrepItem      `json:"prep"`
+	// The ingredients that are short
+	Shopping []IngredientNeed `json:"shopping"`
+}
+
+type ReadinessReport struct {
+	Checks map[string]CheckResult `json:"checks"`
+	Status string                 `json:"status"`
+}
+
+type Recipe struct {
+	Ingredients []RecipeIngredient `json:"ingredients"`
+	Label       string             `json:"label"`
+	MenuItemID  int                `json:"menuItemId"`
+	Type        MenuItemType       `json:"type"`
+	Value       string             `json:"value"`
+}
+
+type RecipeIngredient struct {
+	IngredientID int    `json:"ingredientId"`
+	Name         string `json:"name,omitempty"`
+	// Quantity in one serving, in the ingredient's unit
+	Quantity float64 `json:"quantity"`
+	Unit     string  `json:"unit,omitempty"`
+}
+
+type RecipeInput struct {
+	Ingredients []RecipeIngredient `json:"ingredients"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refreshToken,omitempty"`
+}
+
+type RefreshResponse struct {
+	AccessToken string `json:"accessToken,omitempty"`
+	CSRFToken   string `json:"csrfToken,omitempty"`
+	// The rotated refresh token; the presented one is no longer valid
+	RefreshToken string `json:"refreshToken,omitempty"`
+}
+
+type ResetPasswordRequest struct {
+	Password string `json:"password"`
+	Token    string `json:"token"`
+}
+
+type RevenueReport struct {
+	From     string           `json:"from"`
+	Packages []PackageRevenue `json:"packages"`
+	To       string           `json:"to"`
+	Total    float64          `json:"total"`
+}
+
+type Revoked struct {
+	// Number of sessions revoked
+	Revoked int64 `json:"revoked"`
+}
+
+type Role string
+
+const (
+	RoleOwner      Role = "owner"
+	RoleManager    Role = "manager"
+	RoleBarista    Role = "barista"
+	RoleBookkeeper Role = "bookkeeper"
+)
+
+type RolePermissions map[string][]Permission
+
+type Session struct {
+	CreatedAt time.Time `json:"createdAt"`
+	Device    string    `json:"device"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	// Shared by every token rotated from the same login
+	FamilyID  string     `json:"familyId"`
+	ID        int        `json:"id"`
+	RevokedAt *time.Time `json:"revokedAt,omitempty"`
+	UserID    int        `json:"userId"`
+}
+
+type SetupRequest struct {
+	Password   string `json:"password"`
+	SetupToken string `json:"setupToken"`
+	Username   string `json:"username"`
+}
+
+type SetupStatus struct {
+	SetupRequired bool `json:"setupRequired"`
+}
+
+type Status struct {
+	Status string `json:"status"`
+}
+
+type Success struct {
+	Success bool `json:"success"`
+}
+
+// Everything but bookings, canceled and cancellationRate leaves out canceled bookings.
+type SummaryReport struct {
+	Bookings         int      `json:"bookings"`
+	Canceled         int      `json:"canceled"`
+	CancellationRate float64  `json:"cancellationRate"`
+	From             string   `json:"from"`
+	Headcount        int      `json:"headcount"`
+	Indoor           int      `json:"indoor"`
+	LeadTime         LeadTime `json:"leadTime"`
+	Outdoor          int      `json:"outdoor"`
+	OutdoorWithShade int      `json:"outdoorWithShade"`
+	To               string   `json:"to"`
+}
+
+type TokenInfo struct {
+	Permissions []Permission `json:"permissions"`
+	Role        Role         `json:"role"`
+	UserID      int          `json:"userId"`
+}
+
+// Either code or recoveryCode is required.
+type TwoFactorCodeRequest struct {
+	Code         string `json:"code,omitempty"`
+	RecoveryCode string `json:"recoveryCode,omitempty"`
+}
+
+type TwoFactorEnrollRequest struct {
+	// Login challenge token, only for forced enrollment
+	ChallengeToken string `json:"challengeToken,omitempty"`
+	// TOTP code, only when confirming
+	Code string `json:"code,omitempty"`
+}
+
+type TwoFactorEnrollResponse struct {
+	OtpauthURI string `json:"otpauthUri"`
+	QrCodePNG  string `json:"qrCodePng"`
+	Secret     string `json:"secret"`
+}
+
+type TwoFactorStatus struct {
+	Enabled                bool `json:"enabled"`
+	RecoveryCodesRemaining int  `json:"recoveryCodesRemaining"`
+	Required               bool `json:"required"`
+}
+
+// Either code or recoveryCode is required.
+type TwoFactorVerifyRequest struct {
+	ChallengeToken string `json:"challengeToken"`
+	Code           string `json:"code,omitempty"`
+	RecoveryCode   string `json:"recoveryCode,omitempty"`
+}
+
+type User struct {
+	CreatedAt           time.Time  `json:"createdAt"`
+	Email               string     `json:"email"`
+	FailedLoginAttempts int        `json:"failedLoginAttempts"`
+	ID                  int        `json:"id"`
+	LastFailedLoginAt   *time.Time `json:"lastFailedLoginAt,omitempty"`
+	LockedUntil         *time.Time `json:"lockedUntil,omitempty"`
+	Role                Role       `json:"role"`
+	TOTPEnabled         bool       `json:"totpEnabled"`
+	UpdatedAt           time.Time  `json:"updatedAt"`
+	Username            string     `json:"username"`
+}
+
+type UserInput struct {
+	Email string `json:"email,omitempty"`
+	// Only used when creating a user
+	Password string `json:"password,omitempty"`
+	Role     Role   `json:"role"`
+	Username string `json:"username"`
+}
+
+type VolumePeriod struct {
+	Bookings int `json:"bookings"`
+	Canceled int `json:"canceled"`
+	// Canceled bookings as a fraction of all bookings
+	CancellationRate float64 `json:"cancellationRate"`
+	Headcount        int     `json:"headcount"`
+	// First day of the week or month
+	Start string `json:"start"`
+}
+
+type VolumeReport struct {
+	From     string         `json:"from"`
+	Interval string         `json:"interval"`
+	Periods  []VolumePeriod `json:"periods"`
+	To       string         `json:"to"`
+}
+
+type Webhook struct {
+	Active      bool               `json:"active"`
+	CreatedAt   time.Time          `json:"createdAt"`
+	Description string             `json:"description"`
+	Events      []WebhookEventName `json:"events"`
+	ID          int                `json:"id"`
+	// Whether event data includes the customer's personal data
+	IncludePii bool      `json:"includePii"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+	URL        string    `json:"url"`
+}
+
+// The booking. Without the webhook's personal data opt-in, the
+// customer's name, email, phone, location and notes are left out.
+type WebhookBookingData any
+
+type WebhookCreated struct {
+	Active      bool               `json:"active"`
+	CreatedAt   time.Time          `json:"createdAt"`
+	Description string             `json:"description"`
+	Events      []WebhookEventName `json:"events"`
+	ID          int                `json:"id"`
+	// Whether event data includes the customer's personal data
+	IncludePii bool `json:"includePii"`
+	// Key for verifying delivery signatures
+	Secret    string    `json:"secret"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	URL       string    `json:"url"`
+}
+
+type WebhookDelivery struct {
+	Attempt    int       `json:"attempt"`
+	CreatedAt  time.Time `json:"createdAt"`
+	DurationMs int64     `json:"durationMs"`
+	Error      string    `json:"error,omitempty"`
+	Event      string    `json:"event"`
+	EventID    string    `json:"eventId"`
+	ID         int       `json:"id"`
+	// The receiver's response status; absent when no response arrived
+	StatusCode int `json:"statusCode,omitempty"`
+	WebhookID  int `json:"webhookId"`
+}
+
+type WebhookEvent struct {
+	CreatedAt time.Time `json:"createdAt"`
+	// The booking, inquiry or test details the event is about
+	Data  any    `json:"data"`
+	Event string `json:"event"`
+	// Identifies the event; retries reuse it so receivers can drop duplicates
+	ID string `json:"id"`
+}
+
+// An event name, or "*" for every event
+type WebhookEventName string
+
+const (
+	WebhookEventNameAll             WebhookEventName = "*"
+	WebhookEventNameBookingCreated  WebhookEventName = "booking.created"
+	WebhookEventNameBookingUpdated  WebhookEventName = "booking.updated"
+	WebhookEventNameBookingArchived WebhookEventName = "booking.archived"
+	WebhookEventNameBookingCanceled WebhookEventName = "booking.canceled"
+	WebhookEventNameInquiryCreated  WebhookEventName = "inquiry.created"
+)
+
+type WebhookInput struct {
+	Active      bool               `json:"active"`
+	Description string             `json:"description,omitempty"`
+	Events      []WebhookEventName `json:"events"`
+	// Send customer names, email addresses, phone numbers, locations,
+	// notes and inquiries. Off by default: booking events then carry the
+	// booking without them and inquiry events an empty object.
+	IncludePii bool `json:"includePii,omitempty"`
+	// Signing secret of 16 to 255 characters; generated on create when omitted
+	Secret string `json:"secret,omitempty"`
+	// Absolute http or https URL that receives the POSTs
+	URL string `json:"url"`
+}
+
+type WebhookTestData struct {
+	Message   string `json:"message"`
+	WebhookID int    `json:"webhookId"`
+}
+
+// ArchiveBooking calls POST /api/v1/bookings/{id}/archive: archive a booking
+func (c *Client) ArchiveBooking(ctx context.Context, id int) error {
+	path := "/api/v1/bookings/" + url.PathEscape(fmt.Sprint(id)) + "/archive"
+	return c.do(ctx, "POST", path, nil, nil, nil)
+}
+
+// BulkBookings calls POST /api/v1/bookings/bulk: apply an action to many bookings
+func (c *Client) BulkBookings(ctx context.Context, body BulkBookingRequest) (*BulkBookingResponse, error) {
+	path := "/api/v1/bookings/bulk"
+	var out BulkBookingResponse
+	if err := c.do(ctx, "POST", path, nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ChangePassword calls POST /api/v1/auth/password: change the current user's password
+func (c *Client) ChangePassword(ctx context.Context, body ChangePasswordRequest) (*Revoked, error) {
+	path := "/api/v1/auth/password"
+	var out Revoked
+	if err := c.do(ctx, "POST", path, nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ConfirmTwoFactor calls POST /api/v1/auth/2fa/enroll/confirm: activate 2FA for the current user
+func (c *Client) ConfirmTwoFactor(ctx context.Context, body TwoFactorEnrollRequest) (*LoginResponse, error) {
+	path := "/api/v1/auth/2fa/enroll/confirm"
+	var out LoginResponse
+	if err := c.do(ctx, "POST", path, nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ConfirmTwoFactorSetup calls POST /api/v1/auth/2fa/setup/confirm: finish forced 2FA enrollment and log in
+func (c *Client) ConfirmTwoFactorSetup(ctx context.Context, body TwoFactorEnrollRequest) (*LoginResponse, error) {
+	path := "/api/v1/auth/2fa/setup/confirm"
+	var out LoginResponse
+	if err := c.do(ctx, "POST", path, nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateBooking calls POST /api/v1/bookings: request a booking
+func (c *Client) CreateBooking(ctx context.Context, body BookingInput) (*BookingCreated, error) {
+	path := "/api/v1/bookings"
+	var out BookingCreated
+	if err := c.do(ctx, "POST", path, nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateIngredient calls POST /api/v1/ingredients: add an ingredient
+func (c *Client) CreateIngredient(ctx context.Context, body IngredientInput) (*Ingredient, error) {
+	path := "/api/v1/ingredients"
+	var out Ingredient
+	if err := c.do(ctx, "POST", path, nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateInitialAdmin calls POST /api/v1/setup: create the first owner account with the one-time setup token
+func (c *Client) CreateInitialAdmin(ctx context.Context, body SetupRequest) (*User, error) {
+	path := "/api/v1/setup"
+	var out User
+	if err := c.do(ctx, "POST", path, nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateMenuItem calls POST /api/v1/menu: add a menu item
+func (c *Client) CreateMenuItem(ctx context.Context, body MenuItemInput) (*MenuItem, error) {
+	path := "/api/v1/menu"
+	var out MenuItem
+	if err := c.do(ctx, "POST", path, nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreatePackage calls POST /api/v1/packages: add a package
+func (c *Client) CreatePackage(ctx context.Context, body PackageInput) (*Package, error) {
+	path := "/api/v1/packages"
+	var out Package
+	if err := c.do(ctx, "POST", path, nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateUser calls POST /api/v1/users: create an admin user
+func (c *Client) CreateUser(ctx context.Context, body UserInput) (*User, error) {
+	path := "/api/v1/users"
+	var out User
+	if err := c.do(ctx, "POST", path, nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateWebhook calls POST /api/v1/webhooks: create a webhook subscription
+func (c *Client) CreateWebhook(ctx context.Context, body WebhookInput) (*WebhookCreated, error) {
+	path := "/api/v1/webhooks"
+	var out WebhookCreated
+	if err := c.do(ctx, "POST", path, nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteBooking calls DELETE /api/v1/bookings/{id}: delete a booking
+func (c *Client) DeleteBooking(ctx context.Context, id int) error {
+	path := "/api/v1/bookings/" + url.PathEscape(fmt.Sprint(id))
+	return c.do(ctx, "DELETE", path, nil, nil, nil)
+}
+
+// DeleteIngredient calls DELETE /api/v1/ingredients/{id}: delete an ingredient, removing it from every recipe
+func (c *Client) DeleteIngredient(ctx context.Context, id int) error {
+	path := "/api/v1/ingredients/" + url.PathEscape(fmt.Sprint(id))
+	return c.do(ctx, "DELETE", path, nil, nil, nil)
+}
+
+// DeleteMenuImage calls DELETE /api/v1/menu/{id}/image: remove a menu item's image
+func (c *Client) DeleteMenuImage(ctx context.Context, id int) error {
+	path := "/api/v1/menu/" + url.PathEscape(fmt.Sprint(id)) + "/image"
+	return c.do(ctx, "DELETE", path, nil, nil, nil)
+}
+
+// DeleteMenuItem calls DELETE /api/v1/menu/{id}: delete a menu item
+func (c *Client) DeleteMenuItem(ctx context.Context, id int) (*Message, error) {
+	path := "/api/v1/menu/" + url.PathEscape(fmt.Sprint(id))
+	var out Message
+	if err := c.do(ctx, "DELETE", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeletePackage calls DELETE /api/v1/packages/{id}: delete a package
+func (c *Client) DeletePackage(ctx context.Context, id int) error {
+	path := "/api/v1/packages/" + url.PathEscape(fmt.Sprint(id))
+	return c.do(ctx, "DELETE", path, nil, nil, nil)
+}
+
+// DeleteUser calls DELETE /api/v1/users/{id}: delete an admin user
+func (c *Client) DeleteUser(ctx context.Context, id int) error {
+	path := "/api/v1/users/" + url.PathEscape(fmt.Sprint(id))
+	return c.do(ctx, "DELETE", path, nil, nil, nil)
+}
+
+// DeleteWebhook calls DELETE /api/v1/webhooks/{id}: delete a webhook subscription and its delivery log
+func (c *Client) DeleteWebhook(ctx context.Context, id int) error {
+	path := "/api/v1/webhooks/" + url.PathEscape(fmt.Sprint(id))
+	return c.do(ctx, "DELETE", path, nil, nil, nil)
+}
+
+// DisableTwoFactor calls POST /api/v1/auth/2fa/disable: turn off 2FA for the current user
+func (c *Client) DisableTwoFactor(ctx context.Context, body TwoFactorCodeRequest) (*Success, error) {
+	path := "/api/v1/auth/2fa/disable"
+	var out Success
+	if err := c.do(ctx, "POST", path, nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// EnrollTwoFactor calls POST /api/v1/auth/2fa/enroll: start 2FA enrollment for the current user
+func (c *Client) EnrollTwoFactor(ctx context.Context, body *TwoFactorEnrollRequest) (*TwoFactorEnrollResponse, error) {
+	path := "/api/v1/auth/2fa/enroll"
+	var out TwoFactorEnrollResponse
+	if err := c.do(ctx, "POST", path, nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ExportBookingsParams holds the optional query parameters of ExportBookings
+type ExportBookingsParams struct {
+	Format          *string
+	Columns         *string
+	IncludeArchived *bool
+	Status          *BookingStatus
+	DateFrom        *string
+	DateTo          *string
+	AssignedUserID  *int
+}
+
+// ExportBookings calls GET /api/v1/bookings/export: export bookings as CSV or Excel
+func (c *Client) ExportBookings(ctx context.Context, params *ExportBookingsParams) (string, error) {
+	path := "/api/v1/bookings/export"
+	query := url.Values{}
+	if params != nil {
+		if params.Format != nil {
+			query.Set("format", fmt.Sprint(*params.Format))
+		}
+		if params.Columns != nil {
+			query.Set("columns", fmt.Sprint(*params.Columns))
+		}
+		if params.IncludeArchived != nil {
+			query.Set("include_archived", fmt.Sprint(*params.IncludeArchived))
+		}
+		if params.Status != nil {
+			query.Set("status", fmt.Sprint(*params.Status))
+		}
+		if params.DateFrom != nil {
+			query.Set("dateFrom", fmt.Sprint(*params.DateFrom))
+		}
+		if params.DateTo != nil {
+			query.Set("dateTo", fmt.Sprint(*params.DateTo))
+		}
+		if params.AssignedUserID != nil {
+			query.Set("assignedUserId", fmt.Sprint(*params.AssignedUserID))
+		}
+	}
+	var out string
+	err := c.do(ctx, "GET", path, query, nil, &out)
+	return out, err
+}
+
+// ForgotPassword calls POST /api/v1/auth/password/forgot: email a password reset link
+func (c *Client) ForgotPassword(ctx context.Context, body ForgotPasswordRequest) (*Message, error) {
+	path := "/api/v1/auth/password/forgot"
+	var out Message
+	if err := c.do(ctx, "POST", path, nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetAPIDocs calls GET /api/v1/docs: interactive API documentation
+func (c *Client) GetAPIDocs(ctx context.Context) (string, error) {
+	path := "/api/v1/docs"
+	var out string
+	err := c.do(ctx, "GET", path, nil, nil, &out)
+	return out, err
+}
+
+// GetBooking calls GET /api/v1/bookings/{id}: get a booking
+func (c *Client) GetBooking(ctx context.Context, id int) (*Booking, error) {
+	path := "/api/v1/bookings/" + url.PathEscape(fmt.Sprint(id))
+	var out Booking
+	if err := c.do(ctx, "GET", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetConfig calls GET /api/v1/config: effective configuration with secrets masked
+func (c *Client) GetConfig(ctx context.Context) (map[string]any, error) {
+	path := "/api/v1/config"
+	var out map[string]any
+	if err := c.do(ctx, "GET", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetHealth calls GET /health: basic health check
+func (c *Client) GetHealth(ctx context.Context) (*Health, error) {
+	path := "/health"
+	var out Health
+	if err := c.do(ctx, "GET", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetJWKS calls GET /.well-known/jwks.json: public keys for verifying access tokens
+func (c *Client) GetJWKS(ctx context.Context) (*JWKS, error) {
+	path := "/.well-known/jwks.json"
+	var out JWKS
+	if err := c.do(ctx, "GET", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetLiveness calls GET /livez: liveness probe
+func (c *Client) GetLiveness(ctx context.Context) (*Status, error) {
+	path := "/livez"
+	var out Status
+	if err := c.do(ctx, "GET", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetMediaFile calls GET /media/{key}: an uploaded image
+func (c *Client) GetMediaFile(ctx context.Context, key string) (string, error) {
+	path := "/media/" + url.PathEscape(fmt.Sprint(key))
+	var out string
+	err := c.do(ctx, "GET", path, nil, nil, &out)
+	return out, err
+}
+
+// GetMenuReportParams holds the optional query parameters of GetMenuReport
+type GetMenuReportParams struct {
+	From *string
+	To   *string
+}
+
+// GetMenuReport calls GET /api/v1/reports/menu: most popular coffee flavors and milk options
+func (c *Client) GetMenuReport(ctx context.Context, params *GetMenuReportParams) (*MenuReport, error) {
+	path := "/api/v1/reports/menu"
+	query := url.Values{}
+	if params != nil {
+		if params.From != nil {
+			query.Set("from", fmt.Sprint(*params.From))
+		}
+		if params.To != nil {
+			query.Set("to", fmt.Sprint(*params.To))
+		}
+	}
+	var out MenuReport
+	if err := c.do(ctx, "GET", path, query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetMetrics calls GET /metrics: prometheus metrics
+func (c *Client) GetMetrics(ctx context.Context) (string, error) {
+	path := "/metrics"
+	var out string
+	err := c.do(ctx, "GET", path, nil, nil, &out)
+	return out, err
+}
+
+// GetOpenAPISpec calls GET /api/v1/openapi.json: this document
+func (c *Client) GetOpenAPISpec(ctx context.Context) (map[string]any, error) {
+	path := "/api/v1/openapi.json"
+	var out map[string]any
+	if err := c.do(ctx, "GET", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetPackage calls GET /api/v1/packages/{id}: get a package
+func (c *Client) GetPackage(ctx context.Context, id int) (*Package, error) {
+	path := "/api/v1/packages/" + url.PathEscape(fmt.Sprint(id))
+	var out Package
+	if err := c.do(ctx, "GET", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetPrepSheetParams holds the optional query parameters of GetPrepSheet
+type GetPrepSheetParams struct {
+	Date *string
+}
+
+// GetPrepSheet calls GET /api/v1/prep-sheet: what to prepare and buy for a date's bookings
+func (c *Client) GetPrepSheet(ctx context.Context, params *GetPrepSheetParams) (*PrepSheet, error) {
+	path := "/api/v1/prep-sheet"
+	query := url.Values{}
+	if params != nil {
+		if params.Date != nil {
+			query.Set("date", fmt.Sprint(*params.Date))
+		}
+	}
+	var out PrepSheet
+	if err := c.do(ctx, "GET", path, query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetReadiness calls GET /readyz: readiness probe
+func (c *Client) GetReadiness(ctx context.Context) (*ReadinessReport, error) {
+	path := "/readyz"
+	var out ReadinessReport
+	if err := c.do(ctx, "GET", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetRevenueReportParams holds the optional query parameters of GetRevenueReport
+type GetRevenueReportParams struct {
+	From *string
+	To   *string
+}
+
+// GetRevenueReport calls GET /api/v1/reports/revenue: revenue by package
+func (c *Client) GetRevenueReport(ctx context.Context, params *GetRevenueReportParams) (*RevenueReport, error) {
+	path := "/api/v1/reports/revenue"
+	query := url.Values{}
+	if params != nil {
+		if params.From != nil {
+			query.Set("from", fmt.Sprint(*params.From))
+		}
+		if params.To != nil {
+			query.Set("to", fmt.Sprint(*params.To))
+		}
+	}
+	var out RevenueReport
+	if err := c.do(ctx, "GET", path, query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetSetupStatus calls GET /api/v1/setup: whether first-run setup is pending
+func (c *Client) GetSetupStatus(ctx context.Context) (*SetupStatus, error) {
+	path := "/api/v1/setup"
+	var out SetupStatus
+	if err := c.do(ctx, "GET", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetSummaryReportParams holds the optional query parameters of GetSummaryReport
+type GetSummaryReportParams struct {
+	From *string
+	To   *string
+}
+
+// GetSummaryReport calls GET /api/v1/reports/summary: booking totals, outdoor split, lead time and cancellations
+func (c *Client) GetSummaryReport(ctx context.Context, params *GetSummaryReportParams) (*SummaryReport, error) {
+	path := "/api/v1/reports/summary"
+	query := url.Values{}
+	if params != nil {
+		if params.From != nil {
+			query.Set("from", fmt.Sprint(*params.From))
+		}
+		if params.To != nil {
+			query.Set("to", fmt.Sprint(*params.To))
+		}
+	}
+	var out SummaryReport
+	if err := c.do(ctx, "GET", path, query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetTwoFactorStatus calls GET /api/v1/auth/2fa: the current user's 2FA status
+func (c *Client) GetTwoFactorStatus(ctx context.Context) (*TwoFactorStatus, error) {
+	path := "/api/v1/auth/2fa"
+	var out TwoFactorStatus
+	if err := c.do(ctx, "GET", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetUser calls GET /api/v1/users/{id}: get an admin user
+func (c *Client) GetUser(ctx context.Context, id int) (*User, error) {
+	path := "/api/v1/users/" + url.PathEscape(fmt.Sprint(id))
+	var out User
+	if err := c.do(ctx, "GET", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetVolumeReportParams holds the optional query parameters of GetVolumeReport
+type GetVolumeReportParams struct {
+	Interval *string
+	From     *string
+	To       *string
+}
+
+// GetVolumeReport calls GET /api/v1/reports/volume: bookings and headcount per week or month
+func (c *Client) GetVolumeReport(ctx context.Context, params *GetVolumeReportParams) (*VolumeReport, error) {
+	path := "/api/v1/reports/volume"
+	query := url.Values{}
+	if params != nil {
+		if params.Interval != nil {
+			query.Set("interval", fmt.Sprint(*params.Interval))
+		}
+		if params.From != nil {
+			query.Set("from", fmt.Sprint(*params.From))
+		}
+		if params.To != nil {
+			query.Set("to", fmt.Sprint(*params.To))
+		}
+	}
+	var out VolumeReport
+	if err := c.do(ctx, "GET", path, query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetWebhook calls GET /api/v1/webhooks/{id}: get a webhook subscription
+func (c *Client) GetWebhook(ctx context.Context, id int) (*Webhook, error) {
+	path := "/api/v1/webhooks/" + url.PathEscape(fmt.Sprint(id))
+	var out Webhook
+	if err := c.do(ctx, "GET", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ImportBookings calls POST /api/v1/bookings/import: import bookings from CSV
+func (c *Client) ImportBookings(ctx context.Context, body BookingImportRequest) (*BookingImportResponse, error) {
+	path := "/api/v1/bookings/import"
+	var out BookingImportResponse
+	if err := c.do(ctx, "POST", path, nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListBookingsParams holds the optional query parameters of ListBookings
+type ListBookingsParams struct {
+	IncludeArchived *bool
+	Status          *BookingStatus
+	DateFrom        *string
+	DateTo          *string
+	AssignedUserID  *int
+}
+
+// ListBookings calls GET /api/v1/bookings: list bookings
+func (c *Client) ListBookings(ctx context.Context, params *ListBookingsParams) ([]Booking, error) {
+	path := "/api/v1/bookings"
+	query := url.Values{}
+	if params != nil {
+		if params.IncludeArchived != nil {
+			query.Set("include_archived", fmt.Sprint(*params.IncludeArchived))
+		}
+		if params.Status != nil {
+			query.Set("status", fmt.Sprint(*params.Status))
+		}
+		if params.DateFrom != nil {
+			query.Set("dateFrom", fmt.Sprint(*params.DateFrom))
+		}
+		if params.DateTo != nil {
+			query.Set("dateTo", fmt.Sprint(*params.DateTo))
+		}
+		if params.AssignedUserID != nil {
+			query.Set("assignedUserId", fmt.Sprint(*params.AssignedUserID))
+		}
+	}
+	var out []Booking
+	if err := c.do(ctx, "GET", path, query, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListIngredients calls GET /api/v1/ingredients: list ingredients and their stock
+func (c *Client) ListIngredients(ctx context.Context) ([]Ingredient, error) {
+	path := "/api/v1/ingredients"
+	var out []Ingredient
+	if err := c.do(ctx, "GET", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListMenuItems calls GET /api/v1/menu: list menu items
+func (c *Client) ListMenuItems(ctx context.Context) ([]MenuItem, error) {
+	path := "/api/v1/menu"
+	var out []MenuItem
+	if err := c.do(ctx, "GET", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListMenuItemsByType calls GET /api/v1/menu/{type}: list menu items of one type
+func (c *Client) ListMenuItemsByType(ctx context.Context, typeParam MenuItemType) ([]MenuItem, error) {
+	path := "/api/v1/menu/" + url.PathEscape(fmt.Sprint(typeParam))
+	var out []MenuItem
+	if err := c.do(ctx, "GET", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListPackages calls GET /api/v1/packages: list packages
+func (c *Client) ListPackages(ctx context.Context) ([]Package, error) {
+	path := "/api/v1/packages"
+	var out []Package
+	if err := c.do(ctx, "GET", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListRecipes calls GET /api/v1/recipes: list the recipe of every menu item
+func (c *Client) ListRecipes(ctx context.Context) ([]Recipe, error) {
+	path := "/api/v1/recipes"
+	var out []Recipe
+	if err := c.do(ctx, "GET", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListRoles calls GET /api/v1/users/roles: roles and the permissions each one grants
+func (c *Client) ListRoles(ctx context.Context) (RolePermissions, error) {
+	path := "/api/v1/users/roles"
+	var out RolePermissions
+	if err := c.do(ctx, "GET", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListUserSessions calls GET /api/v1/users/{id}/sessions: list a user's active sessions
+func (c *Client) ListUserSessions(ctx context.Context, id int) ([]Session, error) {
+	path := "/api/v1/users/" + url.PathEscape(fmt.Sprint(id)) + "/sessions"
+	var out []Session
+	if err := c.do(ctx, "GET", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListUsers calls GET /api/v1/users: list admin users
+func (c *Client) ListUsers(ctx context.Context) ([]User, error) {
+	path := "/api/v1/users"
+	var out []User
+	if err := c.do(ctx, "GET", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListWebhookDeliveriesParams holds the optional query parameters of ListWebhookDeliveries
+type ListWebhookDeliveriesParams struct {
+	Limit *int
+}
+
+// ListWebhookDeliveries calls GET /api/v1/webhooks/{id}/deliveries: list a webhook's recent delivery attempts, newest first
+func (c *Client) ListWebhookDeliveries(ctx context.Context, id int, params *ListWebhookDeliveriesParams) ([]WebhookDelivery, error) {
+	path := "/api/v1/webhooks/" + url.PathEscape(fmt.Sprint(id)) + "/deliveries"
+	query := url.Values{}
+	if params != nil {
+		if params.Limit != nil {
+			query.Set("limit", fmt.Sprint(*params.Limit))
+		}
+	}
+	var out []WebhookDelivery
+	if err := c.do(ctx, "GET", path, query, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListWebhooks calls GET /api/v1/webhooks: list webhook subscriptions
+func (c *Client) ListWebhooks(ctx context.Context) ([]Webhook, error) {
+	path := "/api/v1/webhooks"
+	var out []Webhook
+	if err := c.do(ctx, "GET", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Login calls POST /api/v1/auth/login: log in with username and password
+func (c *Client) Login(ctx context.Context, body LoginRequest) (*LoginResponse, error) {
+	path := "/api/v1/auth/login"
+	var out LoginResponse
+	if err := c.do(ctx, "POST", path, nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Logout calls POST /api/v1/auth/logout: revoke the current refresh token
+func (c *Client) Logout(ctx context.Context, body *LogoutRequest) (*Success, error) {
+	path := "/api/v1/auth/logout"
+	var out Success
+	if err := c.do(ctx, "POST", path, nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Ping calls GET /ping: logged ping for checking cron jobs
+func (c *Client) Ping(ctx context.Context) (*Ping, error) {
+	path := "/ping"
+	var out Ping
+	if err := c.do(ctx, "GET", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// PingSimple calls GET /ping-simple: plain text ping
+func (c *Client) PingSimple(ctx context.Context) (string, error) {
+	path := "/ping-simple"
+	var out string
+	err := c.do(ctx, "GET", path, nil, nil, &out)
+	return out, err
+}
+
+// RefreshToken calls POST /api/v1/auth/refresh: exchange a refresh token for new tokens
+func (c *Client) RefreshToken(ctx context.Context, body *RefreshRequest) (*RefreshResponse, error) {
+	path := "/api/v1/auth/refresh"
+	var out RefreshResponse
+	if err := c.do(ctx, "POST", path, nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RegenerateRecoveryCodes calls POST /api/v1/auth/2fa/recovery-codes: replace the current user's recovery codes
+func (c *Client) RegenerateRecoveryCodes(ctx context.Context, body TwoFactorCodeRequest) (*LoginResponse, error) {
+	path := "/api/v1/auth/2fa/recovery-codes"
+	var out LoginResponse
+	if err := c.do(ctx, "POST", path, nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ReorderMenuItems calls PUT /api/v1/menu/order: set the display order of menu items of one type
+func (c *Client) ReorderMenuItems(ctx context.Context, body MenuOrder) ([]MenuItem, error) {
+	path := "/api/v1/menu/order"
+	var out []MenuItem
+	if err := c.do(ctx, "PUT", path, nil, body, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ResetPassword calls POST /api/v1/auth/password/reset: set a new password with a reset token
+func (c *Client) ResetPassword(ctx context.Context, body ResetPasswordRequest) (*Success, error) {
+	path := "/api/v1/auth/password/reset"
+	var out Success
+	if err := c.do(ctx, "POST", path, nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ResetUserTwoFactor calls POST /api/v1/users/{id}/2fa/reset: turn off 2FA for a user who lost their authenticator
+func (c *Client) ResetUserTwoFactor(ctx context.Context, id int) error {
+	path := "/api/v1/users/" + url.PathEscape(fmt.Sprint(id)) + "/2fa/reset"
+	return c.do(ctx, "POST", path, nil, nil, nil)
+}
+
+// RevokeMySessions calls POST /api/v1/auth/sessions/revoke: sign the current user out of every device
+func (c *Client) RevokeMySessions(ctx context.Context) (*Revoked, error) {
+	path := "/api/v1/auth/sessions/revoke"
+	var out Revoked
+	if err := c.do(ctx, "POST", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RevokeUserSessions calls POST /api/v1/users/{id}/sessions/revoke: sign a user out of every device
+func (c *Client) RevokeUserSessions(ctx context.Context, id int) (*Revoked, error) {
+	path := "/api/v1/users/" + url.PathEscape(fmt.Sprint(id)) + "/sessions/revoke"
+	var out Revoked
+	if err := c.do(ctx, "POST", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SendInquiry calls POST /api/v1/contact: send a contact form inquiry
+func (c *Client) SendInquiry(ctx context.Context, body ContactRequest) (*Message, error) {
+	path := "/api/v1/contact"
+	var out Message
+	if err := c.do(ctx, "POST", path, nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SetRecipe calls PUT /api/v1/recipes/{menuItemId}: replace the ingredients in one serving of a menu item
+func (c *Client) SetRecipe(ctx context.Context, menuItemID int, body RecipeInput) (*Recipe, error) {
+	path := "/api/v1/recipes/" + url.PathEscape(fmt.Sprint(menuItemID))
+	var out Recipe
+	if err := c.do(ctx, "PUT", path, nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// StartTwoFactorSetup calls POST /api/v1/auth/2fa/setup: start forced 2FA enrollment with a login challenge token
+func (c *Client) StartTwoFactorSetup(ctx context.Context, body TwoFactorEnrollRequest) (*TwoFactorEnrollResponse, error) {
+	path := "/api/v1/auth/2fa/setup"
+	var out TwoFactorEnrollResponse
+	if err := c.do(ctx, "POST", path, nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// TestRender calls GET /test-render: logged request for checking the hosting platform
+func (c *Client) TestRender(ctx context.Context) (string, error) {
+	path := "/test-render"
+	var out string
+	err := c.do(ctx, "GET", path, nil, nil, &out)
+	return out, err
+}
+
+// TestWebhook calls POST /api/v1/webhooks/{id}/test: send a webhook.test event now
+func (c *Client) TestWebhook(ctx context.Context, id int) (*WebhookDelivery, error) {
+	path := "/api/v1/webhooks/" + url.PathEscape(fmt.Sprint(id)) + "/test"
+	var out WebhookDelivery
+	if err := c.do(ctx, "POST", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UnarchiveBooking calls POST /api/v1/bookings/{id}/unarchive: restore an archived booking
+func (c *Client) UnarchiveBooking(ctx context.Context, id int) error {
+	path := "/api/v1/bookings/" + url.PathEscape(fmt.Sprint(id)) + "/unarchive"
+	return c.do(ctx, "POST", path, nil, nil, nil)
+}
+
+// UnlockUser calls POST /api/v1/users/{id}/unlock: clear a login lockout
+func (c *Client) UnlockUser(ctx context.Context, id int) (*User, error) {
+	path := "/api/v1/users/" + url.PathEscape(fmt.Sprint(id)) + "/unlock"
+	var out User
+	if err := c.do(ctx, "POST", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateBooking calls PUT /api/v1/bookings/{id}: update a booking
+func (c *Client) UpdateBooking(ctx context.Context, id int, body BookingInput) (*Message, error) {
+	path := "/api/v1/bookings/" + url.PathEscape(fmt.Sprint(id))
+	var out Message
+	if err := c.do(ctx, "PUT", path, nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateIngredient calls PUT /api/v1/ingredients/{id}: update an ingredient's name, unit or stock
+func (c *Client) UpdateIngredient(ctx context.Context, id int, body IngredientInput) (*Ingredient, error) {
+	path := "/api/v1/ingredients/" + url.PathEscape(fmt.Sprint(id))
+	var out Ingredient
+	if err := c.do(ctx, "PUT", path, nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateMenuItem calls PUT /api/v1/menu/{id}: update a menu item
+func (c *Client) UpdateMenuItem(ctx context.Context, id int, body MenuItemInput) (*Message, error) {
+	path := "/api/v1/menu/" + url.PathEscape(fmt.Sprint(id))
+	var out Message
+	if err := c.do(ctx, "PUT", path, nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdatePackage calls PUT /api/v1/packages/{id}: update a package
+func (c *Client) UpdatePackage(ctx context.Context, id int, body PackageInput) (*Package, error) {
+	path := "/api/v1/packages/" + url.PathEscape(fmt.Sprint(id))
+	var out Package
+	if err := c.do(ctx, "PUT", path, nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateUser calls PUT /api/v1/users/{id}: update an admin user's username, email and role
+func (c *Client) UpdateUser(ctx context.Context, id int, body UserInput) (*User, error) {
+	path := "/api/v1/users/" + url.PathEscape(fmt.Sprint(id))
+	var out User
+	if err := c.do(ctx, "PUT", path, nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateWebhook calls PUT /api/v1/webhooks/{id}: update a webhook subscription
+func (c *Client) UpdateWebhook(ctx context.Context, id int, body WebhookInput) (*Webhook, error) {
+	path := "/api/v1/webhooks/" + url.PathEscape(fmt.Sprint(id))
+	var out Webhook
+	if err := c.do(ctx, "PUT", path, nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UploadMenuImage calls POST /api/v1/menu/{id}/image: upload or replace a menu item's image
+func (c *Client) UploadMenuImage(ctx context.Context, id int, filename string, file io.Reader) (*MenuItem, error) {
+	path := "/api/v1/menu/" + url.PathEscape(fmt.Sprint(id)) + "/image"
+	var out MenuItem
+	if err := c.do(ctx, "POST", path, nil, &upload{field: "image", filename: filename, file: file}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ValidateToken calls GET /api/v1/auth/validate: describe the current session
+func (c *Client) ValidateToken(ctx context.Context) (*TokenInfo, error) {
+	path := "/api/v1/auth/validate"
+	var out TokenInfo
+	if err := c.do(ctx, "GET", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// VerifyTwoFactor calls POST /api/v1/auth/2fa/verify: complete a login with a TOTP or recovery code
+func (c *Client) VerifyTwoFactor(ctx context.Context, body TwoFactorVerifyRequest) (*LoginResponse, error) {
+	path := "/api/v1/auth/2fa/verify"
+	var out LoginResponse
+	if err := c.do(ctx, "POST", path, nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
@@ -0,0 +1,153 @@
+// Package apiclient is a typed Go client for the Toasted Coffee Co API, for
+// integration scripts and tooling. Types and methods are generated from the
+// OpenAPI spec; regenerate them after changing internal/openapi/openapi.yaml.
+package apiclient
+
+//go:generate go run ../cmd/apigen -out client.gen.go
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Client calls the API at BaseURL, e.g. "http://localhost:8080"
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+
+	// Token is sent as a bearer token when set, e.g. LoginResponse.Token
+	Token string
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient replaces the default HTTP client
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.HTTPClient = hc }
+}
+
+// WithToken authenticates requests with an access token
+func WithToken(token string) Option {
+	return func(c *Client) { c.Token = token }
+}
+
+// NewClient creates a client for the API at baseURL
+func NewClient(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		BaseURL:    strings.TrimSuffix(baseURL, "/"),
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// APIError is returned for responses with a non-2xx status. The API sends
+// errors as a plain text message.
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API error %d: %s", e.StatusCode, e.Message)
+}
+
+// upload is a file sent as the only field of a multipart form
+type upload struct {
+	field    string
+	filename string
+	file     io.Reader
+}
+
+// encode writes the form, returning it and its Content-Type
+func (u *upload) encode() ([]byte, string, error) {
+	var buf bytes.Buffer
+	form := multipart.NewWriter(&buf)
+	part, err := form.CreateFormFile(u.field, u.filename)
+	if err != nil {
+		return nil, "", err
+	}
+	if _, err := io.Copy(part, u.file); err != nil {
+		return nil, "", err
+	}
+	if err := form.Close(); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), form.FormDataContentType(), nil
+}
+
+// do sends a request with body encoded as JSON, or as a multipart form for an
+// *upload, and decodes the response into out. A *string out receives the raw
+// body; a nil out discards it.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out any) error {
+	target := c.BaseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	var reader io.Reader
+	var contentType string
+	switch body := body.(type) {
+	case nil:
+	case *upload:
+		payload, formType, err := body.encode()
+		if err != nil {
+			return fmt.Errorf("failed to encode upload: %w", err)
+		}
+		reader, contentType = bytes.NewReader(payload), formType
+	default:
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader, contentType = bytes.NewReader(payload), "application/json"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return &APIError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(message))}
+	}
+
+	switch out := out.(type) {
+	case nil:
+		return nil
+	case *string:
+		raw, err := io.ReadAll(resp.Body)
+		*out = string(raw)
+		return err
+	default:
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,84 @@
+package apiclient_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/joshuagudgel/toasted-coffee/backend/apiclient"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/openapi"
+)
+
+func TestGeneratedClientIsCurrent(t *testing.T) {
+	want, err := openapi.GenerateClient("apiclient")
+	if err != nil {
+		t.Fatalf("Failed to generate client: %v", err)
+	}
+	got, err := os.ReadFile("client.gen.go")
+	if err != nil {
+		t.Fatalf("Failed to read client: %v", err)
+	}
+	if string(got) != string(want) {
+		t.Error("client.gen.go is out of date with openapi.yaml, run go generate ./apiclient")
+	}
+}
+
+func TestClient(t *testing.T) {
+	router := chi.NewRouter()
+	router.Get("/api/v1/openapi.json", openapi.SpecHandler)
+	router.Get("/api/v1/bookings", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer access-token" {
+			http.Error(w, "Authentication required", http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Query().Get("include_archived") != "true" {
+			http.Error(w, "Expected include_archived", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode([]map[string]interface{}{{"id": 7, "name": "Ada", "archived": true}})
+	})
+	router.Get("/api/v1/bookings/{id}", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Booking not found", http.StatusNotFound)
+	})
+	router.Post("/api/v1/bookings/{id}/archive", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	client := apiclient.NewClient(server.URL+"/", apiclient.WithToken("access-token"))
+	ctx := context.Background()
+
+	includeArchived := true
+	bookings, err := client.ListBookings(ctx, &apiclient.ListBookingsParams{IncludeArchived: &includeArchived})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(bookings) != 1 || bookings[0].ID != 7 || !bookings[0].Archived {
+		t.Errorf("Unexpected bookings: %+v", bookings)
+	}
+
+	_, err = client.GetBooking(ctx, 8)
+	var apiErr *apiclient.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound || apiErr.Message != "Booking not found" {
+		t.Errorf("Expected a 404 APIError, got %v", err)
+	}
+
+	if err := client.ArchiveBooking(ctx, 7); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	spec, err := client.GetOpenAPISpec(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if spec["openapi"] != "3.1.0" {
+		t.Errorf("Unexpected spec version %v", spec["openapi"])
+	}
+}
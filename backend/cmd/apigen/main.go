@@ -0,0 +1,30 @@
+// Command apigen generates the typed API client in package apiclient from
+// the OpenAPI spec in internal/openapi/openapi.yaml.
+//
+// Usage: go generate ./apiclient
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/openapi"
+)
+
+func main() {
+	out := flag.String("out", "client.gen.go", "output file")
+	pkg := flag.String("package", "apiclient", "package name of the generated file")
+	flag.Parse()
+
+	src, err := openapi.GenerateClient(*pkg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error generating client: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := os.WriteFile(*out, src, 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing client: %v\n", err)
+		os.Exit(1)
+	}
+}
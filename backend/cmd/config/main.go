@@ -0,0 +1,32 @@
+// Command config loads and validates the configuration the API would start
+// with and prints it as YAML with secrets masked.
+//
+// Usage: go run ./cmd/config [-config path/to/config.yaml]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+func main() {
+	path := flag.String("config", os.Getenv("CONFIG_FILE"), "YAML or TOML config file (defaults to $CONFIG_FILE)")
+	flag.Parse()
+
+	cfg, err := config.LoadFile(*path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	out, err := yaml.Marshal(cfg.Redacted())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error encoding config: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Print(string(out))
+}
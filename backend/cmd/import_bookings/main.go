@@ -0,0 +1,84 @@
+// Command import_bookings imports bookings from a CSV file into the database
+// the API is configured with, checking each row as the import endpoint does.
+// It prints the outcome of every row that wasn't created and a summary, and
+// exits with status 1 if the file couldn't be imported at all.
+//
+// Usage: go run ./cmd/import_bookings [-config path/to/config.yaml]
+// [-map field=Column,...] [-dry-run] bookings.csv
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/config"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/database"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/importer"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
+)
+
+func main() {
+	path := flag.String("config", os.Getenv("CONFIG_FILE"), "YAML config file (defaults to $CONFIG_FILE)")
+	mappingSpec := flag.String("map", "", "booking field to CSV column, as field=Column pairs separated by commas")
+	dryRun := flag.Bool("dry-run", false, "report what would be imported without saving")
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "Usage: import_bookings [-config file] [-map field=Column,...] [-dry-run] bookings.csv")
+		os.Exit(2)
+	}
+
+	if err := run(flag.Arg(0), *path, *mappingSpec, *dryRun); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(file, configPath, mappingSpec string, dryRun bool) error {
+	mapping, err := importer.ParseMapping(mappingSpec)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.LoadFile(configPath)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	db, err := database.New(cfg.Database)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Close()
+
+	response, err := importer.Import(context.Background(), database.NewBookingRepository(db), f, mapping, dryRun)
+	if err != nil {
+		return err
+	}
+
+	for _, result := range response.Results {
+		switch result.Result {
+		case models.ImportResultInvalid:
+			fmt.Printf("line %d: invalid: %s\n", result.Row, strings.Join(result.Errors, "; "))
+		case models.ImportResultDuplicate:
+			fmt.Printf("line %d: duplicate of booking %d\n", result.Row, result.ID)
+		}
+	}
+
+	verb := "Created"
+	if dryRun {
+		verb = "Dry run, would create"
+	}
+	fmt.Printf("%s %d of %d bookings (%d duplicates, %d invalid)\n",
+		verb, response.Created, response.Rows, response.Duplicates, response.Invalid)
+	return nil
+}
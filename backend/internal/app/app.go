@@ -1,21 +1,51 @@
 package app
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/auth"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/blob"
 	"github.com/joshuagudgel/toasted-coffee/backend/internal/config"
 	"github.com/joshuagudgel/toasted-coffee/backend/internal/database"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/events"
 	"github.com/joshuagudgel/toasted-coffee/backend/internal/handlers"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/jobs"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/logging"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/metrics"
 	"github.com/joshuagudgel/toasted-coffee/backend/internal/server"
 	"github.com/joshuagudgel/toasted-coffee/backend/internal/services"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/tracing"
 )
 
 type App struct {
-	cfg    *config.Config
-	db     *database.DB
-	server *http.Server
+	cfg          *config.Config
+	db           *database.DB
+	server       *http.Server
+	metricsSrv   *http.Server
+	emailService *services.EmailService
+	webhooks     *services.WebhookService
+	events       *events.Broker
+	readiness    *server.Readiness
+
+	// scheduler runs the maintenance jobs, or is nil when they are disabled;
+	// stopJobs ends its loop
+	scheduler *jobs.Scheduler
+	stopJobs  context.CancelFunc
+
+	// stopListening ends the LISTEN loop of the postgres events backend
+	stopListening context.CancelFunc
+
+	shutdownTracing func(context.Context) error
 }
 
 func New() (*App, error) {
@@ -25,60 +55,310 @@ func New() (*App, error) {
 		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
 
+	// Structured logging for slog and the standard log package
+	if err := logging.Setup(cfg.Logging); err != nil {
+		return nil, err
+	}
+
+	// OpenTelemetry tracing; the default "none" exporter leaves it a no-op
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing, cfg.Environment)
+	if err != nil {
+		return nil, err
+	}
+
+	// Load JWT signing keys before anything can issue tokens
+	tokens, err := auth.NewTokenService(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up tokens: %w", err)
+	}
+
 	// Connect to database
-	db, err := database.New(cfg.DatabaseURL)
+	db, err := database.New(cfg.Database)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
-	// Run migrations - Admin seeder and other migrations
-	if err := runDatabaseSetup(db); err != nil {
+	// Run migrations and bootstrap the first admin account
+	setupToken, err := runDatabaseSetup(db, cfg)
+	if err != nil {
 		db.Close()
 		return nil, err
 	}
 
 	// Initialize services
-	emailService := services.NewEmailService()
+	emailService := services.NewEmailService(cfg.Mail)
 
 	// Initialize repositories
 	repos := database.NewRepositories(db)
+	webhooks := services.NewWebhookService(repos.Webhook, cfg.Webhooks)
+
+	// Admin event stream; with the postgres backend every instance hears
+	// every event through LISTEN/NOTIFY
+	broker := events.NewBroker(cfg.Events.History)
+	listenCtx, stopListening := context.WithCancel(context.Background())
+	if cfg.Events.Backend == "postgres" {
+		transport := events.NewPostgresTransport(db.Pool, broker)
+		broker.SetTransport(transport)
+		go transport.Listen(listenCtx)
+	}
+
+	// Prometheus metrics are opt-in; a nil registry records nothing
+	var appMetrics *metrics.Metrics
+	if cfg.Metrics.Enabled {
+		appMetrics = metrics.New()
+		appMetrics.Register(
+			metrics.NewPoolCollector(db.Pool),
+			metrics.NewBookingCollector(repos.BookingStats),
+		)
+		emailService.SetMetrics(appMetrics)
+		webhooks.SetMetrics(appMetrics)
+	}
+
+	// Maintenance jobs run on every instance; the jobs table makes sure each
+	// scheduled run happens on only one of them
+	var scheduler *jobs.Scheduler
+	if cfg.Jobs.Enabled {
+		scheduler, err = newScheduler(cfg, repos, webhooks, broker, appMetrics)
+		if err != nil {
+			stopListening()
+			db.Close()
+			return nil, err
+		}
+	}
+
+	authOpts := handlers.AuthOptions{
+		RequireTwoFactor: cfg.Features.RequireTwoFactor,
+		PasswordResetURL: strings.TrimSuffix(cfg.Server.AdminURL, "/") + "/reset-password",
+	}
+
+	// Cookie session mode is opt-in; bearer tokens keep working either way
+	if cfg.Features.CookieSessions {
+		authOpts.Cookies, err = auth.NewCookieSettings(cfg.Auth.CookieDomain, cfg.Auth.CookieSecure, cfg.Auth.CookieSameSite)
+		if err != nil {
+			stopListening()
+			db.Close()
+			return nil, fmt.Errorf("invalid auth cookie config: %w", err)
+		}
+		slog.InfoContext(context.Background(), "cookie session mode enabled")
+	}
 
 	// Initialize handlers
-	handlers := handlers.NewHandlers(repos, emailService)
+	handlers := handlers.NewHandlers(cfg, repos, tokens, emailService, webhooks, broker, setupToken, authOpts)
 
 	// Setup router
-	router := server.NewRouter(handlers, cfg)
+	readiness := server.NewReadiness(cfg.Health.CheckTimeout, readinessChecks(cfg, db, emailService)...)
+	router := server.NewRouter(handlers, tokens, repos.User, cfg, readiness, appMetrics)
 
 	// Create HTTP server
 	httpServer := &http.Server{
-		Addr:    fmt.Sprintf(":%s", cfg.Port),
-		Handler: router,
+		Addr:         fmt.Sprintf(":%s", cfg.Server.Port),
+		Handler:      router,
+		ReadTimeout:  cfg.Server.ReadTimeout,
+		WriteTimeout: cfg.Server.WriteTimeout,
+		IdleTimeout:  cfg.Server.IdleTimeout,
+	}
+
+	app := &App{
+		cfg:          cfg,
+		db:           db,
+		server:       httpServer,
+		emailService: emailService,
+		webhooks:     webhooks,
+		events:       broker,
+		readiness:    readiness,
+		scheduler:    scheduler,
+		stopJobs:     func() {},
+
+		stopListening:   stopListening,
+		shutdownTracing: shutdownTracing,
+	}
+
+	// Metrics on their own listener, kept off the public port
+	if cfg.Metrics.Enabled && cfg.Metrics.ListenAddr != "" {
+		metricsRouter := http.NewServeMux()
+		metricsRouter.Handle("/metrics", appMetrics.Handler(cfg.Metrics.Token))
+		app.metricsSrv = &http.Server{
+			Addr:        cfg.Metrics.ListenAddr,
+			Handler:     metricsRouter,
+			ReadTimeout: cfg.Server.ReadTimeout,
+		}
 	}
 
-	return &App{
-		cfg:    cfg,
-		db:     db,
-		server: httpServer,
-	}, nil
+	return app, nil
 }
 
+// Run serves until SIGINT or SIGTERM, then shuts down gracefully. A second
+// signal during shutdown terminates immediately.
 func (a *App) Run() error {
-	log.Printf("Server starting on %s", a.server.Addr)
-	return a.server.ListenAndServe()
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	listener, err := net.Listen("tcp", a.server.Addr)
+	if err != nil {
+		return err
+	}
+
+	serveErr := make(chan error, 2)
+	go func() {
+		serveErr <- a.server.Serve(listener)
+	}()
+
+	if a.metricsSrv != nil {
+		metricsListener, err := net.Listen("tcp", a.metricsSrv.Addr)
+		if err != nil {
+			a.server.Close()
+			return fmt.Errorf("failed to listen for metrics: %w", err)
+		}
+		go func() {
+			serveErr <- a.metricsSrv.Serve(metricsListener)
+		}()
+		slog.InfoContext(ctx, "metrics server listening", "addr", a.metricsSrv.Addr)
+	}
+
+	slog.InfoContext(ctx, "server listening", "addr", a.server.Addr)
+	a.readiness.SetReady(true)
+
+	if a.scheduler != nil {
+		jobsCtx, stopJobs := context.WithCancel(context.Background())
+		a.stopJobs = stopJobs
+		a.scheduler.Start(jobsCtx)
+	}
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	// Restore default signal handling so a second signal kills the process
+	stop()
+	return a.shutdown()
 }
 
-func runDatabaseSetup(db *database.DB) error {
+// shutdown stops accepting traffic and scheduled jobs, closes event streams,
+// waits for in-flight requests, jobs, background emails and webhook
+// deliveries, and leaves closing the pool to Close
+func (a *App) shutdown() error {
+	slog.InfoContext(context.Background(), "shutdown signal received, draining")
+	a.readiness.SetReady(false)
+	time.Sleep(a.cfg.Server.ShutdownDelay)
+
+	ctx, cancel := context.WithTimeout(context.Background(), a.cfg.Server.ShutdownTimeout)
+	defer cancel()
+
+	// A job in progress is canceled and recorded as failed
+	a.stopJobs()
+
+	// End event streams first; Shutdown would otherwise wait on them
+	a.stopListening()
+	a.events.Close()
+
+	var errs []error
+	if err := a.server.Shutdown(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to drain HTTP server: %w", err))
+	}
+	if a.metricsSrv != nil {
+		if err := a.metricsSrv.Shutdown(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to stop metrics server: %w", err))
+		}
+	}
+	// Jobs finish before the drains below, since archiving sends webhooks
+	if a.scheduler != nil {
+		if err := a.scheduler.Wait(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := a.emailService.Drain(ctx); err != nil {
+		errs = append(errs, err)
+	}
+	if err := a.webhooks.Drain(ctx); err != nil {
+		errs = append(errs, err)
+	}
+	// Flush spans last so the ones from draining requests are exported too
+	if err := a.shutdownTracing(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to flush traces: %w", err))
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	slog.InfoContext(ctx, "server stopped")
+	return nil
+}
+
+// runDatabaseSetup runs migrations and bootstraps the first admin. It returns a
+// one-time setup token when no users exist and no initial password hash is configured.
+func runDatabaseSetup(db *database.DB, cfg *config.Config) (string, error) {
 	migrator := database.NewMigrator(db)
 	if err := migrator.RunMigrations(); err != nil {
-		return fmt.Errorf("failed to run migrations: %w", err)
+		return "", fmt.Errorf("failed to run migrations: %w", err)
 	}
 
 	seeder := database.NewSeeder(db)
-	if err := seeder.SeedAdminUser(); err != nil {
-		return fmt.Errorf("failed to seed admin user: %w", err)
+	setupRequired, err := seeder.BootstrapAdmin(cfg.Auth.InitialAdminUsername, cfg.Auth.InitialAdminPasswordHash)
+	if err != nil {
+		return "", fmt.Errorf("failed to bootstrap admin user: %w", err)
 	}
 
-	return nil
+	if !setupRequired {
+		return "", nil
+	}
+
+	setupToken, err := auth.GenerateSetupToken()
+	if err != nil {
+		return "", err
+	}
+
+	// The token is valid until it is used or the server restarts. It is
+	// written once, straight to stderr: the default logger masks it, since
+	// its records may be shipped to a log service.
+	setupLog := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	setupLog.WarnContext(context.Background(), "no admin account exists, create one with POST /api/v1/setup",
+		"setup_token", setupToken)
+
+	return setupToken, nil
+}
+
+// newScheduler sets up the scheduler with the maintenance jobs
+func newScheduler(cfg *config.Config, repos *database.Repositories, webhooks *services.WebhookService,
+	broker *events.Broker, appMetrics *metrics.Metrics) (*jobs.Scheduler, error) {
+	scheduler, err := jobs.NewScheduler(repos.Job, cfg.Jobs)
+	if err != nil {
+		return nil, err
+	}
+	scheduler.SetMetrics(appMetrics)
+
+	maintenance, err := jobs.NewMaintenance(repos.Maintenance, cfg.Jobs, webhooks, broker, blob.New(cfg.Media))
+	if err != nil {
+		return nil, err
+	}
+	if err := maintenance.Register(scheduler); err != nil {
+		return nil, err
+	}
+	return scheduler, nil
+}
+
+// readinessChecks lists the dependencies /readyz verifies
+func readinessChecks(cfg *config.Config, db *database.DB, emailService *services.EmailService) []server.Check {
+	migrator := database.NewMigrator(db)
+	checks := []server.Check{
+		{Name: "database", Run: db.Pool.Ping},
+		{Name: "migrations", Run: func(ctx context.Context) error {
+			pending, err := migrator.Pending(ctx)
+			if err != nil {
+				return err
+			}
+			if len(pending) > 0 {
+				return fmt.Errorf("%d migrations not applied, next is %s", len(pending), pending[0])
+			}
+			return nil
+		}},
+	}
+
+	if cfg.Health.CheckSMTP {
+		checks = append(checks, server.Check{Name: "smtp", Run: emailService.Ping})
+	}
+	return checks
 }
 
 func (a *App) Close() error {
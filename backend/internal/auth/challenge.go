@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// ChallengePurpose says what a login challenge token may be exchanged for
+type ChallengePurpose string
+
+const (
+	// ChallengeVerify is issued after the password check when the user has 2FA enabled
+	ChallengeVerify ChallengePurpose = "toasted-coffee-2fa"
+	// ChallengeEnroll is issued when 2FA is required but the user has not enrolled yet
+	ChallengeEnroll ChallengePurpose = "toasted-coffee-2fa-enroll"
+)
+
+// challengeExpiry bounds how long the second login step can take
+const challengeExpiry = 5 * time.Minute
+
+// GenerateChallengeToken issues a short-lived token proving the password step
+// succeeded. It carries no role and is rejected by ValidateToken.
+func (s *TokenService) GenerateChallengeToken(userID int, purpose ChallengePurpose) (string, error) {
+	now := s.now()
+	claims := jwt.RegisteredClaims{
+		ExpiresAt: jwt.NewNumericDate(now.Add(challengeExpiry)),
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
+		Issuer:    "toasted-coffee-co",
+		Subject:   strconv.Itoa(userID),
+		Audience:  []string{string(purpose)},
+		ID:        uuid.New().String(),
+	}
+
+	return s.keys.Sign(claims)
+}
+
+// ValidateChallengeToken verifies a challenge token for the given purpose and returns its user ID
+func (s *TokenService) ValidateChallengeToken(tokenString string, purpose ChallengePurpose) (int, error) {
+	token, err := s.parse(tokenString, &jwt.RegisteredClaims{}, jwt.WithAudience(string(purpose)), jwt.WithIssuer("toasted-coffee-co"))
+
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return 0, ErrTokenExpired
+		}
+		slog.DebugContext(context.Background(), "challenge token rejected", "purpose", purpose, "error", err)
+		return 0, ErrTokenInvalid
+	}
+
+	claims, ok := token.Claims.(*jwt.RegisteredClaims)
+	if !ok || !token.Valid {
+		return 0, ErrTokenInvalid
+	}
+
+	userID, err := strconv.Atoi(claims.Subject)
+	if err != nil {
+		return 0, ErrTokenInvalid
+	}
+
+	return userID, nil
+}
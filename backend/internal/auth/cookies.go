@@ -0,0 +1,135 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Cookie and header names used by the cookie session mode
+const (
+	AccessTokenCookie  = "tc_access"
+	RefreshTokenCookie = "tc_refresh"
+	CSRFCookie         = "tc_csrf"
+	CSRFHeader         = "X-CSRF-Token"
+)
+
+// Cookie paths: the refresh token is only ever sent to the auth endpoints
+const (
+	accessCookiePath  = "/api"
+	refreshCookiePath = "/api/v1/auth"
+	csrfCookiePath    = "/"
+)
+
+// AuthMethodContextKey records how the request was authenticated
+const AuthMethodContextKey ContextKey = "auth_method"
+
+// Authentication methods stored under AuthMethodContextKey
+const (
+	AuthMethodBearer = "bearer"
+	AuthMethodCookie = "cookie"
+)
+
+// CookieSettings controls the attributes of session cookies
+type CookieSettings struct {
+	Domain   string
+	Secure   bool
+	SameSite http.SameSite
+}
+
+// NewCookieSettings builds cookie settings from configuration values.
+// sameSite must be one of "strict", "lax" or "none".
+func NewCookieSettings(domain string, secure bool, sameSite string) (*CookieSettings, error) {
+	settings := &CookieSettings{
+		Domain: domain,
+		Secure: secure,
+	}
+
+	switch strings.ToLower(sameSite) {
+	case "strict":
+		settings.SameSite = http.SameSiteStrictMode
+	case "", "lax":
+		settings.SameSite = http.SameSiteLaxMode
+	case "none":
+		// Browsers reject SameSite=None cookies that are not Secure
+		if !secure {
+			return nil, fmt.Errorf("SameSite=None cookies must be Secure")
+		}
+		settings.SameSite = http.SameSiteNoneMode
+	default:
+		return nil, fmt.Errorf("invalid SameSite value %q", sameSite)
+	}
+
+	return settings, nil
+}
+
+// SetSession writes the access, refresh and CSRF cookies
+func (s *CookieSettings) SetSession(w http.ResponseWriter, accessToken string, accessExpiry time.Time,
+	refreshToken string, refreshExpiry time.Time, csrfToken string) {
+	http.SetCookie(w, s.cookie(AccessTokenCookie, accessToken, accessCookiePath, accessExpiry, true))
+	http.SetCookie(w, s.cookie(RefreshTokenCookie, refreshToken, refreshCookiePath, refreshExpiry, true))
+	// The CSRF cookie must be readable by the SPA so it can echo it in the header
+	http.SetCookie(w, s.cookie(CSRFCookie, csrfToken, csrfCookiePath, refreshExpiry, false))
+}
+
+// ClearSession expires all session cookies
+func (s *CookieSettings) ClearSession(w http.ResponseWriter) {
+	for _, c := range []struct{ name, path string }{
+		{AccessTokenCookie, accessCookiePath},
+		{RefreshTokenCookie, refreshCookiePath},
+		{CSRFCookie, csrfCookiePath},
+	} {
+		cookie := s.cookie(c.name, "", c.path, time.Unix(0, 0), c.name != CSRFCookie)
+		cookie.MaxAge = -1
+		http.SetCookie(w, cookie)
+	}
+}
+
+func (s *CookieSettings) cookie(name, value, path string, expires time.Time, httpOnly bool) *http.Cookie {
+	return &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     path,
+		Domain:   s.Domain,
+		Expires:  expires,
+		Secure:   s.Secure,
+		HttpOnly: httpOnly,
+		SameSite: s.SameSite,
+	}
+}
+
+// GenerateCSRFToken creates a random token for the double-submit cookie pattern
+func GenerateCSRFToken() (string, error) {
+	return randomToken("CSRF")
+}
+
+// VerifyCSRF checks that the CSRF header matches the CSRF cookie
+func VerifyCSRF(r *http.Request) bool {
+	cookie, err := r.Cookie(CSRFCookie)
+	if err != nil || cookie.Value == "" {
+		return false
+	}
+	header := r.Header.Get(CSRFHeader)
+	if header == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(header)) == 1
+}
+
+// IsSafeMethod reports whether the HTTP method does not change state
+func IsSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// AuthenticatedByCookie reports whether JWTAuth accepted the session cookie for this request
+func AuthenticatedByCookie(ctx context.Context) bool {
+	method, _ := ctx.Value(AuthMethodContextKey).(string)
+	return method == AuthMethodCookie
+}
@@ -4,14 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
-	"os"
+	"log/slog"
 	"strconv"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
-	"github.com/joho/godotenv"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/config"
 )
 
 // Make this exportable so middleware can use it
@@ -25,87 +24,79 @@ var (
 	ErrTokenExpired     = errors.New("token has expired")
 	ErrTokenNotValidYet = errors.New("token not valid yet")
 	ErrTokenInvalid     = errors.New("token is invalid")
-	ErrMissingSecret    = errors.New("jwt secret key is not configured")
 )
 
 // Token-related functions and structures
 type Claims struct {
-	UserID int    `json:"userId"`
-	Role   string `json:"role"`
+	UserID      int          `json:"userId"`
+	Role        string       `json:"role"`
+	Permissions []Permission `json:"permissions"`
 	jwt.RegisteredClaims
 }
 
-// Global variables
-var secretKey []byte
-var refreshSecretKey []byte
-var tokenExpiry time.Duration
-var refreshTokenExpiry time.Duration
+// TokenService issues and validates access, refresh and login challenge
+// tokens. Construct it once from configuration and share it between the
+// handlers and the JWT middleware.
+type TokenService struct {
+	keys          *KeyRing
+	accessExpiry  time.Duration
+	refreshExpiry time.Duration
+	now           func() time.Time
+}
 
-func init() {
-	// Load the .env file
-	err := godotenv.Load()
-	if err != nil {
-		log.Printf("Error loading .env file: %v", err)
-	}
-	// Load the secret key from environment
-	secretKeyStr := os.Getenv("JWT_SECRET")
-	if secretKeyStr == "" {
-		// TODO: remove
-		log.Println("WARNING: JWT_SECRET environment variable not set! Using a random key for this session.")
-
-		// Generate a random key for this session
-		randomKey := uuid.New().String()
-		secretKey = []byte(randomKey)
-	} else {
-		secretKey = []byte(secretKeyStr)
-		log.Printf("JWT secret key configured with length: %d bytes", len(secretKey))
+// NewTokenService loads the JWT keys and token lifetimes from configuration
+func NewTokenService(cfg *config.Config) (*TokenService, error) {
+	if cfg.Auth.TokenExpiry <= 0 || cfg.Auth.RefreshTokenExpiry <= 0 {
+		return nil, errors.New("token expiry durations must be positive")
 	}
 
-	// Add this for refreshSecretKey
-	refreshSecretKeyStr := os.Getenv("JWT_REFRESH_SECRET")
-	if refreshSecretKeyStr == "" {
-		log.Println("WARNING: JWT_REFRESH_SECRET environment variable not set! Using same key as JWT_SECRET.")
-		refreshSecretKey = secretKey
-	} else {
-		refreshSecretKey = []byte(refreshSecretKeyStr)
-		log.Printf("JWT refresh secret key configured with length: %d bytes", len(refreshSecretKey))
+	keys, err := LoadKeyRing(KeyRingConfig{
+		PrivateKey:     cfg.Auth.JWTPrivateKey,
+		PrivateKeyFile: cfg.Auth.JWTPrivateKeyFile,
+		VerifyKeys:     cfg.Auth.JWTVerifyKeys,
+		VerifyKeyFiles: cfg.Auth.JWTVerifyKeyFiles,
+		// Production refuses to start without a configured key
+		AllowEphemeral: !cfg.IsProduction(),
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// Parse token expiry from environment
-	tokenExpiryStr := os.Getenv("TOKEN_EXPIRY")
-	if tokenExpiryStr == "" {
-		log.Println("WARNING: TOKEN_EXPIRY not set, defaulting to 15m")
-		tokenExpiry = 15 * time.Minute
-	} else {
-		parsed, err := time.ParseDuration(tokenExpiryStr)
-		if err != nil {
-			log.Printf("WARNING: Invalid TOKEN_EXPIRY format: %v, defaulting to 15m", err)
-			tokenExpiry = 15 * time.Minute
-		} else {
-			tokenExpiry = parsed
-		}
-	}
-	log.Printf("Access token expiry set to: %s", tokenExpiry)
-
-	// Parse refresh token expiry from environment
-	refreshTokenExpiryStr := os.Getenv("REFRESH_TOKEN_EXPIRY")
-	if refreshTokenExpiryStr == "" {
-		log.Println("WARNING: REFRESH_TOKEN_EXPIRY not set, defaulting to 7d")
-		refreshTokenExpiry = 7 * 24 * time.Hour
-	} else {
-		parsed, err := time.ParseDuration(refreshTokenExpiryStr)
-		if err != nil {
-			log.Printf("WARNING: Invalid REFRESH_TOKEN_EXPIRY format: %v, defaulting to 7d", err)
-			refreshTokenExpiry = 7 * 24 * time.Hour
-		} else {
-			refreshTokenExpiry = parsed
-		}
-	}
-	log.Printf("Refresh token expiry set to: %s", refreshTokenExpiry)
+	slog.InfoContext(context.Background(), "token expiry configured",
+		"access", cfg.Auth.TokenExpiry.String(), "refresh", cfg.Auth.RefreshTokenExpiry.String())
+
+	return &TokenService{
+		keys:          keys,
+		accessExpiry:  cfg.Auth.TokenExpiry,
+		refreshExpiry: cfg.Auth.RefreshTokenExpiry,
+		now:           time.Now,
+	}, nil
+}
+
+// SetClock replaces the time source used for issuing and validating tokens
+func (s *TokenService) SetClock(now func() time.Time) {
+	s.now = now
+}
+
+// KeyRing returns the keys tokens are signed and verified with
+func (s *TokenService) KeyRing() *KeyRing {
+	return s.keys
+}
+
+// AccessTokenExpiry returns the lifetime of access tokens
+func (s *TokenService) AccessTokenExpiry() time.Duration {
+	return s.accessExpiry
+}
+
+// parse verifies a token's signature and registered time claims against the service clock
+func (s *TokenService) parse(tokenString string, claims jwt.Claims, opts ...jwt.ParserOption) (*jwt.Token, error) {
+	// The key ring checks the kid and that the signing method matches the key
+	opts = append(opts, jwt.WithTimeFunc(s.now))
+	return jwt.ParseWithClaims(tokenString, claims, s.keys.Keyfunc, opts...)
 }
 
 // Token generation and validation functions
-func GenerateToken(userID int, role string) (string, error) {
+func (s *TokenService) GenerateToken(userID int, role string) (string, error) {
 	// Create unique token ID
 	tokenID := uuid.New().String()
 
@@ -113,35 +104,28 @@ func GenerateToken(userID int, role string) (string, error) {
 	audiences := []string{"toasted-coffee-admin", "toasted-coffee-api"}
 
 	// Create claims with expiration time and additional security claims
+	now := s.now()
 	claims := &Claims{
-		UserID: userID,
-		Role:   role,
+		UserID:      userID,
+		Role:        role,
+		Permissions: PermissionsForRole(role),
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(tokenExpiry)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(now.Add(s.accessExpiry)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
 			Issuer:    "toasted-coffee-co",
 			Audience:  audiences,
 			ID:        tokenID,
 		},
 	}
 
-	// Generate token with claims
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-
-	// Sign token with secret key
-	return token.SignedString(secretKey)
+	// Sign with the key ring's current key
+	return s.keys.Sign(claims)
 }
 
-func ValidateToken(tokenString string) (*Claims, error) {
+func (s *TokenService) ValidateToken(tokenString string) (*Claims, error) {
 	// Parse the token
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		// Verify the signing method is what we expect
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return secretKey, nil
-	})
+	token, err := s.parse(tokenString, &Claims{})
 
 	if err != nil {
 		// Convert JWT errors to our custom errors for more secure error handling
@@ -153,7 +137,7 @@ func ValidateToken(tokenString string) (*Claims, error) {
 		}
 
 		// Don't expose specific JWT errors to callers
-		log.Printf("JWT validation error (not exposed to client): %v", err)
+		slog.DebugContext(context.Background(), "access token rejected", "error", err)
 		return nil, ErrTokenInvalid
 	}
 
@@ -164,7 +148,7 @@ func ValidateToken(tokenString string) (*Claims, error) {
 
 	// Explicitly check expiration even though the JWT library does this
 	// This is for clarity and additional security
-	now := time.Now()
+	now := s.now()
 	if now.After(claims.ExpiresAt.Time) {
 		return nil, ErrTokenExpired
 	}
@@ -187,6 +171,11 @@ func ValidateToken(tokenString string) (*Claims, error) {
 		return nil, errors.New("token has invalid audience")
 	}
 
+	// Reject tokens carrying a role that is no longer part of the permission matrix
+	if !IsValidRole(claims.Role) {
+		return nil, ErrTokenInvalid
+	}
+
 	return claims, nil
 }
 
@@ -197,59 +186,70 @@ func ExtractClaimsFromContext(ctx context.Context) (*Claims, bool) {
 	return nil, false
 }
 
+// RefreshToken is a signed refresh token together with the metadata that is persisted for revocation
+type RefreshToken struct {
+	Token     string
+	ID        string // jti claim; only its hash is stored server side
+	ExpiresAt time.Time
+}
+
 // Refresh token functionality
-func GenerateRefreshToken(userID int) (string, error) {
+func (s *TokenService) GenerateRefreshToken(userID int) (*RefreshToken, error) {
 	// Create unique token ID for revocation capability
 	tokenID := uuid.New().String()
+	now := s.now()
+	expiresAt := now.Add(s.refreshExpiry)
 
 	refreshClaims := jwt.RegisteredClaims{
-		ExpiresAt: jwt.NewNumericDate(time.Now().Add(refreshTokenExpiry)),
-		IssuedAt:  jwt.NewNumericDate(time.Now()),
-		NotBefore: jwt.NewNumericDate(time.Now()),
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
 		Issuer:    "toasted-coffee-co",
 		Subject:   fmt.Sprintf("%d", userID),
 		Audience:  []string{"toasted-coffee-refresh"},
 		ID:        tokenID,
 	}
 
-	refreshToken := jwt.NewWithClaims(jwt.SigningMethodHS256, refreshClaims)
+	signed, err := s.keys.Sign(refreshClaims)
+	if err != nil {
+		return nil, err
+	}
 
-	return refreshToken.SignedString(refreshSecretKey)
+	return &RefreshToken{
+		Token:     signed,
+		ID:        tokenID,
+		ExpiresAt: expiresAt,
+	}, nil
 }
 
-func ValidateRefreshToken(tokenString string) (int, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &jwt.RegisteredClaims{}, func(token *jwt.Token) (interface{}, error) {
-		// Verify signing method
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return refreshSecretKey, nil
-	})
+// ValidateRefreshToken verifies a refresh token and returns the user ID and token ID (jti) it carries
+func (s *TokenService) ValidateRefreshToken(tokenString string) (int, string, error) {
+	token, err := s.parse(tokenString, &jwt.RegisteredClaims{})
 
 	if err != nil {
 		// Convert JWT errors to our custom errors
 		if errors.Is(err, jwt.ErrTokenExpired) {
-			return 0, ErrTokenExpired
+			return 0, "", ErrTokenExpired
 		}
 		// Don't expose specific JWT errors
-		log.Printf("Refresh token validation error (not exposed): %v", err)
-		return 0, ErrTokenInvalid
+		slog.DebugContext(context.Background(), "refresh token rejected", "error", err)
+		return 0, "", ErrTokenInvalid
 	}
 
 	claims, ok := token.Claims.(*jwt.RegisteredClaims)
 	if !ok || !token.Valid {
-		return 0, ErrTokenInvalid
+		return 0, "", ErrTokenInvalid
 	}
 
 	// Explicitly check expiration
-	now := time.Now()
+	now := s.now()
 	if now.After(claims.ExpiresAt.Time) {
-		return 0, ErrTokenExpired
+		return 0, "", ErrTokenExpired
 	}
 
 	// Explicitly check not-before time
 	if now.Before(claims.NotBefore.Time) {
-		return 0, ErrTokenNotValidYet
+		return 0, "", ErrTokenNotValidYet
 	}
 
 	// Verify this is a refresh token
@@ -262,18 +262,22 @@ func ValidateRefreshToken(tokenString string) (int, error) {
 	}
 
 	if !validAudience {
-		return 0, errors.New("token has invalid audience")
+		return 0, "", errors.New("token has invalid audience")
 	}
 
 	userID, err := strconv.Atoi(claims.Subject)
 	if err != nil {
-		return 0, errors.New("invalid user ID in token")
+		return 0, "", errors.New("invalid user ID in token")
+	}
+
+	if claims.ID == "" {
+		return 0, "", errors.New("refresh token has no token ID")
 	}
 
-	return userID, nil
+	return userID, claims.ID, nil
 }
 
-// IsAdmin helper function to check if a user has admin role
-func IsAdmin(claims *Claims) bool {
-	return claims != nil && claims.Role == "admin"
+// IsOwner helper function to check if a user has the owner role
+func IsOwner(claims *Claims) bool {
+	return claims != nil && claims.Role == string(RoleOwner)
 }
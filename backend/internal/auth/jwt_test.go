@@ -0,0 +1,101 @@
+package auth_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/auth"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/config"
+)
+
+func newTestTokenService(t *testing.T) *auth.TokenService {
+	t.Helper()
+	tokens, err := auth.NewTokenService(&config.Config{Auth: config.AuthConfig{
+		TokenExpiry:        15 * time.Minute,
+		RefreshTokenExpiry: 24 * time.Hour,
+	}})
+	if err != nil {
+		t.Fatalf("Failed to create token service: %v", err)
+	}
+	return tokens
+}
+
+func TestNewTokenServiceRequiresKeyInProduction(t *testing.T) {
+	_, err := auth.NewTokenService(&config.Config{
+		Environment: "production",
+		Auth: config.AuthConfig{
+			TokenExpiry:        15 * time.Minute,
+			RefreshTokenExpiry: 24 * time.Hour,
+		},
+	})
+	if !errors.Is(err, auth.ErrNoSigningKey) {
+		t.Errorf("Expected ErrNoSigningKey, got %v", err)
+	}
+}
+
+func TestAccessTokenExpiry(t *testing.T) {
+	issued := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		validateAt  time.Time
+		expectedErr error
+	}{
+		{name: "Just issued", validateAt: issued},
+		{name: "Before expiry", validateAt: issued.Add(14 * time.Minute)},
+		{name: "After expiry", validateAt: issued.Add(16 * time.Minute), expectedErr: auth.ErrTokenExpired},
+		{name: "Before issue", validateAt: issued.Add(-time.Minute), expectedErr: auth.ErrTokenNotValidYet},
+	}
+
+	tokens := newTestTokenService(t)
+	tokens.SetClock(func() time.Time { return issued })
+	token, err := tokens.GenerateToken(1, "owner")
+	if err != nil {
+		t.Fatalf("GenerateToken returned error: %v", err)
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tokens.SetClock(func() time.Time { return tc.validateAt })
+
+			claims, err := tokens.ValidateToken(token)
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("Expected error %v, got %v", tc.expectedErr, err)
+			}
+			if err == nil && claims.UserID != 1 {
+				t.Errorf("Expected user 1, got %d", claims.UserID)
+			}
+		})
+	}
+}
+
+func TestRefreshTokenExpiry(t *testing.T) {
+	issued := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tokens := newTestTokenService(t)
+	tokens.SetClock(func() time.Time { return issued })
+	refresh, err := tokens.GenerateRefreshToken(7)
+	if err != nil {
+		t.Fatalf("GenerateRefreshToken returned error: %v", err)
+	}
+	if !refresh.ExpiresAt.Equal(issued.Add(24 * time.Hour)) {
+		t.Errorf("Expected expiry %v, got %v", issued.Add(24*time.Hour), refresh.ExpiresAt)
+	}
+
+	tokens.SetClock(func() time.Time { return issued.Add(23 * time.Hour) })
+	userID, tokenID, err := tokens.ValidateRefreshToken(refresh.Token)
+	if err != nil || userID != 7 || tokenID != refresh.ID {
+		t.Errorf("Expected user 7 and jti %s, got %d, %s (%v)", refresh.ID, userID, tokenID, err)
+	}
+
+	tokens.SetClock(func() time.Time { return issued.Add(25 * time.Hour) })
+	if _, _, err := tokens.ValidateRefreshToken(refresh.Token); !errors.Is(err, auth.ErrTokenExpired) {
+		t.Errorf("Expected ErrTokenExpired, got %v", err)
+	}
+
+	// Refresh and access tokens are not interchangeable
+	if _, err := tokens.ValidateToken(refresh.Token); err == nil {
+		t.Error("Refresh token must not be accepted as an access token")
+	}
+}
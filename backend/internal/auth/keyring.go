@@ -0,0 +1,375 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// minRSAKeyBits is the smallest RSA modulus accepted for signing or verification
+const minRSAKeyBits = 2048
+
+var (
+	ErrNoSigningKey   = errors.New("no JWT signing key configured")
+	ErrUnknownKeyID   = errors.New("token signed with unknown key")
+	ErrUnsupportedKey = errors.New("unsupported JWT key type, use RSA or Ed25519")
+)
+
+// Key is a JWT key identified by its kid. Verification-only keys have no private half.
+type Key struct {
+	ID     string
+	Method jwt.SigningMethod
+
+	private crypto.Signer
+	public  crypto.PublicKey
+}
+
+// CanSign reports whether the key holds a private key
+func (k *Key) CanSign() bool {
+	return k.private != nil
+}
+
+// KeyRing holds the current signing key and every key tokens may still be
+// verified with. Keeping retired keys in the ring lets tokens issued before a
+// rotation stay valid until they expire.
+type KeyRing struct {
+	mu      sync.RWMutex
+	signing *Key
+	keys    map[string]*Key
+}
+
+// NewKeyRing creates a key ring that signs with signing and also accepts
+// tokens signed by any of the verification keys
+func NewKeyRing(signing *Key, verification ...*Key) (*KeyRing, error) {
+	if signing == nil || !signing.CanSign() {
+		return nil, ErrNoSigningKey
+	}
+
+	ring := &KeyRing{signing: signing, keys: map[string]*Key{signing.ID: signing}}
+	for _, key := range verification {
+		if _, exists := ring.keys[key.ID]; !exists {
+			ring.keys[key.ID] = key
+		}
+	}
+	return ring, nil
+}
+
+// Rotate makes key the signing key. The previous signing key stays in the
+// ring for verification.
+func (k *KeyRing) Rotate(key *Key) error {
+	if key == nil || !key.CanSign() {
+		return ErrNoSigningKey
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.signing = key
+	k.keys[key.ID] = key
+	return nil
+}
+
+// Retire removes a verification key. Tokens signed with it stop validating.
+func (k *KeyRing) Retire(kid string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.signing.ID == kid {
+		return errors.New("cannot retire the current signing key")
+	}
+	delete(k.keys, kid)
+	return nil
+}
+
+// SigningKeyID returns the kid new tokens are signed with
+func (k *KeyRing) SigningKeyID() string {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.signing.ID
+}
+
+// Sign signs claims with the current signing key and sets the kid header
+func (k *KeyRing) Sign(claims jwt.Claims) (string, error) {
+	k.mu.RLock()
+	key := k.signing
+	k.mu.RUnlock()
+
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.private)
+}
+
+// Keyfunc resolves the verification key from the token's kid header. The
+// token's alg must match the key so an RSA public key can never be used as
+// an HMAC secret.
+func (k *KeyRing) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		return nil, fmt.Errorf("token has no kid header")
+	}
+
+	k.mu.RLock()
+	key, ok := k.keys[kid]
+	k.mu.RUnlock()
+	if !ok {
+		return nil, ErrUnknownKeyID
+	}
+
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+	return key.public, nil
+}
+
+// JWK is the public JSON Web Key representation of a verification key
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+}
+
+// JWKS is the document served at /.well-known/jwks.json
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public halves of every key in the ring, signing key first
+func (k *KeyRing) JWKS() JWKS {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	var retired []JWK
+	for kid, key := range k.keys {
+		if kid != k.signing.ID {
+			retired = append(retired, key.jwk())
+		}
+	}
+	sort.Slice(retired, func(i, j int) bool { return retired[i].Kid < retired[j].Kid })
+
+	return JWKS{Keys: append([]JWK{k.signing.jwk()}, retired...)}
+}
+
+func (k *Key) jwk() JWK {
+	jwk := JWK{Use: "sig", Alg: k.Method.Alg(), Kid: k.ID}
+	switch pub := k.public.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+	}
+	return jwk
+}
+
+// thumbprint computes the RFC 7638 JWK thumbprint, used as the default kid so
+// every instance derives the same ID from the same key file
+func thumbprint(jwk JWK) string {
+	var members string
+	switch jwk.Kty {
+	case "RSA":
+		members = fmt.Sprintf(`{"e":%q,"kty":"RSA","n":%q}`, jwk.E, jwk.N)
+	case "OKP":
+		members = fmt.Sprintf(`{"crv":%q,"kty":"OKP","x":%q}`, jwk.Crv, jwk.X)
+	}
+	sum := sha256.Sum256([]byte(members))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// newKey wraps an RSA or Ed25519 key, picking RS256 or EdDSA accordingly
+func newKey(private crypto.Signer, public crypto.PublicKey) (*Key, error) {
+	key := &Key{private: private, public: public}
+	switch pub := public.(type) {
+	case *rsa.PublicKey:
+		if pub.N.BitLen() < minRSAKeyBits {
+			return nil, fmt.Errorf("RSA key must be at least %d bits", minRSAKeyBits)
+		}
+		key.Method = jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		key.Method = jwt.SigningMethodEdDSA
+	default:
+		return nil, ErrUnsupportedKey
+	}
+	key.ID = thumbprint(key.jwk())
+	return key, nil
+}
+
+// GenerateKey creates a new Ed25519 signing key
+func GenerateKey() (*Key, error) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate JWT signing key: %w", err)
+	}
+	return newKey(private, public)
+}
+
+// ParseKeys reads every PEM block in data. Private keys can sign, public keys
+// are verification-only.
+func ParseKeys(data []byte) ([]*Key, error) {
+	var keys []*Key
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+
+		key, err := parsePEMBlock(block)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	if len(keys) == 0 {
+		return nil, errors.New("no PEM encoded keys found")
+	}
+	return keys, nil
+}
+
+func parsePEMBlock(block *pem.Block) (*Key, error) {
+	switch block.Type {
+	case "PRIVATE KEY", "RSA PRIVATE KEY":
+		var parsed interface{}
+		var err error
+		if block.Type == "RSA PRIVATE KEY" {
+			parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+		} else {
+			parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid private key: %w", err)
+		}
+		signer, ok := parsed.(crypto.Signer)
+		if !ok {
+			return nil, ErrUnsupportedKey
+		}
+		return newKey(signer, signer.Public())
+	case "PUBLIC KEY":
+		parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key: %w", err)
+		}
+		return newKey(nil, parsed)
+	case "RSA PUBLIC KEY":
+		parsed, err := x509.ParsePKCS1PublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key: %w", err)
+		}
+		return newKey(nil, parsed)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+}
+
+// KeyRingConfig says where to load JWT keys from. Inline PEM values take
+// precedence over files.
+type KeyRingConfig struct {
+	// PrivateKey / PrivateKeyFile hold the PEM encoded signing key
+	PrivateKey     string
+	PrivateKeyFile string
+
+	// VerifyKeys / VerifyKeyFiles hold retired keys that still verify tokens
+	VerifyKeys     string
+	VerifyKeyFiles []string
+
+	// AllowEphemeral generates a throwaway key when none is configured.
+	// Sessions then don't survive a restart, so it must be off in production.
+	AllowEphemeral bool
+}
+
+// LoadKeyRing builds a key ring from configuration
+func LoadKeyRing(cfg KeyRingConfig) (*KeyRing, error) {
+	signingPEM, err := pemFromEnvOrFile(cfg.PrivateKey, cfg.PrivateKeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var signing *Key
+	if len(signingPEM) == 0 {
+		if !cfg.AllowEphemeral {
+			return nil, fmt.Errorf("%w: set JWT_PRIVATE_KEY or JWT_PRIVATE_KEY_FILE", ErrNoSigningKey)
+		}
+		slog.WarnContext(context.Background(), "no JWT signing key configured; using an ephemeral key, sessions end when the server restarts")
+		if signing, err = GenerateKey(); err != nil {
+			return nil, err
+		}
+	} else {
+		keys, err := ParseKeys(signingPEM)
+		if err != nil {
+			return nil, fmt.Errorf("JWT signing key: %w", err)
+		}
+		if len(keys) != 1 || !keys[0].CanSign() {
+			return nil, fmt.Errorf("JWT signing key must be exactly one private key")
+		}
+		signing = keys[0]
+	}
+
+	var verification []*Key
+	if cfg.VerifyKeys != "" {
+		keys, err := ParseKeys([]byte(unescapePEM(cfg.VerifyKeys)))
+		if err != nil {
+			return nil, fmt.Errorf("JWT verification keys: %w", err)
+		}
+		verification = append(verification, keys...)
+	}
+	for _, path := range cfg.VerifyKeyFiles {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read JWT verification key: %w", err)
+		}
+		keys, err := ParseKeys(data)
+		if err != nil {
+			return nil, fmt.Errorf("JWT verification key %s: %w", path, err)
+		}
+		verification = append(verification, keys...)
+	}
+
+	ring, err := NewKeyRing(signing, verification...)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(context.Background(), "JWT key ring loaded", "alg", signing.Method.Alg(), "kid", signing.ID, "verification_keys", len(ring.keys))
+	return ring, nil
+}
+
+func pemFromEnvOrFile(value, path string) ([]byte, error) {
+	if value != "" {
+		return []byte(unescapePEM(value)), nil
+	}
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWT signing key: %w", err)
+	}
+	return data, nil
+}
+
+// unescapePEM allows PEM values in .env files written on one line with \n
+func unescapePEM(value string) string {
+	return strings.ReplaceAll(value, `\n`, "\n")
+}
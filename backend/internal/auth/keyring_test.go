@@ -0,0 +1,204 @@
+package auth_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/auth"
+)
+
+func rsaKeyPEM(t *testing.T) []byte {
+	t.Helper()
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate RSA key: %v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatalf("Failed to marshal RSA key: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
+
+func testClaims() jwt.RegisteredClaims {
+	return jwt.RegisteredClaims{
+		Subject:   "1",
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+	}
+}
+
+func parse(ring *auth.KeyRing, token string) error {
+	_, err := jwt.ParseWithClaims(token, &jwt.RegisteredClaims{}, ring.Keyfunc)
+	return err
+}
+
+func TestKeyRingSignAndVerify(t *testing.T) {
+	rsaKeys, err := auth.ParseKeys(rsaKeyPEM(t))
+	if err != nil {
+		t.Fatalf("Failed to parse RSA key: %v", err)
+	}
+	edKey, err := auth.GenerateKey()
+	if err != nil {
+		t.Fatalf("Failed to generate Ed25519 key: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		key  *auth.Key
+		alg  string
+	}{
+		{name: "RSA", key: rsaKeys[0], alg: "RS256"},
+		{name: "Ed25519", key: edKey, alg: "EdDSA"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ring, err := auth.NewKeyRing(tc.key)
+			if err != nil {
+				t.Fatalf("Failed to create key ring: %v", err)
+			}
+
+			signed, err := ring.Sign(testClaims())
+			if err != nil {
+				t.Fatalf("Failed to sign: %v", err)
+			}
+
+			token, _, err := jwt.NewParser().ParseUnverified(signed, &jwt.RegisteredClaims{})
+			if err != nil {
+				t.Fatalf("Failed to decode token: %v", err)
+			}
+			if token.Header["alg"] != tc.alg || token.Header["kid"] != tc.key.ID {
+				t.Errorf("Expected alg %s and kid %s, got %v", tc.alg, tc.key.ID, token.Header)
+			}
+
+			if err := parse(ring, signed); err != nil {
+				t.Errorf("Expected token to verify, got %v", err)
+			}
+
+			jwks := ring.JWKS()
+			if len(jwks.Keys) != 1 || jwks.Keys[0].Kid != tc.key.ID || jwks.Keys[0].Alg != tc.alg {
+				t.Errorf("Unexpected JWKS: %+v", jwks)
+			}
+		})
+	}
+}
+
+func TestKeyRingRotation(t *testing.T) {
+	oldKey, _ := auth.GenerateKey()
+	newKey, _ := auth.GenerateKey()
+
+	ring, err := auth.NewKeyRing(oldKey)
+	if err != nil {
+		t.Fatalf("Failed to create key ring: %v", err)
+	}
+	oldToken, _ := ring.Sign(testClaims())
+
+	if err := ring.Rotate(newKey); err != nil {
+		t.Fatalf("Failed to rotate: %v", err)
+	}
+	if ring.SigningKeyID() != newKey.ID {
+		t.Error("Expected new tokens to be signed with the new key")
+	}
+
+	// Tokens issued before the rotation keep working
+	if err := parse(ring, oldToken); err != nil {
+		t.Errorf("Expected pre-rotation token to verify, got %v", err)
+	}
+	if got := len(ring.JWKS().Keys); got != 2 {
+		t.Errorf("Expected both keys in JWKS, got %d", got)
+	}
+
+	// Once the old key is retired its tokens are rejected
+	if err := ring.Retire(oldKey.ID); err != nil {
+		t.Fatalf("Failed to retire key: %v", err)
+	}
+	if err := parse(ring, oldToken); !errors.Is(err, auth.ErrUnknownKeyID) {
+		t.Errorf("Expected retired key to be rejected, got %v", err)
+	}
+	if err := ring.Retire(newKey.ID); err == nil {
+		t.Error("Expected retiring the signing key to fail")
+	}
+}
+
+func TestKeyRingRejectsForeignTokens(t *testing.T) {
+	key, _ := auth.GenerateKey()
+	ring, _ := auth.NewKeyRing(key)
+
+	// HMAC token claiming our kid, e.g. signed with the public key as secret
+	hmacToken := jwt.NewWithClaims(jwt.SigningMethodHS256, testClaims())
+	hmacToken.Header["kid"] = key.ID
+	signedHMAC, _ := hmacToken.SignedString([]byte("secret"))
+
+	// Token from a key that isn't in the ring
+	otherKey, _ := auth.GenerateKey()
+	otherRing, _ := auth.NewKeyRing(otherKey)
+	signedOther, _ := otherRing.Sign(testClaims())
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "HMAC algorithm", token: signedHMAC},
+		{name: "Unknown kid", token: signedOther},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := parse(ring, tc.token); err == nil {
+				t.Error("Expected token to be rejected")
+			}
+		})
+	}
+}
+
+func TestLoadKeyRing(t *testing.T) {
+	signingPEM := string(rsaKeyPEM(t))
+
+	tests := []struct {
+		name        string
+		cfg         auth.KeyRingConfig
+		expectError bool
+	}{
+		{
+			name:        "Production without key",
+			cfg:         auth.KeyRingConfig{},
+			expectError: true,
+		},
+		{
+			name: "Development without key",
+			cfg:  auth.KeyRingConfig{AllowEphemeral: true},
+		},
+		{
+			name: "Inline PEM key",
+			cfg:  auth.KeyRingConfig{PrivateKey: signingPEM},
+		},
+		{
+			name:        "Missing key file",
+			cfg:         auth.KeyRingConfig{PrivateKeyFile: "/nonexistent/jwt.pem"},
+			expectError: true,
+		},
+		{
+			name:        "Invalid PEM",
+			cfg:         auth.KeyRingConfig{PrivateKey: "not a key", AllowEphemeral: true},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := auth.LoadKeyRing(tc.cfg)
+			if tc.expectError && err == nil {
+				t.Error("Expected an error")
+			}
+			if !tc.expectError && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
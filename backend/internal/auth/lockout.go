@@ -0,0 +1,59 @@
+package auth
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// LockoutPolicy controls per-account login throttling. After DelayAfter
+// consecutive failures each further attempt must wait an exponentially
+// growing delay; after LockAfter failures the account is locked outright.
+type LockoutPolicy struct {
+	DelayAfter   int
+	BaseDelay    time.Duration
+	MaxDelay     time.Duration
+	LockAfter    int
+	LockDuration time.Duration
+}
+
+// DefaultLockoutPolicy is applied to admin logins
+var DefaultLockoutPolicy = LockoutPolicy{
+	DelayAfter:   3,
+	BaseDelay:    time.Second,
+	MaxDelay:     30 * time.Second,
+	LockAfter:    10,
+	LockDuration: 15 * time.Minute,
+}
+
+// Delay returns how long to wait after the last failure before another
+// attempt is accepted, given the current number of consecutive failures
+func (p LockoutPolicy) Delay(failedAttempts int) time.Duration {
+	if failedAttempts < p.DelayAfter {
+		return 0
+	}
+
+	delay := p.BaseDelay
+	for i := p.DelayAfter; i < failedAttempts; i++ {
+		delay *= 2
+		if delay >= p.MaxDelay {
+			return p.MaxDelay
+		}
+	}
+	return delay
+}
+
+var (
+	dummyHashOnce sync.Once
+	dummyHash     []byte
+)
+
+// EqualizeLoginTiming performs a bcrypt comparison against a throwaway hash so
+// rejecting an unknown or locked account takes as long as checking a real password
+func EqualizeLoginTiming(password string) {
+	dummyHashOnce.Do(func() {
+		dummyHash, _ = bcrypt.GenerateFromPassword([]byte("toasted-coffee-timing-equalizer"), bcrypt.DefaultCost)
+	})
+	bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+}
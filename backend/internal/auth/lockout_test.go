@@ -0,0 +1,30 @@
+package auth_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/auth"
+)
+
+func TestLockoutPolicyDelay(t *testing.T) {
+	policy := auth.DefaultLockoutPolicy
+
+	tests := []struct {
+		attempts int
+		expected time.Duration
+	}{
+		{attempts: 0, expected: 0},
+		{attempts: 2, expected: 0},
+		{attempts: 3, expected: time.Second},
+		{attempts: 4, expected: 2 * time.Second},
+		{attempts: 6, expected: 8 * time.Second},
+		{attempts: 9, expected: 30 * time.Second},
+	}
+
+	for _, tc := range tests {
+		if got := policy.Delay(tc.attempts); got != tc.expected {
+			t.Errorf("Delay(%d) = %v, expected %v", tc.attempts, got, tc.expected)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+// Password policy settings
+const (
+	MinPasswordLength = 12
+	MaxPasswordLength = 72 // bcrypt ignores anything past 72 bytes
+)
+
+// Predefined errors for password policy violations
+var (
+	ErrPasswordTooShort     = errors.New("password must be at least 12 characters")
+	ErrPasswordTooLong      = errors.New("password must be at most 72 bytes")
+	ErrPasswordTooSimple    = errors.New("password must contain at least three of: lowercase letters, uppercase letters, digits, symbols")
+	ErrPasswordHasUsername  = errors.New("password must not contain the username")
+	ErrPasswordIsCommonWord = errors.New("password is too common")
+)
+
+// commonPasswords holds frequently breached passwords that are long and
+// mixed enough to pass the other rules. They are compared case-insensitively.
+var commonPasswords = map[string]bool{
+	"password1234": true,
+	"password123!": true,
+	"password@123": true,
+	"p@ssw0rd1234": true,
+	"p@ssword1234": true,
+	"qwerty123456": true,
+	"qwerty@12345": true,
+	"welcome12345": true,
+	"welcome@1234": true,
+	"iloveyou1234": true,
+	"changeme123!": true,
+	"admin@123456": true,
+}
+
+// ValidatePasswordStrength checks a candidate password against the password policy
+func ValidatePasswordStrength(username, password string) error {
+	if len(password) < MinPasswordLength {
+		return ErrPasswordTooShort
+	}
+	if len(password) > MaxPasswordLength {
+		return ErrPasswordTooLong
+	}
+
+	lower := strings.ToLower(password)
+	if commonPasswords[lower] {
+		return ErrPasswordIsCommonWord
+	}
+	if username != "" && strings.Contains(lower, strings.ToLower(username)) {
+		return ErrPasswordHasUsername
+	}
+
+	var hasLower, hasUpper, hasDigit, hasSymbol bool
+	for _, c := range password {
+		switch {
+		case unicode.IsLower(c):
+			hasLower = true
+		case unicode.IsUpper(c):
+			hasUpper = true
+		case unicode.IsDigit(c):
+			hasDigit = true
+		default:
+			hasSymbol = true
+		}
+	}
+
+	classes := 0
+	for _, present := range []bool{hasLower, hasUpper, hasDigit, hasSymbol} {
+		if present {
+			classes++
+		}
+	}
+	if classes < 3 {
+		return ErrPasswordTooSimple
+	}
+
+	return nil
+}
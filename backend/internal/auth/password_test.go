@@ -0,0 +1,69 @@
+package auth_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/auth"
+)
+
+func TestValidatePasswordStrength(t *testing.T) {
+	tests := []struct {
+		name     string
+		username string
+		password string
+		expected error
+	}{
+		{
+			name:     "Strong password",
+			username: "owner",
+			password: "Cold-Brew-2025!",
+			expected: nil,
+		},
+		{
+			name:     "Breached password",
+			username: "owner",
+			password: "Password1234",
+			expected: auth.ErrPasswordIsCommonWord,
+		},
+		{
+			name:     "Default admin password",
+			username: "admin",
+			password: "admin",
+			expected: auth.ErrPasswordTooShort,
+		},
+		{
+			name:     "Too short",
+			username: "owner",
+			password: "Ab1!",
+			expected: auth.ErrPasswordTooShort,
+		},
+		{
+			name:     "Too long",
+			username: "owner",
+			password: strings.Repeat("Ab1!", 20),
+			expected: auth.ErrPasswordTooLong,
+		},
+		{
+			name:     "Only lowercase letters",
+			username: "owner",
+			password: "coldbrewcoffee",
+			expected: auth.ErrPasswordTooSimple,
+		},
+		{
+			name:     "Contains username",
+			username: "barista",
+			password: "Barista-2025-ok",
+			expected: auth.ErrPasswordHasUsername,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := auth.ValidatePasswordStrength(tc.username, tc.password)
+			if err != tc.expected {
+				t.Errorf("Expected %v, got %v", tc.expected, err)
+			}
+		})
+	}
+}
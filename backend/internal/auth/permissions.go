@@ -0,0 +1,87 @@
+package auth
+
+// Role identifies what a user is allowed to do in the admin API
+type Role string
+
+const (
+	RoleOwner      Role = "owner"      // full access, including user management
+	RoleManager    Role = "manager"    // runs day-to-day operations
+	RoleBarista    Role = "barista"    // read-only access to the booking schedule
+	RoleBookkeeper Role = "bookkeeper" // read-only access to bookings and packages
+)
+
+// Permission is a single capability checked by the RequirePermission middleware
+type Permission string
+
+const (
+	PermBookingsRead   Permission = "bookings:read"
+	PermBookingsWrite  Permission = "bookings:write"
+	PermBookingsDelete Permission = "bookings:delete"
+	PermMenuWrite      Permission = "menu:write"
+	PermPackagesRead   Permission = "packages:read"
+	PermPackagesWrite  Permission = "packages:write"
+	PermUsersManage    Permission = "users:manage"
+	PermConfigRead     Permission = "config:read"
+	PermWebhooksManage Permission = "webhooks:manage"
+	PermReportsRead    Permission = "reports:read"
+)
+
+// rolePermissions is the permission matrix. A role not listed here has no access.
+var rolePermissions = map[Role][]Permission{
+	RoleOwner: {
+		PermBookingsRead, PermBookingsWrite, PermBookingsDelete,
+		PermMenuWrite,
+		PermPackagesRead, PermPackagesWrite,
+		PermUsersManage,
+		PermConfigRead,
+		PermWebhooksManage,
+		PermReportsRead,
+	},
+	RoleManager: {
+		PermBookingsRead, PermBookingsWrite, PermBookingsDelete,
+		PermMenuWrite,
+		PermPackagesRead, PermPackagesWrite,
+		PermReportsRead,
+	},
+	RoleBarista: {
+		PermBookingsRead,
+	},
+	RoleBookkeeper: {
+		PermBookingsRead,
+		PermPackagesRead,
+		PermReportsRead,
+	},
+}
+
+// Roles returns every known role
+func Roles() []Role {
+	return []Role{RoleOwner, RoleManager, RoleBarista, RoleBookkeeper}
+}
+
+// IsValidRole reports whether role is part of the permission matrix
+func IsValidRole(role string) bool {
+	_, ok := rolePermissions[Role(role)]
+	return ok
+}
+
+// PermissionsForRole returns the permissions granted to a role
+func PermissionsForRole(role string) []Permission {
+	return rolePermissions[Role(role)]
+}
+
+// RoleHasPermission reports whether the matrix grants perm to role
+func RoleHasPermission(role string, perm Permission) bool {
+	for _, p := range rolePermissions[Role(role)] {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// HasPermission checks the caller's role against the permission matrix.
+// The matrix is consulted rather than the token's permission list so that
+// changes to the matrix take effect without reissuing tokens.
+func HasPermission(claims *Claims, perm Permission) bool {
+	return claims != nil && RoleHasPermission(claims.Role, perm)
+}
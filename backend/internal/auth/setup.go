@@ -0,0 +1,48 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+)
+
+// GenerateSetupToken creates a random one-time token used to bootstrap the first admin account
+func GenerateSetupToken() (string, error) {
+	return randomToken("setup")
+}
+
+// GeneratePasswordResetToken creates a random single-use token for a password reset link
+func GeneratePasswordResetToken() (string, error) {
+	return randomToken("password reset")
+}
+
+// randomToken returns 32 random bytes encoded for use in URLs
+func randomToken(purpose string) (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate %s token: %w", purpose, err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// HashToken returns the SHA-256 digest of a token so it never has to be kept in plain text
+func HashToken(token string) []byte {
+	sum := sha256.Sum256([]byte(token))
+	return sum[:]
+}
+
+// HashTokenHex returns the hex-encoded SHA-256 digest of a token for storage in the database
+func HashTokenHex(token string) string {
+	return hex.EncodeToString(HashToken(token))
+}
+
+// TokenMatchesHash compares a presented token against a stored digest in constant time
+func TokenMatchesHash(token string, hash []byte) bool {
+	if len(hash) == 0 {
+		return false
+	}
+	return subtle.ConstantTimeCompare(HashToken(token), hash) == 1
+}
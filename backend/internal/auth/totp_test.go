@@ -0,0 +1,109 @@
+package auth_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/auth"
+)
+
+// RFC 6238 appendix B secret ("12345678901234567890") in base32
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCodeRFCVectors(t *testing.T) {
+	tests := []struct {
+		unix     int64
+		expected string
+	}{
+		{unix: 59, expected: "287082"},
+		{unix: 1111111109, expected: "081804"},
+		{unix: 1234567890, expected: "005924"},
+		{unix: 2000000000, expected: "279037"},
+	}
+
+	for _, tc := range tests {
+		code, err := auth.TOTPCode(rfcSecret, auth.TOTPStep(time.Unix(tc.unix, 0)))
+		if err != nil {
+			t.Fatalf("TOTPCode returned error: %v", err)
+		}
+		if code != tc.expected {
+			t.Errorf("At %d expected %s, got %s", tc.unix, tc.expected, code)
+		}
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+	current, _ := auth.TOTPCode(rfcSecret, auth.TOTPStep(now))
+	previous, _ := auth.TOTPCode(rfcSecret, auth.TOTPStep(now)-1)
+	stale, _ := auth.TOTPCode(rfcSecret, auth.TOTPStep(now)-3)
+
+	if step, ok := auth.ValidateTOTP(rfcSecret, current, now); !ok || step != auth.TOTPStep(now) {
+		t.Error("Expected current code to be accepted")
+	}
+	if _, ok := auth.ValidateTOTP(rfcSecret, previous, now); !ok {
+		t.Error("Expected previous step to be accepted for clock drift")
+	}
+	if _, ok := auth.ValidateTOTP(rfcSecret, stale, now); ok {
+		t.Error("Expected stale code to be rejected")
+	}
+	if _, ok := auth.ValidateTOTP(rfcSecret, "12345", now); ok {
+		t.Error("Expected short code to be rejected")
+	}
+}
+
+func TestTOTPURI(t *testing.T) {
+	uri := auth.TOTPURI(rfcSecret, "owner")
+	if !strings.HasPrefix(uri, "otpauth://totp/Toasted%20Coffee%20Co:owner?") {
+		t.Errorf("Unexpected URI label: %s", uri)
+	}
+	if !strings.Contains(uri, "secret="+rfcSecret) {
+		t.Errorf("URI missing secret: %s", uri)
+	}
+}
+
+func TestGenerateRecoveryCodes(t *testing.T) {
+	codes, err := auth.GenerateRecoveryCodes()
+	if err != nil {
+		t.Fatalf("GenerateRecoveryCodes returned error: %v", err)
+	}
+	if len(codes) != auth.RecoveryCodeCount {
+		t.Fatalf("Expected %d codes, got %d", auth.RecoveryCodeCount, len(codes))
+	}
+
+	seen := map[string]bool{}
+	for _, code := range codes {
+		if len(code) != 11 || code[5] != '-' {
+			t.Errorf("Unexpected code format: %s", code)
+		}
+		if seen[code] {
+			t.Errorf("Duplicate recovery code: %s", code)
+		}
+		seen[code] = true
+	}
+
+	if auth.NormalizeRecoveryCode(" ABCDE-fghij ") != "abcdefghij" {
+		t.Error("Expected recovery codes to normalize case and dashes")
+	}
+}
+
+func TestChallengeTokenIsNotAnAccessToken(t *testing.T) {
+	tokens := newTestTokenService(t)
+	challenge, err := tokens.GenerateChallengeToken(7, auth.ChallengeVerify)
+	if err != nil {
+		t.Fatalf("GenerateChallengeToken returned error: %v", err)
+	}
+
+	if _, err := tokens.ValidateToken(challenge); err == nil {
+		t.Error("Challenge token must not be accepted as an access token")
+	}
+	if _, err := tokens.ValidateChallengeToken(challenge, auth.ChallengeEnroll); err == nil {
+		t.Error("Challenge token must not be accepted for another purpose")
+	}
+
+	userID, err := tokens.ValidateChallengeToken(challenge, auth.ChallengeVerify)
+	if err != nil || userID != 7 {
+		t.Errorf("Expected user 7, got %d (%v)", userID, err)
+	}
+}
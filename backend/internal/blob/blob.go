@@ -0,0 +1,27 @@
+// Package blob stores uploaded files, such as menu item images, and works
+// out the URLs they are served from
+package blob
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/config"
+)
+
+// ErrInvalidKey is returned for keys that aren't a plain file name
+var ErrInvalidKey = errors.New("invalid blob key")
+
+// Store keeps files by key. Keys are file names such as "menu-3-1a2b3c.jpg";
+// a file is replaced when its key is reused.
+type Store interface {
+	Put(ctx context.Context, key string, r io.Reader, contentType string) error
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+}
+
+// New returns the store configured by cfg.Backend
+func New(cfg config.MediaConfig) Store {
+	return NewLocalStore(cfg.Dir, cfg.BaseURL)
+}
@@ -0,0 +1,79 @@
+package blob_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/blob"
+)
+
+func TestLocalStoreKeys(t *testing.T) {
+	store := blob.NewLocalStore(t.TempDir(), "http://localhost:8080/media/")
+
+	for _, key := range []string{"", ".upload-123", "../secret.jpg", "menu/3.jpg", `menu\3.jpg`} {
+		if err := store.Put(context.Background(), key, strings.NewReader("x"), "image/jpeg"); !errors.Is(err, blob.ErrInvalidKey) {
+			t.Errorf("Put(%q): expected ErrInvalidKey, got %v", key, err)
+		}
+		if err := store.Delete(context.Background(), key); !errors.Is(err, blob.ErrInvalidKey) {
+			t.Errorf("Delete(%q): expected ErrInvalidKey, got %v", key, err)
+		}
+	}
+
+	if url := store.URL("menu-3-ab.jpg"); url != "http://localhost:8080/media/menu-3-ab.jpg" {
+		t.Errorf("Unexpected URL %q", url)
+	}
+}
+
+func TestLocalStorePutServeDelete(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "uploads")
+	store := blob.NewLocalStore(dir, "http://localhost:8080/media")
+	ctx := context.Background()
+
+	if err := store.Put(ctx, "menu-3-ab.jpg", strings.NewReader("jpeg data"), "image/jpeg"); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("Expected only the stored file, got %d entries", len(entries))
+	}
+
+	tests := []struct {
+		path           string
+		expectedStatus int
+	}{
+		{path: "/menu-3-ab.jpg", expectedStatus: http.StatusOK},
+		{path: "/menu-4-cd.jpg", expectedStatus: http.StatusNotFound},
+		{path: "/.upload-123", expectedStatus: http.StatusNotFound},
+		{path: "/", expectedStatus: http.StatusNotFound},
+	}
+	for _, tc := range tests {
+		w := httptest.NewRecorder()
+		store.ServeHTTP(w, httptest.NewRequest("GET", tc.path, nil))
+		if w.Code != tc.expectedStatus {
+			t.Errorf("GET %s: expected status %d, got %d", tc.path, tc.expectedStatus, w.Code)
+		}
+		if tc.expectedStatus == http.StatusOK {
+			if w.Body.String() != "jpeg data" {
+				t.Errorf("Unexpected body %q", w.Body.String())
+			}
+			if !strings.Contains(w.Header().Get("Cache-Control"), "immutable") {
+				t.Errorf("Expected an immutable Cache-Control, got %q", w.Header().Get("Cache-Control"))
+			}
+		}
+	}
+
+	if err := store.Delete(ctx, "menu-3-ab.jpg"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if err := store.Delete(ctx, "menu-3-ab.jpg"); err != nil {
+		t.Errorf("Deleting a missing file should succeed, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "menu-3-ab.jpg")); !os.IsNotExist(err) {
+		t.Error("Expected the file to be deleted")
+	}
+}
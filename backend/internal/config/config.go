@@ -1,42 +1,439 @@
 package config
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
+	"github.com/BurntSushi/toml"
 	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
 )
 
-// Config holds all configuration for our application
+// Config holds all configuration for our application. Values come from the
+// defaults below, then the optional YAML or TOML file named by CONFIG_FILE,
+// then environment variables, which always win.
+//
+// Fields are mapped with struct tags: `yaml` names the key in the config file,
+// which TOML files share by matching field names case-insensitively,
+// `env` the environment variable, and `secret` marks values that are masked
+// in Redacted ("url" masks only the password of a connection string).
 type Config struct {
-	Port         string
-	DatabaseURL  string
-	AllowOrigins string
+	Environment string `yaml:"environment" env:"ENVIRONMENT"`
+
+	Server     ServerConfig    `yaml:"server"`
+	Database   DatabaseConfig  `yaml:"database"`
+	Auth       AuthConfig      `yaml:"auth"`
+	Mail       MailConfig      `yaml:"mail"`
+	RateLimits RateLimitConfig `yaml:"rateLimits"`
+	CORS       CORSConfig      `yaml:"cors"`
+	Health     HealthConfig    `yaml:"health"`
+	Metrics    MetricsConfig   `yaml:"metrics"`
+	Logging    LogConfig       `yaml:"logging"`
+	Tracing    TracingConfig   `yaml:"tracing"`
+	Webhooks   WebhookConfig   `yaml:"webhooks"`
+	Events     EventsConfig    `yaml:"events"`
+	Jobs       JobsConfig      `yaml:"jobs"`
+	Reports    ReportsConfig   `yaml:"reports"`
+	Inventory  InventoryConfig `yaml:"inventory"`
+	Media      MediaConfig     `yaml:"media"`
+	Features   FeatureConfig   `yaml:"features"`
+}
+
+// ServerConfig configures the HTTP server
+type ServerConfig struct {
+	Port         string        `yaml:"port" env:"PORT"`
+	ReadTimeout  time.Duration `yaml:"readTimeout" env:"SERVER_READ_TIMEOUT"`
+	WriteTimeout time.Duration `yaml:"writeTimeout" env:"SERVER_WRITE_TIMEOUT"`
+	IdleTimeout  time.Duration `yaml:"idleTimeout" env:"SERVER_IDLE_TIMEOUT"`
+
+	// On SIGTERM/SIGINT the server reports not-ready, waits ShutdownDelay so
+	// load balancers stop routing to it, then gives in-flight requests and
+	// background work up to ShutdownTimeout to finish
+	ShutdownDelay   time.Duration `yaml:"shutdownDelay" env:"SERVER_SHUTDOWN_DELAY"`
+	ShutdownTimeout time.Duration `yaml:"shutdownTimeout" env:"SERVER_SHUTDOWN_TIMEOUT"`
+
+	// AdminURL is the admin dashboard's base URL, used for links in emails
+	AdminURL string `yaml:"adminUrl" env:"ADMIN_URL"`
+}
+
+// DatabaseConfig configures the PostgreSQL connection pool
+type DatabaseConfig struct {
+	URL             string        `yaml:"url" env:"DATABASE_URL" secret:"url"`
+	MaxConns        int32         `yaml:"maxConns" env:"DB_MAX_CONNS"`
+	MinConns        int32         `yaml:"minConns" env:"DB_MIN_CONNS"`
+	MaxConnLifetime time.Duration `yaml:"maxConnLifetime" env:"DB_MAX_CONN_LIFETIME"`
+	MaxConnIdleTime time.Duration `yaml:"maxConnIdleTime" env:"DB_MAX_CONN_IDLE_TIME"`
+}
+
+// AuthConfig configures tokens, session cookies and the first admin account
+type AuthConfig struct {
+	// JWT keys (PEM, RSA or Ed25519). The private key signs new tokens; the
+	// verification keys are retired keys kept around during a rotation.
+	JWTPrivateKey     string   `yaml:"jwtPrivateKey" env:"JWT_PRIVATE_KEY" secret:"true"`
+	JWTPrivateKeyFile string   `yaml:"jwtPrivateKeyFile" env:"JWT_PRIVATE_KEY_FILE"`
+	JWTVerifyKeys     string   `yaml:"jwtVerifyKeys" env:"JWT_VERIFY_KEYS" secret:"true"`
+	JWTVerifyKeyFiles []string `yaml:"jwtVerifyKeyFiles" env:"JWT_VERIFY_KEY_FILES"`
+
+	// Token lifetimes
+	TokenExpiry        time.Duration `yaml:"tokenExpiry" env:"TOKEN_EXPIRY"`
+	RefreshTokenExpiry time.Duration `yaml:"refreshTokenExpiry" env:"REFRESH_TOKEN_EXPIRY"`
+
+	// Session cookie attributes, used when features.cookieSessions is on
+	CookieDomain   string `yaml:"cookieDomain" env:"AUTH_COOKIE_DOMAIN"`
+	CookieSecure   bool   `yaml:"cookieSecure" env:"AUTH_COOKIE_SECURE"`
+	CookieSameSite string `yaml:"cookieSameSite" env:"AUTH_COOKIE_SAMESITE"`
+
+	// First-run bootstrap: when the users table is empty the admin is created
+	// from this bcrypt hash, otherwise a one-time setup token is logged
+	InitialAdminUsername     string `yaml:"initialAdminUsername" env:"INITIAL_ADMIN_USERNAME"`
+	InitialAdminPasswordHash string `yaml:"initialAdminPasswordHash" env:"INITIAL_ADMIN_PASSWORD_HASH" secret:"true"`
+}
+
+// MailConfig configures outgoing email
+type MailConfig struct {
+	SMTPHost          string `yaml:"smtpHost" env:"SMTP_HOST"`
+	SMTPPort          int    `yaml:"smtpPort" env:"SMTP_PORT"`
+	SMTPUser          string `yaml:"smtpUser" env:"SMTP_USER"`
+	SMTPPassword      string `yaml:"smtpPassword" env:"SMTP_PASSWORD" secret:"true"`
+	NotificationEmail string `yaml:"notificationEmail" env:"NOTIFICATION_EMAIL"`
+}
+
+// RateLimitConfig holds per-IP request limits per minute for each route group
+type RateLimitConfig struct {
+	PublicRead  int `yaml:"publicRead" env:"RATE_LIMIT_PUBLIC_READ"`
+	PublicWrite int `yaml:"publicWrite" env:"RATE_LIMIT_PUBLIC_WRITE"`
+	Contact     int `yaml:"contact" env:"RATE_LIMIT_CONTACT"`
+	Auth        int `yaml:"auth" env:"RATE_LIMIT_AUTH"`
+	Admin       int `yaml:"admin" env:"RATE_LIMIT_ADMIN"`
+	HealthCheck int `yaml:"healthCheck" env:"RATE_LIMIT_HEALTH_CHECK"`
+}
+
+// CORSConfig lists the browser origins allowed to call the API
+type CORSConfig struct {
+	AllowOrigins []string `yaml:"allowOrigins" env:"ALLOWED_ORIGINS"`
+}
+
+// HealthConfig configures the /readyz dependency checks
+type HealthConfig struct {
+	// CheckTimeout bounds each dependency check
+	CheckTimeout time.Duration `yaml:"checkTimeout" env:"HEALTH_CHECK_TIMEOUT"`
+
+	// CheckSMTP adds SMTP reachability to readiness. Off by default so a mail
+	// outage doesn't take the API out of rotation.
+	CheckSMTP bool `yaml:"checkSmtp" env:"HEALTH_CHECK_SMTP"`
+}
+
+// MetricsConfig configures the Prometheus endpoint. It is only exposed when
+// protected: either served on a separate (internal) address or behind a token.
+type MetricsConfig struct {
+	Enabled bool `yaml:"enabled" env:"METRICS_ENABLED"`
+
+	// Token is required as a bearer token by scrapers when set
+	Token string `yaml:"token" env:"METRICS_TOKEN" secret:"true"`
+
+	// ListenAddr serves /metrics on its own listener, e.g. "127.0.0.1:9090",
+	// instead of the public port
+	ListenAddr string `yaml:"listenAddr" env:"METRICS_ADDR"`
+}
+
+// LogConfig configures structured logging
+type LogConfig struct {
+	// Level is debug, info, warn or error
+	Level string `yaml:"level" env:"LOG_LEVEL"`
+
+	// Format is json, or text for easier reading in development
+	Format string `yaml:"format" env:"LOG_FORMAT"`
+}
+
+// TracingConfig configures OpenTelemetry tracing
+type TracingConfig struct {
+	// Exporter is none, stdout (pretty-printed spans for local debugging) or
+	// otlp (OTLP over HTTP to a collector)
+	Exporter string `yaml:"exporter" env:"TRACING_EXPORTER"`
+
+	// OTLP collector host:port, e.g. "otel-collector:4318"
+	Endpoint string `yaml:"endpoint" env:"OTEL_EXPORTER_OTLP_ENDPOINT"`
+	Insecure bool   `yaml:"insecure" env:"OTEL_EXPORTER_OTLP_INSECURE"`
+
+	ServiceName string `yaml:"serviceName" env:"OTEL_SERVICE_NAME"`
+
+	// SampleRatio is the share of new traces recorded, from 0 to 1. Requests
+	// that arrive with a sampled trace parent are always recorded.
+	SampleRatio float64 `yaml:"sampleRatio" env:"TRACING_SAMPLE_RATIO"`
+}
+
+// WebhookConfig configures outbound webhook deliveries
+type WebhookConfig struct {
+	// Timeout bounds each delivery attempt
+	Timeout time.Duration `yaml:"timeout" env:"WEBHOOK_TIMEOUT"`
+
+	// A failed delivery is retried until MaxAttempts have been made, waiting
+	// RetryBackoff before the first retry and doubling the wait each time
+	MaxAttempts  int           `yaml:"maxAttempts" env:"WEBHOOK_MAX_ATTEMPTS"`
+	RetryBackoff time.Duration `yaml:"retryBackoff" env:"WEBHOOK_RETRY_BACKOFF"`
+
+	// AllowPrivateNetworks lets webhooks reach loopback, private and
+	// link-local addresses, e.g. a receiver on localhost in development
+	AllowPrivateNetworks bool `yaml:"allowPrivateNetworks" env:"WEBHOOK_ALLOW_PRIVATE_NETWORKS"`
+}
+
+// EventsConfig configures the admin event stream
+type EventsConfig struct {
+	// Backend is memory, which only reaches clients connected to the same
+	// instance, or postgres, which fans events out to every instance with
+	// LISTEN/NOTIFY
+	Backend string `yaml:"backend" env:"EVENTS_BACKEND"`
+
+	// History is how many recent events are kept for clients resuming with
+	// Last-Event-ID
+	History int `yaml:"history" env:"EVENTS_HISTORY"`
+
+	// Heartbeat is how often an idle stream sends a comment so proxies don't
+	// close it
+	Heartbeat time.Duration `yaml:"heartbeat" env:"EVENTS_HEARTBEAT"`
 }
 
-// Load returns configuration from environment variables
+// JobsConfig configures the scheduled maintenance jobs. Each job runs on one
+// instance at a time, however many are deployed.
+type JobsConfig struct {
+	Enabled bool `yaml:"enabled" env:"JOBS_ENABLED"`
+
+	// Schedule is a five-field cron expression evaluated in Timezone
+	Schedule string `yaml:"schedule" env:"JOBS_SCHEDULE"`
+	Timezone string `yaml:"timezone" env:"JOBS_TIMEZONE"`
+
+	// ArchiveAfterDays archives bookings this many days after their date;
+	// 0 leaves them for staff to archive
+	ArchiveAfterDays int `yaml:"archiveAfterDays" env:"JOBS_ARCHIVE_AFTER_DAYS"`
+
+	// PurgeDeletedAfterDays permanently removes deleted menu items and
+	// packages this many days after they were deleted; 0 keeps them
+	PurgeDeletedAfterDays int `yaml:"purgeDeletedAfterDays" env:"JOBS_PURGE_DELETED_AFTER_DAYS"`
+
+	// BookingRetentionDays removes the customer's details from bookings this
+	// many days after their date; 0 keeps them forever
+	BookingRetentionDays int `yaml:"bookingRetentionDays" env:"JOBS_BOOKING_RETENTION_DAYS"`
+
+	// LockTimeout is how long a run may hold its lock before another
+	// instance may take the job over
+	LockTimeout time.Duration `yaml:"lockTimeout" env:"JOBS_LOCK_TIMEOUT"`
+
+	// PollInterval is how often each instance checks for due jobs
+	PollInterval time.Duration `yaml:"pollInterval" env:"JOBS_POLL_INTERVAL"`
+}
+
+// ReportsConfig configures the admin reports
+type ReportsConfig struct {
+	// CacheTTL is how long a report is reused for the same range before
+	// it is queried again; 0 disables caching
+	CacheTTL time.Duration `yaml:"cacheTTL" env:"REPORTS_CACHE_TTL"`
+}
+
+// InventoryConfig configures the prep sheet
+type InventoryConfig struct {
+	// ServingsPerGuest is how many drinks each guest of a booking is
+	// expected to have
+	ServingsPerGuest float64 `yaml:"servingsPerGuest" env:"INVENTORY_SERVINGS_PER_GUEST"`
+}
+
+// MediaConfig configures where uploaded images are stored
+type MediaConfig struct {
+	// Backend is local, which keeps files in Dir and serves them under /media
+	Backend string `yaml:"backend" env:"MEDIA_BACKEND"`
+	Dir     string `yaml:"dir" env:"MEDIA_DIR"`
+
+	// BaseURL is where stored files are served from; a file's URL is
+	// BaseURL followed by its key
+	BaseURL string `yaml:"baseURL" env:"MEDIA_BASE_URL"`
+
+	// MaxUploadSize is the largest image accepted, in bytes
+	MaxUploadSize int64 `yaml:"maxUploadSize" env:"MEDIA_MAX_UPLOAD_SIZE"`
+
+	// ImageSize is the largest width or height, in pixels, images are
+	// resized to
+	ImageSize int `yaml:"imageSize" env:"MEDIA_IMAGE_SIZE"`
+}
+
+// FeatureConfig toggles optional behaviour
+type FeatureConfig struct {
+	// CookieSessions sets tokens as HttpOnly cookies instead of returning them
+	// to the SPA; state-changing requests then need a CSRF token
+	CookieSessions bool `yaml:"cookieSessions" env:"AUTH_COOKIE_MODE"`
+
+	// RequireTwoFactor makes TOTP enrollment mandatory for every admin login
+	RequireTwoFactor bool `yaml:"requireTwoFactor" env:"REQUIRE_2FA"`
+
+	// DebugEndpoints serves /ping, /ping-simple and /test-render
+	DebugEndpoints bool `yaml:"debugEndpoints" env:"DEBUG_ENDPOINTS"`
+}
+
+// Default returns the configuration used when nothing overrides it
+func Default() *Config {
+	return &Config{
+		Environment: "development",
+		Server: ServerConfig{
+			Port:         "8080",
+			ReadTimeout:  15 * time.Second,
+			WriteTimeout: 60 * time.Second,
+			IdleTimeout:  120 * time.Second,
+			AdminURL:     "http://localhost:5174",
+
+			ShutdownTimeout: 30 * time.Second,
+		},
+		Database: DatabaseConfig{
+			MaxConns:        10,
+			MaxConnLifetime: time.Hour,
+			MaxConnIdleTime: 30 * time.Minute,
+		},
+		Auth: AuthConfig{
+			TokenExpiry:          15 * time.Minute,
+			RefreshTokenExpiry:   7 * 24 * time.Hour,
+			CookieSecure:         true,
+			CookieSameSite:       "lax",
+			InitialAdminUsername: "admin",
+		},
+		Mail: MailConfig{
+			SMTPHost: "smtp.gmail.com",
+			SMTPPort: 587,
+		},
+		RateLimits: RateLimitConfig{
+			PublicRead:  100,
+			PublicWrite: 10,
+			Contact:     5,
+			Auth:        20,
+			Admin:       200,
+			HealthCheck: 60,
+		},
+		CORS: CORSConfig{
+			AllowOrigins: []string{"http://localhost:5173"},
+		},
+		Health: HealthConfig{
+			CheckTimeout: 2 * time.Second,
+		},
+		Logging: LogConfig{
+			Level:  "info",
+			Format: "json",
+		},
+		Tracing: TracingConfig{
+			Exporter:    "none",
+			ServiceName: "toasted-coffee-api",
+			SampleRatio: 1,
+		},
+		Webhooks: WebhookConfig{
+			Timeout:      10 * time.Second,
+			MaxAttempts:  5,
+			RetryBackoff: 30 * time.Second,
+		},
+		Events: EventsConfig{
+			Backend:   "memory",
+			History:   500,
+			Heartbeat: 25 * time.Second,
+		},
+		Jobs: JobsConfig{
+			Enabled:      true,
+			Schedule:     "0 3 * * *",
+			Timezone:     "UTC",
+			LockTimeout:  30 * time.Minute,
+			PollInterval: time.Minute,
+		},
+		Reports: ReportsConfig{
+			CacheTTL: 5 * time.Minute,
+		},
+		Inventory: InventoryConfig{
+			ServingsPerGuest: 1,
+		},
+		Media: MediaConfig{
+			Backend:       "local",
+			Dir:           "uploads",
+			BaseURL:       "http://localhost:8080/media",
+			MaxUploadSize: 10 << 20,
+			ImageSize:     800,
+		},
+	}
+}
+
+// Load reads .env, the optional CONFIG_FILE and the environment, then
+// validates the result. All problems are reported in a single error.
 func Load() (*Config, error) {
 	// Load .env file if it exists
 	godotenv.Load()
 
-	// Set default values
-	config := &Config{
-		Port:         getEnv("PORT", "8080"),
-		DatabaseURL:  getEnv("DATABASE_URL", ""),
-		AllowOrigins: getEnv("ALLOWED_ORIGINS", "http://localhost:5173"),
+	return load(os.Getenv("CONFIG_FILE"))
+}
+
+// LoadFile is Load with an explicit config file path; an empty path skips the file
+func LoadFile(path string) (*Config, error) {
+	godotenv.Load()
+
+	return load(path)
+}
+
+func load(path string) (*Config, error) {
+	config := Default()
+
+	if path != "" {
+		if err := config.readFile(path); err != nil {
+			return nil, err
+		}
 	}
 
-	// Validate required DATABASE_URL
-	if config.DatabaseURL == "" {
-		panic("DATABASE_URL environment variable is required")
+	var errs []error
+	errs = append(errs, applyEnv(config, os.LookupEnv)...)
+	if err := config.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid configuration:\n%w", errors.Join(errs...))
 	}
 
 	return config, nil
 }
 
-// Helper function to get environment variables with defaults
-func getEnv(key, defaultValue string) string {
-	if value, exists := os.LookupEnv(key); exists {
-		return value
+// readFile decodes a YAML or TOML file, chosen by its extension, over c.
+// Unknown keys are rejected in both formats.
+func (c *Config) readFile(path string) error {
+	ext := strings.ToLower(filepath.Ext(path))
+	switch ext {
+	case ".yaml", ".yml", ".toml":
+	default:
+		return fmt.Errorf("config file %s: only YAML and TOML files are supported", path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	if ext == ".toml" {
+		meta, err := toml.NewDecoder(bytes.NewReader(data)).Decode(c)
+		if err != nil {
+			return fmt.Errorf("config file %s: %w", path, err)
+		}
+		if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+			keys := make([]string, len(undecoded))
+			for i, key := range undecoded {
+				keys[i] = key.String()
+			}
+			return fmt.Errorf("config file %s: unknown keys %s", path, strings.Join(keys, ", "))
+		}
+		return nil
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("config file %s: %w", path, err)
 	}
-	return defaultValue
+	return nil
+}
+
+// IsProduction reports whether the server runs with ENVIRONMENT=production
+func (c *Config) IsProduction() bool {
+	return c.Environment == "production"
 }
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// redactedValue replaces secrets in Redacted output
+const redactedValue = "[REDACTED]"
+
+// Redacted returns the configuration as nested maps keyed by the YAML names,
+// with secrets masked. It is safe to log or serve to administrators.
+func (c *Config) Redacted() map[string]interface{} {
+	return redactStruct(reflect.ValueOf(c).Elem())
+}
+
+func redactStruct(v reflect.Value) map[string]interface{} {
+	out := map[string]interface{}{}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field, value := t.Field(i), v.Field(i)
+		name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+
+		switch {
+		case field.Type.Kind() == reflect.Struct:
+			out[name] = redactStruct(value)
+		case field.Type == durationType:
+			out[name] = time.Duration(value.Int()).String()
+		default:
+			out[name] = redactValue(field.Tag.Get("secret"), value.Interface())
+		}
+	}
+	return out
+}
+
+func redactValue(secret string, value interface{}) interface{} {
+	s, isString := value.(string)
+	if !isString || s == "" {
+		return value
+	}
+
+	switch secret {
+	case "true":
+		return redactedValue
+	case "url":
+		// Keep the host and database name visible, mask only the password
+		u, err := url.Parse(s)
+		if err != nil {
+			return redactedValue
+		}
+		return u.Redacted()
+	}
+	return value
+}
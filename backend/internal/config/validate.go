@@ -0,0 +1,167 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+var validEnvironments = map[string]bool{
+	"development": true,
+	"test":        true,
+	"staging":     true,
+	"production":  true,
+}
+
+// Validate checks the whole configuration and returns every problem found,
+// joined with errors.Join
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(validEnvironments[c.Environment], "ENVIRONMENT must be one of development, test, staging or production, got %q", c.Environment)
+
+	// Server
+	port, err := strconv.Atoi(c.Server.Port)
+	check(err == nil && port > 0 && port < 65536, "PORT must be a TCP port number, got %q", c.Server.Port)
+	check(c.Server.ReadTimeout >= 0, "SERVER_READ_TIMEOUT must not be negative")
+	check(c.Server.WriteTimeout >= 0, "SERVER_WRITE_TIMEOUT must not be negative")
+	check(c.Server.IdleTimeout >= 0, "SERVER_IDLE_TIMEOUT must not be negative")
+	check(c.Server.ShutdownDelay >= 0, "SERVER_SHUTDOWN_DELAY must not be negative")
+	check(c.Server.ShutdownTimeout > 0, "SERVER_SHUTDOWN_TIMEOUT must be positive")
+	check(isHTTPURL(c.Server.AdminURL), "ADMIN_URL must be an http(s) URL, got %q", c.Server.AdminURL)
+
+	// Database
+	check(c.Database.URL != "", "DATABASE_URL is required")
+	check(c.Database.MaxConns > 0, "DB_MAX_CONNS must be positive")
+	check(c.Database.MinConns >= 0 && c.Database.MinConns <= c.Database.MaxConns,
+		"DB_MIN_CONNS must be between 0 and DB_MAX_CONNS")
+	check(c.Database.MaxConnLifetime >= 0, "DB_MAX_CONN_LIFETIME must not be negative")
+	check(c.Database.MaxConnIdleTime >= 0, "DB_MAX_CONN_IDLE_TIME must not be negative")
+
+	// Auth
+	check(c.Auth.TokenExpiry > 0, "TOKEN_EXPIRY must be positive")
+	check(c.Auth.RefreshTokenExpiry > c.Auth.TokenExpiry, "REFRESH_TOKEN_EXPIRY must be longer than TOKEN_EXPIRY")
+	sameSite := strings.ToLower(c.Auth.CookieSameSite)
+	check(sameSite == "strict" || sameSite == "lax" || sameSite == "none",
+		"AUTH_COOKIE_SAMESITE must be strict, lax or none, got %q", c.Auth.CookieSameSite)
+	check(sameSite != "none" || c.Auth.CookieSecure, "AUTH_COOKIE_SAMESITE=none requires AUTH_COOKIE_SECURE=true")
+	check(c.Auth.InitialAdminUsername != "", "INITIAL_ADMIN_USERNAME must not be empty")
+	if c.IsProduction() {
+		check(c.Auth.JWTPrivateKey != "" || c.Auth.JWTPrivateKeyFile != "",
+			"JWT_PRIVATE_KEY or JWT_PRIVATE_KEY_FILE is required in production")
+		check(!c.Features.CookieSessions || c.Auth.CookieSecure,
+			"AUTH_COOKIE_SECURE must be true in production")
+	}
+
+	// Mail
+	check(c.Mail.SMTPPort > 0 && c.Mail.SMTPPort < 65536, "SMTP_PORT must be a TCP port number")
+	if c.Mail.NotificationEmail != "" {
+		_, err := mail.ParseAddress(c.Mail.NotificationEmail)
+		check(err == nil, "NOTIFICATION_EMAIL is not a valid email address")
+	}
+
+	// Rate limits
+	for _, limit := range []struct {
+		name  string
+		value int
+	}{
+		{"RATE_LIMIT_PUBLIC_READ", c.RateLimits.PublicRead},
+		{"RATE_LIMIT_PUBLIC_WRITE", c.RateLimits.PublicWrite},
+		{"RATE_LIMIT_CONTACT", c.RateLimits.Contact},
+		{"RATE_LIMIT_AUTH", c.RateLimits.Auth},
+		{"RATE_LIMIT_ADMIN", c.RateLimits.Admin},
+		{"RATE_LIMIT_HEALTH_CHECK", c.RateLimits.HealthCheck},
+	} {
+		check(limit.value > 0, "%s must be positive", limit.name)
+	}
+
+	// Health
+	check(c.Health.CheckTimeout > 0, "HEALTH_CHECK_TIMEOUT must be positive")
+
+	// Metrics
+	if c.Metrics.Enabled {
+		check(c.Metrics.Token != "" || c.Metrics.ListenAddr != "",
+			"METRICS_TOKEN or METRICS_ADDR is required when METRICS_ENABLED=true")
+	}
+
+	// Logging
+	switch strings.ToLower(c.Logging.Level) {
+	case "debug", "info", "warn", "error":
+	default:
+		check(false, "LOG_LEVEL must be debug, info, warn or error, got %q", c.Logging.Level)
+	}
+	check(c.Logging.Format == "json" || c.Logging.Format == "text",
+		"LOG_FORMAT must be json or text, got %q", c.Logging.Format)
+
+	// Tracing
+	switch c.Tracing.Exporter {
+	case "none", "stdout", "otlp":
+	default:
+		check(false, "TRACING_EXPORTER must be none, stdout or otlp, got %q", c.Tracing.Exporter)
+	}
+	check(c.Tracing.ServiceName != "", "OTEL_SERVICE_NAME must not be empty")
+	check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "TRACING_SAMPLE_RATIO must be between 0 and 1")
+
+	// Webhooks
+	check(c.Webhooks.Timeout > 0, "WEBHOOK_TIMEOUT must be positive")
+	check(c.Webhooks.MaxAttempts > 0, "WEBHOOK_MAX_ATTEMPTS must be positive")
+	check(c.Webhooks.RetryBackoff >= 0, "WEBHOOK_RETRY_BACKOFF must not be negative")
+
+	// Events
+	check(c.Events.Backend == "memory" || c.Events.Backend == "postgres",
+		"EVENTS_BACKEND must be memory or postgres, got %q", c.Events.Backend)
+	check(c.Events.History > 0, "EVENTS_HISTORY must be positive")
+	check(c.Events.Heartbeat > 0, "EVENTS_HEARTBEAT must be positive")
+
+	// Jobs
+	if _, err := cron.ParseStandard(c.Jobs.Schedule); err != nil {
+		check(false, "JOBS_SCHEDULE is not a valid cron expression: %v", err)
+	}
+	if _, err := time.LoadLocation(c.Jobs.Timezone); err != nil {
+		check(false, "JOBS_TIMEZONE %q is not a known time zone", c.Jobs.Timezone)
+	}
+	check(c.Jobs.ArchiveAfterDays >= 0, "JOBS_ARCHIVE_AFTER_DAYS must not be negative")
+	check(c.Jobs.PurgeDeletedAfterDays >= 0, "JOBS_PURGE_DELETED_AFTER_DAYS must not be negative")
+	check(c.Jobs.BookingRetentionDays >= 0, "JOBS_BOOKING_RETENTION_DAYS must not be negative")
+	check(c.Jobs.BookingRetentionDays == 0 || c.Jobs.BookingRetentionDays >= c.Jobs.ArchiveAfterDays,
+		"JOBS_BOOKING_RETENTION_DAYS must be 0 or at least JOBS_ARCHIVE_AFTER_DAYS")
+	check(c.Jobs.LockTimeout > 0, "JOBS_LOCK_TIMEOUT must be positive")
+	check(c.Jobs.PollInterval > 0, "JOBS_POLL_INTERVAL must be positive")
+
+	// Reports
+	check(c.Reports.CacheTTL >= 0, "REPORTS_CACHE_TTL must not be negative")
+
+	// Inventory
+	check(c.Inventory.ServingsPerGuest > 0, "INVENTORY_SERVINGS_PER_GUEST must be positive")
+
+	// Media
+	check(c.Media.Backend == "local", "MEDIA_BACKEND must be local, got %q", c.Media.Backend)
+	check(c.Media.Backend != "local" || c.Media.Dir != "", "MEDIA_DIR must not be empty")
+	check(c.Media.BaseURL != "", "MEDIA_BASE_URL must not be empty")
+	check(c.Media.MaxUploadSize > 0, "MEDIA_MAX_UPLOAD_SIZE must be positive")
+	check(c.Media.ImageSize >= 16 && c.Media.ImageSize <= 4096, "MEDIA_IMAGE_SIZE must be between 16 and 4096")
+
+	// CORS
+	check(len(c.CORS.AllowOrigins) > 0, "ALLOWED_ORIGINS must list at least one origin")
+	for _, origin := range c.CORS.AllowOrigins {
+		check(isHTTPURL(origin), "ALLOWED_ORIGINS entry %q is not an http(s) origin", origin)
+	}
+
+	return errors.Join(errs...)
+}
+
+func isHTTPURL(value string) bool {
+	u, err := url.Parse(value)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
@@ -0,0 +1,198 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
+)
+
+// ErrTooManyBookings is returned when a bulk filter matches more than
+// models.MaxBulkBookings bookings
+var ErrTooManyBookings = fmt.Errorf("more than %d bookings selected", models.MaxBulkBookings)
+
+// Bulk applies req.Action to the selected bookings in one transaction and
+// returns the outcome for each, in the order the IDs were given or by date
+// for a filter. A dry run works out the same outcomes, then rolls back.
+// Reassigning to a user that doesn't exist returns ErrUserNotFound.
+func (r *BookingRepository) Bulk(ctx context.Context, req *models.BulkBookingRequest) ([]models.BulkBookingResult, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if req.Action == models.BulkActionReassign && req.AssignedUserID != nil {
+		var exists bool
+		if err := tx.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM users WHERE id = $1)",
+			*req.AssignedUserID).Scan(&exists); err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, ErrUserNotFound
+		}
+	}
+
+	ids, found, err := selectBulkBookings(ctx, tx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]models.BulkBookingResult, 0, len(ids))
+	for _, id := range ids {
+		booking, ok := found[id]
+		if !ok {
+			results = append(results, models.BulkBookingResult{ID: id, Result: models.BulkResultNotFound})
+			continue
+		}
+
+		changed, err := applyBulkAction(ctx, tx, req, booking)
+		if err != nil {
+			return nil, fmt.Errorf("booking %d: %w", id, err)
+		}
+		result := models.BulkResultUnchanged
+		if changed {
+			result = models.BulkResultChanged
+		}
+		results = append(results, models.BulkBookingResult{ID: id, Result: result, Booking: booking})
+	}
+
+	if req.DryRun {
+		return results, nil
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// selectBulkBookings locks the bookings a bulk request applies to. It returns
+// the IDs to report on, which for an ID list includes ones that don't exist.
+func selectBulkBookings(ctx context.Context, tx pgx.Tx, req *models.BulkBookingRequest) ([]int, map[int]*models.Booking, error) {
+	query := "SELECT " + bookingColumns + " FROM bookings"
+	var ids []int
+	var args []interface{}
+
+	if req.Filter == nil {
+		seen := map[int]bool{}
+		for _, id := range req.IDs {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+		query += " WHERE id = ANY($1) FOR UPDATE"
+		args = append(args, ids)
+	} else {
+		where, filterArgs := bookingFilterClause(req.Filter)
+		query += where + fmt.Sprintf(" ORDER BY date, id LIMIT %d FOR UPDATE", models.MaxBulkBookings+1)
+		args = filterArgs
+	}
+
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to select bookings: %w", err)
+	}
+	defer rows.Close()
+
+	found := map[int]*models.Booking{}
+	for rows.Next() {
+		booking, err := scanBooking(rows)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read booking: %w", err)
+		}
+		found[booking.ID] = booking
+		if req.Filter != nil {
+			ids = append(ids, booking.ID)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	if len(ids) > models.MaxBulkBookings {
+		return nil, nil, ErrTooManyBookings
+	}
+	return ids, found, nil
+}
+
+// bookingFilterClause builds the WHERE clause and arguments for filter
+func bookingFilterClause(filter *models.BookingFilter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	add := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.Archived != nil {
+		add("archived = $%d", *filter.Archived)
+	}
+	if filter.Status != "" {
+		add("status = $%d", filter.Status)
+	}
+	if filter.DateFrom != "" {
+		add("date >= $%d::date", filter.DateFrom)
+	}
+	if filter.DateTo != "" {
+		add("date <= $%d::date", filter.DateTo)
+	}
+	if filter.AssignedUserID != nil {
+		add("assigned_user_id = $%d", *filter.AssignedUserID)
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// applyBulkAction applies req's action to booking, updating it to match, and
+// reports whether anything changed
+func applyBulkAction(ctx context.Context, tx pgx.Tx, req *models.BulkBookingRequest, booking *models.Booking) (bool, error) {
+	var query string
+	var arg interface{}
+
+	switch req.Action {
+	case models.BulkActionArchive, models.BulkActionUnarchive:
+		archive := req.Action == models.BulkActionArchive
+		if booking.Archived == archive {
+			return false, nil
+		}
+		query, arg = "UPDATE bookings SET archived = $1 WHERE id = $2", archive
+		booking.Archived = archive
+
+	case models.BulkActionSetStatus:
+		if booking.Status == req.Status {
+			return false, nil
+		}
+		query, arg = "UPDATE bookings SET status = $1 WHERE id = $2", req.Status
+		booking.Status = req.Status
+
+	case models.BulkActionReassign:
+		if sameAssignee(booking.AssignedUserID, req.AssignedUserID) {
+			return false, nil
+		}
+		query, arg = "UPDATE bookings SET assigned_user_id = $1 WHERE id = $2", req.AssignedUserID
+		booking.AssignedUserID = req.AssignedUserID
+
+	case models.BulkActionDelete:
+		_, err := tx.Exec(ctx, "DELETE FROM bookings WHERE id = $1", booking.ID)
+		return err == nil, err
+
+	default:
+		return false, fmt.Errorf("unknown bulk action %q", req.Action)
+	}
+
+	_, err := tx.Exec(ctx, query, arg, booking.ID)
+	return err == nil, err
+}
+
+func sameAssignee(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,30 @@
+package database
+
+import (
+	"context"
+
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
+)
+
+// Stream calls fn with each booking matching filter, ordered by date and
+// time. Rows are read as they arrive rather than loaded together, so callers
+// can write out any number of bookings. The first error fn returns stops it.
+func (r *BookingRepository) Stream(ctx context.Context, filter *models.BookingFilter, fn func(*models.Booking) error) error {
+	where, args := bookingFilterClause(filter)
+	rows, err := r.db.Pool.Query(ctx, "SELECT "+bookingColumns+" FROM bookings"+where+" ORDER BY date, time, id", args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		booking, err := scanBooking(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(booking); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
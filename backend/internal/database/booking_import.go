@@ -0,0 +1,100 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
+)
+
+// importLockKey serializes imports, so two importing the same file can't
+// both decide a booking is new
+const importLockKey = "bookings_import"
+
+// Import inserts bookings in one transaction and returns the outcome for
+// each, in order. A booking is a duplicate when one already exists, or was
+// imported earlier in the same batch, for the same date, time and customer
+// name with the same email or phone; duplicates are skipped. Unlike Create,
+// the status and archived flag are kept, since imports are usually of past
+// events. A dry run works out the same outcomes, then rolls back; it reports
+// the IDs of duplicates but not of bookings it would create.
+func (r *BookingRepository) Import(ctx context.Context, bookings []*models.Booking, dryRun bool) ([]models.BookingImportResult, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock(hashtext($1))", importLockKey); err != nil {
+		return nil, err
+	}
+
+	results := make([]models.BookingImportResult, len(bookings))
+	for i, booking := range bookings {
+		id, duplicate, err := importBooking(ctx, tx, booking)
+		if err != nil {
+			return nil, fmt.Errorf("booking %d: %w", i+1, err)
+		}
+		results[i] = models.BookingImportResult{ID: id, Result: models.ImportResultCreated}
+		if duplicate {
+			results[i].Result = models.ImportResultDuplicate
+		}
+	}
+
+	if dryRun {
+		// The rows were never saved, so their IDs mean nothing
+		for i := range results {
+			if results[i].Result == models.ImportResultCreated {
+				results[i].ID = 0
+			}
+		}
+		return results, nil
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// importBooking inserts booking unless it duplicates an existing one, and
+// returns the ID of the new or matching booking
+func importBooking(ctx context.Context, tx pgx.Tx, booking *models.Booking) (int, bool, error) {
+	parsedDate, err := time.Parse("2006-01-02", booking.Date)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid date format: %w", err)
+	}
+
+	var id int
+	err = tx.QueryRow(ctx, `
+        SELECT id FROM bookings
+        WHERE date = $1 AND time = $2 AND lower(name) = lower($3)
+          AND (($4 <> '' AND lower(email) = lower($4)) OR ($5 <> '' AND phone = $5))
+        ORDER BY id
+        LIMIT 1
+    `, parsedDate, booking.Time, booking.Name, booking.Email, booking.Phone).Scan(&id)
+	if err == nil {
+		return id, true, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return 0, false, err
+	}
+
+	if booking.Status == "" {
+		booking.Status = models.BookingStatusPending
+	}
+	err = tx.QueryRow(ctx, `
+        INSERT INTO bookings (name, email, phone, date, time, people, location, notes,
+                             coffee_flavors, milk_options, package, archived, is_outdoor, has_shade, status)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+        RETURNING id
+    `, booking.Name, booking.Email, booking.Phone, parsedDate, booking.Time, booking.People, booking.Location,
+		booking.Notes, booking.CoffeeFlavors, booking.MilkOptions, booking.Package, booking.Archived,
+		booking.IsOutdoor, booking.HasShade, booking.Status).Scan(&id)
+	if err != nil {
+		return 0, false, err
+	}
+	return id, false, nil
+}
@@ -4,7 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"time"
 
 	"github.com/jackc/pgx/v5"
@@ -21,6 +21,31 @@ func NewBookingRepository(db *DB) BookingRepositoryInterface {
 	return &BookingRepository{db: db}
 }
 
+// bookingColumns are the columns read by scanBooking, in order
+const bookingColumns = `id, name, email, phone, date, time, people, location, notes,
+               coffee_flavors, milk_options, package, created_at, archived, is_outdoor, has_shade,
+               status, assigned_user_id`
+
+// scanBooking reads a row selected with bookingColumns
+func scanBooking(row pgx.Row) (*models.Booking, error) {
+	booking := &models.Booking{}
+	var dateTime time.Time
+
+	err := row.Scan(
+		&booking.ID, &booking.Name, &booking.Email, &booking.Phone, &dateTime, &booking.Time, &booking.People,
+		&booking.Location, &booking.Notes, &booking.CoffeeFlavors, &booking.MilkOptions,
+		&booking.Package, &booking.CreatedAt, &booking.Archived, &booking.IsOutdoor, &booking.HasShade,
+		&booking.Status, &booking.AssignedUserID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Assign the date as a string in YYYY-MM-DD format
+	booking.Date = dateTime.Format("2006-01-02")
+	return booking, nil
+}
+
 // Create inserts a new booking into the database
 func (r *BookingRepository) Create(ctx context.Context, booking *models.Booking) (int, error) {
 	// Parse date string to time.Time if needed
@@ -31,16 +56,18 @@ func (r *BookingRepository) Create(ctx context.Context, booking *models.Booking)
 
 	// Set default values for new bookings
 	booking.Archived = false
+	booking.Status = models.BookingStatusPending
+	booking.AssignedUserID = nil
 
 	var id int
 	err = r.db.Pool.QueryRow(ctx, `
         INSERT INTO bookings (name, email, phone, date, time, people, location, notes, 
-                             coffee_flavors, milk_options, package, archived, is_outdoor, has_shade)
-        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+                             coffee_flavors, milk_options, package, archived, is_outdoor, has_shade, status)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
         RETURNING id
     `, booking.Name, booking.Email, booking.Phone, parsedDate, booking.Time, booking.People, booking.Location,
 		booking.Notes, booking.CoffeeFlavors, booking.MilkOptions, booking.Package, booking.Archived,
-		booking.IsOutdoor, booking.HasShade).Scan(&id)
+		booking.IsOutdoor, booking.HasShade, booking.Status).Scan(&id)
 
 	if err != nil {
 		return 0, err
@@ -51,20 +78,11 @@ func (r *BookingRepository) Create(ctx context.Context, booking *models.Booking)
 
 // GetByID retrieves a booking by its ID
 func (r *BookingRepository) GetByID(ctx context.Context, id int) (*models.Booking, error) {
-	booking := &models.Booking{}
-
-	var dateTime time.Time
-
-	err := r.db.Pool.QueryRow(ctx, `
-        SELECT id, name, email, phone, date, time, people, location, notes, 
-               coffee_flavors, milk_options, package, created_at, archived, is_outdoor, has_shade
+	booking, err := scanBooking(r.db.Pool.QueryRow(ctx, `
+        SELECT `+bookingColumns+`
         FROM bookings 
         WHERE id = $1
-    `, id).Scan(
-		&booking.ID, &booking.Name, &booking.Email, &booking.Phone, &dateTime, &booking.Time, &booking.People,
-		&booking.Location, &booking.Notes, &booking.CoffeeFlavors, &booking.MilkOptions,
-		&booking.Package, &booking.CreatedAt, &booking.Archived, &booking.IsOutdoor, &booking.HasShade,
-	)
+    `, id))
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -73,31 +91,19 @@ func (r *BookingRepository) GetByID(ctx context.Context, id int) (*models.Bookin
 		return nil, err
 	}
 
-	// Assign the date as a string in YYYY-MM-DD format
-	booking.Date = dateTime.Format("2006-01-02")
-
 	return booking, nil
 }
 
-// GetAll retrieves all bookings
-func (r *BookingRepository) GetAll(ctx context.Context, includeArchived bool) ([]*models.Booking, error) {
-	log.Println("Starting GetAll query...")
-
+// GetAll retrieves the bookings matching filter
+func (r *BookingRepository) GetAll(ctx context.Context, filter *models.BookingFilter) ([]*models.Booking, error) {
+	where, args := bookingFilterClause(filter)
 	query := `
-        SELECT id, name, email, phone, date, time, people, location, notes, 
-               coffee_flavors, milk_options, package, created_at, archived, is_outdoor, has_shade
+        SELECT ` + bookingColumns + `
         FROM bookings
-    `
-	if !includeArchived {
-		query += " WHERE archived = FALSE"
-	}
-	query += " ORDER BY date DESC, time ASC"
+    ` + where + " ORDER BY date DESC, time ASC"
 
-	//log.Println("Executing query:", query)
-
-	rows, err := r.db.Pool.Query(ctx, query)
+	rows, err := r.db.Pool.Query(ctx, query, args...)
 	if err != nil {
-		log.Printf("Database query error: %v", err)
 		return nil, fmt.Errorf("database query error: %w", err)
 	}
 	defer rows.Close()
@@ -108,32 +114,19 @@ func (r *BookingRepository) GetAll(ctx context.Context, includeArchived bool) ([
 
 	for rows.Next() {
 		rowNum++
-		booking := &models.Booking{}
-
-		var dateTime time.Time // Temporary variable for date
-
-		err := rows.Scan(
-			&booking.ID, &booking.Name, &booking.Email, &booking.Phone, &dateTime, &booking.Time, &booking.People,
-			&booking.Location, &booking.Notes, &booking.CoffeeFlavors, &booking.MilkOptions,
-			&booking.Package, &booking.CreatedAt, &booking.Archived, &booking.IsOutdoor, &booking.HasShade,
-		)
+		booking, err := scanBooking(rows)
 		if err != nil {
-			log.Printf("Error scanning row %d: %v", rowNum, err)
 			return nil, fmt.Errorf("error scanning row %d: %w", rowNum, err)
 		}
 
-		// Assign the date
-		booking.Date = dateTime.Format("2006-01-02")
-
 		bookings = append(bookings, booking)
 	}
 
 	if err := rows.Err(); err != nil {
-		log.Printf("Error after scanning rows: %v", err)
 		return nil, fmt.Errorf("row iteration error: %w", err)
 	}
 
-	log.Printf("Successfully retrieved %d bookings", len(bookings))
+	slog.DebugContext(ctx, "retrieved bookings", "count", len(bookings))
 	return bookings, nil
 }
 
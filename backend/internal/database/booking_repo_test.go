@@ -197,6 +197,12 @@ func TestCreateBooking(t *testing.T) {
 	}
 }
 
+// activeFilter is the filter the booking list uses by default
+func activeFilter() *models.BookingFilter {
+	archived := false
+	return &models.BookingFilter{Archived: &archived}
+}
+
 func TestEmptyDatabase(t *testing.T) {
 	log.Println("Running TestEmptyDatabase...")
 	testDB := setupTestDB(t)
@@ -207,7 +213,7 @@ func TestEmptyDatabase(t *testing.T) {
 	repo := database.NewBookingRepository(db)
 
 	t.Run("Empty database", func(t *testing.T) {
-		bookings, err := repo.GetAll(context.Background(), false)
+		bookings, err := repo.GetAll(context.Background(), activeFilter())
 		if err != nil {
 			t.Fatalf("Failed to retrieve bookings: %v", err)
 		}
@@ -261,7 +267,7 @@ func TestMixOfActiveAndArchivedBookings(t *testing.T) {
 		}
 
 		// Check 1: Retrieve active bookings only
-		activeBookings, err := repo.GetAll(context.Background(), false)
+		activeBookings, err := repo.GetAll(context.Background(), activeFilter())
 		if err != nil {
 			t.Fatalf("Failed to retrieve active bookings: %v", err)
 		}
@@ -278,7 +284,7 @@ func TestMixOfActiveAndArchivedBookings(t *testing.T) {
 		}
 
 		// Check 2: Retrieve all bookings including archived
-		allBookings, err := repo.GetAll(context.Background(), true)
+		allBookings, err := repo.GetAll(context.Background(), &models.BookingFilter{})
 		if err != nil {
 			t.Fatalf("Failed to retrieve all bookings: %v", err)
 		}
@@ -433,7 +439,7 @@ func TestGetAllWithArchiveFiltering(t *testing.T) {
 
 	// Test 1: Get active bookings only
 	t.Run("Get active bookings only", func(t *testing.T) {
-		activeBookings, err := repo.GetAll(context.Background(), false)
+		activeBookings, err := repo.GetAll(context.Background(), activeFilter())
 		if err != nil {
 			t.Fatalf("Failed to retrieve active bookings: %v", err)
 		}
@@ -452,7 +458,7 @@ func TestGetAllWithArchiveFiltering(t *testing.T) {
 
 	// Test 2: Get all bookings including archived
 	t.Run("Get all bookings including archived", func(t *testing.T) {
-		allBookings, err := repo.GetAll(context.Background(), true)
+		allBookings, err := repo.GetAll(context.Background(), &models.BookingFilter{})
 		if err != nil {
 			t.Fatalf("Failed to retrieve all bookings: %v", err)
 		}
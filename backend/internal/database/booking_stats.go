@@ -0,0 +1,35 @@
+package database
+
+import (
+	"context"
+	"time"
+)
+
+// BookingStatsRepository runs the aggregate booking queries behind the
+// metrics gauges
+type BookingStatsRepository struct {
+	db *DB
+}
+
+// NewBookingStatsRepository creates a new booking stats repository
+func NewBookingStatsRepository(db *DB) *BookingStatsRepository {
+	return &BookingStatsRepository{db: db}
+}
+
+// CountCreatedSince returns the number of bookings created at or after since
+func (s *BookingStatsRepository) CountCreatedSince(ctx context.Context, since time.Time) (int, error) {
+	var count int
+	err := s.db.Pool.QueryRow(ctx, `
+        SELECT COUNT(*) FROM bookings WHERE created_at >= $1
+    `, since).Scan(&count)
+	return count, err
+}
+
+// CountUpcoming returns the number of unarchived bookings dated on or after from
+func (s *BookingStatsRepository) CountUpcoming(ctx context.Context, from time.Time) (int, error) {
+	var count int
+	err := s.db.Pool.QueryRow(ctx, `
+        SELECT COUNT(*) FROM bookings WHERE date >= $1 AND archived = false
+    `, from.Format("2006-01-02")).Scan(&count)
+	return count, err
+}
@@ -3,10 +3,10 @@ package database
 import (
 	"context"
 	"fmt"
-	"log"
-	"time"
+	"log/slog"
 
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/config"
 )
 
 // DB wraps the database connection pool
@@ -15,18 +15,21 @@ type DB struct {
 }
 
 // New creates a new database connection
-func New(databaseURL string) (*DB, error) {
-	config, err := pgxpool.ParseConfig(databaseURL)
+func New(cfg config.DatabaseConfig) (*DB, error) {
+	poolConfig, err := pgxpool.ParseConfig(cfg.URL)
 	if err != nil {
 		return nil, fmt.Errorf("unable to parse database URL: %w", err)
 	}
 
 	// Set connection pool settings
-	config.MaxConns = 10
-	config.MaxConnLifetime = time.Hour
+	poolConfig.MaxConns = cfg.MaxConns
+	poolConfig.MinConns = cfg.MinConns
+	poolConfig.MaxConnLifetime = cfg.MaxConnLifetime
+	poolConfig.MaxConnIdleTime = cfg.MaxConnIdleTime
+	poolConfig.ConnConfig.Tracer = queryTracer{}
 
 	// Create the connection pool
-	pool, err := pgxpool.NewWithConfig(context.Background(), config)
+	pool, err := pgxpool.NewWithConfig(context.Background(), poolConfig)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create connection pool: %w", err)
 	}
@@ -36,7 +39,7 @@ func New(databaseURL string) (*DB, error) {
 		return nil, fmt.Errorf("unable to connect to database: %w", err)
 	}
 
-	log.Println("Connected to PostgreSQL database")
+	slog.InfoContext(context.Background(), "connected to PostgreSQL")
 	return &DB{Pool: pool}, nil
 }
 
@@ -44,6 +47,6 @@ func New(databaseURL string) (*DB, error) {
 func (db *DB) Close() {
 	if db.Pool != nil {
 		db.Pool.Close()
-		log.Println("Database connection closed")
+		slog.InfoContext(context.Background(), "database connection closed")
 	}
 }
@@ -0,0 +1,184 @@
+package database
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
+)
+
+// Errors returned by InventoryRepository
+var (
+	ErrIngredientNotFound = errors.New("ingredient not found")
+	ErrIngredientExists   = errors.New("ingredient already exists")
+)
+
+// InventoryRepository handles ingredients, their stock and the recipes that
+// use them
+type InventoryRepository struct {
+	db *DB
+}
+
+// NewInventoryRepository creates a new inventory repository
+func NewInventoryRepository(db *DB) InventoryRepositoryInterface {
+	return &InventoryRepository{db: db}
+}
+
+const ingredientColumns = `id, name, unit, stock::float8, created_at, updated_at`
+
+func scanIngredient(row pgx.Row) (*models.Ingredient, error) {
+	ingredient := &models.Ingredient{}
+	err := row.Scan(&ingredient.ID, &ingredient.Name, &ingredient.Unit, &ingredient.Stock,
+		&ingredient.CreatedAt, &ingredient.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrIngredientNotFound
+		}
+		return nil, err
+	}
+	return ingredient, nil
+}
+
+// GetIngredients retrieves every ingredient, by name
+func (r *InventoryRepository) GetIngredients(ctx context.Context) ([]*models.Ingredient, error) {
+	rows, err := r.db.Pool.Query(ctx, `SELECT `+ingredientColumns+` FROM ingredients ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ingredients := []*models.Ingredient{}
+	for rows.Next() {
+		ingredient, err := scanIngredient(rows)
+		if err != nil {
+			return nil, err
+		}
+		ingredients = append(ingredients, ingredient)
+	}
+	return ingredients, rows.Err()
+}
+
+// CreateIngredient adds an ingredient and returns it. Names are unique.
+func (r *InventoryRepository) CreateIngredient(ctx context.Context, input *models.IngredientInput) (*models.Ingredient, error) {
+	ingredient, err := scanIngredient(r.db.Pool.QueryRow(ctx, `
+        INSERT INTO ingredients (name, unit, stock)
+        VALUES ($1, $2, $3)
+        RETURNING `+ingredientColumns,
+		input.Name, input.Unit, input.Stock))
+	if isUniqueViolation(err) {
+		return nil, ErrIngredientExists
+	}
+	return ingredient, err
+}
+
+// UpdateIngredient changes an ingredient's name, unit and stock
+func (r *InventoryRepository) UpdateIngredient(ctx context.Context, id int, input *models.IngredientInput) (*models.Ingredient, error) {
+	ingredient, err := scanIngredient(r.db.Pool.QueryRow(ctx, `
+        UPDATE ingredients
+        SET name = $1, unit = $2, stock = $3, updated_at = NOW()
+        WHERE id = $4
+        RETURNING `+ingredientColumns,
+		input.Name, input.Unit, input.Stock, id))
+	if isUniqueViolation(err) {
+		return nil, ErrIngredientExists
+	}
+	return ingredient, err
+}
+
+// DeleteIngredient removes an ingredient and takes it out of every recipe
+func (r *InventoryRepository) DeleteIngredient(ctx context.Context, id int) error {
+	tag, err := r.db.Pool.Exec(ctx, `DELETE FROM ingredients WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrIngredientNotFound
+	}
+	return nil
+}
+
+// GetRecipes returns a recipe for every menu item, ordered like the menu.
+// Items nobody has written a recipe for have no ingredients.
+func (r *InventoryRepository) GetRecipes(ctx context.Context) ([]*models.Recipe, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+        SELECT m.id, m.value, m.label, m.type, i.id, i.name, i.unit, ri.quantity::float8
+        FROM menu_items m
+        LEFT JOIN recipe_ingredients ri ON ri.menu_item_id = m.id
+        LEFT JOIN ingredients i ON i.id = ri.ingredient_id
+        WHERE m.deleted_at IS NULL
+        ORDER BY m.type, m.label, m.id, i.name
+    `)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	recipes := []*models.Recipe{}
+	var recipe *models.Recipe
+	for rows.Next() {
+		var menuItemID int
+		var value, label, itemType string
+		var ingredientID *int
+		var name, unit *string
+		var quantity *float64
+		if err := rows.Scan(&menuItemID, &value, &label, &itemType, &ingredientID, &name, &unit, &quantity); err != nil {
+			return nil, err
+		}
+
+		if recipe == nil || recipe.MenuItemID != menuItemID {
+			recipe = &models.Recipe{
+				MenuItemID:  menuItemID,
+				Value:       value,
+				Label:       label,
+				Type:        models.ItemType(itemType),
+				Ingredients: []models.RecipeIngredient{},
+			}
+			recipes = append(recipes, recipe)
+		}
+		if ingredientID != nil {
+			recipe.Ingredients = append(recipe.Ingredients, models.RecipeIngredient{
+				IngredientID: *ingredientID, Name: *name, Unit: *unit, Quantity: *quantity,
+			})
+		}
+	}
+	return recipes, rows.Err()
+}
+
+// SetRecipe replaces the ingredients of a menu item's recipe. An ingredient
+// that doesn't exist returns ErrIngredientNotFound.
+func (r *InventoryRepository) SetRecipe(ctx context.Context, menuItemID int, ingredients []models.RecipeIngredient) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var exists bool
+	if err := tx.QueryRow(ctx, `
+        SELECT EXISTS (SELECT 1 FROM menu_items WHERE id = $1 AND deleted_at IS NULL)
+    `, menuItemID).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return ErrMenuItemNotFound
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM recipe_ingredients WHERE menu_item_id = $1`, menuItemID); err != nil {
+		return err
+	}
+	for _, ingredient := range ingredients {
+		tag, err := tx.Exec(ctx, `
+            INSERT INTO recipe_ingredients (menu_item_id, ingredient_id, quantity)
+            SELECT $1, id, $3 FROM ingredients WHERE id = $2
+        `, menuItemID, ingredient.IngredientID, ingredient.Quantity)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return ErrIngredientNotFound
+		}
+	}
+
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,89 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
+)
+
+var (
+	// ErrJobNotFound is returned when no job has the requested name
+	ErrJobNotFound = errors.New("job not found")
+	// ErrJobLockLost is returned by Finish when the lock expired and another
+	// instance claimed the job
+	ErrJobLockLost = errors.New("job lock lost")
+)
+
+// JobRepository stores scheduled job state and the locks that keep a job
+// from running on more than one instance at a time
+type JobRepository struct {
+	db *DB
+}
+
+// NewJobRepository creates a new job repository
+func NewJobRepository(db *DB) JobRepositoryInterface {
+	return &JobRepository{db: db}
+}
+
+// Ensure creates the row for a job if it doesn't exist yet
+func (r *JobRepository) Ensure(ctx context.Context, name string) error {
+	_, err := r.db.Pool.Exec(ctx, `INSERT INTO jobs (name) VALUES ($1) ON CONFLICT (name) DO NOTHING`, name)
+	return err
+}
+
+// Get retrieves a job's state
+func (r *JobRepository) Get(ctx context.Context, name string) (*models.JobState, error) {
+	state := &models.JobState{}
+	err := r.db.Pool.QueryRow(ctx, `
+        SELECT name, last_run_at, COALESCE(last_duration_ms, 0), last_result, last_error,
+               COALESCE(locked_by, ''), locked_until, created_at
+        FROM jobs
+        WHERE name = $1
+    `, name).Scan(&state.Name, &state.LastRunAt, &state.LastDurationMS, &state.LastResult, &state.LastError,
+		&state.LockedBy, &state.LockedUntil, &state.CreatedAt)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrJobNotFound
+		}
+		return nil, err
+	}
+	return state, nil
+}
+
+// Claim locks a job for owner until the lease runs out. It only succeeds if
+// the job is unlocked and hasn't run since lastRunAt, so when several
+// instances find the same run due exactly one of them gets it.
+func (r *JobRepository) Claim(ctx context.Context, name, owner string, lastRunAt *time.Time, lease time.Duration) (bool, error) {
+	tag, err := r.db.Pool.Exec(ctx, `
+        UPDATE jobs
+        SET locked_by = $2, locked_until = CURRENT_TIMESTAMP + make_interval(secs => $4)
+        WHERE name = $1
+          AND last_run_at IS NOT DISTINCT FROM $3
+          AND (locked_until IS NULL OR locked_until < CURRENT_TIMESTAMP)
+    `, name, owner, lastRunAt, lease.Seconds())
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// Finish records a run and releases owner's lock
+func (r *JobRepository) Finish(ctx context.Context, name, owner string, run *models.JobRun) error {
+	tag, err := r.db.Pool.Exec(ctx, `
+        UPDATE jobs
+        SET last_run_at = $3, last_duration_ms = $4, last_result = $5, last_error = $6,
+            locked_by = NULL, locked_until = NULL
+        WHERE name = $1 AND locked_by = $2
+    `, name, owner, run.StartedAt, run.Duration.Milliseconds(), run.Result, run.Error)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrJobLockLost
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
+)
+
+// MaintenanceRepository holds the bulk clean-up queries run by scheduled jobs
+type MaintenanceRepository struct {
+	db *DB
+}
+
+// NewMaintenanceRepository creates a new maintenance repository
+func NewMaintenanceRepository(db *DB) MaintenanceRepositoryInterface {
+	return &MaintenanceRepository{db: db}
+}
+
+// ArchivePastBookings archives every unarchived booking dated before the
+// given day and returns them
+func (r *MaintenanceRepository) ArchivePastBookings(ctx context.Context, before time.Time) ([]*models.Booking, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+        UPDATE bookings
+        SET archived = true
+        WHERE archived = false AND date < $1::date
+        RETURNING `+bookingColumns,
+		before.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	bookings := []*models.Booking{}
+	for rows.Next() {
+		booking, err := scanBooking(rows)
+		if err != nil {
+			return nil, err
+		}
+		bookings = append(bookings, booking)
+	}
+	return bookings, rows.Err()
+}
+
+// PurgeDeleted permanently removes menu items and packages deleted before
+// the given time. It returns how many were removed and the image keys of the
+// removed menu items, whose files the caller deletes.
+func (r *MaintenanceRepository) PurgeDeleted(ctx context.Context, before time.Time) (int64, []string, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `
+        DELETE FROM menu_items
+        WHERE deleted_at < $1
+        RETURNING COALESCE(image_key, '')
+    `, before)
+	if err != nil {
+		return 0, nil, err
+	}
+	var purged int64
+	imageKeys := []string{}
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			rows.Close()
+			return 0, nil, err
+		}
+		purged++
+		if key != "" {
+			imageKeys = append(imageKeys, key)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, nil, err
+	}
+
+	tag, err := tx.Exec(ctx, `DELETE FROM packages WHERE deleted_at < $1`, before)
+	if err != nil {
+		return 0, nil, err
+	}
+	purged += tag.RowsAffected()
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, nil, err
+	}
+	return purged, imageKeys, nil
+}
+
+// DeleteExpiredTokens removes expired refresh and password reset tokens and
+// returns how many were removed
+func (r *MaintenanceRepository) DeleteExpiredTokens(ctx context.Context) (int64, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	var deleted int64
+	for _, table := range []string{"refresh_tokens", "password_reset_tokens"} {
+		tag, err := tx.Exec(ctx, `DELETE FROM `+table+` WHERE expires_at < CURRENT_TIMESTAMP`)
+		if err != nil {
+			return 0, err
+		}
+		deleted += tag.RowsAffected()
+	}
+	return deleted, tx.Commit(ctx)
+}
+
+// AnonymizeBookings clears the customer's details from bookings dated before
+// the given day, keeping the rest for reporting, and returns how many were
+// anonymized
+func (r *MaintenanceRepository) AnonymizeBookings(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := r.db.Pool.Exec(ctx, `
+        UPDATE bookings
+        SET name = $2, email = '', phone = '', location = '', notes = '',
+            anonymized_at = CURRENT_TIMESTAMP
+        WHERE anonymized_at IS NULL AND date < $1::date
+    `, before.Format("2006-01-02"), models.AnonymizedName)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
@@ -2,11 +2,16 @@ package database
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
 )
 
+// ErrMenuItemNotFound is returned when a menu item doesn't exist or was deleted
+var ErrMenuItemNotFound = errors.New("menu item not found")
+
 type MenuRepository struct {
 	db *DB
 }
@@ -16,74 +21,101 @@ func NewMenuRepository(db *DB) MenuRepositoryInterface {
 	return &MenuRepository{db: db}
 }
 
+const menuItemColumns = `id, value, label, type, active, display_order, description, tags,
+        price_modifier::float8, COALESCE(image_key, ''), created_at, updated_at`
+
+func scanMenuItem(row pgx.Row) (models.MenuItem, error) {
+	var item models.MenuItem
+	var itemType string
+	err := row.Scan(
+		&item.ID, &item.Value, &item.Label, &itemType, &item.Active,
+		&item.DisplayOrder, &item.Description, &item.Tags, &item.PriceModifier, &item.ImageKey,
+		&item.CreatedAt, &item.UpdatedAt,
+	)
+	item.Type = models.ItemType(itemType)
+	return item, err
+}
+
+func collectMenuItems(rows pgx.Rows) ([]models.MenuItem, error) {
+	defer rows.Close()
+
+	items := []models.MenuItem{}
+	for rows.Next() {
+		item, err := scanMenuItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
 // Implementation of repository methods
 func (r *MenuRepository) GetAll(ctx context.Context) ([]models.MenuItem, error) {
 	rows, err := r.db.Pool.Query(ctx, `
-        SELECT id, value, label, type, active, created_at, updated_at
+        SELECT `+menuItemColumns+`
         FROM menu_items
-        ORDER BY type, label
+        WHERE deleted_at IS NULL
+        ORDER BY type, display_order, label
     `)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
-
-	var items []models.MenuItem
-	for rows.Next() {
-		var item models.MenuItem
-		var itemType string
-		if err := rows.Scan(
-			&item.ID, &item.Value, &item.Label, &itemType, &item.Active,
-			&item.CreatedAt, &item.UpdatedAt,
-		); err != nil {
-			return nil, err
-		}
-		item.Type = models.ItemType(itemType)
-		items = append(items, item)
-	}
 
-	return items, nil
+	return collectMenuItems(rows)
 }
 
 // GetByType retrieves menu items of a specific type
 func (r *MenuRepository) GetByType(ctx context.Context, itemType models.ItemType) ([]models.MenuItem, error) {
 	rows, err := r.db.Pool.Query(ctx, `
-        SELECT id, value, label, type, active, created_at, updated_at
+        SELECT `+menuItemColumns+`
         FROM menu_items
-        WHERE type = $1
-        ORDER BY label
+        WHERE type = $1 AND deleted_at IS NULL
+        ORDER BY display_order, label
     `, string(itemType))
 
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
 
-	var items []models.MenuItem
-	for rows.Next() {
-		var item models.MenuItem
-		var itemType string
-		if err := rows.Scan(
-			&item.ID, &item.Value, &item.Label, &itemType, &item.Active,
-			&item.CreatedAt, &item.UpdatedAt,
-		); err != nil {
-			return nil, err
-		}
-		item.Type = models.ItemType(itemType)
-		items = append(items, item)
+	return collectMenuItems(rows)
+}
+
+// GetByID retrieves a single menu item
+func (r *MenuRepository) GetByID(ctx context.Context, id int) (*models.MenuItem, error) {
+	item, err := scanMenuItem(r.db.Pool.QueryRow(ctx, `
+        SELECT `+menuItemColumns+`
+        FROM menu_items
+        WHERE id = $1 AND deleted_at IS NULL
+    `, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrMenuItemNotFound
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	return items, nil
+	return &item, nil
 }
 
-// Create adds a new menu item
+// Create adds a new menu item after the others of its type
 func (r *MenuRepository) Create(ctx context.Context, item *models.MenuItem) (int, error) {
+	tags := item.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
 	var id int
 	err := r.db.Pool.QueryRow(ctx, `
-        INSERT INTO menu_items (value, label, type, active)
-        VALUES ($1, $2, $3, $4)
-        RETURNING id
-    `, item.Value, item.Label, item.Type, item.Active).Scan(&id)
+        INSERT INTO menu_items (value, label, type, active, description, tags, price_modifier, display_order)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, (
+            SELECT COALESCE(MAX(display_order) + 1, 0)
+            FROM menu_items
+            WHERE type = $3 AND deleted_at IS NULL
+        ))
+        RETURNING id, display_order
+    `, item.Value, item.Label, item.Type, item.Active, item.Description, tags, item.PriceModifier).Scan(&id, &item.DisplayOrder)
 
 	if err != nil {
 		return 0, err
@@ -92,13 +124,19 @@ func (r *MenuRepository) Create(ctx context.Context, item *models.MenuItem) (int
 	return id, nil
 }
 
-// Update modifies an existing menu item
+// Update modifies an existing menu item, keeping its place and image
 func (r *MenuRepository) Update(ctx context.Context, id int, item *models.MenuItem) error {
+	tags := item.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
 	tag, err := r.db.Pool.Exec(ctx, `
         UPDATE menu_items
-        SET value = $1, label = $2, type = $3, active = $4, updated_at = CURRENT_TIMESTAMP
-        WHERE id = $5
-    `, item.Value, item.Label, item.Type, item.Active, id)
+        SET value = $1, label = $2, type = $3, active = $4, description = $5, tags = $6,
+            price_modifier = $7, updated_at = CURRENT_TIMESTAMP
+        WHERE id = $8 AND deleted_at IS NULL
+    `, item.Value, item.Label, item.Type, item.Active, item.Description, tags, item.PriceModifier, id)
 
 	if err != nil {
 		return err
@@ -112,11 +150,12 @@ func (r *MenuRepository) Update(ctx context.Context, id int, item *models.MenuIt
 	return nil
 }
 
-// Delete removes a menu item
+// Delete marks a menu item as deleted. The purge job removes it later.
 func (r *MenuRepository) Delete(ctx context.Context, id int) error {
 	tag, err := r.db.Pool.Exec(ctx, `
-        DELETE FROM menu_items
-        WHERE id = $1
+        UPDATE menu_items
+        SET deleted_at = CURRENT_TIMESTAMP
+        WHERE id = $1 AND deleted_at IS NULL
     `, id)
 
 	if err != nil {
@@ -130,3 +169,67 @@ func (r *MenuRepository) Delete(ctx context.Context, id int) error {
 
 	return nil
 }
+
+// Reorder puts the listed menu items of a type first, in the order given,
+// followed by the rest of the type in their current order. An ID that isn't
+// a menu item of the type returns ErrMenuItemNotFound and changes nothing.
+func (r *MenuRepository) Reorder(ctx context.Context, itemType models.ItemType, ids []int) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	for i, id := range ids {
+		tag, err := tx.Exec(ctx, `
+            UPDATE menu_items
+            SET display_order = $1
+            WHERE id = $2 AND type = $3 AND deleted_at IS NULL
+        `, i, id, string(itemType))
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return ErrMenuItemNotFound
+		}
+	}
+
+	_, err = tx.Exec(ctx, `
+        WITH rest AS (
+            SELECT id, ROW_NUMBER() OVER (ORDER BY display_order, label) AS row_num
+            FROM menu_items
+            WHERE type = $1 AND deleted_at IS NULL AND NOT (id = ANY($2))
+        )
+        UPDATE menu_items
+        SET display_order = $3 + rest.row_num - 1
+        FROM rest
+        WHERE menu_items.id = rest.id
+    `, string(itemType), ids, len(ids))
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// SetImage stores the key of a menu item's image, or removes it when key is
+// empty, and returns the key it replaced so its file can be deleted
+func (r *MenuRepository) SetImage(ctx context.Context, id int, key string) (string, error) {
+	var previous string
+	err := r.db.Pool.QueryRow(ctx, `
+        UPDATE menu_items
+        SET image_key = NULLIF($2, ''), updated_at = CURRENT_TIMESTAMP
+        FROM (
+            SELECT id, image_key FROM menu_items
+            WHERE id = $1 AND deleted_at IS NULL
+            FOR UPDATE
+        ) old
+        WHERE menu_items.id = old.id
+        RETURNING COALESCE(old.image_key, '')
+    `, id, key).Scan(&previous)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", ErrMenuItemNotFound
+	}
+
+	return previous, err
+}
@@ -42,6 +42,23 @@ func setupMenuTestDB(t *testing.T) *TestDB {
 		t.Fatalf("Failed to create test table: %v", err)
 	}
 
+	_, err = pool.Exec(context.Background(), `ALTER TABLE menu_items ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE`)
+	if err != nil {
+		t.Fatalf("Failed to add deleted_at column: %v", err)
+	}
+
+	_, err = pool.Exec(context.Background(), `
+    ALTER TABLE menu_items
+        ADD COLUMN IF NOT EXISTS display_order INT NOT NULL DEFAULT 0,
+        ADD COLUMN IF NOT EXISTS description TEXT NOT NULL DEFAULT '',
+        ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}',
+        ADD COLUMN IF NOT EXISTS price_modifier NUMERIC(6,2) NOT NULL DEFAULT 0,
+        ADD COLUMN IF NOT EXISTS image_key VARCHAR(255)
+    `)
+	if err != nil {
+		t.Fatalf("Failed to add menu item detail columns: %v", err)
+	}
+
 	return &TestDB{Pool: pool}
 }
 
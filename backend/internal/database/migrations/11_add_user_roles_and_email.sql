@@ -0,0 +1,6 @@
+-- Contact email for admin users
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email VARCHAR(255);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP;
+
+-- The single "admin" role is replaced by the owner role of the permission matrix
+UPDATE users SET role = 'owner' WHERE role = 'admin';
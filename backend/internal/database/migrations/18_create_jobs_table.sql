@@ -0,0 +1,13 @@
+-- One row per scheduled job. An instance runs a job only after claiming its
+-- lock, so each scheduled run happens once across all instances; a lock
+-- left by a crashed instance lapses at locked_until.
+CREATE TABLE IF NOT EXISTS jobs (
+    name VARCHAR(100) PRIMARY KEY,
+    last_run_at TIMESTAMP WITH TIME ZONE,
+    last_duration_ms BIGINT,
+    last_result TEXT NOT NULL DEFAULT '',
+    last_error TEXT NOT NULL DEFAULT '',
+    locked_by VARCHAR(255),
+    locked_until TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,7 @@
+-- Deleted menu items and packages are kept until the purge job removes them
+ALTER TABLE menu_items ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE packages ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+
+-- Set when the retention job has removed a booking's personal details
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS anonymized_at TIMESTAMP WITH TIME ZONE;
+CREATE INDEX IF NOT EXISTS idx_bookings_date ON bookings(date);
@@ -0,0 +1,19 @@
+-- Ingredients kept in stock, each measured in a single unit (g, ml, each, ...)
+CREATE TABLE IF NOT EXISTS ingredients (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    unit VARCHAR(20) NOT NULL,
+    stock NUMERIC(12, 3) NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Recipes: the quantity of each ingredient in one serving of a menu item
+CREATE TABLE IF NOT EXISTS recipe_ingredients (
+    menu_item_id INTEGER NOT NULL REFERENCES menu_items(id) ON DELETE CASCADE,
+    ingredient_id INTEGER NOT NULL REFERENCES ingredients(id) ON DELETE CASCADE,
+    quantity NUMERIC(12, 3) NOT NULL CHECK (quantity > 0),
+    PRIMARY KEY (menu_item_id, ingredient_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_recipe_ingredients_ingredient_id ON recipe_ingredients(ingredient_id);
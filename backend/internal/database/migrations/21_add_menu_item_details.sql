@@ -0,0 +1,26 @@
+ALTER TABLE menu_items
+    ADD COLUMN IF NOT EXISTS display_order INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS description TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS price_modifier NUMERIC(6,2) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS image_key VARCHAR(255);
+
+-- Keep the current alphabetical order within each type
+WITH ordered_items AS (
+  SELECT id, ROW_NUMBER() OVER (PARTITION BY type ORDER BY label) AS row_num
+  FROM menu_items
+)
+UPDATE menu_items
+SET display_order = oi.row_num - 1
+FROM ordered_items oi
+WHERE menu_items.id = oi.id;
+
+-- Dietary tags for the default milk options
+UPDATE menu_items SET tags = ARRAY['nut_free']
+WHERE type = 'milk_option' AND value IN ('whole', 'half_and_half') AND tags = '{}';
+
+UPDATE menu_items SET tags = ARRAY['dairy_free']
+WHERE type = 'milk_option' AND value = 'almond' AND tags = '{}';
+
+UPDATE menu_items SET tags = ARRAY['dairy_free', 'nut_free']
+WHERE type = 'milk_option' AND value IN ('oat', 'rice') AND tags = '{}';
@@ -0,0 +1,2 @@
+-- Webhooks only receive customer names, contact details, locations and notes when opted in
+ALTER TABLE webhooks ADD COLUMN IF NOT EXISTS include_pii BOOLEAN NOT NULL DEFAULT false;
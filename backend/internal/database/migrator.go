@@ -3,13 +3,23 @@ package database
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
 )
 
+const migrationDir = "internal/database/migrations"
+
+// schema_migrations records every migration file that has been applied, so
+// each runs once and readiness can tell whether the schema is current
+const createSchemaMigrationsTable = `
+CREATE TABLE IF NOT EXISTS schema_migrations (
+    version VARCHAR(255) PRIMARY KEY,
+    applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+)`
+
 type Migrator struct {
 	db *DB
 }
@@ -19,26 +29,67 @@ func NewMigrator(db *DB) *Migrator {
 }
 
 func (m *Migrator) RunMigrations() error {
-	log.Println("Running database migrations...")
+	ctx := context.Background()
+	slog.InfoContext(ctx, "running database migrations")
 
-	migrationFiles, err := m.getMigrationFiles()
+	if _, err := m.db.Pool.Exec(ctx, createSchemaMigrationsTable); err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	pending, err := m.Pending(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to get migration files: %w", err)
+		return err
 	}
 
-	for _, file := range migrationFiles {
-		if err := m.runMigration(file); err != nil {
+	for _, file := range pending {
+		if err := m.runMigration(ctx, file); err != nil {
 			return fmt.Errorf("failed to run migration %s: %w", file, err)
 		}
+		if _, err := m.db.Pool.Exec(ctx,
+			"INSERT INTO schema_migrations (version) VALUES ($1) ON CONFLICT DO NOTHING", file); err != nil {
+			return fmt.Errorf("failed to record migration %s: %w", file, err)
+		}
 	}
 
-	log.Println("All migrations completed successfully")
+	slog.InfoContext(ctx, "migrations completed", "applied", len(pending))
 	return nil
 }
 
-func (m *Migrator) getMigrationFiles() ([]string, error) {
-	migrationDir := "internal/database/migrations"
+// Pending returns the migration files that have not been applied yet, in order
+func (m *Migrator) Pending(ctx context.Context) ([]string, error) {
+	migrationFiles, err := m.getMigrationFiles()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get migration files: %w", err)
+	}
 
+	rows, err := m.db.Pool.Query(ctx, "SELECT version FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := map[string]bool{}
+	for rows.Next() {
+		var version string
+		if err := rows.Scan(&version); err != nil {
+			return nil, fmt.Errorf("failed to read applied migrations: %w", err)
+		}
+		applied[version] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+
+	var pending []string
+	for _, file := range migrationFiles {
+		if !applied[file] {
+			pending = append(pending, file)
+		}
+	}
+	return pending, nil
+}
+
+func (m *Migrator) getMigrationFiles() ([]string, error) {
 	entries, err := os.ReadDir(migrationDir)
 	if err != nil {
 		return nil, err
@@ -55,25 +106,27 @@ func (m *Migrator) getMigrationFiles() ([]string, error) {
 	return files, nil
 }
 
-func (m *Migrator) runMigration(filename string) error {
-	migrationPath := filepath.Join("internal/database/migrations", filename)
+func (m *Migrator) runMigration(ctx context.Context, filename string) error {
+	migrationPath := filepath.Join(migrationDir, filename)
 
 	migrationSQL, err := os.ReadFile(migrationPath)
 	if err != nil {
-		log.Printf("Warning: Could not read migration file %s: %v", filename, err)
+		slog.WarnContext(ctx, "could not read migration file", "file", filename, "error", err)
 		return nil // Non-fatal for missing files
 	}
 
-	_, err = m.db.Pool.Exec(context.Background(), string(migrationSQL))
+	_, err = m.db.Pool.Exec(ctx, string(migrationSQL))
 	if err != nil {
+		// Databases created before schema_migrations existed re-run each
+		// file once; those already applied fail with these errors
 		if m.isMigrationAlreadyApplied(err) {
-			log.Printf("Migration %s already applied, skipping", filename)
+			slog.InfoContext(ctx, "migration already applied, skipping", "file", filename)
 			return nil
 		}
 		return err
 	}
 
-	log.Printf("Migration %s executed successfully", filename)
+	slog.InfoContext(ctx, "migration applied", "file", filename)
 	return nil
 }
 
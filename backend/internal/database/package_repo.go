@@ -30,10 +30,11 @@ func (r *packageRepository) GetAll(ctx context.Context, includeInactive bool) ([
 	query := `
         SELECT p.id, p.name, p.price, p.description, p.display_order, p.active, p.created_at, p.updated_at
         FROM packages p
+        WHERE p.deleted_at IS NULL
     `
 
 	if !includeInactive {
-		query += " WHERE p.active = true"
+		query += " AND p.active = true"
 	}
 
 	query += " ORDER BY p.display_order, p.name"
@@ -92,7 +93,7 @@ func (r *packageRepository) GetByID(ctx context.Context, id int) (*models.Packag
 	query := `
         SELECT id, name, price, description, display_order, active, created_at, updated_at
         FROM packages
-        WHERE id = $1
+        WHERE id = $1 AND deleted_at IS NULL
     `
 
 	var pkg models.Package
@@ -183,7 +184,7 @@ func (r *packageRepository) Update(ctx context.Context, id int, input *models.Pa
 	_, err = tx.Exec(ctx, `
         UPDATE packages
         SET name = $1, price = $2, description = $3, display_order = $4, active = $5, updated_at = $6
-        WHERE id = $7
+        WHERE id = $7 AND deleted_at IS NULL
     `, input.Name, input.Price, input.Description, input.DisplayOrder, input.Active, time.Now(), id)
 	if err != nil {
 		return err
@@ -209,8 +210,8 @@ func (r *packageRepository) Update(ctx context.Context, id int, input *models.Pa
 	return tx.Commit(ctx)
 }
 
-// Delete removes a package
+// Delete marks a package as deleted. The purge job removes it later.
 func (r *packageRepository) Delete(ctx context.Context, id int) error {
-	_, err := r.db.Pool.Exec(ctx, `DELETE FROM packages WHERE id = $1`, id)
+	_, err := r.db.Pool.Exec(ctx, `UPDATE packages SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL`, time.Now(), id)
 	return err
 }
@@ -0,0 +1,103 @@
+package database
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
+)
+
+// ErrResetTokenInvalid is returned for unknown, expired or already used reset tokens
+var ErrResetTokenInvalid = errors.New("password reset token invalid or expired")
+
+// PasswordResetRepository handles persistence of password reset tokens
+type PasswordResetRepository struct {
+	db *DB
+}
+
+// NewPasswordResetRepository creates a new password reset repository
+func NewPasswordResetRepository(db *DB) PasswordResetRepositoryInterface {
+	return &PasswordResetRepository{db: db}
+}
+
+// Create stores a new reset token. Any earlier unused tokens for the same
+// user are invalidated so only the most recent email works.
+func (r *PasswordResetRepository) Create(ctx context.Context, token *models.PasswordResetToken) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `
+        UPDATE password_reset_tokens SET used_at = CURRENT_TIMESTAMP
+        WHERE user_id = $1 AND used_at IS NULL
+    `, token.UserID); err != nil {
+		return err
+	}
+
+	err = tx.QueryRow(ctx, `
+        INSERT INTO password_reset_tokens (user_id, token_hash, expires_at)
+        VALUES ($1, $2, $3)
+        RETURNING id, created_at
+    `, token.UserID, token.TokenHash, token.ExpiresAt).Scan(&token.ID, &token.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetByTokenHash looks up a reset token by its hash
+func (r *PasswordResetRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*models.PasswordResetToken, error) {
+	token := &models.PasswordResetToken{}
+	err := r.db.Pool.QueryRow(ctx, `
+        SELECT id, user_id, token_hash, created_at, expires_at, used_at
+        FROM password_reset_tokens
+        WHERE token_hash = $1
+    `, tokenHash).Scan(&token.ID, &token.UserID, &token.TokenHash, &token.CreatedAt, &token.ExpiresAt, &token.UsedAt)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrResetTokenInvalid
+		}
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// Consume marks the token used and sets the new password hash in one
+// transaction. The account's failed login counter and lock are cleared too.
+func (r *PasswordResetRepository) Consume(ctx context.Context, id int, passwordHash string) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var userID int
+	err = tx.QueryRow(ctx, `
+        UPDATE password_reset_tokens SET used_at = CURRENT_TIMESTAMP
+        WHERE id = $1 AND used_at IS NULL AND expires_at > CURRENT_TIMESTAMP
+        RETURNING user_id
+    `, id).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrResetTokenInvalid
+		}
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `
+        UPDATE users
+        SET password = $2, failed_login_attempts = 0, last_failed_login_at = NULL, locked_until = NULL,
+            updated_at = CURRENT_TIMESTAMP
+        WHERE id = $1
+    `, userID, passwordHash); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
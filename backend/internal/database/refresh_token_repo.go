@@ -0,0 +1,159 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
+)
+
+// Predefined refresh token repository errors
+var (
+	ErrRefreshTokenNotFound = errors.New("refresh token not found")
+	ErrRefreshTokenReused   = errors.New("refresh token already used or revoked")
+)
+
+// RefreshTokenRepository handles persistence of issued refresh tokens
+type RefreshTokenRepository struct {
+	db *DB
+}
+
+// NewRefreshTokenRepository creates a new refresh token repository
+func NewRefreshTokenRepository(db *DB) RefreshTokenRepositoryInterface {
+	return &RefreshTokenRepository{db: db}
+}
+
+const refreshTokenColumns = `id, jti_hash, family_id::text, user_id, COALESCE(device, ''),
+               created_at, expires_at, revoked_at, replaced_by`
+
+func scanRefreshToken(row pgx.Row) (*models.RefreshToken, error) {
+	token := &models.RefreshToken{}
+	err := row.Scan(&token.ID, &token.JTIHash, &token.FamilyID, &token.UserID, &token.Device,
+		&token.CreatedAt, &token.ExpiresAt, &token.RevokedAt, &token.ReplacedBy)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrRefreshTokenNotFound
+		}
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// Create stores a newly issued refresh token
+func (r *RefreshTokenRepository) Create(ctx context.Context, token *models.RefreshToken) error {
+	return r.db.Pool.QueryRow(ctx, `
+        INSERT INTO refresh_tokens (jti_hash, family_id, user_id, device, expires_at)
+        VALUES ($1, $2, $3, NULLIF($4, ''), $5)
+        RETURNING id, created_at
+    `, token.JTIHash, token.FamilyID, token.UserID, token.Device, token.ExpiresAt).Scan(&token.ID, &token.CreatedAt)
+}
+
+// GetByJTIHash looks up a refresh token by the hash of its jti claim
+func (r *RefreshTokenRepository) GetByJTIHash(ctx context.Context, jtiHash string) (*models.RefreshToken, error) {
+	return scanRefreshToken(r.db.Pool.QueryRow(ctx, `
+        SELECT `+refreshTokenColumns+`
+        FROM refresh_tokens
+        WHERE jti_hash = $1
+    `, jtiHash))
+}
+
+// Rotate revokes the old token and stores its replacement in one transaction.
+// If the old token was already revoked (a concurrent or replayed refresh)
+// ErrRefreshTokenReused is returned and nothing is stored.
+func (r *RefreshTokenRepository) Rotate(ctx context.Context, oldID int, next *models.RefreshToken) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, `
+        INSERT INTO refresh_tokens (jti_hash, family_id, user_id, device, expires_at)
+        VALUES ($1, $2, $3, NULLIF($4, ''), $5)
+        RETURNING id, created_at
+    `, next.JTIHash, next.FamilyID, next.UserID, next.Device, next.ExpiresAt).Scan(&next.ID, &next.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	commandTag, err := tx.Exec(ctx, `
+        UPDATE refresh_tokens
+        SET revoked_at = CURRENT_TIMESTAMP, replaced_by = $1
+        WHERE id = $2 AND revoked_at IS NULL
+    `, next.ID, oldID)
+	if err != nil {
+		return err
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return ErrRefreshTokenReused
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Revoke revokes a single refresh token
+func (r *RefreshTokenRepository) Revoke(ctx context.Context, id int) error {
+	_, err := r.db.Pool.Exec(ctx, `
+        UPDATE refresh_tokens
+        SET revoked_at = CURRENT_TIMESTAMP
+        WHERE id = $1 AND revoked_at IS NULL
+    `, id)
+	return err
+}
+
+// RevokeFamily revokes every token descended from the same login
+func (r *RefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	_, err := r.db.Pool.Exec(ctx, `
+        UPDATE refresh_tokens
+        SET revoked_at = CURRENT_TIMESTAMP
+        WHERE family_id = $1 AND revoked_at IS NULL
+    `, familyID)
+	return err
+}
+
+// RevokeAllForUser revokes every active refresh token of a user and returns how many were revoked
+func (r *RefreshTokenRepository) RevokeAllForUser(ctx context.Context, userID int) (int64, error) {
+	commandTag, err := r.db.Pool.Exec(ctx, `
+        UPDATE refresh_tokens
+        SET revoked_at = CURRENT_TIMESTAMP
+        WHERE user_id = $1 AND revoked_at IS NULL
+    `, userID)
+	if err != nil {
+		return 0, err
+	}
+	return commandTag.RowsAffected(), nil
+}
+
+// GetActiveForUser lists the unexpired, unrevoked sessions of a user
+func (r *RefreshTokenRepository) GetActiveForUser(ctx context.Context, userID int) ([]*models.RefreshToken, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+        SELECT `+refreshTokenColumns+`
+        FROM refresh_tokens
+        WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > CURRENT_TIMESTAMP
+        ORDER BY created_at DESC
+    `, userID)
+	if err != nil {
+		return nil, fmt.Errorf("database query error: %w", err)
+	}
+	defer rows.Close()
+
+	tokens := []*models.RefreshToken{}
+	for rows.Next() {
+		token, err := scanRefreshToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, token)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return tokens, nil
+}
@@ -0,0 +1,181 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
+)
+
+// ReportRepository runs the aggregate queries behind the admin reports. Every
+// report covers the bookings whose event date is in the given range.
+type ReportRepository struct {
+	db *DB
+}
+
+// NewReportRepository creates a new report repository
+func NewReportRepository(db *DB) ReportRepositoryInterface {
+	return &ReportRepository{db: db}
+}
+
+// Volume counts bookings per week or month of rng, which must be
+// models.ReportIntervalWeek or models.ReportIntervalMonth
+func (r *ReportRepository) Volume(ctx context.Context, interval string, rng models.ReportRange) (*models.VolumeReport, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+        SELECT p.start::date,
+               COUNT(b.id),
+               COALESCE(SUM(b.people) FILTER (WHERE b.status <> $4), 0),
+               COUNT(b.id) FILTER (WHERE b.status = $4)
+        FROM generate_series(date_trunc($1, $2::date::timestamp), $3::date::timestamp, ('1 ' || $1)::interval) AS p(start)
+        LEFT JOIN bookings b ON b.date >= p.start AND b.date < p.start + ('1 ' || $1)::interval
+                            AND b.date BETWEEN $2::date AND $3::date
+        GROUP BY p.start
+        ORDER BY p.start
+    `, interval, rng.From, rng.To, models.BookingStatusCanceled)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	report := &models.VolumeReport{ReportRange: rng, Interval: interval, Periods: []models.VolumePeriod{}}
+	for rows.Next() {
+		var period models.VolumePeriod
+		var start time.Time
+		if err := rows.Scan(&start, &period.Bookings, &period.Headcount, &period.Canceled); err != nil {
+			return nil, err
+		}
+		period.Start = start.Format("2006-01-02")
+		period.CancellationRate = models.Rate(period.Canceled, period.Bookings)
+		report.Periods = append(report.Periods, period)
+	}
+	return report, rows.Err()
+}
+
+// Revenue estimates revenue by package. A booking is priced at the first
+// amount in its package's current price, times its headcount when the price
+// is per person, guest or head.
+func (r *ReportRepository) Revenue(ctx context.Context, rng models.ReportRange) (*models.RevenueReport, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+        SELECT COALESCE(b.package, ''),
+               COUNT(*),
+               COALESCE(SUM(b.people), 0),
+               COALESCE(SUM(CASE WHEN p.per_person THEN p.amount * b.people ELSE p.amount END), 0)::float8,
+               bool_and(p.amount IS NOT NULL)
+        FROM bookings b
+        LEFT JOIN LATERAL (
+            SELECT substring(replace(price, ',', '') FROM '[0-9]+(?:\.[0-9]+)?')::numeric AS amount,
+                   price ~* '(per|/)\s*(person|guest|head)' AS per_person
+            FROM packages
+            WHERE lower(name) = lower(b.package)
+            ORDER BY deleted_at IS NOT NULL, id DESC
+            LIMIT 1
+        ) p ON true
+        WHERE b.date BETWEEN $1::date AND $2::date AND b.status <> $3
+        GROUP BY 1
+        ORDER BY 4 DESC, 1
+    `, rng.From, rng.To, models.BookingStatusCanceled)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	report := &models.RevenueReport{ReportRange: rng, Packages: []models.PackageRevenue{}}
+	for rows.Next() {
+		var pkg models.PackageRevenue
+		if err := rows.Scan(&pkg.Package, &pkg.Bookings, &pkg.Headcount, &pkg.Revenue, &pkg.Priced); err != nil {
+			return nil, err
+		}
+		report.Total += pkg.Revenue
+		report.Packages = append(report.Packages, pkg)
+	}
+	return report, rows.Err()
+}
+
+// Menu ranks coffee flavors and milk options by the bookings choosing them
+func (r *ReportRepository) Menu(ctx context.Context, rng models.ReportRange) (*models.MenuReport, error) {
+	report := &models.MenuReport{ReportRange: rng}
+	err := r.db.Pool.QueryRow(ctx, `
+        SELECT COUNT(*) FROM bookings
+        WHERE date BETWEEN $1::date AND $2::date AND status <> $3
+    `, rng.From, rng.To, models.BookingStatusCanceled).Scan(&report.Bookings)
+	if err != nil {
+		return nil, err
+	}
+
+	if report.CoffeeFlavors, err = r.popularity(ctx, "coffee_flavors", rng, report.Bookings); err != nil {
+		return nil, err
+	}
+	if report.MilkOptions, err = r.popularity(ctx, "milk_options", rng, report.Bookings); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// popularity counts the bookings choosing each value of the array column
+func (r *ReportRepository) popularity(ctx context.Context, column string, rng models.ReportRange, total int) ([]models.Popularity, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+        SELECT v.value, COUNT(DISTINCT b.id)
+        FROM bookings b, unnest(b.`+column+`) AS v(value)
+        WHERE b.date BETWEEN $1::date AND $2::date AND b.status <> $3
+        GROUP BY v.value
+        ORDER BY 2 DESC, 1
+    `, rng.From, rng.To, models.BookingStatusCanceled)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	values := []models.Popularity{}
+	for rows.Next() {
+		var p models.Popularity
+		if err := rows.Scan(&p.Value, &p.Bookings); err != nil {
+			return nil, err
+		}
+		p.Share = models.Rate(p.Bookings, total)
+		values = append(values, p)
+	}
+	return values, rows.Err()
+}
+
+// Summary totals the range. Lead times leave out bookings created after
+// their event, which are imports of past events.
+func (r *ReportRepository) Summary(ctx context.Context, rng models.ReportRange) (*models.SummaryReport, error) {
+	report := &models.SummaryReport{ReportRange: rng}
+	err := r.db.Pool.QueryRow(ctx, `
+        SELECT COUNT(*),
+               COUNT(*) FILTER (WHERE status = $3),
+               COALESCE(SUM(people) FILTER (WHERE status <> $3), 0),
+               COUNT(*) FILTER (WHERE status <> $3 AND is_outdoor),
+               COUNT(*) FILTER (WHERE status <> $3 AND is_outdoor AND has_shade)
+        FROM bookings
+        WHERE date BETWEEN $1::date AND $2::date
+    `, rng.From, rng.To, models.BookingStatusCanceled).Scan(
+		&report.Bookings, &report.Canceled, &report.Headcount, &report.Outdoor, &report.OutdoorWithShade,
+	)
+	if err != nil {
+		return nil, err
+	}
+	report.CancellationRate = models.Rate(report.Canceled, report.Bookings)
+	report.Indoor = report.Bookings - report.Canceled - report.Outdoor
+
+	lead := &report.LeadTime
+	err = r.db.Pool.QueryRow(ctx, `
+        SELECT COUNT(*),
+               COALESCE(AVG(days), 0)::float8,
+               COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY days), 0),
+               COALESCE(percentile_cont(0.9) WITHIN GROUP (ORDER BY days), 0),
+               COALESCE(MIN(days), 0),
+               COALESCE(MAX(days), 0)
+        FROM (
+            SELECT date - created_at::date AS days
+            FROM bookings
+            WHERE date BETWEEN $1::date AND $2::date AND status <> $3 AND created_at::date <= date
+        ) t
+    `, rng.From, rng.To, models.BookingStatusCanceled).Scan(
+		&lead.Bookings, &lead.AverageDays, &lead.MedianDays, &lead.P90Days, &lead.MinDays, &lead.MaxDays,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
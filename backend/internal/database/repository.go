@@ -2,32 +2,136 @@ package database
 
 import (
 	"context"
+	"time"
 
 	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
 )
 
 type Repositories struct {
-	Booking BookingRepositoryInterface
-	User    UserRepositoryInterface
-	Menu    MenuRepositoryInterface
-	Package PackageRepositoryInterface
+	Booking      BookingRepositoryInterface
+	BookingStats BookingStatsRepositoryInterface
+	User         UserRepositoryInterface
+	Menu         MenuRepositoryInterface
+	Package      PackageRepositoryInterface
+	Refresh      RefreshTokenRepositoryInterface
+	TwoFactor    TwoFactorRepositoryInterface
+	Reset        PasswordResetRepositoryInterface
+	Webhook      WebhookRepositoryInterface
+	Job          JobRepositoryInterface
+	Maintenance  MaintenanceRepositoryInterface
+	Report       ReportRepositoryInterface
+	Inventory    InventoryRepositoryInterface
 }
 
 // BookingRepositoryInterface defines the methods for booking operations
 type BookingRepositoryInterface interface {
 	Create(ctx context.Context, booking *models.Booking) (int, error)
 	GetByID(ctx context.Context, id int) (*models.Booking, error)
-	GetAll(ctx context.Context, includeArchived bool) ([]*models.Booking, error)
+	GetAll(ctx context.Context, filter *models.BookingFilter) ([]*models.Booking, error)
 	Delete(ctx context.Context, id int) error
 	Update(ctx context.Context, id int, booking *models.Booking) error
 	Archive(ctx context.Context, id int) error
 	Unarchive(ctx context.Context, id int) error
+	Bulk(ctx context.Context, req *models.BulkBookingRequest) ([]models.BulkBookingResult, error)
+	Stream(ctx context.Context, filter *models.BookingFilter, fn func(*models.Booking) error) error
+	Import(ctx context.Context, bookings []*models.Booking, dryRun bool) ([]models.BookingImportResult, error)
+}
+
+// BookingStatsRepositoryInterface defines the aggregate booking counts
+// exported as metrics
+type BookingStatsRepositoryInterface interface {
+	CountCreatedSince(ctx context.Context, since time.Time) (int, error)
+	CountUpcoming(ctx context.Context, from time.Time) (int, error)
 }
 
 // UserRepositoryInterface defines the methods for user operations
 type UserRepositoryInterface interface {
 	GetByID(ctx context.Context, id int) (*models.User, error)
 	GetByUsername(ctx context.Context, username string) (*models.User, error)
+	GetAll(ctx context.Context) ([]*models.User, error)
+	Count(ctx context.Context) (int, error)
+	Create(ctx context.Context, user *models.User) (int, error)
+	CreateFirst(ctx context.Context, user *models.User) (int, error)
+	Update(ctx context.Context, id int, user *models.User) error
+	RecordFailedLogin(ctx context.Context, id int, lockAfter int, lockUntil time.Time) (attempts int, locked bool, err error)
+	ResetLoginFailures(ctx context.Context, id int) error
+	UpdatePassword(ctx context.Context, id int, passwordHash string) error
+	Delete(ctx context.Context, id int) error
+}
+
+// RefreshTokenRepositoryInterface defines the methods for refresh token persistence
+type RefreshTokenRepositoryInterface interface {
+	Create(ctx context.Context, token *models.RefreshToken) error
+	GetByJTIHash(ctx context.Context, jtiHash string) (*models.RefreshToken, error)
+	Rotate(ctx context.Context, oldID int, next *models.RefreshToken) error
+	Revoke(ctx context.Context, id int) error
+	RevokeFamily(ctx context.Context, familyID string) error
+	RevokeAllForUser(ctx context.Context, userID int) (int64, error)
+	GetActiveForUser(ctx context.Context, userID int) ([]*models.RefreshToken, error)
+}
+
+// TwoFactorRepositoryInterface defines the methods for TOTP and recovery code persistence
+type TwoFactorRepositoryInterface interface {
+	BeginEnrollment(ctx context.Context, userID int, secret string) error
+	Enable(ctx context.Context, userID int, step int64, codeHashes []string) error
+	Disable(ctx context.Context, userID int) error
+	RecordStep(ctx context.Context, userID int, step int64) error
+	UseRecoveryCode(ctx context.Context, userID int, codeHash string) error
+	ReplaceRecoveryCodes(ctx context.Context, userID int, codeHashes []string) error
+	CountRecoveryCodes(ctx context.Context, userID int) (int, error)
+}
+
+// PasswordResetRepositoryInterface defines the methods for password reset token persistence
+type PasswordResetRepositoryInterface interface {
+	Create(ctx context.Context, token *models.PasswordResetToken) error
+	GetByTokenHash(ctx context.Context, tokenHash string) (*models.PasswordResetToken, error)
+	Consume(ctx context.Context, id int, passwordHash string) error
+}
+
+// WebhookRepositoryInterface defines the methods for webhook subscriptions and deliveries
+type WebhookRepositoryInterface interface {
+	GetAll(ctx context.Context) ([]*models.Webhook, error)
+	GetActiveForEvent(ctx context.Context, event string) ([]*models.Webhook, error)
+	GetByID(ctx context.Context, id int) (*models.Webhook, error)
+	Create(ctx context.Context, hook *models.Webhook) error
+	Update(ctx context.Context, id int, hook *models.Webhook) error
+	Delete(ctx context.Context, id int) error
+	LogDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+	GetDeliveries(ctx context.Context, webhookID int, limit int) ([]*models.WebhookDelivery, error)
+}
+
+// JobRepositoryInterface defines the methods for scheduled job state and locking
+type JobRepositoryInterface interface {
+	Ensure(ctx context.Context, name string) error
+	Get(ctx context.Context, name string) (*models.JobState, error)
+	Claim(ctx context.Context, name, owner string, lastRunAt *time.Time, lease time.Duration) (bool, error)
+	Finish(ctx context.Context, name, owner string, run *models.JobRun) error
+}
+
+// MaintenanceRepositoryInterface defines the clean-up operations run by scheduled jobs
+type MaintenanceRepositoryInterface interface {
+	ArchivePastBookings(ctx context.Context, before time.Time) ([]*models.Booking, error)
+	PurgeDeleted(ctx context.Context, before time.Time) (purged int64, imageKeys []string, err error)
+	DeleteExpiredTokens(ctx context.Context) (int64, error)
+	AnonymizeBookings(ctx context.Context, before time.Time) (int64, error)
+}
+
+// InventoryRepositoryInterface defines the methods for ingredients and recipes
+type InventoryRepositoryInterface interface {
+	GetIngredients(ctx context.Context) ([]*models.Ingredient, error)
+	CreateIngredient(ctx context.Context, input *models.IngredientInput) (*models.Ingredient, error)
+	UpdateIngredient(ctx context.Context, id int, input *models.IngredientInput) (*models.Ingredient, error)
+	DeleteIngredient(ctx context.Context, id int) error
+	GetRecipes(ctx context.Context) ([]*models.Recipe, error)
+	SetRecipe(ctx context.Context, menuItemID int, ingredients []models.RecipeIngredient) error
+}
+
+// ReportRepositoryInterface defines the aggregate queries behind the reports
+type ReportRepositoryInterface interface {
+	Volume(ctx context.Context, interval string, rng models.ReportRange) (*models.VolumeReport, error)
+	Revenue(ctx context.Context, rng models.ReportRange) (*models.RevenueReport, error)
+	Menu(ctx context.Context, rng models.ReportRange) (*models.MenuReport, error)
+	Summary(ctx context.Context, rng models.ReportRange) (*models.SummaryReport, error)
 }
 
 // MenuRespositoryInterface defines the methods for menu operations
@@ -37,6 +141,9 @@ type MenuRepositoryInterface interface {
 	Create(ctx context.Context, item *models.MenuItem) (int, error)
 	Update(ctx context.Context, id int, item *models.MenuItem) error
 	Delete(ctx context.Context, id int) error
+	GetByID(ctx context.Context, id int) (*models.MenuItem, error)
+	Reorder(ctx context.Context, itemType models.ItemType, ids []int) error
+	SetImage(ctx context.Context, id int, key string) (string, error)
 }
 
 // PackageRepositoryInterface defines the methods for package operations
@@ -51,9 +158,18 @@ type PackageRepositoryInterface interface {
 // NewRepositories creates all repositories
 func NewRepositories(db *DB) *Repositories {
 	return &Repositories{
-		Booking: NewBookingRepository(db),
-		User:    NewUserRepository(db),
-		Menu:    NewMenuRepository(db),
-		Package: NewPackageRepository(db),
+		Booking:      NewBookingRepository(db),
+		BookingStats: NewBookingStatsRepository(db),
+		User:         NewUserRepository(db),
+		Menu:         NewMenuRepository(db),
+		Package:      NewPackageRepository(db),
+		Refresh:      NewRefreshTokenRepository(db),
+		TwoFactor:    NewTwoFactorRepository(db),
+		Reset:        NewPasswordResetRepository(db),
+		Webhook:      NewWebhookRepository(db),
+		Job:          NewJobRepository(db),
+		Maintenance:  NewMaintenanceRepository(db),
+		Report:       NewReportRepository(db),
+		Inventory:    NewInventoryRepository(db),
 	}
 }
@@ -2,8 +2,10 @@ package database
 
 import (
 	"context"
-	"log"
+	"fmt"
+	"log/slog"
 
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -15,37 +17,46 @@ func NewSeeder(db *DB) *Seeder {
 	return &Seeder{db: db}
 }
 
-func (s *Seeder) SeedAdminUser() error {
-	log.Println("Setting up admin user...")
-
-	var count int
-	err := s.db.Pool.QueryRow(context.Background(), `
-        SELECT COUNT(*) FROM users WHERE username = $1
-    `, "admin").Scan(&count)
+// BootstrapAdmin prepares the first admin account on a fresh database.
+// If users already exist nothing happens. If an initial password hash is
+// configured the admin is created from it. Otherwise setupRequired is true
+// and the caller must offer the one-time setup endpoint instead.
+func (s *Seeder) BootstrapAdmin(username, passwordHash string) (setupRequired bool, err error) {
+	ctx := context.Background()
+	users := NewUserRepository(s.db)
 
+	count, err := users.Count(ctx)
 	if err != nil {
-		log.Printf("Warning: Failed to check for admin user: %v", err)
-		return err
+		return false, fmt.Errorf("failed to count users: %w", err)
 	}
 
-	if count == 0 {
-		hashedPassword, err := bcrypt.GenerateFromPassword([]byte("admin"), bcrypt.DefaultCost)
-		if err != nil {
-			return err
-		}
+	if count > 0 {
+		slog.DebugContext(ctx, "users already exist, skipping admin bootstrap")
+		return false, nil
+	}
 
-		_, err = s.db.Pool.Exec(context.Background(), `
-            INSERT INTO users (username, password, role) VALUES ($1, $2, $3)
-        `, "admin", string(hashedPassword), "admin")
+	if passwordHash == "" {
+		slog.InfoContext(ctx, "no users exist and no initial admin password hash is configured")
+		return true, nil
+	}
 
-		if err != nil {
-			return err
-		}
+	if _, err := bcrypt.Cost([]byte(passwordHash)); err != nil {
+		return false, fmt.Errorf("initial admin password hash is not a valid bcrypt hash: %w", err)
+	}
 
-		log.Println("Admin user created successfully")
-	} else {
-		log.Println("Admin user already exists, skipping creation")
+	_, err = users.CreateFirst(ctx, &models.User{
+		Username: username,
+		Password: passwordHash,
+		Role:     "owner",
+	})
+	if err == ErrUsersExist {
+		slog.InfoContext(ctx, "users were created concurrently, skipping admin bootstrap")
+		return false, nil
+	}
+	if err != nil {
+		return false, err
 	}
 
-	return nil
+	slog.InfoContext(ctx, "initial admin created from configured password hash", "username", username)
+	return false, nil
 }
@@ -0,0 +1,68 @@
+package database
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/tracing"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// queryTracer opens a client span for every query and logs it at debug
+// level. Both pick up the request from the query's context, tying repository
+// calls to the request that made them. Only the SQL is recorded, never the
+// arguments.
+type queryTracer struct{}
+
+type queryTraceKey struct{}
+
+type queryTrace struct {
+	sql   string
+	start time.Time
+	span  trace.Span
+}
+
+func (queryTracer) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	sql := strings.Join(strings.Fields(data.SQL), " ")
+	operation := sql
+	if i := strings.IndexByte(sql, ' '); i > 0 {
+		operation = sql[:i]
+	}
+	operation = strings.ToUpper(operation)
+
+	ctx, span := tracing.Tracer().Start(ctx, "db "+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemPostgreSQL,
+			semconv.DBOperationName(operation),
+			semconv.DBQueryText(sql),
+		),
+	)
+	return context.WithValue(ctx, queryTraceKey{}, queryTrace{sql: sql, start: time.Now(), span: span})
+}
+
+func (queryTracer) TraceQueryEnd(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryEndData) {
+	qt, ok := ctx.Value(queryTraceKey{}).(queryTrace)
+	if !ok {
+		return
+	}
+	tracing.RecordError(qt.span, data.Err)
+	qt.span.End()
+
+	if !slog.Default().Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+	attrs := []any{
+		"sql", qt.sql,
+		"duration_ms", time.Since(qt.start).Milliseconds(),
+		"rows", data.CommandTag.RowsAffected(),
+	}
+	if data.Err != nil {
+		attrs = append(attrs, "error", data.Err)
+	}
+	slog.DebugContext(ctx, "query", attrs...)
+}
@@ -0,0 +1,198 @@
+package database
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Predefined two-factor repository errors
+var (
+	ErrTwoFactorEnabled    = errors.New("two-factor authentication already enabled")
+	ErrTwoFactorNotPending = errors.New("no two-factor enrollment in progress")
+	ErrTOTPCodeReused      = errors.New("TOTP code already used")
+	ErrRecoveryCodeInvalid = errors.New("recovery code invalid or already used")
+)
+
+// TwoFactorRepository persists TOTP secrets and recovery codes
+type TwoFactorRepository struct {
+	db *DB
+}
+
+// NewTwoFactorRepository creates a new two-factor repository
+func NewTwoFactorRepository(db *DB) TwoFactorRepositoryInterface {
+	return &TwoFactorRepository{db: db}
+}
+
+// BeginEnrollment stores a pending TOTP secret. It is refused while 2FA is
+// already enabled so an attacker with a session can't silently replace it.
+func (r *TwoFactorRepository) BeginEnrollment(ctx context.Context, userID int, secret string) error {
+	commandTag, err := r.db.Pool.Exec(ctx, `
+        UPDATE users SET totp_secret = $2, totp_last_step = NULL
+        WHERE id = $1 AND totp_enabled = FALSE
+    `, userID, secret)
+	if err != nil {
+		return err
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return r.enrollmentConflict(ctx, userID)
+	}
+
+	return nil
+}
+
+// Enable activates the pending secret and replaces the user's recovery codes
+func (r *TwoFactorRepository) Enable(ctx context.Context, userID int, step int64, codeHashes []string) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	commandTag, err := tx.Exec(ctx, `
+        UPDATE users SET totp_enabled = TRUE, totp_last_step = $2, updated_at = CURRENT_TIMESTAMP
+        WHERE id = $1 AND totp_enabled = FALSE AND totp_secret IS NOT NULL
+    `, userID, step)
+	if err != nil {
+		return err
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return r.enrollmentConflict(ctx, userID)
+	}
+
+	if err := replaceRecoveryCodes(ctx, tx, userID, codeHashes); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Disable removes the TOTP secret and all recovery codes
+func (r *TwoFactorRepository) Disable(ctx context.Context, userID int) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	commandTag, err := tx.Exec(ctx, `
+        UPDATE users
+        SET totp_enabled = FALSE, totp_secret = NULL, totp_last_step = NULL, updated_at = CURRENT_TIMESTAMP
+        WHERE id = $1
+    `, userID)
+	if err != nil {
+		return err
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM user_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// RecordStep marks a TOTP time step as used. A step at or before the last
+// accepted one returns ErrTOTPCodeReused.
+func (r *TwoFactorRepository) RecordStep(ctx context.Context, userID int, step int64) error {
+	commandTag, err := r.db.Pool.Exec(ctx, `
+        UPDATE users SET totp_last_step = $2
+        WHERE id = $1 AND (totp_last_step IS NULL OR totp_last_step < $2)
+    `, userID, step)
+	if err != nil {
+		return err
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return ErrTOTPCodeReused
+	}
+
+	return nil
+}
+
+// UseRecoveryCode consumes an unused recovery code
+func (r *TwoFactorRepository) UseRecoveryCode(ctx context.Context, userID int, codeHash string) error {
+	commandTag, err := r.db.Pool.Exec(ctx, `
+        UPDATE user_recovery_codes SET used_at = CURRENT_TIMESTAMP
+        WHERE id = (
+            SELECT id FROM user_recovery_codes
+            WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+            LIMIT 1
+        )
+    `, userID, codeHash)
+	if err != nil {
+		return err
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return ErrRecoveryCodeInvalid
+	}
+
+	return nil
+}
+
+// ReplaceRecoveryCodes discards all existing recovery codes and stores new ones
+func (r *TwoFactorRepository) ReplaceRecoveryCodes(ctx context.Context, userID int, codeHashes []string) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := replaceRecoveryCodes(ctx, tx, userID, codeHashes); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// CountRecoveryCodes returns how many unused recovery codes a user has left
+func (r *TwoFactorRepository) CountRecoveryCodes(ctx context.Context, userID int) (int, error) {
+	var count int
+	err := r.db.Pool.QueryRow(ctx, `
+        SELECT COUNT(*) FROM user_recovery_codes WHERE user_id = $1 AND used_at IS NULL
+    `, userID).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func replaceRecoveryCodes(ctx context.Context, tx pgx.Tx, userID int, codeHashes []string) error {
+	if _, err := tx.Exec(ctx, `DELETE FROM user_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+
+	for _, hash := range codeHashes {
+		if _, err := tx.Exec(ctx, `
+            INSERT INTO user_recovery_codes (user_id, code_hash) VALUES ($1, $2)
+        `, userID, hash); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// enrollmentConflict explains why an enrollment update matched no rows
+func (r *TwoFactorRepository) enrollmentConflict(ctx context.Context, userID int) error {
+	var enabled bool
+	err := r.db.Pool.QueryRow(ctx, `SELECT totp_enabled FROM users WHERE id = $1`, userID).Scan(&enabled)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+
+	if enabled {
+		return ErrTwoFactorEnabled
+	}
+	return ErrTwoFactorNotPending
+}
@@ -2,10 +2,26 @@ package database
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
 )
 
+// Predefined user repository errors
+var (
+	ErrUsersExist    = errors.New("users already exist")
+	ErrUserNotFound  = errors.New("user not found")
+	ErrUsernameTaken = errors.New("username already taken")
+	ErrLastOwner     = errors.New("cannot remove the last owner")
+)
+
+// uniqueViolationCode is the Postgres SQLSTATE for unique constraint violations
+const uniqueViolationCode = "23505"
+
 type UserRepository struct {
 	db *DB
 }
@@ -15,30 +31,280 @@ func NewUserRepository(db *DB) UserRepositoryInterface {
 	return &UserRepository{db: db}
 }
 
-func (r *UserRepository) GetByID(ctx context.Context, id int) (*models.User, error) {
-	user := &models.User{}
+const userColumns = `id, username, COALESCE(email, ''), password, role, created_at, COALESCE(updated_at, created_at),
+    failed_login_attempts, last_failed_login_at, locked_until, totp_enabled, COALESCE(totp_secret, '')`
 
-	err := r.db.Pool.QueryRow(ctx, `
-        SELECT id, username, password, role FROM users WHERE id = $1
-    `, id).Scan(&user.ID, &user.Username, &user.Password, &user.Role)
+func scanUser(row pgx.Row) (*models.User, error) {
+	user := &models.User{}
+	err := row.Scan(&user.ID, &user.Username, &user.Email, &user.Password, &user.Role,
+		&user.CreatedAt, &user.UpdatedAt, &user.FailedLoginAttempts, &user.LastFailedLoginAt, &user.LockedUntil,
+		&user.TOTPEnabled, &user.TOTPSecret)
 
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
 		return nil, err
 	}
 
 	return user, nil
 }
 
+func (r *UserRepository) GetByID(ctx context.Context, id int) (*models.User, error) {
+	return scanUser(r.db.Pool.QueryRow(ctx, `
+        SELECT `+userColumns+` FROM users WHERE id = $1
+    `, id))
+}
+
 func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*models.User, error) {
-	user := &models.User{}
+	return scanUser(r.db.Pool.QueryRow(ctx, `
+        SELECT `+userColumns+` FROM users WHERE username = $1
+    `, username))
+}
+
+// GetAll retrieves all users ordered by username
+func (r *UserRepository) GetAll(ctx context.Context) ([]*models.User, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+        SELECT `+userColumns+` FROM users ORDER BY username
+    `)
+	if err != nil {
+		return nil, fmt.Errorf("database query error: %w", err)
+	}
+	defer rows.Close()
+
+	users := []*models.User{}
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return users, nil
+}
+
+// Count returns the number of user accounts
+func (r *UserRepository) Count(ctx context.Context) (int, error) {
+	var count int
+	err := r.db.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM users`).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
 
+// Create inserts a new user. The password must already be hashed.
+func (r *UserRepository) Create(ctx context.Context, user *models.User) (int, error) {
+	var id int
 	err := r.db.Pool.QueryRow(ctx, `
-        SELECT id, username, password, role FROM users WHERE username = $1
-    `, username).Scan(&user.ID, &user.Username, &user.Password, &user.Role)
+        INSERT INTO users (username, email, password, role)
+        VALUES ($1, NULLIF($2, ''), $3, $4)
+        RETURNING id
+    `, user.Username, user.Email, user.Password, user.Role).Scan(&id)
 
 	if err != nil {
-		return nil, err
+		if isUniqueViolation(err) {
+			return 0, ErrUsernameTaken
+		}
+		return 0, err
 	}
 
-	return user, nil
+	return id, nil
+}
+
+// CreateFirst inserts a user only if the users table is empty, so concurrent
+// setup requests (or multiple instances) can never create two initial admins
+func (r *UserRepository) CreateFirst(ctx context.Context, user *models.User) (int, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Serialize bootstrap attempts across connections
+	if _, err := tx.Exec(ctx, `LOCK TABLE users IN EXCLUSIVE MODE`); err != nil {
+		return 0, err
+	}
+
+	var id int
+	err = tx.QueryRow(ctx, `
+        INSERT INTO users (username, email, password, role)
+        SELECT $1, NULLIF($2, ''), $3, $4
+        WHERE NOT EXISTS (SELECT 1 FROM users)
+        RETURNING id
+    `, user.Username, user.Email, user.Password, user.Role).Scan(&id)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, ErrUsersExist
+		}
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// Update modifies a user's username, email and role. Demoting the last
+// owner is refused so the system can never be left without one.
+func (r *UserRepository) Update(ctx context.Context, id int, user *models.User) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := ensureOwnerRemains(ctx, tx, id, user.Role); err != nil {
+		return err
+	}
+
+	commandTag, err := tx.Exec(ctx, `
+        UPDATE users
+        SET username = $1, email = NULLIF($2, ''), role = $3, updated_at = CURRENT_TIMESTAMP
+        WHERE id = $4
+    `, user.Username, user.Email, user.Role, id)
+
+	if err != nil {
+		if isUniqueViolation(err) {
+			return ErrUsernameTaken
+		}
+		return err
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Delete removes a user. Deleting the last owner is refused.
+func (r *UserRepository) Delete(ctx context.Context, id int) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := ensureOwnerRemains(ctx, tx, id, ""); err != nil {
+		return err
+	}
+
+	commandTag, err := tx.Exec(ctx, `DELETE FROM users WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+
+	return tx.Commit(ctx)
+}
+
+// RecordFailedLogin increments a user's failed login counter. When the count
+// reaches lockAfter the account is locked until lockUntil and the counter
+// starts over; locked reports whether this failure triggered the lock.
+func (r *UserRepository) RecordFailedLogin(ctx context.Context, id int, lockAfter int, lockUntil time.Time) (attempts int, locked bool, err error) {
+	err = r.db.Pool.QueryRow(ctx, `
+        UPDATE users
+        SET failed_login_attempts = CASE WHEN failed_login_attempts + 1 >= $2 THEN 0 ELSE failed_login_attempts + 1 END,
+            locked_until = CASE WHEN failed_login_attempts + 1 >= $2 THEN $3 ELSE locked_until END,
+            last_failed_login_at = CURRENT_TIMESTAMP
+        WHERE id = $1
+        RETURNING failed_login_attempts, failed_login_attempts = 0
+    `, id, lockAfter, lockUntil).Scan(&attempts, &locked)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, false, ErrUserNotFound
+		}
+		return 0, false, err
+	}
+
+	return attempts, locked, nil
+}
+
+// ResetLoginFailures clears the failed login counter and any lock on the account
+func (r *UserRepository) ResetLoginFailures(ctx context.Context, id int) error {
+	commandTag, err := r.db.Pool.Exec(ctx, `
+        UPDATE users
+        SET failed_login_attempts = 0, last_failed_login_at = NULL, locked_until = NULL
+        WHERE id = $1
+    `, id)
+	if err != nil {
+		return err
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
+// UpdatePassword replaces a user's password hash
+func (r *UserRepository) UpdatePassword(ctx context.Context, id int, passwordHash string) error {
+	commandTag, err := r.db.Pool.Exec(ctx, `
+        UPDATE users SET password = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1
+    `, id, passwordHash)
+	if err != nil {
+		return err
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
+// ensureOwnerRemains returns ErrLastOwner if changing user id to newRole
+// (empty for deletion) would leave no owner accounts
+func ensureOwnerRemains(ctx context.Context, tx pgx.Tx, id int, newRole string) error {
+	if newRole == "owner" {
+		return nil
+	}
+
+	// Lock owner rows so two concurrent demotions can't both pass the check
+	rows, err := tx.Query(ctx, `SELECT id FROM users WHERE role = 'owner' FOR UPDATE`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	owners := 0
+	isOwner := false
+	for rows.Next() {
+		var ownerID int
+		if err := rows.Scan(&ownerID); err != nil {
+			return err
+		}
+		owners++
+		if ownerID == id {
+			isOwner = true
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if isOwner && owners == 1 {
+		return ErrLastOwner
+	}
+
+	return nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode
 }
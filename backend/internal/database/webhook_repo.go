@@ -0,0 +1,151 @@
+package database
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
+)
+
+// ErrWebhookNotFound is returned when no webhook has the requested ID
+var ErrWebhookNotFound = errors.New("webhook not found")
+
+// WebhookRepository handles webhook subscriptions and their delivery log
+type WebhookRepository struct {
+	db *DB
+}
+
+// NewWebhookRepository creates a new webhook repository
+func NewWebhookRepository(db *DB) WebhookRepositoryInterface {
+	return &WebhookRepository{db: db}
+}
+
+const webhookColumns = `id, url, secret, events, description, active, include_pii, created_at, updated_at`
+
+func scanWebhook(row pgx.Row) (*models.Webhook, error) {
+	hook := &models.Webhook{}
+	err := row.Scan(&hook.ID, &hook.URL, &hook.Secret, &hook.Events, &hook.Description, &hook.Active,
+		&hook.IncludePII, &hook.CreatedAt, &hook.UpdatedAt)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, err
+	}
+
+	return hook, nil
+}
+
+func (r *WebhookRepository) query(ctx context.Context, sql string, args ...interface{}) ([]*models.Webhook, error) {
+	rows, err := r.db.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	hooks := []*models.Webhook{}
+	for rows.Next() {
+		hook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		hooks = append(hooks, hook)
+	}
+	return hooks, rows.Err()
+}
+
+// GetAll retrieves every webhook
+func (r *WebhookRepository) GetAll(ctx context.Context) ([]*models.Webhook, error) {
+	return r.query(ctx, `SELECT `+webhookColumns+` FROM webhooks ORDER BY id`)
+}
+
+// GetActiveForEvent retrieves the active webhooks subscribed to event
+func (r *WebhookRepository) GetActiveForEvent(ctx context.Context, event string) ([]*models.Webhook, error) {
+	return r.query(ctx, `
+        SELECT `+webhookColumns+`
+        FROM webhooks
+        WHERE active = true AND ($1 = ANY(events) OR '*' = ANY(events))
+        ORDER BY id
+    `, event)
+}
+
+// GetByID retrieves a webhook by ID
+func (r *WebhookRepository) GetByID(ctx context.Context, id int) (*models.Webhook, error) {
+	return scanWebhook(r.db.Pool.QueryRow(ctx, `SELECT `+webhookColumns+` FROM webhooks WHERE id = $1`, id))
+}
+
+// Create stores a new webhook and fills in its ID and timestamps
+func (r *WebhookRepository) Create(ctx context.Context, hook *models.Webhook) error {
+	return r.db.Pool.QueryRow(ctx, `
+        INSERT INTO webhooks (url, secret, events, description, active, include_pii)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        RETURNING id, created_at, updated_at
+    `, hook.URL, hook.Secret, hook.Events, hook.Description, hook.Active, hook.IncludePII).Scan(&hook.ID, &hook.CreatedAt, &hook.UpdatedAt)
+}
+
+// Update replaces a webhook's settings
+func (r *WebhookRepository) Update(ctx context.Context, id int, hook *models.Webhook) error {
+	tag, err := r.db.Pool.Exec(ctx, `
+        UPDATE webhooks
+        SET url = $1, secret = $2, events = $3, description = $4, active = $5, include_pii = $6,
+            updated_at = CURRENT_TIMESTAMP
+        WHERE id = $7
+    `, hook.URL, hook.Secret, hook.Events, hook.Description, hook.Active, hook.IncludePII, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrWebhookNotFound
+	}
+	return nil
+}
+
+// Delete removes a webhook and its delivery log
+func (r *WebhookRepository) Delete(ctx context.Context, id int) error {
+	tag, err := r.db.Pool.Exec(ctx, `DELETE FROM webhooks WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrWebhookNotFound
+	}
+	return nil
+}
+
+// LogDelivery records a delivery attempt and fills in its ID and timestamp
+func (r *WebhookRepository) LogDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	return r.db.Pool.QueryRow(ctx, `
+        INSERT INTO webhook_deliveries (webhook_id, event_id, event, attempt, status_code, error, duration_ms)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        RETURNING id, created_at
+    `, delivery.WebhookID, delivery.EventID, delivery.Event, delivery.Attempt, delivery.StatusCode,
+		delivery.Error, delivery.DurationMs).Scan(&delivery.ID, &delivery.CreatedAt)
+}
+
+// GetDeliveries returns a webhook's most recent delivery attempts, newest first
+func (r *WebhookRepository) GetDeliveries(ctx context.Context, webhookID int, limit int) ([]*models.WebhookDelivery, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+        SELECT id, webhook_id, event_id, event, attempt, status_code, error, duration_ms, created_at
+        FROM webhook_deliveries
+        WHERE webhook_id = $1
+        ORDER BY created_at DESC, id DESC
+        LIMIT $2
+    `, webhookID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []*models.WebhookDelivery{}
+	for rows.Next() {
+		d := &models.WebhookDelivery{}
+		if err := rows.Scan(&d.ID, &d.WebhookID, &d.EventID, &d.Event, &d.Attempt, &d.StatusCode,
+			&d.Error, &d.DurationMs, &d.CreatedAt); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
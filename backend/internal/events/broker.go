@@ -0,0 +1,185 @@
+// Package events fans out booking and inquiry changes to admin dashboards
+// connected to the Server-Sent Events stream
+package events
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// subscriberBuffer is how many events a slow client may fall behind before
+// it is disconnected; it resumes from Last-Event-ID when it reconnects
+const subscriberBuffer = 64
+
+// Event is a change pushed to stream subscribers. Data is the booking or
+// inquiry, or null when it was too large to send between instances.
+type Event struct {
+	ID   string          `json:"id"`
+	Type string          `json:"type"`
+	Time time.Time       `json:"time"`
+	Data json.RawMessage `json:"data"`
+}
+
+// Transport carries published events to every instance. Each instance,
+// including the publisher, hands what it receives to Broker.Deliver.
+type Transport interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+// Broker is an in-process pub/sub for events. It keeps the most recent
+// events so reconnecting clients can catch up. A nil *Broker drops
+// everything published to it.
+type Broker struct {
+	transport Transport
+
+	mu          sync.Mutex
+	subscribers map[*Subscription]struct{}
+	history     []Event
+	historySize int
+	closed      bool
+}
+
+// NewBroker creates a broker that remembers the last historySize events
+func NewBroker(historySize int) *Broker {
+	return &Broker{
+		subscribers: map[*Subscription]struct{}{},
+		historySize: historySize,
+	}
+}
+
+// SetTransport sends published events through t instead of delivering them
+// straight to local subscribers
+func (b *Broker) SetTransport(t Transport) {
+	b.transport = t
+}
+
+// Publish encodes data and sends it to subscribers as an event of eventType.
+// ctx only carries the request ID and trace for logging.
+func (b *Broker) Publish(ctx context.Context, eventType string, data interface{}) {
+	if b == nil {
+		return
+	}
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to encode event", "type", eventType, "error", err)
+		return
+	}
+	event := Event{ID: newID(), Type: eventType, Time: time.Now().UTC(), Data: encoded}
+
+	if b.transport == nil {
+		b.Deliver(event)
+		return
+	}
+	if err := b.transport.Publish(ctx, event); err != nil {
+		// Other instances miss the event, but this one's clients still see it
+		slog.ErrorContext(ctx, "failed to publish event", "type", eventType, "error", err)
+		b.Deliver(event)
+	}
+}
+
+// Deliver records event in the history and sends it to every subscriber.
+// Subscribers whose buffer is full are disconnected.
+func (b *Broker) Deliver(event Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return
+	}
+
+	b.history = append(b.history, event)
+	if len(b.history) > b.historySize {
+		b.history = b.history[len(b.history)-b.historySize:]
+	}
+
+	for sub := range b.subscribers {
+		select {
+		case sub.events <- event:
+		default:
+			slog.Warn("event stream subscriber too slow, disconnecting")
+			b.remove(sub)
+		}
+	}
+}
+
+// Subscription receives events until it is closed. Events is closed when the
+// subscriber is dropped for being too slow or the broker shuts down.
+type Subscription struct {
+	events chan Event
+}
+
+// Events returns the channel events are delivered on
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Subscribe registers a new subscriber. With a lastEventID it also returns
+// the events published after that one; ok is false when the ID is no longer
+// (or was never) in the history, so the client may have missed events.
+func (b *Broker) Subscribe(lastEventID string) (sub *Subscription, missed []Event, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	sub = &Subscription{events: make(chan Event, subscriberBuffer)}
+	if b.closed {
+		close(sub.events)
+		return sub, nil, true
+	}
+	b.subscribers[sub] = struct{}{}
+
+	if lastEventID == "" {
+		return sub, nil, true
+	}
+	for i, event := range b.history {
+		if event.ID == lastEventID {
+			return sub, append([]Event(nil), b.history[i+1:]...), true
+		}
+	}
+	return sub, nil, false
+}
+
+// Unsubscribe removes sub; it is safe to call more than once
+func (b *Broker) Unsubscribe(sub *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.remove(sub)
+}
+
+// Subscribers returns the number of connected subscribers
+func (b *Broker) Subscribers() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subscribers)
+}
+
+// Close disconnects every subscriber, so streams end on shutdown instead of
+// holding the server open
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for sub := range b.subscribers {
+		b.remove(sub)
+	}
+}
+
+// remove must be called with b.mu held
+func (b *Broker) remove(sub *Subscription) {
+	if _, ok := b.subscribers[sub]; ok {
+		delete(b.subscribers, sub)
+		close(sub.events)
+	}
+}
+
+func newID() string {
+	b := make([]byte, 12)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,170 @@
+package events_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/events"
+)
+
+// loopback delivers published events back to the broker, like NOTIFY does
+type loopback struct {
+	broker    *events.Broker
+	err       error
+	published int
+}
+
+func (l *loopback) Publish(ctx context.Context, event events.Event) error {
+	l.published++
+	if l.err != nil {
+		return l.err
+	}
+	l.broker.Deliver(event)
+	return nil
+}
+
+func receive(t *testing.T, sub *events.Subscription, n int) []events.Event {
+	t.Helper()
+	var got []events.Event
+	for len(got) < n {
+		select {
+		case event := <-sub.Events():
+			got = append(got, event)
+		default:
+			t.Fatalf("Expected %d events, got %d", n, len(got))
+		}
+	}
+	return got
+}
+
+func TestBrokerFanOut(t *testing.T) {
+	broker := events.NewBroker(10)
+	first, _, _ := broker.Subscribe("")
+	second, _, _ := broker.Subscribe("")
+
+	broker.Publish(context.Background(), "booking.created", map[string]int{"id": 7})
+
+	for _, sub := range []*events.Subscription{first, second} {
+		event := receive(t, sub, 1)[0]
+		if event.Type != "booking.created" || event.ID == "" || string(event.Data) != `{"id":7}` {
+			t.Errorf("Unexpected event %+v", event)
+		}
+	}
+
+	broker.Unsubscribe(first)
+	broker.Unsubscribe(first)
+	if broker.Subscribers() != 1 {
+		t.Errorf("Expected 1 subscriber, got %d", broker.Subscribers())
+	}
+}
+
+func TestBrokerResume(t *testing.T) {
+	// The history keeps the last three of five events
+	broker := events.NewBroker(3)
+	sub, _, _ := broker.Subscribe("")
+	for i := 0; i < 5; i++ {
+		broker.Publish(context.Background(), "booking.updated", i)
+	}
+	published := receive(t, sub, 5)
+
+	tests := []struct {
+		name           string
+		lastEventID    string
+		expectOK       bool
+		expectedMissed []int
+	}{
+		{name: "New client", lastEventID: "", expectOK: true},
+		{name: "Up to date", lastEventID: published[4].ID, expectOK: true},
+		{name: "Behind", lastEventID: published[2].ID, expectOK: true, expectedMissed: []int{3, 4}},
+		{name: "Evicted ID", lastEventID: published[0].ID, expectOK: false},
+		{name: "Unknown ID", lastEventID: "gone", expectOK: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			sub, missed, ok := broker.Subscribe(tc.lastEventID)
+			defer broker.Unsubscribe(sub)
+
+			if ok != tc.expectOK {
+				t.Errorf("Expected ok=%v, got %v", tc.expectOK, ok)
+			}
+			if len(missed) != len(tc.expectedMissed) {
+				t.Fatalf("Expected %d missed events, got %d", len(tc.expectedMissed), len(missed))
+			}
+			for i, event := range missed {
+				var data int
+				if err := json.Unmarshal(event.Data, &data); err != nil || data != tc.expectedMissed[i] {
+					t.Errorf("Expected replayed event %d, got %s", tc.expectedMissed[i], event.Data)
+				}
+			}
+		})
+	}
+}
+
+func TestBrokerDropsSlowSubscriber(t *testing.T) {
+	broker := events.NewBroker(1000)
+	slow, _, _ := broker.Subscribe("")
+
+	for i := 0; i < 100; i++ {
+		broker.Publish(context.Background(), "booking.created", i)
+	}
+
+	if broker.Subscribers() != 0 {
+		t.Fatalf("Expected the slow subscriber to be dropped")
+	}
+	var n int
+	for range slow.Events() {
+		n++
+	}
+	if n == 0 || n >= 100 {
+		t.Errorf("Expected a partial backlog before the channel closed, got %d events", n)
+	}
+}
+
+func TestBrokerTransport(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{name: "Delivered through the transport"},
+		{name: "Delivered locally when the transport fails", err: errors.New("connection refused")},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			broker := events.NewBroker(10)
+			transport := &loopback{broker: broker, err: tc.err}
+			broker.SetTransport(transport)
+			sub, _, _ := broker.Subscribe("")
+
+			broker.Publish(context.Background(), "inquiry.created", map[string]string{"name": "Ada"})
+
+			if transport.published != 1 {
+				t.Errorf("Expected 1 published event, got %d", transport.published)
+			}
+			receive(t, sub, 1)
+		})
+	}
+}
+
+func TestBrokerClose(t *testing.T) {
+	broker := events.NewBroker(10)
+	sub, _, _ := broker.Subscribe("")
+	broker.Close()
+
+	if _, open := <-sub.Events(); open {
+		t.Errorf("Expected Close to end subscriptions")
+	}
+
+	// Later subscribers end immediately and publishing is ignored
+	late, _, _ := broker.Subscribe("")
+	broker.Publish(context.Background(), "booking.created", 1)
+	if _, open := <-late.Events(); open {
+		t.Errorf("Expected a closed broker to end new subscriptions")
+	}
+
+	var disabled *events.Broker
+	disabled.Publish(context.Background(), "booking.created", 1)
+}
@@ -0,0 +1,98 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Channel is the Postgres NOTIFY channel events are published on
+const Channel = "toasted_events"
+
+// maxNotifyPayload keeps payloads under Postgres' 8000 byte NOTIFY limit
+const maxNotifyPayload = 7500
+
+// PostgresTransport publishes events with NOTIFY so every backend instance
+// listening on the channel delivers them to its own subscribers
+type PostgresTransport struct {
+	pool   *pgxpool.Pool
+	broker *Broker
+}
+
+// NewPostgresTransport creates a transport that delivers what it hears to broker
+func NewPostgresTransport(pool *pgxpool.Pool, broker *Broker) *PostgresTransport {
+	return &PostgresTransport{pool: pool, broker: broker}
+}
+
+// Publish sends event to every listening instance, this one included. Events
+// too large for NOTIFY are sent without their data; clients refetch instead.
+func (t *PostgresTransport) Publish(ctx context.Context, event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	if len(payload) > maxNotifyPayload {
+		event.Data = json.RawMessage("null")
+		if payload, err = json.Marshal(event); err != nil {
+			return err
+		}
+	}
+
+	if _, err := t.pool.Exec(ctx, "SELECT pg_notify($1, $2)", Channel, string(payload)); err != nil {
+		return fmt.Errorf("failed to notify %s: %w", Channel, err)
+	}
+	return nil
+}
+
+// Listen holds a connection listening on the channel and delivers events to
+// the broker until ctx is canceled, reconnecting after errors
+func (t *PostgresTransport) Listen(ctx context.Context) {
+	wait := time.Second
+	for {
+		err := t.listen(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		slog.Error("event listener disconnected, reconnecting", "error", err, "retry_in", wait)
+
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return
+		}
+		wait = min(wait*2, 30*time.Second)
+	}
+}
+
+func (t *PostgresTransport) listen(ctx context.Context) error {
+	pooled, err := t.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	// The connection is left in LISTEN mode, so it must not go back to the pool
+	conn := pooled.Hijack()
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+Channel); err != nil {
+		return err
+	}
+	slog.Info("listening for events", "channel", Channel)
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		var event Event
+		if err := json.Unmarshal([]byte(notification.Payload), &event); err != nil {
+			slog.Error("ignoring malformed event notification", "error", err)
+			continue
+		}
+		t.broker.Deliver(event)
+	}
+}
@@ -0,0 +1,114 @@
+// Package export writes bookings to CSV and Excel files for bookkeeping.
+// Rows are written as they are read, so exports of any size use little memory.
+package export
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
+)
+
+// Export formats
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// listSeparator joins the values of list fields such as CoffeeFlavors into
+// one cell
+const listSeparator = "; "
+
+// Date is a calendar day with no time of day, written as a date cell
+type Date struct {
+	time.Time
+}
+
+// Column is one column of a booking export. Value returns a string, int,
+// bool, Date, time.Time or nil for an empty cell.
+type Column struct {
+	// Key selects the column and matches the booking's JSON field
+	Key    string
+	Header string
+	Value  func(b *models.Booking) interface{}
+}
+
+var bookingColumns = []Column{
+	{"id", "ID", func(b *models.Booking) interface{} { return b.ID }},
+	{"name", "Name", func(b *models.Booking) interface{} { return b.Name }},
+	{"email", "Email", func(b *models.Booking) interface{} { return b.Email }},
+	{"phone", "Phone", func(b *models.Booking) interface{} { return b.Phone }},
+	{"date", "Date", func(b *models.Booking) interface{} {
+		date, err := time.Parse("2006-01-02", b.Date)
+		if err != nil {
+			return b.Date
+		}
+		return Date{date}
+	}},
+	{"time", "Time", func(b *models.Booking) interface{} { return b.Time }},
+	{"people", "People", func(b *models.Booking) interface{} { return b.People }},
+	{"location", "Location", func(b *models.Booking) interface{} { return b.Location }},
+	{"notes", "Notes", func(b *models.Booking) interface{} { return b.Notes }},
+	{"coffeeFlavors", "Coffee Flavors", func(b *models.Booking) interface{} {
+		return strings.Join(b.CoffeeFlavors, listSeparator)
+	}},
+	{"milkOptions", "Milk Options", func(b *models.Booking) interface{} {
+		return strings.Join(b.MilkOptions, listSeparator)
+	}},
+	{"package", "Package", func(b *models.Booking) interface{} { return b.Package }},
+	{"status", "Status", func(b *models.Booking) interface{} { return b.Status }},
+	{"assignedUserId", "Assigned User ID", func(b *models.Booking) interface{} {
+		if b.AssignedUserID == nil {
+			return nil
+		}
+		return *b.AssignedUserID
+	}},
+	{"archived", "Archived", func(b *models.Booking) interface{} { return b.Archived }},
+	{"isOutdoor", "Outdoor", func(b *models.Booking) interface{} { return b.IsOutdoor }},
+	{"hasShade", "Shade", func(b *models.Booking) interface{} { return b.HasShade }},
+	{"createdAt", "Created At (UTC)", func(b *models.Booking) interface{} { return b.CreatedAt.UTC() }},
+}
+
+// BookingColumns returns every column a booking export can have, in the
+// default order
+func BookingColumns() []Column {
+	return append([]Column(nil), bookingColumns...)
+}
+
+// ColumnKeys lists the keys of every booking column
+func ColumnKeys() []string {
+	keys := make([]string, len(bookingColumns))
+	for i, c := range bookingColumns {
+		keys[i] = c.Key
+	}
+	return keys
+}
+
+// ParseColumns returns the columns named in a comma separated list of keys,
+// in the order given. An empty list selects every column.
+func ParseColumns(list string) ([]Column, error) {
+	if strings.TrimSpace(list) == "" {
+		return BookingColumns(), nil
+	}
+
+	var columns []Column
+	for _, key := range strings.Split(list, ",") {
+		key = strings.TrimSpace(key)
+		column, ok := findColumn(key)
+		if !ok {
+			return nil, fmt.Errorf("unknown column %q, must be one of: %s", key, strings.Join(ColumnKeys(), " "))
+		}
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+func findColumn(key string) (Column, bool) {
+	for _, c := range bookingColumns {
+		if c.Key == key {
+			return c, true
+		}
+	}
+	return Column{}, false
+}
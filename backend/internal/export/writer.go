@@ -0,0 +1,183 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
+	"github.com/xuri/excelize/v2"
+)
+
+// Writer writes bookings as rows of an export. Close finishes the file;
+// Abort gives up on it instead, releasing anything held for it.
+type Writer interface {
+	Write(booking *models.Booking) error
+	Close() error
+	Abort()
+}
+
+// ContentType returns the media type of files in format
+func ContentType(format string) string {
+	if format == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// NewWriter starts an export in format to w with a header row naming columns
+func NewWriter(format string, w io.Writer, columns []Column) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w, columns)
+	case FormatXLSX:
+		return newXLSXWriter(w, columns)
+	default:
+		return nil, fmt.Errorf("unknown export format %q", format)
+	}
+}
+
+type csvWriter struct {
+	csv     *csv.Writer
+	columns []Column
+	record  []string
+}
+
+func newCSVWriter(w io.Writer, columns []Column) (*csvWriter, error) {
+	cw := &csvWriter{csv: csv.NewWriter(w), columns: columns, record: make([]string, len(columns))}
+	for i, c := range columns {
+		cw.record[i] = c.Header
+	}
+	return cw, cw.csv.Write(cw.record)
+}
+
+func (cw *csvWriter) Write(booking *models.Booking) error {
+	for i, c := range cw.columns {
+		cw.record[i] = csvValue(c.Value(booking))
+	}
+	return cw.csv.Write(cw.record)
+}
+
+func (cw *csvWriter) Close() error {
+	cw.csv.Flush()
+	return cw.csv.Error()
+}
+
+func (cw *csvWriter) Abort() {}
+
+// csvValue formats a cell. Text that a spreadsheet would run as a formula is
+// prefixed with a quote, since names and notes come from customers.
+func csvValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		if v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+			return "'" + v
+		}
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case Date:
+		return v.Format("2006-01-02")
+	case time.Time:
+		return v.Format(time.RFC3339)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// xlsxWriter streams rows into a single worksheet. The workbook is written
+// to the output when it is closed; rows beyond a few megabytes are kept in a
+// temporary file rather than in memory.
+type xlsxWriter struct {
+	out     io.Writer
+	file    *excelize.File
+	stream  *excelize.StreamWriter
+	columns []Column
+	row     int
+
+	dateStyle, timeStyle int
+}
+
+func newXLSXWriter(w io.Writer, columns []Column) (*xlsxWriter, error) {
+	file := excelize.NewFile()
+	xw := &xlsxWriter{out: w, file: file, columns: columns, row: 1}
+
+	err := func() error {
+		if err := file.SetSheetName("Sheet1", "Bookings"); err != nil {
+			return err
+		}
+		headerStyle, err := file.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+		if err != nil {
+			return err
+		}
+		dateFormat, timeFormat := "yyyy-mm-dd", "yyyy-mm-dd hh:mm"
+		if xw.dateStyle, err = file.NewStyle(&excelize.Style{CustomNumFmt: &dateFormat}); err != nil {
+			return err
+		}
+		if xw.timeStyle, err = file.NewStyle(&excelize.Style{CustomNumFmt: &timeFormat}); err != nil {
+			return err
+		}
+		if xw.stream, err = file.NewStreamWriter("Bookings"); err != nil {
+			return err
+		}
+		if err := xw.stream.SetPanes(&excelize.Panes{
+			Freeze: true, YSplit: 1, TopLeftCell: "A2", ActivePane: "bottomLeft",
+		}); err != nil {
+			return err
+		}
+
+		header := make([]interface{}, len(columns))
+		for i, c := range columns {
+			header[i] = excelize.Cell{StyleID: headerStyle, Value: c.Header}
+		}
+		return xw.writeRow(header)
+	}()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return xw, nil
+}
+
+func (xw *xlsxWriter) Write(booking *models.Booking) error {
+	row := make([]interface{}, len(xw.columns))
+	for i, c := range xw.columns {
+		switch v := c.Value(booking).(type) {
+		case Date:
+			row[i] = excelize.Cell{StyleID: xw.dateStyle, Value: v.Time}
+		case time.Time:
+			row[i] = excelize.Cell{StyleID: xw.timeStyle, Value: v}
+		default:
+			row[i] = v
+		}
+	}
+	return xw.writeRow(row)
+}
+
+func (xw *xlsxWriter) writeRow(values []interface{}) error {
+	cell, err := excelize.CoordinatesToCellName(1, xw.row)
+	if err != nil {
+		return err
+	}
+	xw.row++
+	return xw.stream.SetRow(cell, values)
+}
+
+func (xw *xlsxWriter) Close() error {
+	defer xw.file.Close()
+	if err := xw.stream.Flush(); err != nil {
+		return err
+	}
+	return xw.file.Write(xw.out)
+}
+
+func (xw *xlsxWriter) Abort() {
+	xw.file.Close()
+}
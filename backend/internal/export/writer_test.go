@@ -0,0 +1,123 @@
+package export_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/export"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
+	"github.com/xuri/excelize/v2"
+)
+
+func testBooking() *models.Booking {
+	assignee := 3
+	return &models.Booking{
+		ID:             12,
+		Name:           "=HYPERLINK(\"http://evil\")",
+		Email:          "ada@example.com",
+		Date:           "2026-10-18",
+		Time:           "14:00",
+		People:         40,
+		Location:       "Main St, Suite 2",
+		CoffeeFlavors:  []string{"vanilla", "mocha"},
+		MilkOptions:    []string{"oat"},
+		Status:         models.BookingStatusConfirmed,
+		AssignedUserID: &assignee,
+		IsOutdoor:      true,
+		CreatedAt:      time.Date(2026, 9, 1, 8, 30, 0, 0, time.UTC),
+	}
+}
+
+func TestParseColumns(t *testing.T) {
+	tests := []struct {
+		name         string
+		list         string
+		expectedKeys []string
+		expectError  bool
+	}{
+		{name: "Empty selects all", list: "", expectedKeys: export.ColumnKeys()},
+		{name: "Order is kept", list: "date, name,id", expectedKeys: []string{"date", "name", "id"}},
+		{name: "Unknown column", list: "id,price", expectError: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			columns, err := export.ParseColumns(tc.list)
+			if (err != nil) != tc.expectError {
+				t.Fatalf("Expected error=%v, got %v", tc.expectError, err)
+			}
+			if len(columns) != len(tc.expectedKeys) {
+				t.Fatalf("Expected %d columns, got %d", len(tc.expectedKeys), len(columns))
+			}
+			for i, c := range columns {
+				if c.Key != tc.expectedKeys[i] {
+					t.Errorf("Expected column %d to be %s, got %s", i, tc.expectedKeys[i], c.Key)
+				}
+			}
+		})
+	}
+}
+
+func TestCSVWriter(t *testing.T) {
+	columns, _ := export.ParseColumns("id,name,date,location,coffeeFlavors,assignedUserId,isOutdoor,phone,createdAt")
+	var buf bytes.Buffer
+	writer, err := export.NewWriter(export.FormatCSV, &buf, columns)
+	if err != nil {
+		t.Fatalf("Failed to create writer: %v", err)
+	}
+	if err := writer.Write(testBooking()); err != nil {
+		t.Fatalf("Failed to write booking: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Failed to close writer: %v", err)
+	}
+
+	expected := "ID,Name,Date,Location,Coffee Flavors,Assigned User ID,Outdoor,Phone,Created At (UTC)\n" +
+		`12,"'=HYPERLINK(""http://evil"")",2026-10-18,"Main St, Suite 2",vanilla; mocha,3,true,,2026-09-01T08:30:00Z` + "\n"
+	if buf.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
+
+func TestXLSXWriter(t *testing.T) {
+	columns, _ := export.ParseColumns("id,name,date,milkOptions,people")
+	var buf bytes.Buffer
+	writer, err := export.NewWriter(export.FormatXLSX, &buf, columns)
+	if err != nil {
+		t.Fatalf("Failed to create writer: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := writer.Write(testBooking()); err != nil {
+			t.Fatalf("Failed to write booking: %v", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Failed to close writer: %v", err)
+	}
+
+	file, err := excelize.OpenReader(&buf)
+	if err != nil {
+		t.Fatalf("Export is not a valid workbook: %v", err)
+	}
+	defer file.Close()
+
+	rows, err := file.GetRows("Bookings")
+	if err != nil {
+		t.Fatalf("Failed to read rows: %v", err)
+	}
+	if len(rows) != 4 {
+		t.Fatalf("Expected a header and 3 rows, got %d", len(rows))
+	}
+	expected := []string{"12", "=HYPERLINK(\"http://evil\")", "2026-10-18", "oat", "40"}
+	for i, value := range expected {
+		if rows[1][i] != value {
+			t.Errorf("Expected cell %d to be %q, got %q", i, value, rows[1][i])
+		}
+	}
+
+	// Dates are stored as dates, not text
+	if cellType, _ := file.GetCellType("Bookings", "C2"); cellType == excelize.CellTypeInlineString || cellType == excelize.CellTypeSharedString {
+		t.Errorf("Expected the date to be a date cell, got type %v", cellType)
+	}
+}
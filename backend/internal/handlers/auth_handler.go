@@ -1,19 +1,46 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
-	"log"
+	"errors"
+	"io"
+	"log/slog"
+	"math"
 	"net/http"
+	"strconv"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/joshuagudgel/toasted-coffee/backend/internal/auth"
 	"github.com/joshuagudgel/toasted-coffee/backend/internal/database"
 	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/services"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type AuthHandler struct {
-	userRepo database.UserRepositoryInterface
+	userRepo      database.UserRepositoryInterface
+	refreshRepo   database.RefreshTokenRepositoryInterface
+	twoFactorRepo database.TwoFactorRepositoryInterface
+	resetRepo     database.PasswordResetRepositoryInterface
+	tokens        *auth.TokenService
+	emailService  *services.EmailService
+	cookies       *auth.CookieSettings // nil unless cookie session mode is enabled
+	lockout       auth.LockoutPolicy
+
+	requireTwoFactor bool
+	passwordResetURL string
+}
+
+// AuthOptions holds the optional behaviour of the auth handler
+type AuthOptions struct {
+	// Cookies enables cookie session mode when non-nil
+	Cookies *auth.CookieSettings
+	// RequireTwoFactor forces users without TOTP to enroll before they get a session
+	RequireTwoFactor bool
+	// PasswordResetURL is the admin page that accepts ?token= from reset emails
+	PasswordResetURL string
 }
 
 type LoginRequest struct {
@@ -21,10 +48,19 @@ type LoginRequest struct {
 	Password string `json:"password"`
 }
 
+// LoginResponse carries the tokens in the body, or only the CSRF token when
+// cookie session mode keeps them in HttpOnly cookies
 type LoginResponse struct {
-	Token        string      `json:"token"`
-	RefreshToken string      `json:"refreshToken"`
-	User         models.User `json:"user"`
+	Token        string       `json:"token,omitempty"`
+	RefreshToken string       `json:"refreshToken,omitempty"`
+	CSRFToken    string       `json:"csrfToken,omitempty"`
+	User         *models.User `json:"user,omitempty"`
+
+	// Set instead of a session when a second factor is needed
+	TwoFactorRequired  bool     `json:"twoFactorRequired,omitempty"`
+	EnrollmentRequired bool     `json:"enrollmentRequired,omitempty"`
+	ChallengeToken     string   `json:"challengeToken,omitempty"`
+	RecoveryCodes      []string `json:"recoveryCodes,omitempty"`
 }
 
 type RefreshRequest struct {
@@ -32,85 +68,141 @@ type RefreshRequest struct {
 }
 
 type RefreshResponse struct {
-	AccessToken string `json:"accessToken"`
+	AccessToken  string `json:"accessToken,omitempty"`
+	RefreshToken string `json:"refreshToken,omitempty"` // rotated; the presented token is no longer valid
+	CSRFToken    string `json:"csrfToken,omitempty"`
 }
 
-func NewAuthHandler(userRepo database.UserRepositoryInterface) *AuthHandler {
-	return &AuthHandler{userRepo: userRepo}
+type LogoutRequest struct {
+	RefreshToken string `json:"refreshToken"`
 }
 
-func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
-	startTime := time.Now()
-	log.Printf("LOGIN START: Authentication request received at %v", startTime)
+// maxDeviceLength matches the refresh_tokens.device column
+const maxDeviceLength = 255
+
+// NewAuthHandler creates an auth handler
+func NewAuthHandler(userRepo database.UserRepositoryInterface, refreshRepo database.RefreshTokenRepositoryInterface,
+	twoFactorRepo database.TwoFactorRepositoryInterface, resetRepo database.PasswordResetRepositoryInterface,
+	tokens *auth.TokenService, emailService *services.EmailService, opts AuthOptions) *AuthHandler {
+	return &AuthHandler{
+		userRepo:         userRepo,
+		refreshRepo:      refreshRepo,
+		twoFactorRepo:    twoFactorRepo,
+		resetRepo:        resetRepo,
+		tokens:           tokens,
+		emailService:     emailService,
+		cookies:          opts.Cookies,
+		lockout:          auth.DefaultLockoutPolicy,
+		requireTwoFactor: opts.RequireTwoFactor,
+		passwordResetURL: opts.PasswordResetURL,
+	}
+}
 
+func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	var req LoginRequest
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		log.Printf("ERROR: Failed to decode request body: %v", err)
+		slog.WarnContext(ctx, "invalid login request body", "error", err)
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
-	log.Printf("LOGIN TIMING: Request body decoded in %v", time.Since(startTime))
-
-	log.Printf("Login request received for user: %s", req.Username)
 
 	// Get user by username
-	userLookupStart := time.Now()
-	user, err := h.userRepo.GetByUsername(r.Context(), req.Username)
+	user, err := h.userRepo.GetByUsername(ctx, req.Username)
 	if err != nil {
-		log.Printf("ERROR: User '%s' lookup failed: %v", req.Username, err)
+		// The attempted username isn't logged: it is often a mistyped password
+		if !errors.Is(err, database.ErrUserNotFound) {
+			slog.ErrorContext(ctx, "login user lookup failed", "error", err)
+		}
+		slog.WarnContext(ctx, "login failed: unknown user", "remote_ip", r.RemoteAddr)
+		// Spend the same bcrypt time as a real check so unknown usernames can't be probed
+		auth.EqualizeLoginTiming(req.Password)
 		http.Error(w, "Invalid username or password", http.StatusUnauthorized)
 		return
 	}
-	log.Printf("LOGIN TIMING: Database user lookup took %v", time.Since(userLookupStart))
-	log.Printf("User found: %s (ID: %d, Role: %s)", user.Username, user.ID, user.Role)
+
+	// Refuse locked or throttled accounts before looking at the password
+	if retryAfter := h.loginRetryAfter(user, time.Now()); retryAfter > 0 {
+		slog.WarnContext(ctx, "login refused: account throttled", "user_id", user.ID, "retry_after", retryAfter.String())
+		auth.EqualizeLoginTiming(req.Password)
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		http.Error(w, "Too many failed login attempts, try again later", http.StatusTooManyRequests)
+		return
+	}
 
 	// Compare passwords
-	pwCompareStart := time.Now()
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
-		log.Printf("ERROR: Password verification failed for '%s': %v", user.Username, err)
+		slog.WarnContext(ctx, "login failed: wrong password", "user_id", user.ID, "remote_ip", r.RemoteAddr)
+		h.recordFailedLogin(r, user)
 		http.Error(w, "Invalid username or password", http.StatusUnauthorized)
 		return
 	}
-	log.Printf("LOGIN TIMING: Password verification took %v", time.Since(pwCompareStart))
-	log.Printf("Password verification successful for user: %s", user.Username)
 
-	// Generate JWT token
-	tokenGenStart := time.Now()
-	token, err := auth.GenerateToken(user.ID, user.Role)
-	if err != nil {
-		http.Error(w, "Error generating token", http.StatusInternalServerError)
+	// With 2FA the session is only issued once the second factor is verified
+	if user.TOTPEnabled {
+		h.writeChallenge(w, r, user, auth.ChallengeVerify)
+		return
+	}
+	if h.requireTwoFactor {
+		h.writeChallenge(w, r, user, auth.ChallengeEnroll)
 		return
 	}
-	log.Printf("LOGIN TIMING: JWT token generation took %v", time.Since(tokenGenStart))
-	log.Printf("JWT token generated successfully")
 
-	refreshTokenStart := time.Now()
-	refreshToken, err := auth.GenerateRefreshToken(user.ID)
+	resp, err := h.completeLogin(w, r, user)
 	if err != nil {
-		log.Printf("ERROR: Refresh token generation failed: %v", err)
-		http.Error(w, "Error generating refresh token", http.StatusInternalServerError)
+		http.Error(w, "Error generating token", http.StatusInternalServerError)
 		return
 	}
-	log.Printf("LOGIN TIMING: Refresh token generation took %v", time.Since(refreshTokenStart))
-	log.Printf("Refresh token generated successfully")
 
-	// Return tokens in response body instead of setting cookies
 	w.Header().Set("Content-Type", "application/json")
-	resp := LoginResponse{
-		Token:        token,
-		RefreshToken: refreshToken,
-		User:         *user,
-	}
 
 	if err := json.NewEncoder(w).Encode(resp); err != nil {
-		log.Printf("ERROR: Failed to encode response: %v", err)
+		slog.ErrorContext(ctx, "failed to encode login response", "error", err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 
-	log.Printf("LOGIN COMPLETE: Total authentication time: %v", time.Since(startTime))
-	log.Printf("Login successful for user: %s, role: %s", user.Username, user.Role)
+	slog.InfoContext(ctx, "login successful", "user_id", user.ID, "role", user.Role)
+}
+
+// completeLogin clears failed attempts and issues the session: access and
+// refresh tokens in the response, or as cookies in cookie session mode
+func (h *AuthHandler) completeLogin(w http.ResponseWriter, r *http.Request, user *models.User) (*LoginResponse, error) {
+	if user.FailedLoginAttempts > 0 || user.LockedUntil != nil {
+		if err := h.userRepo.ResetLoginFailures(r.Context(), user.ID); err != nil {
+			slog.ErrorContext(r.Context(), "failed to reset login failures", "user_id", user.ID, "error", err)
+		}
+	}
+
+	// Generate JWT token
+	token, err := h.tokens.GenerateToken(user.ID, user.Role)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "access token generation failed", "user_id", user.ID, "error", err)
+		return nil, err
+	}
+
+	refreshToken, err := h.issueRefreshToken(r, user.ID, uuid.New().String(), 0)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "refresh token generation failed", "user_id", user.ID, "error", err)
+		return nil, err
+	}
+
+	resp := &LoginResponse{User: user}
+	if h.cookies != nil {
+		// Cookie mode: tokens stay out of reach of JavaScript
+		csrfToken, err := h.setSessionCookies(w, r, token, refreshToken)
+		if err != nil {
+			slog.ErrorContext(r.Context(), "CSRF token generation failed", "error", err)
+			return nil, err
+		}
+		resp.CSRFToken = csrfToken
+	} else {
+		resp.Token = token
+		resp.RefreshToken = refreshToken.Token
+	}
+
+	return resp, nil
 }
 
 func (h *AuthHandler) ValidateToken(w http.ResponseWriter, r *http.Request) {
@@ -123,31 +215,64 @@ func (h *AuthHandler) ValidateToken(w http.ResponseWriter, r *http.Request) {
 	// Return user info based on claims
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"userId": claims.UserID,
-		"role":   claims.Role,
+		"userId":      claims.UserID,
+		"role":        claims.Role,
+		"permissions": auth.PermissionsForRole(claims.Role),
 	})
 }
 
 func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
-	// Get refresh token from request body instead of cookie
-	var req RefreshRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	presented, fromCookie, err := h.refreshTokenFromRequest(r)
+	if err != nil {
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
-	if req.RefreshToken == "" {
+	if presented == "" {
 		http.Error(w, "Refresh token not provided", http.StatusUnauthorized)
 		return
 	}
 
-	// Validate refresh token
-	userID, err := auth.ValidateRefreshToken(req.RefreshToken)
+	if fromCookie && !auth.VerifyCSRF(r) {
+		http.Error(w, "Invalid CSRF token", http.StatusForbidden)
+		return
+	}
+
+	// Validate refresh token signature and claims
+	userID, tokenID, err := h.tokens.ValidateRefreshToken(presented)
 	if err != nil {
 		http.Error(w, "Invalid refresh token", http.StatusUnauthorized)
 		return
 	}
 
+	// Look up the server-side record; unknown tokens are rejected
+	stored, err := h.refreshRepo.GetByJTIHash(r.Context(), auth.HashTokenHex(tokenID))
+	if err != nil {
+		if !errors.Is(err, database.ErrRefreshTokenNotFound) {
+			slog.ErrorContext(r.Context(), "refresh token lookup failed", "error", err)
+		}
+		http.Error(w, "Invalid refresh token", http.StatusUnauthorized)
+		return
+	}
+
+	if stored.UserID != userID {
+		http.Error(w, "Invalid refresh token", http.StatusUnauthorized)
+		return
+	}
+
+	// A revoked token being presented again means it was stolen or replayed:
+	// revoke the whole family so neither party can keep using it
+	if stored.RevokedAt != nil {
+		h.revokeFamilyOnReuse(r, stored)
+		http.Error(w, "Invalid refresh token", http.StatusUnauthorized)
+		return
+	}
+
+	if !stored.IsActive(time.Now()) {
+		http.Error(w, "Invalid refresh token", http.StatusUnauthorized)
+		return
+	}
+
 	// Get user details to include role information
 	user, err := h.userRepo.GetByID(r.Context(), userID)
 	if err != nil {
@@ -155,28 +280,237 @@ func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Sessions from before 2FA became mandatory end here; logging in again
+	// leads the user through enrollment
+	if h.requireTwoFactor && !user.TOTPEnabled {
+		slog.WarnContext(r.Context(), "refresh refused: two-factor enrollment required", "user_id", user.ID)
+		http.Error(w, "Two-factor enrollment required, log in again", http.StatusForbidden)
+		return
+	}
+
+	// Rotate: issue a replacement in the same family and revoke the presented token
+	newRefreshToken, err := h.issueRefreshToken(r, user.ID, stored.FamilyID, stored.ID)
+	if err != nil {
+		if errors.Is(err, database.ErrRefreshTokenReused) {
+			h.revokeFamilyOnReuse(r, stored)
+			http.Error(w, "Invalid refresh token", http.StatusUnauthorized)
+			return
+		}
+		slog.ErrorContext(r.Context(), "refresh token rotation failed", "error", err)
+		http.Error(w, "Error generating refresh token", http.StatusInternalServerError)
+		return
+	}
+
 	// Generate new access token
-	newAccessToken, err := auth.GenerateToken(user.ID, user.Role)
+	newAccessToken, err := h.tokens.GenerateToken(user.ID, user.Role)
 	if err != nil {
 		http.Error(w, "Error generating token", http.StatusInternalServerError)
 		return
 	}
 
-	// Return new access token in response body
-	w.Header().Set("Content-Type", "application/json")
-	resp := RefreshResponse{
-		AccessToken: newAccessToken,
+	var resp RefreshResponse
+	if h.cookies != nil && fromCookie {
+		csrfToken, err := h.setSessionCookies(w, r, newAccessToken, newRefreshToken)
+		if err != nil {
+			slog.ErrorContext(r.Context(), "CSRF token generation failed", "error", err)
+			http.Error(w, "Error generating token", http.StatusInternalServerError)
+			return
+		}
+		resp.CSRFToken = csrfToken
+	} else {
+		resp.AccessToken = newAccessToken
+		resp.RefreshToken = newRefreshToken.Token
 	}
+
+	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(resp)
 }
 
 func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
-	// For token-based auth without cookies, the client simply discards the tokens
-	// The server doesn't need to do anything special
-	// In a production system, you might want to blacklist the token
+	// Revoke the refresh token so it can't mint new access tokens.
+	// Access tokens are short lived and simply expire.
+	presented, fromCookie, _ := h.refreshTokenFromRequest(r)
+	if fromCookie && !auth.VerifyCSRF(r) {
+		http.Error(w, "Invalid CSRF token", http.StatusForbidden)
+		return
+	}
+
+	if presented != "" {
+		if _, tokenID, err := h.tokens.ValidateRefreshToken(presented); err == nil {
+			stored, err := h.refreshRepo.GetByJTIHash(r.Context(), auth.HashTokenHex(tokenID))
+			if err == nil {
+				if err := h.refreshRepo.Revoke(r.Context(), stored.ID); err != nil {
+					slog.ErrorContext(r.Context(), "failed to revoke refresh token on logout", "error", err)
+					http.Error(w, "Failed to log out", http.StatusInternalServerError)
+					return
+				}
+			}
+		}
+	}
 
+	if h.cookies != nil {
+		h.cookies.ClearSession(w)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(map[string]bool{
 		"success": true,
 	})
 }
+
+// RevokeAllSessions signs the current user out of every device
+func (h *AuthHandler) RevokeAllSessions(w http.ResponseWriter, r *http.Request) {
+	claims, ok := auth.ExtractClaimsFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	revoked, err := h.refreshRepo.RevokeAllForUser(r.Context(), claims.UserID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to revoke sessions", "user_id", claims.UserID, "error", err)
+		http.Error(w, "Failed to revoke sessions", http.StatusInternalServerError)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "sessions revoked", "user_id", claims.UserID, "revoked", revoked)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]int64{
+		"revoked": revoked,
+	})
+}
+
+// issueRefreshToken signs a refresh token and persists it. When replacesID is
+// non-zero the stored token with that ID is rotated out in the same transaction.
+func (h *AuthHandler) issueRefreshToken(r *http.Request, userID int, familyID string, replacesID int) (*auth.RefreshToken, error) {
+	refreshToken, err := h.tokens.GenerateRefreshToken(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	device := r.UserAgent()
+	if len(device) > maxDeviceLength {
+		device = device[:maxDeviceLength]
+	}
+
+	record := &models.RefreshToken{
+		JTIHash:   auth.HashTokenHex(refreshToken.ID),
+		FamilyID:  familyID,
+		UserID:    userID,
+		Device:    device,
+		ExpiresAt: refreshToken.ExpiresAt,
+	}
+
+	if replacesID == 0 {
+		err = h.refreshRepo.Create(r.Context(), record)
+	} else {
+		err = h.refreshRepo.Rotate(r.Context(), replacesID, record)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return refreshToken, nil
+}
+
+// loginRetryAfter returns how long the user must wait before another login
+// attempt is accepted, or zero if one is allowed now
+func (h *AuthHandler) loginRetryAfter(user *models.User, now time.Time) time.Duration {
+	if user.IsLocked(now) {
+		return user.LockedUntil.Sub(now)
+	}
+
+	if user.LastFailedLoginAt == nil {
+		return 0
+	}
+	nextAllowed := user.LastFailedLoginAt.Add(h.lockout.Delay(user.FailedLoginAttempts))
+	if now.Before(nextAllowed) {
+		return nextAllowed.Sub(now)
+	}
+	return 0
+}
+
+// recordFailedLogin counts a failed password check and locks the account, with
+// an email alert, once the policy threshold is reached
+func (h *AuthHandler) recordFailedLogin(r *http.Request, user *models.User) {
+	lockedUntil := time.Now().Add(h.lockout.LockDuration)
+	attempts, locked, err := h.userRepo.RecordFailedLogin(r.Context(), user.ID, h.lockout.LockAfter, lockedUntil)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to record failed login", "user_id", user.ID, "error", err)
+		return
+	}
+
+	if !locked {
+		slog.WarnContext(r.Context(), "consecutive failed logins", "user_id", user.ID, "attempts", attempts)
+		return
+	}
+
+	slog.WarnContext(r.Context(), "account locked after repeated failed logins",
+		"user_id", user.ID, "locked_until", lockedUntil, "remote_ip", r.RemoteAddr)
+
+	if h.emailService != nil {
+		// Send asynchronously so the response time doesn't reveal the lock
+		username, email, remoteAddr := user.Username, user.Email, r.RemoteAddr
+		ctx := context.WithoutCancel(r.Context())
+		h.emailService.Async(func() {
+			h.emailService.SendAccountLockedAlert(ctx, username, email, lockedUntil, remoteAddr)
+		})
+	}
+}
+
+// refreshTokenFromRequest reads the refresh token from the JSON body or, in
+// cookie mode, from the refresh cookie. fromCookie reports which was used.
+func (h *AuthHandler) refreshTokenFromRequest(r *http.Request) (token string, fromCookie bool, err error) {
+	var req RefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		return "", false, err
+	}
+	if req.RefreshToken != "" {
+		return req.RefreshToken, false, nil
+	}
+
+	if h.cookies != nil {
+		if cookie, err := r.Cookie(auth.RefreshTokenCookie); err == nil && cookie.Value != "" {
+			return cookie.Value, true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// setSessionCookies writes the session cookies and returns the CSRF token the
+// SPA must echo in the X-CSRF-Token header. An existing CSRF cookie is kept so
+// other open tabs stay valid across a refresh.
+func (h *AuthHandler) setSessionCookies(w http.ResponseWriter, r *http.Request, accessToken string,
+	refreshToken *auth.RefreshToken) (string, error) {
+	var csrfToken string
+	if cookie, err := r.Cookie(auth.CSRFCookie); err == nil && cookie.Value != "" {
+		csrfToken = cookie.Value
+	} else {
+		csrfToken, err = auth.GenerateCSRFToken()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	h.cookies.SetSession(w, accessToken, time.Now().Add(h.tokens.AccessTokenExpiry()),
+		refreshToken.Token, refreshToken.ExpiresAt, csrfToken)
+	return csrfToken, nil
+}
+
+// revokeFamilyOnReuse handles refresh token reuse detection
+func (h *AuthHandler) revokeFamilyOnReuse(r *http.Request, stored *models.RefreshToken) {
+	slog.WarnContext(r.Context(), "refresh token reuse detected; revoking all tokens in family",
+		"user_id", stored.UserID, "family_id", stored.FamilyID)
+	if err := h.refreshRepo.RevokeFamily(r.Context(), stored.FamilyID); err != nil {
+		slog.ErrorContext(r.Context(), "failed to revoke refresh token family", "family_id", stored.FamilyID, "error", err)
+	}
+}
+
+// JWKS publishes the public keys tokens are signed with
+func (h *AuthHandler) JWKS(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	json.NewEncoder(w).Encode(h.tokens.KeyRing().JWKS())
+}
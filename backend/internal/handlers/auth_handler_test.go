@@ -0,0 +1,415 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/auth"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/config"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/database"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/handlers"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// MockRefreshTokenRepository is an in-memory refresh token store for testing
+type MockRefreshTokenRepository struct {
+	mu     sync.Mutex
+	nextID int
+	tokens map[int]*models.RefreshToken
+}
+
+func NewMockRefreshTokenRepository() *MockRefreshTokenRepository {
+	return &MockRefreshTokenRepository{tokens: map[int]*models.RefreshToken{}}
+}
+
+func (m *MockRefreshTokenRepository) Create(ctx context.Context, token *models.RefreshToken) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nextID++
+	token.ID = m.nextID
+	token.CreatedAt = time.Now()
+	stored := *token
+	m.tokens[token.ID] = &stored
+	return nil
+}
+
+func (m *MockRefreshTokenRepository) GetByJTIHash(ctx context.Context, jtiHash string) (*models.RefreshToken, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, t := range m.tokens {
+		if t.JTIHash == jtiHash {
+			found := *t
+			return &found, nil
+		}
+	}
+	return nil, database.ErrRefreshTokenNotFound
+}
+
+func (m *MockRefreshTokenRepository) Rotate(ctx context.Context, oldID int, next *models.RefreshToken) error {
+	m.mu.Lock()
+	old := m.tokens[oldID]
+	if old == nil || old.RevokedAt != nil {
+		m.mu.Unlock()
+		return database.ErrRefreshTokenReused
+	}
+	now := time.Now()
+	old.RevokedAt = &now
+	m.mu.Unlock()
+	return m.Create(ctx, next)
+}
+
+func (m *MockRefreshTokenRepository) Revoke(ctx context.Context, id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if t := m.tokens[id]; t != nil && t.RevokedAt == nil {
+		now := time.Now()
+		t.RevokedAt = &now
+	}
+	return nil
+}
+
+func (m *MockRefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	for _, t := range m.tokens {
+		if t.FamilyID == familyID && t.RevokedAt == nil {
+			t.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (m *MockRefreshTokenRepository) RevokeAllForUser(ctx context.Context, userID int) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	var revoked int64
+	for _, t := range m.tokens {
+		if t.UserID == userID && t.RevokedAt == nil {
+			t.RevokedAt = &now
+			revoked++
+		}
+	}
+	return revoked, nil
+}
+
+func (m *MockRefreshTokenRepository) GetActiveForUser(ctx context.Context, userID int) ([]*models.RefreshToken, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	active := []*models.RefreshToken{}
+	for _, t := range m.tokens {
+		if t.UserID == userID && t.IsActive(time.Now()) {
+			active = append(active, t)
+		}
+	}
+	return active, nil
+}
+
+// Verify interface implementation
+var _ database.RefreshTokenRepositoryInterface = &MockRefreshTokenRepository{}
+
+const testPassword = "Cold-Brew-2025!"
+
+func newTestTokenService(t *testing.T) *auth.TokenService {
+	t.Helper()
+	tokens, err := auth.NewTokenService(&config.Config{Auth: config.AuthConfig{
+		TokenExpiry:        15 * time.Minute,
+		RefreshTokenExpiry: 24 * time.Hour,
+	}})
+	if err != nil {
+		t.Fatalf("Failed to create token service: %v", err)
+	}
+	return tokens
+}
+
+func newTestAuthHandler(t *testing.T) (*handlers.AuthHandler, *MockRefreshTokenRepository) {
+	t.Helper()
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(testPassword), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("Failed to hash password: %v", err)
+	}
+	user := &models.User{ID: 1, Username: "owner", Password: string(hash), Role: "owner"}
+
+	userRepo := &MockUserRepository{
+		GetByIDFunc: func(ctx context.Context, id int) (*models.User, error) {
+			return user, nil
+		},
+		GetByUsernameFunc: func(ctx context.Context, username string) (*models.User, error) {
+			if username != user.Username {
+				return nil, database.ErrUserNotFound
+			}
+			return user, nil
+		},
+	}
+	refreshRepo := NewMockRefreshTokenRepository()
+
+	return handlers.NewAuthHandler(userRepo, refreshRepo, nil, nil, newTestTokenService(t), nil, handlers.AuthOptions{}), refreshRepo
+}
+
+func doJSON(handler http.HandlerFunc, path string, body interface{}) *httptest.ResponseRecorder {
+	payload, _ := json.Marshal(body)
+	req := httptest.NewRequest("POST", path, bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler(w, req)
+	return w
+}
+
+func login(t *testing.T, h *handlers.AuthHandler) handlers.LoginResponse {
+	t.Helper()
+
+	w := doJSON(h.Login, "/api/v1/auth/login", handlers.LoginRequest{Username: "owner", Password: testPassword})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Login failed with status %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp handlers.LoginResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse login response: %v", err)
+	}
+	return resp
+}
+
+func refresh(h *handlers.AuthHandler, refreshToken string) (*httptest.ResponseRecorder, handlers.RefreshResponse) {
+	w := doJSON(h.RefreshToken, "/api/v1/auth/refresh", handlers.RefreshRequest{RefreshToken: refreshToken})
+	var resp handlers.RefreshResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	return w, resp
+}
+
+func TestRefreshTokenRotation(t *testing.T) {
+	h, _ := newTestAuthHandler(t)
+	loginResp := login(t, h)
+
+	w, first := refresh(h, loginResp.RefreshToken)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected refresh to succeed, got %d: %s", w.Code, w.Body.String())
+	}
+	if first.RefreshToken == "" || first.RefreshToken == loginResp.RefreshToken {
+		t.Fatal("Expected a new rotated refresh token")
+	}
+	if first.AccessToken == "" {
+		t.Error("Expected a new access token")
+	}
+
+	w, _ = refresh(h, first.RefreshToken)
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected rotated token to be usable, got %d", w.Code)
+	}
+}
+
+func TestRefreshTokenReuseRevokesFamily(t *testing.T) {
+	h, repo := newTestAuthHandler(t)
+	loginResp := login(t, h)
+
+	_, rotated := refresh(h, loginResp.RefreshToken)
+
+	// Replaying the original token is reuse
+	w, _ := refresh(h, loginResp.RefreshToken)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected reused token to be rejected, got %d", w.Code)
+	}
+
+	// The legitimate rotated token must now be revoked as well
+	w, _ = refresh(h, rotated.RefreshToken)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected token family to be revoked after reuse, got %d", w.Code)
+	}
+
+	active, _ := repo.GetActiveForUser(context.Background(), 1)
+	if len(active) != 0 {
+		t.Errorf("Expected no active sessions, got %d", len(active))
+	}
+}
+
+func TestLogoutRevokesRefreshToken(t *testing.T) {
+	h, _ := newTestAuthHandler(t)
+	loginResp := login(t, h)
+
+	w := doJSON(h.Logout, "/api/v1/auth/logout", handlers.LogoutRequest{RefreshToken: loginResp.RefreshToken})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected logout to succeed, got %d", w.Code)
+	}
+
+	w, _ = refresh(h, loginResp.RefreshToken)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected refresh after logout to be rejected, got %d", w.Code)
+	}
+}
+
+func TestCookieModeLoginAndRefresh(t *testing.T) {
+	hash, _ := bcrypt.GenerateFromPassword([]byte(testPassword), bcrypt.MinCost)
+	user := &models.User{ID: 1, Username: "owner", Password: string(hash), Role: "owner"}
+	userRepo := &MockUserRepository{
+		GetByIDFunc: func(ctx context.Context, id int) (*models.User, error) {
+			return user, nil
+		},
+		GetByUsernameFunc: func(ctx context.Context, username string) (*models.User, error) {
+			return user, nil
+		},
+	}
+	cookies, err := auth.NewCookieSettings("", true, "strict")
+	if err != nil {
+		t.Fatalf("Failed to build cookie settings: %v", err)
+	}
+	h := handlers.NewAuthHandler(userRepo, NewMockRefreshTokenRepository(), nil, nil, newTestTokenService(t), nil, handlers.AuthOptions{Cookies: cookies})
+
+	w := doJSON(h.Login, "/api/v1/auth/login", handlers.LoginRequest{Username: "owner", Password: testPassword})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Login failed with status %d: %s", w.Code, w.Body.String())
+	}
+
+	var loginResp handlers.LoginResponse
+	json.Unmarshal(w.Body.Bytes(), &loginResp)
+	if loginResp.Token != "" || loginResp.RefreshToken != "" {
+		t.Error("Tokens must not be returned in the body in cookie mode")
+	}
+	if loginResp.CSRFToken == "" {
+		t.Fatal("Expected a CSRF token in the login response")
+	}
+
+	set := map[string]*http.Cookie{}
+	for _, c := range w.Result().Cookies() {
+		set[c.Name] = c
+	}
+	for _, name := range []string{auth.AccessTokenCookie, auth.RefreshTokenCookie} {
+		c := set[name]
+		if c == nil {
+			t.Fatalf("Expected cookie %s to be set", name)
+		}
+		if !c.HttpOnly || !c.Secure || c.SameSite != http.SameSiteStrictMode {
+			t.Errorf("Cookie %s has insecure attributes: %+v", name, c)
+		}
+	}
+	if set[auth.CSRFCookie] == nil || set[auth.CSRFCookie].HttpOnly {
+		t.Fatal("Expected a readable CSRF cookie")
+	}
+
+	refreshWithCookies := func(csrfHeader string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/v1/auth/refresh", nil)
+		req.AddCookie(set[auth.RefreshTokenCookie])
+		req.AddCookie(set[auth.CSRFCookie])
+		if csrfHeader != "" {
+			req.Header.Set(auth.CSRFHeader, csrfHeader)
+		}
+		w := httptest.NewRecorder()
+		h.RefreshToken(w, req)
+		return w
+	}
+
+	if w := refreshWithCookies(""); w.Code != http.StatusForbidden {
+		t.Errorf("Expected refresh without CSRF header to be rejected, got %d", w.Code)
+	}
+
+	w = refreshWithCookies(loginResp.CSRFToken)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected cookie refresh to succeed, got %d: %s", w.Code, w.Body.String())
+	}
+	var refreshResp handlers.RefreshResponse
+	json.Unmarshal(w.Body.Bytes(), &refreshResp)
+	if refreshResp.AccessToken != "" || refreshResp.CSRFToken != loginResp.CSRFToken {
+		t.Errorf("Unexpected cookie-mode refresh response: %+v", refreshResp)
+	}
+}
+
+func TestLoginThrottling(t *testing.T) {
+	hash, _ := bcrypt.GenerateFromPassword([]byte(testPassword), bcrypt.MinCost)
+	now := time.Now()
+	lockedUntil := now.Add(10 * time.Minute)
+	recentFailure := now.Add(-100 * time.Millisecond)
+	oldFailure := now.Add(-time.Minute)
+
+	tests := []struct {
+		name           string
+		user           *models.User
+		username       string
+		password       string
+		expectedStatus int
+		expectRecord   bool
+		expectReset    bool
+	}{
+		{
+			name:           "Unknown user",
+			username:       "nobody",
+			password:       testPassword,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Wrong password records failure",
+			user:           &models.User{ID: 1, Username: "owner", Password: string(hash), Role: "owner"},
+			username:       "owner",
+			password:       "wrong-password",
+			expectedStatus: http.StatusUnauthorized,
+			expectRecord:   true,
+		},
+		{
+			name: "Locked account refuses correct password",
+			user: &models.User{ID: 1, Username: "owner", Password: string(hash), Role: "owner",
+				LockedUntil: &lockedUntil},
+			username:       "owner",
+			password:       testPassword,
+			expectedStatus: http.StatusTooManyRequests,
+		},
+		{
+			name: "Progressive delay not yet elapsed",
+			user: &models.User{ID: 1, Username: "owner", Password: string(hash), Role: "owner",
+				FailedLoginAttempts: 3, LastFailedLoginAt: &recentFailure},
+			username:       "owner",
+			password:       testPassword,
+			expectedStatus: http.StatusTooManyRequests,
+		},
+		{
+			name: "Successful login after delay resets failures",
+			user: &models.User{ID: 1, Username: "owner", Password: string(hash), Role: "owner",
+				FailedLoginAttempts: 3, LastFailedLoginAt: &oldFailure},
+			username:       "owner",
+			password:       testPassword,
+			expectedStatus: http.StatusOK,
+			expectReset:    true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			userRepo := &MockUserRepository{
+				GetByUsernameFunc: func(ctx context.Context, username string) (*models.User, error) {
+					if tc.user == nil || username != tc.user.Username {
+						return nil, database.ErrUserNotFound
+					}
+					return tc.user, nil
+				},
+				RecordFailedLoginFunc: func(ctx context.Context, id int, lockAfter int, lockUntil time.Time) (int, bool, error) {
+					return 1, false, nil
+				},
+				ResetLoginFailuresFunc: func(ctx context.Context, id int) error {
+					return nil
+				},
+			}
+			h := handlers.NewAuthHandler(userRepo, NewMockRefreshTokenRepository(), nil, nil, newTestTokenService(t), nil, handlers.AuthOptions{})
+
+			w := doJSON(h.Login, "/api/v1/auth/login", handlers.LoginRequest{Username: tc.username, Password: tc.password})
+
+			if w.Code != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d (%s)", tc.expectedStatus, w.Code, w.Body.String())
+			}
+			if tc.expectedStatus == http.StatusTooManyRequests && w.Header().Get("Retry-After") == "" {
+				t.Error("Expected a Retry-After header")
+			}
+			if userRepo.RecordFailedLoginCalled != tc.expectRecord {
+				t.Errorf("Expected RecordFailedLogin called=%t", tc.expectRecord)
+			}
+			if userRepo.ResetLoginFailuresCalled != tc.expectReset {
+				t.Errorf("Expected ResetLoginFailures called=%t", tc.expectReset)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/auth"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/database"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
+)
+
+// Bulk applies one action to many bookings in a single transaction and
+// reports the outcome for each. With dryRun nothing is saved.
+func (h *BookingHandler) Bulk(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req models.BulkBookingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		slog.WarnContext(ctx, "invalid bulk booking request body", "error", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if msg := validateBulkBookingRequest(&req); msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	// The route requires bookings:write; deleting needs bookings:delete as well
+	claims, _ := auth.ExtractClaimsFromContext(ctx)
+	if req.Action == models.BulkActionDelete && !auth.HasPermission(claims, auth.PermBookingsDelete) {
+		http.Error(w, "Insufficient permissions", http.StatusForbidden)
+		return
+	}
+
+	results, err := h.repo.Bulk(ctx, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, database.ErrUserNotFound):
+			http.Error(w, "Assigned user not found", http.StatusBadRequest)
+		case errors.Is(err, database.ErrTooManyBookings):
+			http.Error(w, "Filter matches more than "+strconv.Itoa(models.MaxBulkBookings)+" bookings", http.StatusBadRequest)
+		default:
+			slog.ErrorContext(ctx, "bulk booking operation failed", "action", req.Action, "error", err)
+			http.Error(w, "Failed to apply bulk operation", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	response := models.BulkBookingResponse{
+		Action:  req.Action,
+		DryRun:  req.DryRun,
+		Results: results,
+	}
+	for _, result := range results {
+		if result.Result != models.BulkResultNotFound {
+			response.Matched++
+		}
+		if result.Result == models.BulkResultChanged {
+			response.Changed++
+			if !req.DryRun {
+				h.notify(ctx, bulkEvent(req.Action), result.Booking)
+			}
+		}
+	}
+
+	slog.InfoContext(ctx, "bulk booking operation", "action", req.Action, "dry_run", req.DryRun,
+		"matched", response.Matched, "changed", response.Changed)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// bulkEvent is the event sent for a booking changed by action
+func bulkEvent(action string) string {
+	switch action {
+	case models.BulkActionArchive:
+		return models.EventBookingArchived
+	case models.BulkActionDelete:
+		// Subscribers see a deleted booking as canceled
+		return models.EventBookingCanceled
+	default:
+		return models.EventBookingUpdated
+	}
+}
+
+// validateBulkBookingRequest returns a message describing the first problem with req
+func validateBulkBookingRequest(req *models.BulkBookingRequest) string {
+	switch {
+	case len(req.IDs) > 0 && req.Filter != nil:
+		return "Use either ids or filter, not both"
+	case len(req.IDs) == 0 && req.Filter == nil:
+		return "ids or filter is required"
+	case len(req.IDs) > models.MaxBulkBookings:
+		return "At most " + strconv.Itoa(models.MaxBulkBookings) + " ids are allowed"
+	}
+	for _, id := range req.IDs {
+		if id < 1 {
+			return "Invalid booking ID " + strconv.Itoa(id)
+		}
+	}
+
+	if f := req.Filter; f != nil {
+		if f.IsEmpty() {
+			return "filter must set at least one field"
+		}
+		if msg := validateBookingFilter(f); msg != "" {
+			return msg
+		}
+	}
+
+	switch req.Action {
+	case models.BulkActionArchive, models.BulkActionUnarchive, models.BulkActionDelete, models.BulkActionReassign:
+	case models.BulkActionSetStatus:
+		if !models.IsValidBookingStatus(req.Status) {
+			return "status must be one of: " + strings.Join(models.BookingStatuses(), " ")
+		}
+	default:
+		return "action must be one of: " + strings.Join(models.BulkActions(), " ")
+	}
+	return ""
+}
+
+// validateBookingFilter returns a message describing the first problem with f
+func validateBookingFilter(f *models.BookingFilter) string {
+	if f.Status != "" && !models.IsValidBookingStatus(f.Status) {
+		return "Unknown status " + strconv.Quote(f.Status) + ", must be one of: " + strings.Join(models.BookingStatuses(), " ")
+	}
+	for _, date := range []string{f.DateFrom, f.DateTo} {
+		if _, err := time.Parse("2006-01-02", date); date != "" && err != nil {
+			return "Invalid date format. Use YYYY-MM-DD"
+		}
+	}
+	return ""
+}
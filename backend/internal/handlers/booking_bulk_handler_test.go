@@ -0,0 +1,150 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/auth"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/database"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/events"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/handlers"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
+)
+
+func TestBulkBookingHandler(t *testing.T) {
+	archived := &models.Booking{ID: 1, Name: "Ada", Archived: true, Status: models.BookingStatusCompleted}
+	changed := []models.BulkBookingResult{
+		{ID: 1, Result: models.BulkResultChanged, Booking: archived},
+		{ID: 2, Result: models.BulkResultUnchanged, Booking: &models.Booking{ID: 2, Archived: true}},
+		{ID: 3, Result: models.BulkResultNotFound},
+	}
+	pastMonth := &models.BookingFilter{DateTo: "2026-09-30"}
+	unknownUser := 99
+
+	tests := []struct {
+		name           string
+		body           interface{}
+		role           string
+		bulkErr        error
+		expectedStatus int
+		expectBulk     bool
+		expectedEvents int
+		expectedCounts [2]int // matched, changed
+	}{
+		{
+			name:           "Archive by IDs",
+			body:           models.BulkBookingRequest{IDs: []int{1, 2, 3}, Action: models.BulkActionArchive},
+			expectedStatus: http.StatusOK,
+			expectBulk:     true,
+			expectedEvents: 1,
+			expectedCounts: [2]int{2, 1},
+		},
+		{
+			name:           "Dry run sends no events",
+			body:           models.BulkBookingRequest{Filter: pastMonth, Action: models.BulkActionArchive, DryRun: true},
+			expectedStatus: http.StatusOK,
+			expectBulk:     true,
+			expectedCounts: [2]int{2, 1},
+		},
+		{
+			name:           "IDs and filter together",
+			body:           models.BulkBookingRequest{IDs: []int{1}, Filter: pastMonth, Action: models.BulkActionArchive},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Empty filter",
+			body:           models.BulkBookingRequest{Filter: &models.BookingFilter{}, Action: models.BulkActionDelete},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid filter date",
+			body:           models.BulkBookingRequest{Filter: &models.BookingFilter{DateFrom: "May 1"}, Action: models.BulkActionArchive},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Unknown action",
+			body:           models.BulkBookingRequest{IDs: []int{1}, Action: "refund"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Set status needs a valid status",
+			body:           models.BulkBookingRequest{IDs: []int{1}, Action: models.BulkActionSetStatus, Status: "done"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Delete needs bookings:delete",
+			body:           models.BulkBookingRequest{IDs: []int{1}, Action: models.BulkActionDelete},
+			role:           string(auth.RoleBarista),
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Reassign to unknown user",
+			body:           models.BulkBookingRequest{IDs: []int{1}, Action: models.BulkActionReassign, AssignedUserID: &unknownUser},
+			bulkErr:        database.ErrUserNotFound,
+			expectedStatus: http.StatusBadRequest,
+			expectBulk:     true,
+		},
+		{
+			name:           "Filter matches too many bookings",
+			body:           models.BulkBookingRequest{Filter: pastMonth, Action: models.BulkActionArchive},
+			bulkErr:        database.ErrTooManyBookings,
+			expectedStatus: http.StatusBadRequest,
+			expectBulk:     true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := &MockBookingRepository{
+				BulkFunc: func(ctx context.Context, req *models.BulkBookingRequest) ([]models.BulkBookingResult, error) {
+					if tc.bulkErr != nil {
+						return nil, tc.bulkErr
+					}
+					return changed, nil
+				},
+			}
+			broker := events.NewBroker(10)
+			sub, _, _ := broker.Subscribe("")
+			handler := handlers.NewBookingHandler(mockRepo, nil, nil, broker)
+
+			role := tc.role
+			if role == "" {
+				role = string(auth.RoleManager)
+			}
+			body, _ := json.Marshal(tc.body)
+			req := httptest.NewRequest("POST", "/api/v1/bookings/bulk", bytes.NewReader(body))
+			req = req.WithContext(context.WithValue(req.Context(), auth.ClaimsContextKey, &auth.Claims{UserID: 1, Role: role}))
+			w := httptest.NewRecorder()
+
+			handler.Bulk(w, req)
+
+			if w.Code != tc.expectedStatus {
+				t.Fatalf("Expected status %d, got %d (%s)", tc.expectedStatus, w.Code, w.Body.String())
+			}
+			if mockRepo.BulkCalled != tc.expectBulk {
+				t.Errorf("Expected Bulk called=%v", tc.expectBulk)
+			}
+			if len(sub.Events()) != tc.expectedEvents {
+				t.Errorf("Expected %d events, got %d", tc.expectedEvents, len(sub.Events()))
+			}
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var response models.BulkBookingResponse
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if [2]int{response.Matched, response.Changed} != tc.expectedCounts || len(response.Results) != len(changed) {
+				t.Errorf("Unexpected response %+v", response)
+			}
+			if response.DryRun != mockRepo.BulkRequest.DryRun {
+				t.Errorf("Expected dryRun to be passed through")
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/export"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
+)
+
+// exportWriteTimeout replaces the server's write timeout for exports, which
+// can take longer to stream than an ordinary response
+const exportWriteTimeout = 10 * time.Minute
+
+// Export streams the bookings matching the list filters as a CSV or Excel
+// file. The columns parameter picks and orders the columns; by default every
+// column is included.
+func (h *BookingHandler) Export(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+
+	format := query.Get("format")
+	switch format {
+	case "":
+		format = export.FormatCSV
+	case export.FormatCSV, export.FormatXLSX:
+	default:
+		http.Error(w, "format must be csv or xlsx", http.StatusBadRequest)
+		return
+	}
+
+	columns, err := export.ParseColumns(query.Get("columns"))
+	if err != nil {
+		http.Error(w, "Invalid columns: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	filter, msg := bookingListFilter(query)
+	if msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Now().Add(exportWriteTimeout)); err != nil && err != http.ErrNotSupported {
+		slog.ErrorContext(ctx, "failed to extend write deadline", "error", err)
+	}
+
+	out := &countingWriter{w: w}
+	writer, err := export.NewWriter(format, out, columns)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to start booking export", "format", format, "error", err)
+		http.Error(w, "Failed to export bookings", http.StatusInternalServerError)
+		return
+	}
+
+	filename := fmt.Sprintf("bookings-%s.%s", time.Now().Format("2006-01-02"), format)
+	w.Header().Set("Content-Type", export.ContentType(format))
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+
+	rows := 0
+	err = h.repo.Stream(ctx, filter, func(booking *models.Booking) error {
+		rows++
+		return writer.Write(booking)
+	})
+	if err == nil {
+		err = writer.Close()
+	} else {
+		writer.Abort()
+	}
+
+	if err != nil {
+		slog.ErrorContext(ctx, "booking export failed", "format", format, "rows", rows, "error", err)
+		if out.n == 0 {
+			w.Header().Del("Content-Disposition")
+			http.Error(w, "Failed to export bookings", http.StatusInternalServerError)
+			return
+		}
+		// Part of the file has been sent; abort the connection so the client
+		// sees a failed download rather than a file that looks complete
+		panic(http.ErrAbortHandler)
+	}
+
+	slog.InfoContext(ctx, "exported bookings", "format", format, "rows", rows, "columns", len(columns))
+}
+
+// countingWriter counts the bytes written through it, so a failed export
+// can tell whether the response has started
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
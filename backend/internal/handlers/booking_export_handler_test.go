@@ -0,0 +1,111 @@
+package handlers_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/handlers"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
+)
+
+func TestExportBookingsHandler(t *testing.T) {
+	bookings := []*models.Booking{
+		{ID: 1, Name: "Ada", Date: "2026-10-01", CoffeeFlavors: []string{"vanilla", "mocha"}},
+		{ID: 2, Name: "Grace", Date: "2026-10-02", CoffeeFlavors: []string{"caramel"}},
+	}
+
+	tests := []struct {
+		name                string
+		query               string
+		streamErr           error
+		expectedStatus      int
+		expectedContentType string
+		expectedBody        string
+		checkFilter         func(t *testing.T, f *models.BookingFilter)
+	}{
+		{
+			name:                "CSV with chosen columns",
+			query:               "?columns=id,name,coffeeFlavors",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/csv; charset=utf-8",
+			expectedBody:        "ID,Name,Coffee Flavors\n1,Ada,vanilla; mocha\n2,Grace,caramel\n",
+			checkFilter: func(t *testing.T, f *models.BookingFilter) {
+				if f.Archived == nil || *f.Archived {
+					t.Errorf("Expected archived bookings to be left out by default")
+				}
+			},
+		},
+		{
+			name:                "Excel",
+			query:               "?format=xlsx",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+		},
+		{
+			name:           "Filters are passed through",
+			query:          "?include_archived=true&status=confirmed&dateFrom=2026-10-01&dateTo=2026-10-31&assignedUserId=4",
+			expectedStatus: http.StatusOK,
+			checkFilter: func(t *testing.T, f *models.BookingFilter) {
+				if f.Archived != nil || f.Status != "confirmed" || f.DateFrom != "2026-10-01" ||
+					f.DateTo != "2026-10-31" || f.AssignedUserID == nil || *f.AssignedUserID != 4 {
+					t.Errorf("Unexpected filter %+v", f)
+				}
+			},
+		},
+		{name: "Unknown format", query: "?format=pdf", expectedStatus: http.StatusBadRequest},
+		{name: "Unknown column", query: "?columns=id,price", expectedStatus: http.StatusBadRequest},
+		{name: "Invalid date", query: "?dateFrom=October", expectedStatus: http.StatusBadRequest},
+		{name: "Invalid status", query: "?status=done", expectedStatus: http.StatusBadRequest},
+		{name: "Invalid assignee", query: "?assignedUserId=me", expectedStatus: http.StatusBadRequest},
+		{name: "Database error", streamErr: errors.New("connection lost"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := &MockBookingRepository{
+				StreamFunc: func(ctx context.Context, f *models.BookingFilter, fn func(*models.Booking) error) error {
+					if tc.streamErr != nil {
+						return tc.streamErr
+					}
+					for _, b := range bookings {
+						if err := fn(b); err != nil {
+							return err
+						}
+					}
+					return nil
+				},
+			}
+			handler := handlers.NewBookingHandler(mockRepo, nil, nil, nil)
+
+			w := httptest.NewRecorder()
+			handler.Export(w, httptest.NewRequest("GET", "/api/v1/bookings/export"+tc.query, nil))
+
+			if w.Code != tc.expectedStatus {
+				t.Fatalf("Expected status %d, got %d (%s)", tc.expectedStatus, w.Code, w.Body.String())
+			}
+			if tc.expectedStatus != http.StatusOK {
+				if w.Header().Get("Content-Disposition") != "" {
+					t.Errorf("Expected no attachment for an error")
+				}
+				return
+			}
+
+			if tc.expectedContentType != "" && w.Header().Get("Content-Type") != tc.expectedContentType {
+				t.Errorf("Expected Content-Type %s, got %s", tc.expectedContentType, w.Header().Get("Content-Type"))
+			}
+			if !strings.HasPrefix(w.Header().Get("Content-Disposition"), `attachment; filename="bookings-`) {
+				t.Errorf("Expected an attachment, got %q", w.Header().Get("Content-Disposition"))
+			}
+			if tc.expectedBody != "" && w.Body.String() != tc.expectedBody {
+				t.Errorf("Expected body:\n%s\ngot:\n%s", tc.expectedBody, w.Body.String())
+			}
+			if tc.checkFilter != nil {
+				tc.checkFilter(t, mockRepo.StreamFilter)
+			}
+		})
+	}
+}
@@ -1,18 +1,19 @@
 package handlers
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
-	"log"
+	"log/slog"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/joshuagudgel/toasted-coffee/backend/internal/database"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/events"
 	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
 	"github.com/joshuagudgel/toasted-coffee/backend/internal/services"
 )
@@ -21,79 +22,73 @@ import (
 type BookingHandler struct {
 	repo         database.BookingRepositoryInterface
 	emailService *services.EmailService
+	webhooks     *services.WebhookService
+	events       *events.Broker
 }
 
 // NewBookingHandler creates a new booking handler
-func NewBookingHandler(repo database.BookingRepositoryInterface, emailService *services.EmailService) *BookingHandler {
+func NewBookingHandler(repo database.BookingRepositoryInterface, emailService *services.EmailService,
+	webhooks *services.WebhookService, broker *events.Broker) *BookingHandler {
 	return &BookingHandler{
 		repo:         repo,
 		emailService: emailService,
+		webhooks:     webhooks,
+		events:       broker,
 	}
 }
 
+// notify sends a booking change to webhooks and the admin event stream
+func (h *BookingHandler) notify(ctx context.Context, event string, booking *models.Booking) {
+	h.webhooks.Emit(ctx, event, booking)
+	h.events.Publish(ctx, event, booking)
+}
+
 // Create handles creation of a new booking
 func (h *BookingHandler) Create(w http.ResponseWriter, r *http.Request) {
 	var booking models.Booking
-
-	// Log the incoming request
-	body, _ := io.ReadAll(r.Body)
-	r.Body = io.NopCloser(bytes.NewBuffer(body))
-	log.Printf("Received booking request: %s", string(body))
+	ctx := r.Context()
 
 	if err := json.NewDecoder(r.Body).Decode(&booking); err != nil {
-		log.Printf("Error decoding request body: %v", err)
+		slog.WarnContext(ctx, "invalid booking request body", "error", err)
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
-	// Validate the booking email and phone
-	if booking.Email == "" && booking.Phone == "" {
-		log.Println("Booking rejected: no contact information provided")
-		http.Error(w, "Email or phone number is required", http.StatusBadRequest)
+	if err := models.ValidateNewBooking(&booking); err != nil {
+		slog.InfoContext(ctx, "booking rejected", "reason", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	_, err := time.Parse("2006-01-02", booking.Date)
+	// Log what was booked, not who booked it
+	slog.InfoContext(ctx, "creating booking",
+		"date", booking.Date, "people", booking.People, "package", booking.Package)
+
+	id, err := h.repo.Create(ctx, &booking)
 	if err != nil {
-		http.Error(w, "Invalid date format. Use YYYY-MM-DD", http.StatusBadRequest)
+		slog.ErrorContext(ctx, "failed to create booking", "error", err)
+		h.sendFailureAlert(ctx, &booking, err)
+		http.Error(w, "Failed to create booking", http.StatusInternalServerError)
 		return
 	}
 
-	// Log the decoded booking
-	log.Printf("Decoded booking: %+v", booking)
+	slog.InfoContext(ctx, "booking created", "booking_id", id)
 
-	id, err := h.repo.Create(r.Context(), &booking)
-	if err != nil {
-		log.Printf("Error creating booking: %v", err)
+	booking.ID = id
+	h.notify(ctx, models.EventBookingCreated, &booking)
 
-		// Send failure notification email
-		emailErr := h.emailService.SendBookingFailureAlert(
+	// Send confirmation email; a failure is logged but doesn't fail the request
+	if h.emailService != nil {
+		h.emailService.SendBookingConfirmation(
+			ctx,
+			id,
 			booking.Name,
-			booking.Email,
-			booking.Phone,
-			fmt.Sprintf("Database error: %v", err),
+			booking.Date,
+			booking.Time,
+			booking.Location,
+			booking.People,
+			booking.Package,
 		)
-		if emailErr != nil {
-			log.Printf("Failed to send booking failure alert: %v", emailErr)
-		}
-
-		http.Error(w, "Failed to create booking", http.StatusInternalServerError)
-		return
-	}
-
-	// Send confirmation email
-	emailErr := h.emailService.SendBookingConfirmation(
-		id,
-		booking.Name,
-		booking.Date,
-		booking.Time,
-		booking.Location,
-		booking.People,
-		booking.Package,
-	)
-	if emailErr != nil {
-		// Just log the error but don't fail the request
-		log.Printf("Failed to send confirmation email: %v", emailErr)
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -104,16 +99,30 @@ func (h *BookingHandler) Create(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// sendFailureAlert tells the notification inbox about a booking that couldn't
+// be saved so the customer can still be contacted
+func (h *BookingHandler) sendFailureAlert(ctx context.Context, booking *models.Booking, cause error) {
+	if h.emailService == nil {
+		return
+	}
+	h.emailService.SendBookingFailureAlert(
+		ctx,
+		booking.Name,
+		booking.Email,
+		booking.Phone,
+		fmt.Sprintf("Database error: %v", cause),
+	)
+}
+
 // GetByID retrieves a booking by ID
 func (h *BookingHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	// Parse the ID from the URL
 	idStr := chi.URLParam(r, "id")
-	log.Printf("GetByID request for booking: %s", idStr)
 
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
 		// Handle invalid ID format specifically
-		log.Printf("Invalid booking ID format: %s", idStr)
+		slog.InfoContext(r.Context(), "invalid booking ID", "id", idStr)
 		http.Error(w, "Invalid booking ID", http.StatusBadRequest)
 		return
 	}
@@ -121,7 +130,7 @@ func (h *BookingHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	// Get the booking from the repository
 	booking, err := h.repo.GetByID(r.Context(), id)
 	if err != nil {
-		log.Printf("Error retrieving booking %d: %v", id, err)
+		slog.ErrorContext(r.Context(), "failed to retrieve booking", "booking_id", id, "error", err)
 
 		// Check for "not found" error specifically
 		if strings.Contains(err.Error(), "not found") {
@@ -136,7 +145,7 @@ func (h *BookingHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 
 	// Check if booking is nil even without an error
 	if booking == nil {
-		log.Printf("Booking not found with ID: %d", id)
+		slog.InfoContext(r.Context(), "booking not found", "booking_id", id)
 		http.Error(w, "Booking not found", http.StatusNotFound)
 		return
 	}
@@ -144,42 +153,67 @@ func (h *BookingHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	// Return the booking as JSON
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(booking); err != nil {
-		log.Printf("Error encoding booking response: %v", err)
+		slog.ErrorContext(r.Context(), "failed to encode booking", "booking_id", id, "error", err)
 		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 		return
 	}
 }
 
-// GetAll retrieves all bookings
+// GetAll retrieves the bookings matching the list filters
 func (h *BookingHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	filter, msg := bookingListFilter(r.URL.Query())
+	if msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
 
-	includeArchived := r.URL.Query().Get("include_archived") == "true"
-	log.Printf("Fetching bookings, includeArchived: %v", includeArchived)
-
-	bookings, err := h.repo.GetAll(r.Context(), includeArchived)
+	bookings, err := h.repo.GetAll(r.Context(), filter)
 	if err != nil {
-		log.Printf("ERROR in GetAll: %v", err)
+		slog.ErrorContext(r.Context(), "failed to list bookings", "error", err)
 		http.Error(w, "Failed to retrieve bookings", http.StatusInternalServerError)
 		return
 	}
 
-	log.Printf("Found %d bookings", len(bookings))
+	slog.DebugContext(r.Context(), "listed bookings", "count", len(bookings), "include_archived", filter.Archived == nil)
 
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(bookings); err != nil {
-		log.Printf("ERROR encoding response: %v", err)
+		slog.ErrorContext(r.Context(), "failed to encode bookings", "error", err)
 		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 		return
 	}
 }
 
+// bookingListFilter reads the booking list filters, shared by the list and the
+// export, from query. Archived bookings are left out unless
+// include_archived=true.
+func bookingListFilter(query url.Values) (*models.BookingFilter, string) {
+	filter := &models.BookingFilter{
+		Status:   query.Get("status"),
+		DateFrom: query.Get("dateFrom"),
+		DateTo:   query.Get("dateTo"),
+	}
+	if query.Get("include_archived") != "true" {
+		archived := false
+		filter.Archived = &archived
+	}
+	if value := query.Get("assignedUserId"); value != "" {
+		id, err := strconv.Atoi(value)
+		if err != nil || id < 1 {
+			return nil, "Invalid assignedUserId"
+		}
+		filter.AssignedUserID = &id
+	}
+	return filter, validateBookingFilter(filter)
+}
+
 // Delete removes a booking
 func (h *BookingHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	// Parse booking ID from the URL
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		log.Printf("Invalid booking ID format: %s", idStr)
+		slog.InfoContext(r.Context(), "invalid booking ID", "id", idStr)
 		http.Error(w, "Invalid booking ID", http.StatusBadRequest)
 		return
 	}
@@ -187,7 +221,7 @@ func (h *BookingHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	// Check if the booking exists first
 	booking, err := h.repo.GetByID(r.Context(), id)
 	if err != nil {
-		log.Printf("Error checking booking existence %d: %v", id, err)
+		slog.ErrorContext(r.Context(), "failed to check booking", "booking_id", id, "error", err)
 
 		if strings.Contains(err.Error(), "not found") {
 			http.Error(w, "Booking not found", http.StatusNotFound)
@@ -207,7 +241,7 @@ func (h *BookingHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	// Delete the booking
 	err = h.repo.Delete(r.Context(), id)
 	if err != nil {
-		log.Printf("Error deleting booking %d: %v", id, err)
+		slog.ErrorContext(r.Context(), "failed to delete booking", "booking_id", id, "error", err)
 
 		if strings.Contains(err.Error(), "not found") {
 			http.Error(w, "Booking not found", http.StatusNotFound)
@@ -218,6 +252,9 @@ func (h *BookingHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Subscribers see a deleted booking as canceled
+	h.notify(r.Context(), models.EventBookingCanceled, booking)
+
 	// Return success with no content
 	w.WriteHeader(http.StatusNoContent) // 204 status code indicates successful deletion with no content to return
 }
@@ -228,7 +265,7 @@ func (h *BookingHandler) Update(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		log.Printf("Invalid booking ID format: %s", idStr)
+		slog.InfoContext(r.Context(), "invalid booking ID", "id", idStr)
 		http.Error(w, "Invalid booking ID", http.StatusBadRequest)
 		return
 	}
@@ -236,28 +273,22 @@ func (h *BookingHandler) Update(w http.ResponseWriter, r *http.Request) {
 	// Get current booking to check for archive status changes
 	currentBooking, err := h.repo.GetByID(r.Context(), id)
 	if err != nil || currentBooking == nil {
-		log.Printf("Cannot find booking to update: %d", id)
+		slog.InfoContext(r.Context(), "booking to update not found", "booking_id", id)
 		http.Error(w, "Booking not found", http.StatusNotFound)
 		return
 	}
 
 	// Parse request body
 	var booking models.Booking
-
-	// Log the incoming request
-	body, _ := io.ReadAll(r.Body)
-	r.Body = io.NopCloser(bytes.NewBuffer(body))
-	log.Printf("Received booking update request for ID %d: %s", id, string(body))
-
 	if err := json.NewDecoder(r.Body).Decode(&booking); err != nil {
-		log.Printf("Error decoding request body: %v", err)
+		slog.WarnContext(r.Context(), "invalid booking update body", "booking_id", id, "error", err)
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
 	// Validate booking data (same validation as Create)
 	if booking.Email == "" && booking.Phone == "" {
-		log.Println("Booking update rejected: no contact information provided")
+		slog.InfoContext(r.Context(), "booking update rejected: no contact information provided", "booking_id", id)
 		http.Error(w, "Email or phone number is required", http.StatusBadRequest)
 		return
 	}
@@ -280,17 +311,14 @@ func (h *BookingHandler) Update(w http.ResponseWriter, r *http.Request) {
 
 	// Track archive status changes
 	if currentBooking.Archived != booking.Archived {
-		if booking.Archived {
-			log.Printf("Booking %d is being archived via update", id)
-		} else {
-			log.Printf("Booking %d is being unarchived via update", id)
-		}
+		slog.InfoContext(r.Context(), "booking archive status changed via update",
+			"booking_id", id, "archived", booking.Archived)
 	}
 
 	// Update the booking
 	err = h.repo.Update(r.Context(), id, &booking)
 	if err != nil {
-		log.Printf("Error updating booking %d: %v", id, err)
+		slog.ErrorContext(r.Context(), "failed to update booking", "booking_id", id, "error", err)
 
 		if strings.Contains(err.Error(), "not found") {
 			http.Error(w, "Booking not found", http.StatusNotFound)
@@ -301,7 +329,16 @@ func (h *BookingHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	log.Printf("Successfully updated booking %d (archived status: %v)", id, booking.Archived)
+	slog.InfoContext(r.Context(), "booking updated", "booking_id", id, "archived", booking.Archived)
+
+	booking.ID = id
+	booking.CreatedAt = currentBooking.CreatedAt
+	booking.Status = currentBooking.Status
+	booking.AssignedUserID = currentBooking.AssignedUserID
+	h.notify(r.Context(), models.EventBookingUpdated, &booking)
+	if booking.Archived && !currentBooking.Archived {
+		h.notify(r.Context(), models.EventBookingArchived, &booking)
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
@@ -316,7 +353,7 @@ func (h *BookingHandler) Archive(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		log.Printf("Invalid booking ID format for archive: %s", idStr)
+		slog.InfoContext(r.Context(), "invalid booking ID", "id", idStr)
 		http.Error(w, "Invalid booking ID", http.StatusBadRequest)
 		return
 	}
@@ -324,27 +361,27 @@ func (h *BookingHandler) Archive(w http.ResponseWriter, r *http.Request) {
 	// Check if booking exists first
 	booking, err := h.repo.GetByID(r.Context(), id)
 	if err != nil {
-		log.Printf("Error checking booking existence %d: %v", id, err)
+		slog.ErrorContext(r.Context(), "failed to check booking", "booking_id", id, "error", err)
 		http.Error(w, "Failed to check booking", http.StatusInternalServerError)
 		return
 	}
 
 	if booking == nil {
-		log.Printf("Cannot archive non-existent booking: %d", id)
+		slog.InfoContext(r.Context(), "booking to archive not found", "booking_id", id)
 		http.Error(w, "Booking not found", http.StatusNotFound)
 		return
 	}
 
 	// Don't archive if already archived
 	if booking.Archived {
-		log.Printf("Booking %d is already archived", id)
+		slog.DebugContext(r.Context(), "booking already archived", "booking_id", id)
 		w.WriteHeader(http.StatusNoContent)
 		return
 	}
 
 	err = h.repo.Archive(r.Context(), id)
 	if err != nil {
-		log.Printf("Error archiving booking %d: %v", id, err)
+		slog.ErrorContext(r.Context(), "failed to archive booking", "booking_id", id, "error", err)
 
 		if strings.Contains(err.Error(), "not found") {
 			http.Error(w, "Booking not found", http.StatusNotFound)
@@ -355,7 +392,10 @@ func (h *BookingHandler) Archive(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	log.Printf("Successfully archived booking %d", id)
+	slog.InfoContext(r.Context(), "booking archived", "booking_id", id)
+
+	booking.Archived = true
+	h.notify(r.Context(), models.EventBookingArchived, booking)
 	w.WriteHeader(http.StatusNoContent)
 }
 
@@ -365,7 +405,7 @@ func (h *BookingHandler) Unarchive(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		log.Printf("Invalid booking ID format for unarchive: %s", idStr)
+		slog.InfoContext(r.Context(), "invalid booking ID", "id", idStr)
 		http.Error(w, "Invalid booking ID", http.StatusBadRequest)
 		return
 	}
@@ -373,27 +413,27 @@ func (h *BookingHandler) Unarchive(w http.ResponseWriter, r *http.Request) {
 	// Check if booking exists first
 	booking, err := h.repo.GetByID(r.Context(), id)
 	if err != nil {
-		log.Printf("Error checking booking existence %d: %v", id, err)
+		slog.ErrorContext(r.Context(), "failed to check booking", "booking_id", id, "error", err)
 		http.Error(w, "Failed to check booking", http.StatusInternalServerError)
 		return
 	}
 
 	if booking == nil {
-		log.Printf("Cannot unarchive non-existent booking: %d", id)
+		slog.InfoContext(r.Context(), "booking to unarchive not found", "booking_id", id)
 		http.Error(w, "Booking not found", http.StatusNotFound)
 		return
 	}
 
 	// Don't unarchive if already active
 	if !booking.Archived {
-		log.Printf("Booking %d is already active (not archived)", id)
+		slog.DebugContext(r.Context(), "booking already active", "booking_id", id)
 		w.WriteHeader(http.StatusNoContent)
 		return
 	}
 
 	err = h.repo.Unarchive(r.Context(), id)
 	if err != nil {
-		log.Printf("Error unarchiving booking %d: %v", id, err)
+		slog.ErrorContext(r.Context(), "failed to unarchive booking", "booking_id", id, "error", err)
 
 		if strings.Contains(err.Error(), "not found") {
 			http.Error(w, "Booking not found", http.StatusNotFound)
@@ -404,6 +444,6 @@ func (h *BookingHandler) Unarchive(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	log.Printf("Successfully unarchived booking %d", id)
+	slog.InfoContext(r.Context(), "booking unarchived", "booking_id", id)
 	w.WriteHeader(http.StatusNoContent)
 }
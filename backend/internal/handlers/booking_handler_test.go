@@ -31,9 +31,9 @@ type MockBookingRepository struct {
 	GetByIDArg    int
 
 	// GetAll
-	GetAllFunc            func(context.Context, bool) ([]*models.Booking, error)
-	GetAllCalled          bool
-	GetAllIncludeArchived bool
+	GetAllFunc   func(context.Context, *models.BookingFilter) ([]*models.Booking, error)
+	GetAllCalled bool
+	GetAllFilter *models.BookingFilter
 
 	// Delete
 	DeleteFunc   func(context.Context, int) error
@@ -55,6 +55,20 @@ type MockBookingRepository struct {
 	UnarchiveFunc   func(context.Context, int) error
 	UnarchiveCalled bool
 	UnarchiveArg    int
+
+	// Bulk
+	BulkFunc    func(context.Context, *models.BulkBookingRequest) ([]models.BulkBookingResult, error)
+	BulkCalled  bool
+	BulkRequest *models.BulkBookingRequest
+
+	// Stream
+	StreamFunc   func(context.Context, *models.BookingFilter, func(*models.Booking) error) error
+	StreamFilter *models.BookingFilter
+
+	// Import
+	ImportFunc   func(context.Context, []*models.Booking, bool) ([]models.BookingImportResult, error)
+	ImportCalled bool
+	Imported     []*models.Booking
 }
 
 // Implement interface methods with tracking
@@ -70,10 +84,10 @@ func (m *MockBookingRepository) GetByID(ctx context.Context, id int) (*models.Bo
 	return m.GetByIDFunc(ctx, id)
 }
 
-func (m *MockBookingRepository) GetAll(ctx context.Context, includeArchived bool) ([]*models.Booking, error) {
+func (m *MockBookingRepository) GetAll(ctx context.Context, filter *models.BookingFilter) ([]*models.Booking, error) {
 	m.GetAllCalled = true
-	m.GetAllIncludeArchived = includeArchived
-	return m.GetAllFunc(ctx, includeArchived)
+	m.GetAllFilter = filter
+	return m.GetAllFunc(ctx, filter)
 }
 
 func (m *MockBookingRepository) Delete(ctx context.Context, id int) error {
@@ -109,6 +123,32 @@ func (m *MockBookingRepository) Unarchive(ctx context.Context, id int) error {
 	return nil
 }
 
+func (m *MockBookingRepository) Bulk(ctx context.Context, req *models.BulkBookingRequest) ([]models.BulkBookingResult, error) {
+	m.BulkCalled = true
+	m.BulkRequest = req
+	if m.BulkFunc != nil {
+		return m.BulkFunc(ctx, req)
+	}
+	return nil, nil
+}
+
+func (m *MockBookingRepository) Stream(ctx context.Context, filter *models.BookingFilter, fn func(*models.Booking) error) error {
+	m.StreamFilter = filter
+	if m.StreamFunc != nil {
+		return m.StreamFunc(ctx, filter, fn)
+	}
+	return nil
+}
+
+func (m *MockBookingRepository) Import(ctx context.Context, bookings []*models.Booking, dryRun bool) ([]models.BookingImportResult, error) {
+	m.ImportCalled = true
+	m.Imported = bookings
+	if m.ImportFunc != nil {
+		return m.ImportFunc(ctx, bookings, dryRun)
+	}
+	return nil, nil
+}
+
 // Verify interface implementation
 var _ database.BookingRepositoryInterface = &MockBookingRepository{}
 
@@ -221,7 +261,7 @@ func TestCreateBookingHandler(t *testing.T) {
 			}
 
 			// Create handler with mock
-			handler := handlers.NewBookingHandler(mockRepo, nil)
+			handler := handlers.NewBookingHandler(mockRepo, nil, nil, nil)
 
 			// Create request body
 			body, _ := json.Marshal(tc.booking)
@@ -409,7 +449,7 @@ func TestUpdateBookingHandler(t *testing.T) {
 			}
 
 			// Create handler with mock
-			handler := handlers.NewBookingHandler(mockRepo, nil)
+			handler := handlers.NewBookingHandler(mockRepo, nil, nil, nil)
 
 			// Create request with URL parameter and body
 			body, _ := json.Marshal(tc.updatedBooking)
@@ -489,14 +529,14 @@ func TestGetAllBookingsHandler(t *testing.T) {
 	log.Println("Starting TestGetAllBookingsHandler")
 	tests := []struct {
 		name           string
-		mockGetAllFunc func(context.Context, bool) ([]*models.Booking, error)
+		mockGetAllFunc func(context.Context, *models.BookingFilter) ([]*models.Booking, error)
 		expectedStatus int
 		expectedCount  int
 		expectedErr    string
 	}{
 		{
 			name: "Successfully retrieve bookings",
-			mockGetAllFunc: func(ctx context.Context, includeArchived bool) ([]*models.Booking, error) {
+			mockGetAllFunc: func(ctx context.Context, filter *models.BookingFilter) ([]*models.Booking, error) {
 				return []*models.Booking{
 					{ID: 1, Name: "User1"},
 					{ID: 2, Name: "User2"},
@@ -507,7 +547,7 @@ func TestGetAllBookingsHandler(t *testing.T) {
 		},
 		{
 			name: "Empty bookings list",
-			mockGetAllFunc: func(ctx context.Context, includeArchived bool) ([]*models.Booking, error) {
+			mockGetAllFunc: func(ctx context.Context, filter *models.BookingFilter) ([]*models.Booking, error) {
 				return []*models.Booking{}, nil
 			},
 			expectedStatus: http.StatusOK,
@@ -515,7 +555,7 @@ func TestGetAllBookingsHandler(t *testing.T) {
 		},
 		{
 			name: "Database error",
-			mockGetAllFunc: func(ctx context.Context, includeArchived bool) ([]*models.Booking, error) {
+			mockGetAllFunc: func(ctx context.Context, filter *models.BookingFilter) ([]*models.Booking, error) {
 				return nil, fmt.Errorf("database connection error")
 			},
 			expectedStatus: http.StatusInternalServerError,
@@ -531,7 +571,7 @@ func TestGetAllBookingsHandler(t *testing.T) {
 			}
 
 			// Create handler with mock
-			handler := handlers.NewBookingHandler(mockRepo, nil)
+			handler := handlers.NewBookingHandler(mockRepo, nil, nil, nil)
 
 			// Create request
 			req := httptest.NewRequest("GET", "/api/v1/bookings", nil)
@@ -641,7 +681,7 @@ func TestGetBookingByIDHandler(t *testing.T) {
 			}
 
 			// Create handler with mock
-			handler := handlers.NewBookingHandler(mockRepo, nil)
+			handler := handlers.NewBookingHandler(mockRepo, nil, nil, nil)
 
 			// Create request with URL parameter
 			req := httptest.NewRequest("GET", "/api/v1/bookings/"+tc.bookingID, nil)
@@ -764,7 +804,7 @@ func TestDeleteBookingHandler(t *testing.T) {
 			}
 
 			// Create handler with mock
-			handler := handlers.NewBookingHandler(mockRepo, nil)
+			handler := handlers.NewBookingHandler(mockRepo, nil, nil, nil)
 
 			// Create request with URL parameter
 			req := httptest.NewRequest("DELETE", "/api/v1/bookings/"+tc.bookingID, nil)
@@ -801,13 +841,13 @@ func TestResponseHeaders(t *testing.T) {
 	log.Println("Starting TestTestResponseHeaders")
 	// Create mock repository
 	mockRepo := &MockBookingRepository{
-		GetAllFunc: func(ctx context.Context, includeArchived bool) ([]*models.Booking, error) {
+		GetAllFunc: func(ctx context.Context, filter *models.BookingFilter) ([]*models.Booking, error) {
 			return []*models.Booking{}, nil
 		},
 	}
 
 	// Create handler with mock
-	handler := handlers.NewBookingHandler(mockRepo, nil)
+	handler := handlers.NewBookingHandler(mockRepo, nil, nil, nil)
 
 	// Create request
 	req := httptest.NewRequest("GET", "/api/v1/bookings", nil)
@@ -909,7 +949,7 @@ func TestArchiveBookingHandler(t *testing.T) {
 			}
 
 			// Create handler with mock
-			handler := handlers.NewBookingHandler(mockRepo, nil)
+			handler := handlers.NewBookingHandler(mockRepo, nil, nil, nil)
 
 			// Create request with URL parameter
 			req := httptest.NewRequest("POST", "/api/v1/bookings/"+tc.bookingID+"/archive", nil)
@@ -1035,7 +1075,7 @@ func TestUnarchiveBookingHandler(t *testing.T) {
 			}
 
 			// Create handler with mock
-			handler := handlers.NewBookingHandler(mockRepo, nil)
+			handler := handlers.NewBookingHandler(mockRepo, nil, nil, nil)
 
 			// Create request with URL parameter
 			req := httptest.NewRequest("POST", "/api/v1/bookings/"+tc.bookingID+"/unarchive", nil)
@@ -1086,7 +1126,7 @@ func TestGetAllBookingsWithArchiveFiltering(t *testing.T) {
 	tests := []struct {
 		name             string
 		queryParams      string
-		mockGetAllFunc   func(context.Context, bool) ([]*models.Booking, error)
+		mockGetAllFunc   func(context.Context, *models.BookingFilter) ([]*models.Booking, error)
 		expectedStatus   int
 		expectedCount    int
 		expectedArchived bool
@@ -1094,9 +1134,9 @@ func TestGetAllBookingsWithArchiveFiltering(t *testing.T) {
 		{
 			name:        "Get active bookings only (default)",
 			queryParams: "",
-			mockGetAllFunc: func(ctx context.Context, includeArchived bool) ([]*models.Booking, error) {
-				if includeArchived {
-					t.Error("Expected includeArchived=false, got true")
+			mockGetAllFunc: func(ctx context.Context, filter *models.BookingFilter) ([]*models.Booking, error) {
+				if filter.Archived == nil || *filter.Archived {
+					t.Error("Expected only active bookings to be requested")
 				}
 				return []*models.Booking{
 					{ID: 1, Name: "Active 1", Archived: false},
@@ -1110,9 +1150,9 @@ func TestGetAllBookingsWithArchiveFiltering(t *testing.T) {
 		{
 			name:        "Get active bookings only (explicit)",
 			queryParams: "?include_archived=false",
-			mockGetAllFunc: func(ctx context.Context, includeArchived bool) ([]*models.Booking, error) {
-				if includeArchived {
-					t.Error("Expected includeArchived=false, got true")
+			mockGetAllFunc: func(ctx context.Context, filter *models.BookingFilter) ([]*models.Booking, error) {
+				if filter.Archived == nil || *filter.Archived {
+					t.Error("Expected only active bookings to be requested")
 				}
 				return []*models.Booking{
 					{ID: 1, Name: "Active 1", Archived: false},
@@ -1126,9 +1166,9 @@ func TestGetAllBookingsWithArchiveFiltering(t *testing.T) {
 		{
 			name:        "Get all bookings including archived",
 			queryParams: "?include_archived=true",
-			mockGetAllFunc: func(ctx context.Context, includeArchived bool) ([]*models.Booking, error) {
-				if !includeArchived {
-					t.Error("Expected includeArchived=true, got false")
+			mockGetAllFunc: func(ctx context.Context, filter *models.BookingFilter) ([]*models.Booking, error) {
+				if filter.Archived != nil {
+					t.Error("Expected archived bookings to be requested")
 				}
 				return []*models.Booking{
 					{ID: 1, Name: "Active 1", Archived: false},
@@ -1144,10 +1184,10 @@ func TestGetAllBookingsWithArchiveFiltering(t *testing.T) {
 		{
 			name:        "Invalid include_archived parameter",
 			queryParams: "?include_archived=invalid",
-			mockGetAllFunc: func(ctx context.Context, includeArchived bool) ([]*models.Booking, error) {
+			mockGetAllFunc: func(ctx context.Context, filter *models.BookingFilter) ([]*models.Booking, error) {
 				// Should default to false for invalid values
-				if includeArchived {
-					t.Error("Expected includeArchived=false for invalid parameter, got true")
+				if filter.Archived == nil || *filter.Archived {
+					t.Error("Expected only active bookings to be requested for invalid parameter")
 				}
 				return []*models.Booking{
 					{ID: 1, Name: "Active 1", Archived: false},
@@ -1168,7 +1208,7 @@ func TestGetAllBookingsWithArchiveFiltering(t *testing.T) {
 			}
 
 			// Create handler with mock
-			handler := handlers.NewBookingHandler(mockRepo, nil)
+			handler := handlers.NewBookingHandler(mockRepo, nil, nil, nil)
 
 			// Create request with query parameters
 			req := httptest.NewRequest("GET", "/api/v1/bookings"+tc.queryParams, nil)
@@ -1182,10 +1222,10 @@ func TestGetAllBookingsWithArchiveFiltering(t *testing.T) {
 				t.Errorf("Expected status %d, got %d", tc.expectedStatus, w.Code)
 			}
 
-			// Verify GetAll was called with correct includeArchived parameter
-			if mockRepo.GetAllCalled && mockRepo.GetAllIncludeArchived != tc.expectedArchived {
-				t.Errorf("Expected GetAll called with includeArchived=%v, got %v",
-					tc.expectedArchived, mockRepo.GetAllIncludeArchived)
+			// Verify GetAll was asked for archived bookings only when requested
+			if mockRepo.GetAllCalled && (mockRepo.GetAllFilter.Archived == nil) != tc.expectedArchived {
+				t.Errorf("Expected GetAll called with archived bookings included=%v, got filter %+v",
+					tc.expectedArchived, mockRepo.GetAllFilter)
 			}
 
 			// If successful, check the count of bookings
@@ -1202,3 +1242,51 @@ func TestGetAllBookingsWithArchiveFiltering(t *testing.T) {
 		})
 	}
 }
+
+func TestGetAllBookingsFilters(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		checkFilter    func(t *testing.T, f *models.BookingFilter)
+	}{
+		{
+			name:           "Filters are passed through",
+			query:          "?status=confirmed&dateFrom=2026-10-01&dateTo=2026-10-31&assignedUserId=4",
+			expectedStatus: http.StatusOK,
+			checkFilter: func(t *testing.T, f *models.BookingFilter) {
+				if f.Archived == nil || *f.Archived || f.Status != "confirmed" || f.DateFrom != "2026-10-01" ||
+					f.DateTo != "2026-10-31" || f.AssignedUserID == nil || *f.AssignedUserID != 4 {
+					t.Errorf("Unexpected filter %+v", f)
+				}
+			},
+		},
+		{name: "Invalid date", query: "?dateTo=October", expectedStatus: http.StatusBadRequest},
+		{name: "Invalid status", query: "?status=done", expectedStatus: http.StatusBadRequest},
+		{name: "Invalid assignee", query: "?assignedUserId=0", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := &MockBookingRepository{
+				GetAllFunc: func(ctx context.Context, f *models.BookingFilter) ([]*models.Booking, error) {
+					return []*models.Booking{}, nil
+				},
+			}
+			handler := handlers.NewBookingHandler(mockRepo, nil, nil, nil)
+
+			w := httptest.NewRecorder()
+			handler.GetAll(w, httptest.NewRequest("GET", "/api/v1/bookings"+tc.query, nil))
+
+			if w.Code != tc.expectedStatus {
+				t.Fatalf("Expected status %d, got %d (%s)", tc.expectedStatus, w.Code, w.Body.String())
+			}
+			if tc.expectedStatus != http.StatusOK && mockRepo.GetAllCalled {
+				t.Errorf("Expected invalid filters to be rejected before querying")
+			}
+			if tc.checkFilter != nil {
+				tc.checkFilter(t, mockRepo.GetAllFilter)
+			}
+		})
+	}
+}
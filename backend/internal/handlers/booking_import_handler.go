@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/importer"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
+)
+
+// maxImportBodyBytes limits the size of an import request
+const maxImportBodyBytes = 10 << 20
+
+// Import saves the bookings in a CSV file, checking each row with the same
+// rules as Create and skipping duplicates of existing bookings. Rows that
+// fail are reported and the rest are saved together. With dryRun nothing is
+// saved.
+func (h *BookingHandler) Import(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req models.BookingImportRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxImportBodyBytes)).Decode(&req); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, "Import file is too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		slog.WarnContext(ctx, "invalid booking import request body", "error", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	response, err := importer.Import(ctx, h.repo, strings.NewReader(req.CSV), importer.Mapping(req.Mapping), req.DryRun)
+	if err != nil {
+		if errors.Is(err, importer.ErrInvalidFile) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		slog.ErrorContext(ctx, "booking import failed", "error", err)
+		http.Error(w, "Failed to import bookings", http.StatusInternalServerError)
+		return
+	}
+
+	slog.InfoContext(ctx, "imported bookings", "dry_run", req.DryRun, "rows", response.Rows,
+		"created", response.Created, "duplicates", response.Duplicates, "invalid", response.Invalid)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
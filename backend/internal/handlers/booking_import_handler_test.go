@@ -0,0 +1,144 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/handlers"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
+)
+
+func TestImportBookingsHandler(t *testing.T) {
+	csv := "name,email,phone,date,time\n" +
+		"Ada,ada@example.com,,2025-06-01,14:00\n" +
+		"Grace,,,2025-06-02,10:00\n" +
+		"Ada,ADA@example.com,,2025-06-01,14:00\n"
+
+	tests := []struct {
+		name           string
+		body           interface{}
+		importErr      error
+		expectedStatus int
+		expectImport   bool
+		expected       *models.BookingImportResponse
+	}{
+		{
+			name:           "Valid rows are imported and invalid ones reported",
+			body:           models.BookingImportRequest{CSV: csv},
+			expectedStatus: http.StatusOK,
+			expectImport:   true,
+			expected: &models.BookingImportResponse{
+				Rows: 3, Created: 1, Duplicates: 1, Invalid: 1,
+				Results: []models.BookingImportResult{
+					{Row: 2, Result: models.ImportResultCreated, ID: 10},
+					{Row: 3, Result: models.ImportResultInvalid, Errors: []string{"Email or phone number is required"}},
+					{Row: 4, Result: models.ImportResultDuplicate, ID: 10},
+				},
+			},
+		},
+		{
+			name:           "Dry run",
+			body:           models.BookingImportRequest{CSV: csv, DryRun: true},
+			expectedStatus: http.StatusOK,
+			expectImport:   true,
+		},
+		{
+			name:           "Only invalid rows",
+			body:           models.BookingImportRequest{CSV: "name,date\nAda,2025-06-01\n"},
+			expectedStatus: http.StatusOK,
+			expected: &models.BookingImportResponse{
+				Rows: 1, Invalid: 1,
+				Results: []models.BookingImportResult{
+					{Row: 2, Result: models.ImportResultInvalid, Errors: []string{"Email or phone number is required"}},
+				},
+			},
+		},
+		{name: "Invalid body", body: "not json", expectedStatus: http.StatusBadRequest},
+		{name: "Empty file", body: models.BookingImportRequest{}, expectedStatus: http.StatusBadRequest},
+		{
+			name:           "Unknown mapping field",
+			body:           models.BookingImportRequest{CSV: csv, Mapping: map[string]string{"price": "Price"}},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Too large",
+			body:           models.BookingImportRequest{CSV: strings.Repeat("x", 11<<20)},
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:           "Database error",
+			body:           models.BookingImportRequest{CSV: csv},
+			importErr:      errors.New("connection lost"),
+			expectedStatus: http.StatusInternalServerError,
+			expectImport:   true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var dryRun bool
+			mockRepo := &MockBookingRepository{
+				ImportFunc: func(ctx context.Context, bookings []*models.Booking, d bool) ([]models.BookingImportResult, error) {
+					dryRun = d
+					if tc.importErr != nil {
+						return nil, tc.importErr
+					}
+					return []models.BookingImportResult{
+						{Result: models.ImportResultCreated, ID: 10},
+						{Result: models.ImportResultDuplicate, ID: 10},
+					}, nil
+				},
+			}
+			handler := handlers.NewBookingHandler(mockRepo, nil, nil, nil)
+
+			var body []byte
+			if s, ok := tc.body.(string); ok {
+				body = []byte(s)
+			} else {
+				body, _ = json.Marshal(tc.body)
+			}
+			w := httptest.NewRecorder()
+			handler.Import(w, httptest.NewRequest("POST", "/api/v1/bookings/import", bytes.NewReader(body)))
+
+			if w.Code != tc.expectedStatus {
+				t.Fatalf("Expected status %d, got %d (%s)", tc.expectedStatus, w.Code, w.Body.String())
+			}
+			if mockRepo.ImportCalled != tc.expectImport {
+				t.Fatalf("Expected Import called=%v, got %v", tc.expectImport, mockRepo.ImportCalled)
+			}
+			if tc.expectImport && len(mockRepo.Imported) != 2 {
+				t.Errorf("Expected the 2 valid rows to be imported, got %d", len(mockRepo.Imported))
+			}
+			if req, ok := tc.body.(models.BookingImportRequest); ok && tc.expectImport && dryRun != req.DryRun {
+				t.Errorf("Expected dryRun=%v to be passed on", req.DryRun)
+			}
+
+			if tc.expected != nil {
+				var response models.BookingImportResponse
+				if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+					t.Fatalf("Failed to decode response: %v", err)
+				}
+				if len(response.Results) != len(tc.expected.Results) {
+					t.Fatalf("Expected %d results, got %d", len(tc.expected.Results), len(response.Results))
+				}
+				for i, result := range response.Results {
+					want := tc.expected.Results[i]
+					if result.Row != want.Row || result.Result != want.Result || result.ID != want.ID ||
+						strings.Join(result.Errors, "|") != strings.Join(want.Errors, "|") {
+						t.Errorf("Expected result %d to be %+v, got %+v", i, want, result)
+					}
+				}
+				if response.Rows != tc.expected.Rows || response.Created != tc.expected.Created ||
+					response.Duplicates != tc.expected.Duplicates || response.Invalid != tc.expected.Invalid {
+					t.Errorf("Expected counts %+v, got %+v", tc.expected, response)
+				}
+			}
+		})
+	}
+}
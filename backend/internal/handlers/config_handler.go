@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/config"
+)
+
+type ConfigHandler struct {
+	cfg *config.Config
+}
+
+// NewConfigHandler creates a handler that exposes the running configuration
+func NewConfigHandler(cfg *config.Config) *ConfigHandler {
+	return &ConfigHandler{cfg: cfg}
+}
+
+// Get returns the effective configuration with secrets masked
+func (h *ConfigHandler) Get(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.cfg.Redacted())
+}
@@ -2,9 +2,11 @@ package handlers
 
 import (
 	"encoding/json"
-	"log"
+	"log/slog"
 	"net/http"
 
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/events"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
 	"github.com/joshuagudgel/toasted-coffee/backend/internal/services"
 )
 
@@ -15,13 +17,22 @@ type ContactRequest struct {
 	Message string `json:"message"`
 }
 
+// WithoutPII leaves nothing of an inquiry: every field identifies the sender
+func (c *ContactRequest) WithoutPII() interface{} {
+	return struct{}{}
+}
+
 type ContactHandler struct {
 	emailService *services.EmailService
+	webhooks     *services.WebhookService
+	events       *events.Broker
 }
 
-func NewContactHandler(emailService *services.EmailService) *ContactHandler {
+func NewContactHandler(emailService *services.EmailService, webhooks *services.WebhookService, broker *events.Broker) *ContactHandler {
 	return &ContactHandler{
 		emailService: emailService,
+		webhooks:     webhooks,
+		events:       broker,
 	}
 }
 
@@ -29,7 +40,7 @@ func (h *ContactHandler) HandleInquiry(w http.ResponseWriter, r *http.Request) {
 	var request ContactRequest
 
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		log.Printf("Error decoding contact request: %v", err)
+		slog.WarnContext(r.Context(), "invalid contact request body", "error", err)
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
@@ -50,8 +61,13 @@ func (h *ContactHandler) HandleInquiry(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Notify subscribers even if the email below fails, so an inquiry isn't lost
+	h.webhooks.Emit(r.Context(), models.EventInquiryCreated, &request)
+	h.events.Publish(r.Context(), models.EventInquiryCreated, &request)
+
 	// Send the inquiry email
 	err := h.emailService.SendInquiry(
+		r.Context(),
 		request.Name,
 		request.Email,
 		request.Phone,
@@ -59,7 +75,6 @@ func (h *ContactHandler) HandleInquiry(w http.ResponseWriter, r *http.Request) {
 	)
 
 	if err != nil {
-		log.Printf("Failed to send inquiry email: %v", err)
 		http.Error(w, "Failed to send inquiry", http.StatusInternalServerError)
 		return
 	}
@@ -0,0 +1,250 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/auth"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/handlers"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/openapi"
+)
+
+func contractBooking() *models.Booking {
+	return &models.Booking{
+		ID:            7,
+		Name:          "Ada Lovelace",
+		Email:         "ada@example.com",
+		Date:          "2026-05-01",
+		Time:          "09:00",
+		People:        40,
+		Location:      "Town Hall",
+		CoffeeFlavors: []string{"vanilla"},
+		MilkOptions:   []string{"oat"},
+		Package:       "Group",
+		Status:        models.BookingStatusConfirmed,
+		CreatedAt:     time.Now(),
+	}
+}
+
+// TestResponsesMatchSpec runs handlers against mock repositories and checks
+// each response's status, Content-Type and body against openapi.yaml
+func TestResponsesMatchSpec(t *testing.T) {
+	validator, err := openapi.NewValidator()
+	if err != nil {
+		t.Fatalf("Failed to load spec: %v", err)
+	}
+
+	bookingRepo := &MockBookingRepository{
+		CreateFunc: func(ctx context.Context, b *models.Booking) (int, error) { return 7, nil },
+		GetByIDFunc: func(ctx context.Context, id int) (*models.Booking, error) {
+			if id != 7 {
+				return nil, errors.New("booking not found")
+			}
+			return contractBooking(), nil
+		},
+		GetAllFunc: func(ctx context.Context, f *models.BookingFilter) ([]*models.Booking, error) {
+			return []*models.Booking{contractBooking()}, nil
+		},
+		UpdateFunc: func(ctx context.Context, id int, b *models.Booking) error { return nil },
+		DeleteFunc: func(ctx context.Context, id int) error { return nil },
+		StreamFunc: func(ctx context.Context, f *models.BookingFilter, fn func(*models.Booking) error) error {
+			return fn(contractBooking())
+		},
+		BulkFunc: func(ctx context.Context, req *models.BulkBookingRequest) ([]models.BulkBookingResult, error) {
+			return []models.BulkBookingResult{
+				{ID: 7, Result: models.BulkResultChanged, Booking: contractBooking()},
+				{ID: 8, Result: models.BulkResultNotFound},
+			}, nil
+		},
+		ImportFunc: func(ctx context.Context, bookings []*models.Booking, dryRun bool) ([]models.BookingImportResult, error) {
+			return []models.BookingImportResult{{Result: models.ImportResultCreated}}, nil
+		},
+	}
+	menuRepo := &MockMenuRepository{
+		GetAllFunc: func(ctx context.Context) ([]models.MenuItem, error) { return []models.MenuItem{}, nil },
+		CreateFunc: func(ctx context.Context, item *models.MenuItem) (int, error) { return 3, nil },
+		GetByTypeFunc: func(ctx context.Context, itemType models.ItemType) ([]models.MenuItem, error) {
+			return []models.MenuItem{{ID: 3, Value: "almond", Label: "Almond Milk", Type: itemType, Active: true,
+				Tags: []string{models.TagDairyFree}, PriceModifier: 0.5, ImageKey: "menu-3-1a2b.jpg"}}, nil
+		},
+		ReorderFunc:  func(ctx context.Context, itemType models.ItemType, ids []int) error { return nil },
+		SetImageFunc: func(ctx context.Context, id int, key string) (string, error) { return "", nil },
+	}
+	user := &models.User{ID: 1, Username: "owner", Role: "owner", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	userRepo := &MockUserRepository{
+		GetAllFunc:  func(ctx context.Context) ([]*models.User, error) { return []*models.User{user}, nil },
+		GetByIDFunc: func(ctx context.Context, id int) (*models.User, error) { return user, nil },
+		CountFunc:   func(ctx context.Context) (int, error) { return 1, nil },
+	}
+	statusCode := http.StatusOK
+	webhookRepo := &MockWebhookRepository{
+		GetAllFunc: func(ctx context.Context) ([]*models.Webhook, error) {
+			return []*models.Webhook{{ID: 1, URL: "https://example.com/hooks", Events: []string{"*"}, Active: true}}, nil
+		},
+		GetByIDFunc: func(ctx context.Context, id int) (*models.Webhook, error) {
+			return &models.Webhook{ID: id, URL: "https://example.com/hooks", Events: []string{"*"}}, nil
+		},
+		CreateFunc: func(ctx context.Context, hook *models.Webhook) error { hook.ID = 2; return nil },
+		GetDeliveriesFunc: func(ctx context.Context, webhookID int, limit int) ([]*models.WebhookDelivery, error) {
+			return []*models.WebhookDelivery{
+				{ID: 1, WebhookID: webhookID, EventID: "evt_1", Event: "booking.created", Attempt: 1, Error: "connection refused"},
+				{ID: 2, WebhookID: webhookID, EventID: "evt_1", Event: "booking.created", Attempt: 2, StatusCode: &statusCode},
+			}, nil
+		},
+	}
+
+	authHandler, refreshRepo := newTestAuthHandler(t)
+	bookingHandler := handlers.NewBookingHandler(bookingRepo, nil, nil, nil)
+	menuHandler, _ := newTestMenuHandler(t, menuRepo)
+	userHandler := handlers.NewUserHandler(userRepo, refreshRepo, nil)
+	setupHandler := handlers.NewSetupHandler(userRepo, "")
+	webhookHandler := handlers.NewWebhookHandler(webhookRepo, nil)
+	reportHandler := newTestReportHandler(&MockReportRepository{})
+	inventoryHandler := handlers.NewInventoryHandler(newTestInventoryRepo(), bookingRepo, 1)
+
+	newBooking := contractBooking()
+	newBooking.ID = 0
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string // path template in the spec
+		target         string
+		body           interface{}
+		handler        http.HandlerFunc
+		expectedStatus int
+	}{
+		{name: "Create booking", method: "POST", path: "/api/v1/bookings", target: "/api/v1/bookings",
+			body: newBooking, handler: bookingHandler.Create, expectedStatus: http.StatusCreated},
+		{name: "Create booking with invalid body", method: "POST", path: "/api/v1/bookings", target: "/api/v1/bookings",
+			body: "not a booking", handler: bookingHandler.Create, expectedStatus: http.StatusBadRequest},
+		{name: "List bookings", method: "GET", path: "/api/v1/bookings", target: "/api/v1/bookings?include_archived=true",
+			handler: bookingHandler.GetAll, expectedStatus: http.StatusOK},
+		{name: "Get booking", method: "GET", path: "/api/v1/bookings/{id}", target: "/api/v1/bookings/7",
+			handler: bookingHandler.GetByID, expectedStatus: http.StatusOK},
+		{name: "Get missing booking", method: "GET", path: "/api/v1/bookings/{id}", target: "/api/v1/bookings/8",
+			handler: bookingHandler.GetByID, expectedStatus: http.StatusNotFound},
+		{name: "Update booking", method: "PUT", path: "/api/v1/bookings/{id}", target: "/api/v1/bookings/7",
+			body: newBooking, handler: bookingHandler.Update, expectedStatus: http.StatusOK},
+		{name: "Delete booking", method: "DELETE", path: "/api/v1/bookings/{id}", target: "/api/v1/bookings/7",
+			handler: bookingHandler.Delete, expectedStatus: http.StatusNoContent},
+		{name: "Bulk archive dry run", method: "POST", path: "/api/v1/bookings/bulk", target: "/api/v1/bookings/bulk",
+			body:    models.BulkBookingRequest{IDs: []int{7, 8}, Action: models.BulkActionArchive, DryRun: true},
+			handler: bookingHandler.Bulk, expectedStatus: http.StatusOK},
+		{name: "Bulk without a selection", method: "POST", path: "/api/v1/bookings/bulk", target: "/api/v1/bookings/bulk",
+			body:    models.BulkBookingRequest{Action: models.BulkActionArchive},
+			handler: bookingHandler.Bulk, expectedStatus: http.StatusBadRequest},
+		{name: "Export bookings", method: "GET", path: "/api/v1/bookings/export", target: "/api/v1/bookings/export?format=xlsx",
+			handler: bookingHandler.Export, expectedStatus: http.StatusOK},
+		{name: "Export bookings in an unknown format", method: "GET", path: "/api/v1/bookings/export",
+			target: "/api/v1/bookings/export?format=pdf", handler: bookingHandler.Export, expectedStatus: http.StatusBadRequest},
+		{name: "Import bookings dry run", method: "POST", path: "/api/v1/bookings/import", target: "/api/v1/bookings/import",
+			body: models.BookingImportRequest{
+				CSV:    "Customer,Email,Date\nAda,ada@example.com,2026-10-01\nGrace,,October\n",
+				DryRun: true, Mapping: map[string]string{"name": "Customer"},
+			},
+			handler: bookingHandler.Import, expectedStatus: http.StatusOK},
+		{name: "Import an empty file", method: "POST", path: "/api/v1/bookings/import", target: "/api/v1/bookings/import",
+			body: models.BookingImportRequest{}, handler: bookingHandler.Import, expectedStatus: http.StatusBadRequest},
+		{name: "Volume report", method: "GET", path: "/api/v1/reports/volume",
+			target: "/api/v1/reports/volume?interval=week", handler: reportHandler.Volume, expectedStatus: http.StatusOK},
+		{name: "Volume report with an unknown interval", method: "GET", path: "/api/v1/reports/volume",
+			target: "/api/v1/reports/volume?interval=day", handler: reportHandler.Volume, expectedStatus: http.StatusBadRequest},
+		{name: "Revenue report", method: "GET", path: "/api/v1/reports/revenue", target: "/api/v1/reports/revenue",
+			handler: reportHandler.Revenue, expectedStatus: http.StatusOK},
+		{name: "Menu report", method: "GET", path: "/api/v1/reports/menu", target: "/api/v1/reports/menu",
+			handler: reportHandler.Menu, expectedStatus: http.StatusOK},
+		{name: "Summary report", method: "GET", path: "/api/v1/reports/summary", target: "/api/v1/reports/summary",
+			handler: reportHandler.Summary, expectedStatus: http.StatusOK},
+		{name: "List ingredients", method: "GET", path: "/api/v1/ingredients", target: "/api/v1/ingredients",
+			handler: inventoryHandler.GetIngredients, expectedStatus: http.StatusOK},
+		{name: "Create ingredient", method: "POST", path: "/api/v1/ingredients", target: "/api/v1/ingredients",
+			body:    models.IngredientInput{Name: "Vanilla syrup", Unit: "ml", Stock: 750},
+			handler: inventoryHandler.CreateIngredient, expectedStatus: http.StatusCreated},
+		{name: "Create ingredient without a unit", method: "POST", path: "/api/v1/ingredients", target: "/api/v1/ingredients",
+			body:    models.IngredientInput{Name: "Vanilla syrup"},
+			handler: inventoryHandler.CreateIngredient, expectedStatus: http.StatusBadRequest},
+		{name: "List recipes", method: "GET", path: "/api/v1/recipes", target: "/api/v1/recipes",
+			handler: inventoryHandler.GetRecipes, expectedStatus: http.StatusOK},
+		{name: "Prep sheet", method: "GET", path: "/api/v1/prep-sheet", target: "/api/v1/prep-sheet?date=2026-05-01",
+			handler: inventoryHandler.PrepSheet, expectedStatus: http.StatusOK},
+		{name: "List menu items", method: "GET", path: "/api/v1/menu", target: "/api/v1/menu",
+			handler: menuHandler.GetAll, expectedStatus: http.StatusOK},
+		{name: "List menu items of unknown type", method: "GET", path: "/api/v1/menu/{type}", target: "/api/v1/menu/tea",
+			handler: menuHandler.GetByType, expectedStatus: http.StatusBadRequest},
+		{name: "Create menu item", method: "POST", path: "/api/v1/menu", target: "/api/v1/menu",
+			body:    models.MenuItem{Value: "mocha", Label: "Mocha", Type: models.CoffeeFlavor, Active: true},
+			handler: menuHandler.Create, expectedStatus: http.StatusCreated},
+		{name: "Reorder menu items", method: "PUT", path: "/api/v1/menu/order", target: "/api/v1/menu/order",
+			body:    models.MenuOrder{Type: models.MilkOption, IDs: []int{3}},
+			handler: menuHandler.Reorder, expectedStatus: http.StatusOK},
+		{name: "Upload menu image without a file", method: "POST", path: "/api/v1/menu/{id}/image", target: "/api/v1/menu/3/image",
+			handler: menuHandler.UploadImage, expectedStatus: http.StatusBadRequest},
+		{name: "Delete menu image", method: "DELETE", path: "/api/v1/menu/{id}/image", target: "/api/v1/menu/3/image",
+			handler: menuHandler.DeleteImage, expectedStatus: http.StatusNoContent},
+		{name: "List users", method: "GET", path: "/api/v1/users", target: "/api/v1/users",
+			handler: userHandler.GetAll, expectedStatus: http.StatusOK},
+		{name: "List roles", method: "GET", path: "/api/v1/users/roles", target: "/api/v1/users/roles",
+			handler: userHandler.Roles, expectedStatus: http.StatusOK},
+		{name: "Login", method: "POST", path: "/api/v1/auth/login", target: "/api/v1/auth/login",
+			body: handlers.LoginRequest{Username: "owner", Password: testPassword}, handler: authHandler.Login, expectedStatus: http.StatusOK},
+		{name: "Login with wrong password", method: "POST", path: "/api/v1/auth/login", target: "/api/v1/auth/login",
+			body: handlers.LoginRequest{Username: "nobody", Password: "wrong"}, handler: authHandler.Login, expectedStatus: http.StatusUnauthorized},
+		{name: "List sessions", method: "GET", path: "/api/v1/users/{id}/sessions", target: "/api/v1/users/1/sessions",
+			handler: userHandler.GetSessions, expectedStatus: http.StatusOK},
+		{name: "Validate token", method: "GET", path: "/api/v1/auth/validate", target: "/api/v1/auth/validate",
+			handler: authHandler.ValidateToken, expectedStatus: http.StatusOK},
+		{name: "Setup status", method: "GET", path: "/api/v1/setup", target: "/api/v1/setup",
+			handler: setupHandler.Status, expectedStatus: http.StatusOK},
+		{name: "Setup already completed", method: "POST", path: "/api/v1/setup", target: "/api/v1/setup",
+			body: handlers.SetupRequest{}, handler: setupHandler.CreateAdmin, expectedStatus: http.StatusGone},
+		{name: "List webhooks", method: "GET", path: "/api/v1/webhooks", target: "/api/v1/webhooks",
+			handler: webhookHandler.GetAll, expectedStatus: http.StatusOK},
+		{name: "Create webhook", method: "POST", path: "/api/v1/webhooks", target: "/api/v1/webhooks",
+			body:    models.WebhookInput{URL: "https://example.com/hooks", Events: []string{"booking.created"}, Active: true},
+			handler: webhookHandler.Create, expectedStatus: http.StatusCreated},
+		{name: "Create webhook with unknown event", method: "POST", path: "/api/v1/webhooks", target: "/api/v1/webhooks",
+			body:    models.WebhookInput{URL: "https://example.com/hooks", Events: []string{"menu.updated"}},
+			handler: webhookHandler.Create, expectedStatus: http.StatusBadRequest},
+		{name: "List webhook deliveries", method: "GET", path: "/api/v1/webhooks/{id}/deliveries", target: "/api/v1/webhooks/1/deliveries",
+			handler: webhookHandler.GetDeliveries, expectedStatus: http.StatusOK},
+		{name: "Spec", method: "GET", path: "/api/v1/openapi.json", target: "/api/v1/openapi.json",
+			handler: openapi.SpecHandler, expectedStatus: http.StatusOK},
+		{name: "Docs", method: "GET", path: "/api/v1/docs", target: "/api/v1/docs",
+			handler: openapi.DocsHandler, expectedStatus: http.StatusOK},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Route through chi so URL parameters resolve as in production
+			router := chi.NewRouter()
+			router.Method(tc.method, tc.path, tc.handler)
+
+			var body bytes.Buffer
+			if tc.body != nil {
+				json.NewEncoder(&body).Encode(tc.body)
+			}
+			req := httptest.NewRequest(tc.method, tc.target, &body)
+			req.Header.Set("Content-Type", "application/json")
+			req = req.WithContext(context.WithValue(req.Context(), auth.ClaimsContextKey, &auth.Claims{UserID: 1, Role: "owner"}))
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			if w.Code != tc.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tc.expectedStatus, w.Code, w.Body.String())
+			}
+			if err := validator.ValidateResponse(tc.method, tc.path, w.Code, w.Header(), w.Body.Bytes()); err != nil {
+				t.Errorf("Response does not match the spec: %v", err)
+			}
+		})
+	}
+}
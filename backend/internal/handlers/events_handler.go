@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/events"
+)
+
+// EventResync tells a resuming client that events it missed are no longer
+// available, so it should reload what it shows
+const EventResync = "resync"
+
+// streamRetry is the reconnect delay, in milliseconds, suggested to clients
+const streamRetry = 5000
+
+// EventsHandler streams booking and inquiry changes to the admin dashboard
+type EventsHandler struct {
+	broker    *events.Broker
+	heartbeat time.Duration
+}
+
+// NewEventsHandler creates a new events handler
+func NewEventsHandler(broker *events.Broker, heartbeat time.Duration) *EventsHandler {
+	return &EventsHandler{
+		broker:    broker,
+		heartbeat: heartbeat,
+	}
+}
+
+// Stream sends events as Server-Sent Events until the client disconnects. A
+// client resuming with Last-Event-ID (or the lastEventId query parameter, for
+// the first connection) first receives the events it missed.
+func (h *EventsHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	if h.broker == nil {
+		http.Error(w, "Event stream is not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	// The stream outlives the server's write timeout
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && err != http.ErrNotSupported {
+		slog.ErrorContext(r.Context(), "failed to clear write deadline", "error", err)
+	}
+
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("lastEventId")
+	}
+
+	sub, missed, ok := h.broker.Subscribe(lastEventID)
+	defer h.broker.Unsubscribe(sub)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	// Stop nginx and similar proxies from buffering the stream
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprintf(w, "retry: %d\n\n", streamRetry)
+	if !ok {
+		fmt.Fprintf(w, "event: %s\ndata: {}\n\n", EventResync)
+	}
+	for _, event := range missed {
+		writeEvent(w, event)
+	}
+	if err := rc.Flush(); err != nil {
+		slog.ErrorContext(r.Context(), "event stream cannot be flushed", "error", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "event stream opened", "resumed", lastEventID != "", "replayed", len(missed))
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case event, open := <-sub.Events():
+			if !open {
+				// Dropped for falling behind, or shutting down; the client
+				// reconnects and resumes
+				return
+			}
+			writeEvent(w, event)
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+		case <-r.Context().Done():
+			return
+		}
+
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// writeEvent writes event in the text/event-stream format. Data is single-line
+// JSON, but newlines are split into data lines anyway to keep the framing intact.
+func writeEvent(w http.ResponseWriter, event events.Event) {
+	fmt.Fprintf(w, "id: %s\nevent: %s\n", event.ID, event.Type)
+	for _, line := range bytes.Split(event.Data, []byte("\n")) {
+		fmt.Fprintf(w, "data: %s\n", line)
+	}
+	fmt.Fprint(w, "\n")
+}
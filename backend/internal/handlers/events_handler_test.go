@@ -0,0 +1,148 @@
+package handlers_test
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/events"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/handlers"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
+)
+
+// readFrames reads n Server-Sent Events frames, skipping comments and the
+// retry hint, and returns each frame's lines joined with "|"
+func readFrames(t *testing.T, scanner *bufio.Scanner, n int) []string {
+	t.Helper()
+	var frames []string
+	var lines []string
+	for len(frames) < n && scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if len(lines) > 0 {
+				frames = append(frames, strings.Join(lines, "|"))
+				lines = nil
+			}
+		case strings.HasPrefix(line, ":"), strings.HasPrefix(line, "retry:"):
+		default:
+			lines = append(lines, line)
+		}
+	}
+	if len(frames) < n {
+		t.Fatalf("Expected %d frames, got %v (%v)", n, frames, scanner.Err())
+	}
+	return frames
+}
+
+// openStream connects to the stream. The handler subscribes before it sends
+// the headers, so events published after this returns are received.
+func openStream(t *testing.T, url string, lastEventID string) (*bufio.Scanner, func()) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	req, _ := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		cancel()
+		t.Fatalf("Failed to open stream: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("Unexpected response %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	return bufio.NewScanner(resp.Body), func() {
+		cancel()
+		resp.Body.Close()
+	}
+}
+
+func TestEventStream(t *testing.T) {
+	broker := events.NewBroker(10)
+	server := httptest.NewServer(http.HandlerFunc(handlers.NewEventsHandler(broker, time.Hour).Stream))
+	defer server.Close()
+
+	live, closeLive := openStream(t, server.URL, "")
+	defer closeLive()
+
+	broker.Publish(context.Background(), models.EventBookingCreated, map[string]int{"id": 1})
+	broker.Publish(context.Background(), models.EventInquiryCreated, map[string]string{"name": "Ada"})
+	frames := readFrames(t, live, 2)
+
+	ids := make([]string, len(frames))
+	for i, frame := range frames {
+		id, rest, _ := strings.Cut(frame, "|")
+		ids[i] = strings.TrimPrefix(id, "id: ")
+		frames[i] = rest
+	}
+	expected := []string{
+		`event: booking.created|data: {"id":1}`,
+		`event: inquiry.created|data: {"name":"Ada"}`,
+	}
+	if strings.Join(frames, "\n") != strings.Join(expected, "\n") {
+		t.Fatalf("Expected frames %v, got %v", expected, frames)
+	}
+
+	tests := []struct {
+		name        string
+		lastEventID string
+		expected    string
+	}{
+		{name: "Resume replays missed events", lastEventID: ids[0], expected: "id: " + ids[1] + "|event: inquiry.created"},
+		{name: "Unknown ID asks for a resync", lastEventID: "gone", expected: "event: resync|data: {}"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			stream, closeStream := openStream(t, server.URL, tc.lastEventID)
+			defer closeStream()
+
+			frame := readFrames(t, stream, 1)[0]
+			if !strings.HasPrefix(frame, tc.expected) {
+				t.Errorf("Expected frame starting %q, got %q", tc.expected, frame)
+			}
+		})
+	}
+
+	// Closing the broker ends the stream
+	broker.Close()
+	for live.Scan() {
+	}
+}
+
+func TestBookingHandlerPublishesEvents(t *testing.T) {
+	broker := events.NewBroker(10)
+	sub, _, _ := broker.Subscribe("")
+
+	bookingRepo := &MockBookingRepository{
+		GetByIDFunc: func(ctx context.Context, id int) (*models.Booking, error) {
+			return &models.Booking{ID: id, Name: "Ada"}, nil
+		},
+		ArchiveFunc: func(ctx context.Context, id int) error { return nil },
+	}
+	handler := handlers.NewBookingHandler(bookingRepo, nil, nil, broker)
+
+	router := chi.NewRouter()
+	router.Post("/api/v1/bookings/{id}/archive", handler.Archive)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/api/v1/bookings/7/archive", nil))
+
+	if w.Code >= 300 {
+		t.Fatalf("Unexpected status %d (%s)", w.Code, w.Body.String())
+	}
+	select {
+	case event := <-sub.Events():
+		if event.Type != models.EventBookingArchived || !strings.Contains(string(event.Data), `"id":7`) {
+			t.Errorf("Unexpected event %+v", event)
+		}
+	default:
+		t.Errorf("Expected a %s event", models.EventBookingArchived)
+	}
+}
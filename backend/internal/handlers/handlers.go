@@ -1,24 +1,44 @@
 package handlers
 
 import (
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/auth"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/blob"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/config"
 	"github.com/joshuagudgel/toasted-coffee/backend/internal/database"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/events"
 	"github.com/joshuagudgel/toasted-coffee/backend/internal/services"
 )
 
 type Handlers struct {
-	Auth    *AuthHandler
-	Booking *BookingHandler
-	Contact *ContactHandler
-	Menu    *MenuHandler
-	Package *PackageHandler
+	Auth      *AuthHandler
+	Booking   *BookingHandler
+	Config    *ConfigHandler
+	Contact   *ContactHandler
+	Events    *EventsHandler
+	Inventory *InventoryHandler
+	Menu      *MenuHandler
+	Package   *PackageHandler
+	Report    *ReportHandler
+	Setup     *SetupHandler
+	User      *UserHandler
+	Webhook   *WebhookHandler
 }
 
-func NewHandlers(repos *database.Repositories, emailService *services.EmailService) *Handlers {
+func NewHandlers(cfg *config.Config, repos *database.Repositories, tokens *auth.TokenService,
+	emailService *services.EmailService, webhooks *services.WebhookService, broker *events.Broker,
+	setupToken string, authOpts AuthOptions) *Handlers {
 	return &Handlers{
-		Auth:    NewAuthHandler(repos.User),
-		Booking: NewBookingHandler(repos.Booking, emailService),
-		Contact: NewContactHandler(emailService),
-		Menu:    NewMenuHandler(repos.Menu),
-		Package: NewPackageHandler(repos.Package),
+		Auth:      NewAuthHandler(repos.User, repos.Refresh, repos.TwoFactor, repos.Reset, tokens, emailService, authOpts),
+		Booking:   NewBookingHandler(repos.Booking, emailService, webhooks, broker),
+		Config:    NewConfigHandler(cfg),
+		Contact:   NewContactHandler(emailService, webhooks, broker),
+		Events:    NewEventsHandler(broker, cfg.Events.Heartbeat),
+		Inventory: NewInventoryHandler(repos.Inventory, repos.Booking, cfg.Inventory.ServingsPerGuest),
+		Menu:      NewMenuHandler(repos.Menu, blob.New(cfg.Media), cfg.Media),
+		Package:   NewPackageHandler(repos.Package),
+		Report:    NewReportHandler(services.NewReportService(repos.Report, cfg.Reports)),
+		Setup:     NewSetupHandler(repos.User, setupToken),
+		User:      NewUserHandler(repos.User, repos.Refresh, repos.TwoFactor),
+		Webhook:   NewWebhookHandler(repos.Webhook, webhooks),
 	}
 }
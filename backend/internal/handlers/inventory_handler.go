@@ -0,0 +1,265 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/database"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/inventory"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
+)
+
+// InventoryHandler handles HTTP requests for ingredients, recipes and the
+// prep sheet
+type InventoryHandler struct {
+	repo             database.InventoryRepositoryInterface
+	bookings         database.BookingRepositoryInterface
+	servingsPerGuest float64
+}
+
+// NewInventoryHandler creates a new inventory handler
+func NewInventoryHandler(repo database.InventoryRepositoryInterface, bookings database.BookingRepositoryInterface,
+	servingsPerGuest float64) *InventoryHandler {
+	return &InventoryHandler{
+		repo:             repo,
+		bookings:         bookings,
+		servingsPerGuest: servingsPerGuest,
+	}
+}
+
+// GetIngredients returns every ingredient and its stock
+func (h *InventoryHandler) GetIngredients(w http.ResponseWriter, r *http.Request) {
+	ingredients, err := h.repo.GetIngredients(r.Context())
+	if err != nil {
+		writeInventoryError(w, r, err, "Failed to retrieve ingredients")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ingredients)
+}
+
+// CreateIngredient adds an ingredient
+func (h *InventoryHandler) CreateIngredient(w http.ResponseWriter, r *http.Request) {
+	var input models.IngredientInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if msg := validateIngredientInput(&input); msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	ingredient, err := h.repo.CreateIngredient(r.Context(), &input)
+	if err != nil {
+		writeInventoryError(w, r, err, "Failed to create ingredient")
+		return
+	}
+
+	slog.InfoContext(r.Context(), "ingredient created", "ingredient_id", ingredient.ID, "name", ingredient.Name)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(ingredient)
+}
+
+// UpdateIngredient replaces an ingredient's name, unit and stock
+func (h *InventoryHandler) UpdateIngredient(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid ingredient ID", http.StatusBadRequest)
+		return
+	}
+
+	var input models.IngredientInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if msg := validateIngredientInput(&input); msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	ingredient, err := h.repo.UpdateIngredient(r.Context(), id, &input)
+	if err != nil {
+		writeInventoryError(w, r, err, "Failed to update ingredient")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ingredient)
+}
+
+// DeleteIngredient removes an ingredient, taking it out of every recipe
+func (h *InventoryHandler) DeleteIngredient(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid ingredient ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.repo.DeleteIngredient(r.Context(), id); err != nil {
+		writeInventoryError(w, r, err, "Failed to delete ingredient")
+		return
+	}
+
+	slog.InfoContext(r.Context(), "ingredient deleted", "ingredient_id", id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetRecipes returns the recipe of every menu item
+func (h *InventoryHandler) GetRecipes(w http.ResponseWriter, r *http.Request) {
+	recipes, err := h.repo.GetRecipes(r.Context())
+	if err != nil {
+		writeInventoryError(w, r, err, "Failed to retrieve recipes")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(recipes)
+}
+
+// SetRecipe replaces the ingredients in one serving of a menu item. An empty
+// list removes the recipe.
+func (h *InventoryHandler) SetRecipe(w http.ResponseWriter, r *http.Request) {
+	menuItemID, err := strconv.Atoi(chi.URLParam(r, "menuItemId"))
+	if err != nil {
+		http.Error(w, "Invalid menu item ID", http.StatusBadRequest)
+		return
+	}
+
+	var input models.RecipeInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if msg := validateRecipeInput(&input); msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.repo.SetRecipe(r.Context(), menuItemID, input.Ingredients); err != nil {
+		writeInventoryError(w, r, err, "Failed to save recipe")
+		return
+	}
+
+	recipes, err := h.repo.GetRecipes(r.Context())
+	if err != nil {
+		writeInventoryError(w, r, err, "Failed to retrieve recipes")
+		return
+	}
+	for _, recipe := range recipes {
+		if recipe.MenuItemID == menuItemID {
+			slog.InfoContext(r.Context(), "recipe saved", "menu_item_id", menuItemID, "ingredients", len(recipe.Ingredients))
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(recipe)
+			return
+		}
+	}
+	http.Error(w, "Menu item not found", http.StatusNotFound)
+}
+
+// PrepSheet returns what to prepare and buy for the bookings on a date,
+// today unless ?date= is given. Bookings from today until the day before
+// use stock first, so an ingredient is flagged short when they and the
+// date's bookings together need more than is in stock.
+func (h *InventoryHandler) PrepSheet(w http.ResponseWriter, r *http.Request) {
+	today := time.Now().Format("2006-01-02")
+	date := r.URL.Query().Get("date")
+	if date == "" {
+		date = today
+	}
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		http.Error(w, "Invalid date format. Use YYYY-MM-DD", http.StatusBadRequest)
+		return
+	}
+
+	archived := false
+	filter := &models.BookingFilter{Archived: &archived, DateFrom: min(today, date), DateTo: date}
+	var bookings []*models.Booking
+	err := h.bookings.Stream(r.Context(), filter, func(booking *models.Booking) error {
+		bookings = append(bookings, booking)
+		return nil
+	})
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to load bookings for prep sheet", "date", date, "error", err)
+		http.Error(w, "Failed to build prep sheet", http.StatusInternalServerError)
+		return
+	}
+
+	recipes, err := h.repo.GetRecipes(r.Context())
+	if err != nil {
+		writeInventoryError(w, r, err, "Failed to build prep sheet")
+		return
+	}
+	ingredients, err := h.repo.GetIngredients(r.Context())
+	if err != nil {
+		writeInventoryError(w, r, err, "Failed to build prep sheet")
+		return
+	}
+
+	sheet := inventory.BuildPrepSheet(date, bookings, recipes, ingredients, h.servingsPerGuest)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sheet)
+}
+
+// validateIngredientInput returns a message describing the first problem with input
+func validateIngredientInput(input *models.IngredientInput) string {
+	input.Name = strings.TrimSpace(input.Name)
+	input.Unit = strings.TrimSpace(input.Unit)
+	if input.Name == "" || len(input.Name) > 100 {
+		return "Name is required and must be at most 100 characters"
+	}
+	if input.Unit == "" || len(input.Unit) > 20 {
+		return "Unit is required and must be at most 20 characters"
+	}
+	if input.Stock < 0 {
+		return "Stock cannot be negative"
+	}
+	return ""
+}
+
+// validateRecipeInput returns a message describing the first problem with input
+func validateRecipeInput(input *models.RecipeInput) string {
+	seen := make(map[int]bool, len(input.Ingredients))
+	for _, ingredient := range input.Ingredients {
+		if ingredient.IngredientID <= 0 {
+			return "Each ingredient needs an ingredientId"
+		}
+		if ingredient.Quantity <= 0 {
+			return "Quantities must be greater than zero"
+		}
+		if seen[ingredient.IngredientID] {
+			return "Ingredient " + strconv.Itoa(ingredient.IngredientID) + " is listed more than once"
+		}
+		seen[ingredient.IngredientID] = true
+	}
+	return ""
+}
+
+// writeInventoryError maps repository errors to HTTP responses
+func writeInventoryError(w http.ResponseWriter, r *http.Request, err error, fallback string) {
+	switch {
+	case errors.Is(err, database.ErrIngredientNotFound):
+		http.Error(w, "Ingredient not found", http.StatusNotFound)
+	case errors.Is(err, database.ErrMenuItemNotFound):
+		http.Error(w, "Menu item not found", http.StatusNotFound)
+	case errors.Is(err, database.ErrIngredientExists):
+		http.Error(w, "An ingredient with that name already exists", http.StatusConflict)
+	default:
+		slog.ErrorContext(r.Context(), "inventory handler error", "error", err)
+		http.Error(w, fallback, http.StatusInternalServerError)
+	}
+}
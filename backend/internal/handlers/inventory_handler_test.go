@@ -0,0 +1,314 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/database"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/handlers"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
+)
+
+// MockInventoryRepository implements the inventory repository interface for testing
+type MockInventoryRepository struct {
+	// GetIngredients
+	GetIngredientsFunc func(context.Context) ([]*models.Ingredient, error)
+
+	// CreateIngredient
+	CreateIngredientFunc   func(context.Context, *models.IngredientInput) (*models.Ingredient, error)
+	CreateIngredientCalled bool
+
+	// UpdateIngredient
+	UpdateIngredientFunc func(context.Context, int, *models.IngredientInput) (*models.Ingredient, error)
+
+	// DeleteIngredient
+	DeleteIngredientFunc func(context.Context, int) error
+
+	// GetRecipes
+	GetRecipesFunc func(context.Context) ([]*models.Recipe, error)
+
+	// SetRecipe
+	SetRecipeFunc        func(context.Context, int, []models.RecipeIngredient) error
+	SetRecipeCalled      bool
+	SetRecipeIngredients []models.RecipeIngredient
+}
+
+func (m *MockInventoryRepository) GetIngredients(ctx context.Context) ([]*models.Ingredient, error) {
+	return m.GetIngredientsFunc(ctx)
+}
+
+func (m *MockInventoryRepository) CreateIngredient(ctx context.Context, input *models.IngredientInput) (*models.Ingredient, error) {
+	m.CreateIngredientCalled = true
+	return m.CreateIngredientFunc(ctx, input)
+}
+
+func (m *MockInventoryRepository) UpdateIngredient(ctx context.Context, id int, input *models.IngredientInput) (*models.Ingredient, error) {
+	return m.UpdateIngredientFunc(ctx, id, input)
+}
+
+func (m *MockInventoryRepository) DeleteIngredient(ctx context.Context, id int) error {
+	return m.DeleteIngredientFunc(ctx, id)
+}
+
+func (m *MockInventoryRepository) GetRecipes(ctx context.Context) ([]*models.Recipe, error) {
+	return m.GetRecipesFunc(ctx)
+}
+
+func (m *MockInventoryRepository) SetRecipe(ctx context.Context, menuItemID int, ingredients []models.RecipeIngredient) error {
+	m.SetRecipeCalled = true
+	m.SetRecipeIngredients = ingredients
+	return m.SetRecipeFunc(ctx, menuItemID, ingredients)
+}
+
+// Verify interface implementation
+var _ database.InventoryRepositoryInterface = &MockInventoryRepository{}
+
+// newTestInventoryRepo returns a repository with oat milk, stocked with 2000
+// ml, in the recipe of the oat milk option
+func newTestInventoryRepo() *MockInventoryRepository {
+	return &MockInventoryRepository{
+		GetIngredientsFunc: func(ctx context.Context) ([]*models.Ingredient, error) {
+			return []*models.Ingredient{
+				{ID: 1, Name: "Oat milk", Unit: "ml", Stock: 2000, CreatedAt: time.Now(), UpdatedAt: time.Now()},
+			}, nil
+		},
+		CreateIngredientFunc: func(ctx context.Context, input *models.IngredientInput) (*models.Ingredient, error) {
+			return &models.Ingredient{ID: 2, Name: input.Name, Unit: input.Unit, Stock: input.Stock}, nil
+		},
+		GetRecipesFunc: func(ctx context.Context) ([]*models.Recipe, error) {
+			return []*models.Recipe{
+				{MenuItemID: 3, Value: "vanilla", Label: "Vanilla", Type: models.CoffeeFlavor, Ingredients: []models.RecipeIngredient{}},
+				{MenuItemID: 4, Value: "oat", Label: "Oat", Type: models.MilkOption, Ingredients: []models.RecipeIngredient{
+					{IngredientID: 1, Name: "Oat milk", Unit: "ml", Quantity: 60},
+				}},
+			}, nil
+		},
+		SetRecipeFunc: func(ctx context.Context, menuItemID int, ingredients []models.RecipeIngredient) error {
+			return nil
+		},
+	}
+}
+
+func TestCreateIngredientHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          models.IngredientInput
+		repoErr        error
+		expectedStatus int
+	}{
+		{name: "Valid ingredient", input: models.IngredientInput{Name: " Oat milk ", Unit: "ml", Stock: 2000}, expectedStatus: http.StatusCreated},
+		{name: "Missing name", input: models.IngredientInput{Unit: "ml"}, expectedStatus: http.StatusBadRequest},
+		{name: "Missing unit", input: models.IngredientInput{Name: "Oat milk"}, expectedStatus: http.StatusBadRequest},
+		{name: "Negative stock", input: models.IngredientInput{Name: "Oat milk", Unit: "ml", Stock: -1}, expectedStatus: http.StatusBadRequest},
+		{
+			name:           "Duplicate name",
+			input:          models.IngredientInput{Name: "Oat milk", Unit: "ml"},
+			repoErr:        database.ErrIngredientExists,
+			expectedStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := newTestInventoryRepo()
+			var created *models.IngredientInput
+			mockRepo.CreateIngredientFunc = func(ctx context.Context, input *models.IngredientInput) (*models.Ingredient, error) {
+				created = input
+				if tc.repoErr != nil {
+					return nil, tc.repoErr
+				}
+				return &models.Ingredient{ID: 2, Name: input.Name, Unit: input.Unit, Stock: input.Stock}, nil
+			}
+			handler := handlers.NewInventoryHandler(mockRepo, &MockBookingRepository{}, 1)
+
+			body, _ := json.Marshal(tc.input)
+			w := httptest.NewRecorder()
+			handler.CreateIngredient(w, httptest.NewRequest("POST", "/api/v1/ingredients", bytes.NewBuffer(body)))
+
+			if w.Code != tc.expectedStatus {
+				t.Fatalf("Expected status %d, got %d (%s)", tc.expectedStatus, w.Code, w.Body.String())
+			}
+			if tc.expectedStatus == http.StatusBadRequest && mockRepo.CreateIngredientCalled {
+				t.Error("CreateIngredient should not be called for invalid input")
+			}
+			if tc.expectedStatus == http.StatusCreated && created.Name != "Oat milk" {
+				t.Errorf("Expected the name to be trimmed, got %q", created.Name)
+			}
+		})
+	}
+}
+
+func TestSetRecipeHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		menuItemID     string
+		ingredients    []models.RecipeIngredient
+		repoErr        error
+		expectedStatus int
+	}{
+		{
+			name:           "Valid recipe",
+			menuItemID:     "4",
+			ingredients:    []models.RecipeIngredient{{IngredientID: 1, Quantity: 60}},
+			expectedStatus: http.StatusOK,
+		},
+		{name: "Empty recipe", menuItemID: "4", ingredients: []models.RecipeIngredient{}, expectedStatus: http.StatusOK},
+		{name: "Invalid menu item ID", menuItemID: "oat", expectedStatus: http.StatusBadRequest},
+		{
+			name:           "Zero quantity",
+			menuItemID:     "4",
+			ingredients:    []models.RecipeIngredient{{IngredientID: 1}},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Ingredient listed twice",
+			menuItemID:     "4",
+			ingredients:    []models.RecipeIngredient{{IngredientID: 1, Quantity: 60}, {IngredientID: 1, Quantity: 10}},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Unknown ingredient",
+			menuItemID:     "4",
+			ingredients:    []models.RecipeIngredient{{IngredientID: 9, Quantity: 60}},
+			repoErr:        database.ErrIngredientNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Unknown menu item",
+			menuItemID:     "99",
+			ingredients:    []models.RecipeIngredient{{IngredientID: 1, Quantity: 60}},
+			repoErr:        database.ErrMenuItemNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := newTestInventoryRepo()
+			mockRepo.SetRecipeFunc = func(ctx context.Context, menuItemID int, ingredients []models.RecipeIngredient) error {
+				return tc.repoErr
+			}
+			handler := handlers.NewInventoryHandler(mockRepo, &MockBookingRepository{}, 1)
+
+			body, _ := json.Marshal(models.RecipeInput{Ingredients: tc.ingredients})
+			req := httptest.NewRequest("PUT", "/api/v1/recipes/"+tc.menuItemID, bytes.NewBuffer(body))
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("menuItemId", tc.menuItemID)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+			w := httptest.NewRecorder()
+
+			handler.SetRecipe(w, req)
+
+			if w.Code != tc.expectedStatus {
+				t.Fatalf("Expected status %d, got %d (%s)", tc.expectedStatus, w.Code, w.Body.String())
+			}
+			if tc.expectedStatus == http.StatusBadRequest && mockRepo.SetRecipeCalled {
+				t.Error("SetRecipe should not be called for invalid input")
+			}
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var recipe models.Recipe
+			if err := json.NewDecoder(w.Body).Decode(&recipe); err != nil {
+				t.Fatalf("Failed to decode recipe: %v", err)
+			}
+			if recipe.MenuItemID != 4 {
+				t.Errorf("Expected the saved recipe, got menu item %d", recipe.MenuItemID)
+			}
+		})
+	}
+}
+
+func TestPrepSheetHandler(t *testing.T) {
+	bookings := []*models.Booking{
+		{ID: 1, Date: "2099-06-01", Time: "09:00", People: 20, CoffeeFlavors: []string{"vanilla"}, MilkOptions: []string{"oat"}},
+		{ID: 2, Date: "2099-06-02", Time: "10:00", People: 30, CoffeeFlavors: []string{"vanilla"}, MilkOptions: []string{"oat"}},
+	}
+
+	tests := []struct {
+		name             string
+		query            string
+		expectedStatus   int
+		expectedFrom     string
+		expectedTo       string
+		expectedBookings int
+		expectedShort    bool
+	}{
+		{
+			name:             "Earlier bookings use stock first",
+			query:            "?date=2099-06-02",
+			expectedStatus:   http.StatusOK,
+			expectedTo:       "2099-06-02",
+			expectedBookings: 1,
+			expectedShort:    true,
+		},
+		{
+			name:             "Past date",
+			query:            "?date=2020-01-01",
+			expectedStatus:   http.StatusOK,
+			expectedFrom:     "2020-01-01",
+			expectedTo:       "2020-01-01",
+			expectedBookings: 0,
+		},
+		{name: "Defaults to today", expectedStatus: http.StatusOK},
+		{name: "Invalid date", query: "?date=June", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			bookingRepo := &MockBookingRepository{
+				StreamFunc: func(ctx context.Context, f *models.BookingFilter, fn func(*models.Booking) error) error {
+					for _, b := range bookings {
+						if b.Date < f.DateFrom || b.Date > f.DateTo {
+							continue
+						}
+						if err := fn(b); err != nil {
+							return err
+						}
+					}
+					return nil
+				},
+			}
+			handler := handlers.NewInventoryHandler(newTestInventoryRepo(), bookingRepo, 1)
+
+			w := httptest.NewRecorder()
+			handler.PrepSheet(w, httptest.NewRequest("GET", "/api/v1/prep-sheet"+tc.query, nil))
+
+			if w.Code != tc.expectedStatus {
+				t.Fatalf("Expected status %d, got %d (%s)", tc.expectedStatus, w.Code, w.Body.String())
+			}
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+
+			filter := bookingRepo.StreamFilter
+			if filter.Archived == nil || *filter.Archived {
+				t.Error("Expected archived bookings to be left out")
+			}
+			if tc.expectedFrom != "" && filter.DateFrom != tc.expectedFrom {
+				t.Errorf("Expected bookings from %s, got %s", tc.expectedFrom, filter.DateFrom)
+			}
+			if tc.expectedTo != "" && filter.DateTo != tc.expectedTo {
+				t.Errorf("Expected bookings to %s, got %s", tc.expectedTo, filter.DateTo)
+			}
+
+			var sheet models.PrepSheet
+			if err := json.NewDecoder(w.Body).Decode(&sheet); err != nil {
+				t.Fatalf("Failed to decode prep sheet: %v", err)
+			}
+			if sheet.Bookings != tc.expectedBookings {
+				t.Errorf("Expected %d bookings, got %d", tc.expectedBookings, sheet.Bookings)
+			}
+			if short := len(sheet.Shopping) > 0; short != tc.expectedShort {
+				t.Errorf("Expected short %v, got shopping list %+v", tc.expectedShort, sheet.Shopping)
+			}
+		})
+	}
+}
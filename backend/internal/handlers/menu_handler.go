@@ -2,22 +2,35 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"log/slog"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/blob"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/config"
 	"github.com/joshuagudgel/toasted-coffee/backend/internal/database"
 	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
 )
 
+// Limits on a menu item's description and price modifier
+const (
+	maxMenuDescription   = 500
+	maxMenuPriceModifier = 100
+)
+
 // MenuHandler handles HTTP requests for menu items
 type MenuHandler struct {
-	repo database.MenuRepositoryInterface
+	repo  database.MenuRepositoryInterface
+	store blob.Store
+	media config.MediaConfig
 }
 
-// NewMenuHandler creates a new menu handler
-func NewMenuHandler(repo database.MenuRepositoryInterface) *MenuHandler {
-	return &MenuHandler{repo: repo}
+// NewMenuHandler creates a new menu handler. Item images are kept in store.
+func NewMenuHandler(repo database.MenuRepositoryInterface, store blob.Store, media config.MediaConfig) *MenuHandler {
+	return &MenuHandler{repo: repo, store: store, media: media}
 }
 
 // GetAll returns all menu items
@@ -30,7 +43,7 @@ func (h *MenuHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(items)
+	json.NewEncoder(w).Encode(h.withImageURLs(items))
 }
 
 // GetByType returns menu items of a specific type
@@ -49,7 +62,7 @@ func (h *MenuHandler) GetByType(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(items)
+	json.NewEncoder(w).Encode(h.withImageURLs(items))
 }
 
 // Create handles POST /menu requests to add a new menu item
@@ -61,13 +74,8 @@ func (h *MenuHandler) Create(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Validate the menu item
-	if menuItem.Value == "" || menuItem.Label == "" {
-		http.Error(w, "Value and label are required", http.StatusBadRequest)
-		return
-	}
-
-	if menuItem.Type != models.CoffeeFlavor && menuItem.Type != models.MilkOption {
-		http.Error(w, "Type must be either coffee_flavor or milk_option", http.StatusBadRequest)
+	if msg := validateMenuItem(&menuItem); msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
 		return
 	}
 
@@ -105,13 +113,8 @@ func (h *MenuHandler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Validate the menu item
-	if menuItem.Value == "" || menuItem.Label == "" {
-		http.Error(w, "Value and label are required", http.StatusBadRequest)
-		return
-	}
-
-	if menuItem.Type != models.CoffeeFlavor && menuItem.Type != models.MilkOption {
-		http.Error(w, "Type must be either coffee_flavor or milk_option", http.StatusBadRequest)
+	if msg := validateMenuItem(&menuItem); msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
 		return
 	}
 
@@ -154,3 +157,98 @@ func (h *MenuHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		"message": "Menu item deleted successfully",
 	})
 }
+
+// Reorder handles PUT /menu/order requests to change the order items of one
+// type are shown in, returning the items of the type in their new order
+func (h *MenuHandler) Reorder(w http.ResponseWriter, r *http.Request) {
+	var order models.MenuOrder
+	if err := json.NewDecoder(r.Body).Decode(&order); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if order.Type != models.CoffeeFlavor && order.Type != models.MilkOption {
+		http.Error(w, "Type must be either coffee_flavor or milk_option", http.StatusBadRequest)
+		return
+	}
+	if len(order.IDs) == 0 {
+		http.Error(w, "At least one menu item ID is required", http.StatusBadRequest)
+		return
+	}
+	seen := make(map[int]bool, len(order.IDs))
+	for _, id := range order.IDs {
+		if seen[id] {
+			http.Error(w, "Menu item "+strconv.Itoa(id)+" is listed more than once", http.StatusBadRequest)
+			return
+		}
+		seen[id] = true
+	}
+
+	if err := h.repo.Reorder(r.Context(), order.Type, order.IDs); err != nil {
+		writeMenuError(w, r, err, "Failed to reorder menu items")
+		return
+	}
+
+	items, err := h.repo.GetByType(r.Context(), order.Type)
+	if err != nil {
+		writeMenuError(w, r, err, "Failed to retrieve menu items")
+		return
+	}
+
+	slog.InfoContext(r.Context(), "menu reordered", "type", order.Type, "ids", order.IDs)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.withImageURLs(items))
+}
+
+// withImageURLs fills in where each item's image is served from
+func (h *MenuHandler) withImageURLs(items []models.MenuItem) []models.MenuItem {
+	for i := range items {
+		if items[i].ImageKey != "" {
+			items[i].ImageURL = h.store.URL(items[i].ImageKey)
+		}
+	}
+	return items
+}
+
+// validateMenuItem returns a message describing the first problem with item
+func validateMenuItem(item *models.MenuItem) string {
+	if item.Value == "" || item.Label == "" {
+		return "Value and label are required"
+	}
+	if item.Type != models.CoffeeFlavor && item.Type != models.MilkOption {
+		return "Type must be either coffee_flavor or milk_option"
+	}
+
+	item.Description = strings.TrimSpace(item.Description)
+	if len(item.Description) > maxMenuDescription {
+		return "Description must be at most 500 characters"
+	}
+	if item.PriceModifier < -maxMenuPriceModifier || item.PriceModifier > maxMenuPriceModifier {
+		return "Price modifier must be between -100 and 100"
+	}
+
+	tags := []string{}
+	seen := make(map[string]bool, len(item.Tags))
+	for _, tag := range item.Tags {
+		if !models.IsValidMenuTag(tag) {
+			return "Unknown tag " + strconv.Quote(tag) + ", must be one of: " + strings.Join(models.MenuTags(), " ")
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	item.Tags = tags
+	return ""
+}
+
+// writeMenuError maps repository errors to HTTP responses
+func writeMenuError(w http.ResponseWriter, r *http.Request, err error, fallback string) {
+	if errors.Is(err, database.ErrMenuItemNotFound) {
+		http.Error(w, "Menu item not found", http.StatusNotFound)
+		return
+	}
+	slog.ErrorContext(r.Context(), "menu handler error", "error", err)
+	http.Error(w, fallback, http.StatusInternalServerError)
+}
@@ -1,8 +1,18 @@
 package handlers_test
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
 
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/blob"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/config"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/database"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/handlers"
 	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
 )
 
@@ -32,6 +42,18 @@ type MockMenuRepository struct {
 	DeleteFunc   func(context.Context, int) error
 	DeleteCalled bool
 	DeleteArg    int
+
+	// GetByID
+	GetByIDFunc func(context.Context, int) (*models.MenuItem, error)
+
+	// Reorder
+	ReorderFunc   func(context.Context, models.ItemType, []int) error
+	ReorderCalled bool
+	ReorderIDs    []int
+
+	// SetImage
+	SetImageFunc func(context.Context, int, string) (string, error)
+	SetImageKeys []string
 }
 
 // Implement interface methods
@@ -64,3 +86,187 @@ func (m *MockMenuRepository) Delete(ctx context.Context, id int) error {
 	m.DeleteArg = id
 	return m.DeleteFunc(ctx, id)
 }
+
+func (m *MockMenuRepository) GetByID(ctx context.Context, id int) (*models.MenuItem, error) {
+	return m.GetByIDFunc(ctx, id)
+}
+
+func (m *MockMenuRepository) Reorder(ctx context.Context, itemType models.ItemType, ids []int) error {
+	m.ReorderCalled = true
+	m.ReorderIDs = ids
+	return m.ReorderFunc(ctx, itemType, ids)
+}
+
+func (m *MockMenuRepository) SetImage(ctx context.Context, id int, key string) (string, error) {
+	m.SetImageKeys = append(m.SetImageKeys, key)
+	return m.SetImageFunc(ctx, id, key)
+}
+
+// Verify interface implementation
+var _ database.MenuRepositoryInterface = &MockMenuRepository{}
+
+// newTestMenuHandler returns a handler keeping images in a temporary
+// directory, which is returned too
+func newTestMenuHandler(t *testing.T, repo *MockMenuRepository) (*handlers.MenuHandler, string) {
+	dir := t.TempDir()
+	media := config.Default().Media
+	media.Dir = dir
+	return handlers.NewMenuHandler(repo, blob.NewLocalStore(dir, media.BaseURL), media), dir
+}
+
+func TestCreateMenuItemHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		item           models.MenuItem
+		expectedStatus int
+		expectedTags   []string
+	}{
+		{
+			name: "Dietary tags and price modifier",
+			item: models.MenuItem{Value: "almond", Label: "Almond Milk", Type: models.MilkOption, Active: true,
+				Description: " Unsweetened ", Tags: []string{"dairy_free", "vegan", "dairy_free"}, PriceModifier: 0.5},
+			expectedStatus: http.StatusCreated,
+			expectedTags:   []string{"dairy_free", "vegan"},
+		},
+		{
+			name:           "No tags",
+			item:           models.MenuItem{Value: "whole", Label: "Whole Milk", Type: models.MilkOption},
+			expectedStatus: http.StatusCreated,
+			expectedTags:   []string{},
+		},
+		{
+			name:           "Unknown tag",
+			item:           models.MenuItem{Value: "whole", Label: "Whole Milk", Type: models.MilkOption, Tags: []string{"organic"}},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Price modifier out of range",
+			item:           models.MenuItem{Value: "whole", Label: "Whole Milk", Type: models.MilkOption, PriceModifier: 250},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Unknown type",
+			item:           models.MenuItem{Value: "green", Label: "Green Tea", Type: "tea"},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := &MockMenuRepository{
+				CreateFunc: func(ctx context.Context, item *models.MenuItem) (int, error) { return 3, nil },
+			}
+			handler, _ := newTestMenuHandler(t, mockRepo)
+
+			body, _ := json.Marshal(tc.item)
+			w := httptest.NewRecorder()
+			handler.Create(w, httptest.NewRequest("POST", "/api/v1/menu", bytes.NewBuffer(body)))
+
+			if w.Code != tc.expectedStatus {
+				t.Fatalf("Expected status %d, got %d (%s)", tc.expectedStatus, w.Code, w.Body.String())
+			}
+			if tc.expectedStatus != http.StatusCreated {
+				if mockRepo.CreateCalled {
+					t.Error("Create should not be called for invalid input")
+				}
+				return
+			}
+
+			created := mockRepo.CreateItem
+			if len(created.Tags) != len(tc.expectedTags) {
+				t.Fatalf("Expected tags %v, got %v", tc.expectedTags, created.Tags)
+			}
+			for i, tag := range tc.expectedTags {
+				if created.Tags[i] != tag {
+					t.Errorf("Expected tags %v, got %v", tc.expectedTags, created.Tags)
+				}
+			}
+			if created.Description != strings.TrimSpace(tc.item.Description) {
+				t.Errorf("Expected the description to be trimmed, got %q", created.Description)
+			}
+		})
+	}
+}
+
+func TestGetAllMenuItemsImageURLs(t *testing.T) {
+	mockRepo := &MockMenuRepository{
+		GetAllFunc: func(ctx context.Context) ([]models.MenuItem, error) {
+			return []models.MenuItem{
+				{ID: 1, Value: "oat", Label: "Oat Milk", Type: models.MilkOption, ImageKey: "menu-1-abc.jpg"},
+				{ID: 2, Value: "whole", Label: "Whole Milk", Type: models.MilkOption},
+			}, nil
+		},
+	}
+	handler, _ := newTestMenuHandler(t, mockRepo)
+
+	w := httptest.NewRecorder()
+	handler.GetAll(w, httptest.NewRequest("GET", "/api/v1/menu", nil))
+
+	var items []map[string]interface{}
+	if err := json.NewDecoder(w.Body).Decode(&items); err != nil {
+		t.Fatalf("Failed to decode menu: %v", err)
+	}
+	if items[0]["imageUrl"] != "http://localhost:8080/media/menu-1-abc.jpg" {
+		t.Errorf("Expected the image URL, got %v", items[0]["imageUrl"])
+	}
+	if _, ok := items[1]["imageUrl"]; ok {
+		t.Errorf("Expected no image URL for an item without an image, got %v", items[1]["imageUrl"])
+	}
+	if _, ok := items[0]["ImageKey"]; ok {
+		t.Error("Image key should not be exposed")
+	}
+}
+
+func TestReorderMenuHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		order          models.MenuOrder
+		repoErr        error
+		expectedStatus int
+	}{
+		{
+			name:           "Valid order",
+			order:          models.MenuOrder{Type: models.MilkOption, IDs: []int{3, 1, 2}},
+			expectedStatus: http.StatusOK,
+		},
+		{name: "No IDs", order: models.MenuOrder{Type: models.MilkOption}, expectedStatus: http.StatusBadRequest},
+		{name: "Unknown type", order: models.MenuOrder{Type: "tea", IDs: []int{1}}, expectedStatus: http.StatusBadRequest},
+		{
+			name:           "ID listed twice",
+			order:          models.MenuOrder{Type: models.MilkOption, IDs: []int{1, 1}},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Item of another type",
+			order:          models.MenuOrder{Type: models.CoffeeFlavor, IDs: []int{1}},
+			repoErr:        database.ErrMenuItemNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := &MockMenuRepository{
+				ReorderFunc: func(ctx context.Context, itemType models.ItemType, ids []int) error { return tc.repoErr },
+				GetByTypeFunc: func(ctx context.Context, itemType models.ItemType) ([]models.MenuItem, error) {
+					return []models.MenuItem{{ID: 3, Value: "oat", Label: "Oat Milk", Type: itemType}}, nil
+				},
+			}
+			handler, _ := newTestMenuHandler(t, mockRepo)
+
+			body, _ := json.Marshal(tc.order)
+			w := httptest.NewRecorder()
+			handler.Reorder(w, httptest.NewRequest("PUT", "/api/v1/menu/order", bytes.NewBuffer(body)))
+
+			if w.Code != tc.expectedStatus {
+				t.Fatalf("Expected status %d, got %d (%s)", tc.expectedStatus, w.Code, w.Body.String())
+			}
+			if tc.expectedStatus == http.StatusBadRequest && mockRepo.ReorderCalled {
+				t.Error("Reorder should not be called for invalid input")
+			}
+			if tc.expectedStatus == http.StatusOK && !mockRepo.GetByTypeCalled {
+				t.Error("Expected the reordered items to be returned")
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/imaging"
+)
+
+// multipartOverhead allows for the form encoding around an uploaded image
+const multipartOverhead = 64 << 10
+
+// UploadImage handles POST /menu/{id}/image requests. The image, sent as the
+// "image" field of a multipart form, is resized and replaces any the item
+// had. The updated item is returned.
+func (h *MenuHandler) UploadImage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid menu item ID", http.StatusBadRequest)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, h.media.MaxUploadSize+multipartOverhead)
+	file, header, err := r.FormFile("image")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, "Image is too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "An image file is required in the image field", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+	if header.Size > h.media.MaxUploadSize {
+		http.Error(w, "Image is too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	img, err := imaging.Resize(file, h.media.ImageSize)
+	if err != nil {
+		if errors.Is(err, imaging.ErrUnsupported) || errors.Is(err, imaging.ErrTooLarge) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		slog.ErrorContext(ctx, "failed to resize menu image", "menu_item_id", id, "error", err)
+		http.Error(w, "Failed to process image", http.StatusInternalServerError)
+		return
+	}
+
+	if _, err := h.repo.GetByID(ctx, id); err != nil {
+		writeMenuError(w, r, err, "Failed to retrieve menu item")
+		return
+	}
+
+	// A new key each time, so cached copies of the old image are never served
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		slog.ErrorContext(ctx, "failed to generate image key", "error", err)
+		http.Error(w, "Failed to save image", http.StatusInternalServerError)
+		return
+	}
+	key := fmt.Sprintf("menu-%d-%s%s", id, hex.EncodeToString(suffix), img.Ext)
+
+	if err := h.store.Put(ctx, key, bytes.NewReader(img.Data), img.ContentType); err != nil {
+		slog.ErrorContext(ctx, "failed to store menu image", "menu_item_id", id, "key", key, "error", err)
+		http.Error(w, "Failed to save image", http.StatusInternalServerError)
+		return
+	}
+
+	previous, err := h.repo.SetImage(ctx, id, key)
+	if err != nil {
+		h.deleteImage(r, key)
+		writeMenuError(w, r, err, "Failed to save image")
+		return
+	}
+	if previous != "" {
+		h.deleteImage(r, previous)
+	}
+
+	item, err := h.repo.GetByID(ctx, id)
+	if err != nil {
+		writeMenuError(w, r, err, "Failed to retrieve menu item")
+		return
+	}
+	item.ImageURL = h.store.URL(item.ImageKey)
+
+	slog.InfoContext(ctx, "menu image uploaded", "menu_item_id", id, "key", key,
+		"width", img.Width, "height", img.Height, "bytes", len(img.Data))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(item)
+}
+
+// DeleteImage handles DELETE /menu/{id}/image requests to remove an item's image
+func (h *MenuHandler) DeleteImage(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid menu item ID", http.StatusBadRequest)
+		return
+	}
+
+	previous, err := h.repo.SetImage(r.Context(), id, "")
+	if err != nil {
+		writeMenuError(w, r, err, "Failed to delete image")
+		return
+	}
+	if previous != "" {
+		h.deleteImage(r, previous)
+	}
+
+	slog.InfoContext(r.Context(), "menu image deleted", "menu_item_id", id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// deleteImage removes a stored image that is no longer used. Failures are
+// only logged; the file is orphaned but nothing refers to it.
+func (h *MenuHandler) deleteImage(r *http.Request, key string) {
+	if err := h.store.Delete(r.Context(), key); err != nil {
+		slog.WarnContext(r.Context(), "failed to delete menu image", "key", key, "error", err)
+	}
+}
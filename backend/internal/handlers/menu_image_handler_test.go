@@ -0,0 +1,206 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/database"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
+)
+
+// testPNG encodes a width by height PNG, transparent unless opaque is set
+func testPNG(t *testing.T, width, height int, opaque bool) []byte {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	fill := color.NRGBA{R: 200, G: 120, B: 60, A: 128}
+	if opaque {
+		fill.A = 255
+	}
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, fill)
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("Failed to encode test image: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// imageRequest builds a multipart upload of data in the image field
+func imageRequest(t *testing.T, id string, field string, data []byte) *http.Request {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile(field, "photo.png")
+	if err != nil {
+		t.Fatalf("Failed to build form: %v", err)
+	}
+	part.Write(data)
+	form.Close()
+
+	req := httptest.NewRequest("POST", "/api/v1/menu/"+id+"/image", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", id)
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+}
+
+func TestUploadMenuImageHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		id             string
+		field          string
+		data           []byte
+		previousKey    string
+		setImageErr    error
+		expectedStatus int
+		expectedExt    string
+		expectedSize   [2]int
+	}{
+		{
+			name:           "Photo is resized to JPEG",
+			id:             "3",
+			field:          "image",
+			data:           testPNG(t, 1600, 1200, true),
+			expectedStatus: http.StatusOK,
+			expectedExt:    ".jpg",
+			expectedSize:   [2]int{800, 600},
+		},
+		{
+			name:           "Small transparent image stays PNG",
+			id:             "3",
+			field:          "image",
+			data:           testPNG(t, 120, 300, false),
+			previousKey:    "menu-3-old.jpg",
+			expectedStatus: http.StatusOK,
+			expectedExt:    ".png",
+			expectedSize:   [2]int{120, 300},
+		},
+		{name: "Not an image", id: "3", field: "image", data: []byte("hello"), expectedStatus: http.StatusBadRequest},
+		{name: "Wrong field", id: "3", field: "file", data: testPNG(t, 10, 10, true), expectedStatus: http.StatusBadRequest},
+		{name: "Invalid ID", id: "oat", field: "image", data: testPNG(t, 10, 10, true), expectedStatus: http.StatusBadRequest},
+		{
+			name:           "Deleted menu item",
+			id:             "3",
+			field:          "image",
+			data:           testPNG(t, 10, 10, true),
+			setImageErr:    database.ErrMenuItemNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var key string
+			mockRepo := &MockMenuRepository{
+				GetByIDFunc: func(ctx context.Context, id int) (*models.MenuItem, error) {
+					return &models.MenuItem{ID: id, Value: "oat", Label: "Oat Milk", Type: models.MilkOption, ImageKey: key}, nil
+				},
+				SetImageFunc: func(ctx context.Context, id int, newKey string) (string, error) {
+					if tc.setImageErr != nil {
+						return "", tc.setImageErr
+					}
+					key = newKey
+					return tc.previousKey, nil
+				},
+			}
+			handler, dir := newTestMenuHandler(t, mockRepo)
+			if tc.previousKey != "" {
+				os.WriteFile(filepath.Join(dir, tc.previousKey), []byte("old"), 0o644)
+			}
+
+			w := httptest.NewRecorder()
+			handler.UploadImage(w, imageRequest(t, tc.id, tc.field, tc.data))
+
+			if w.Code != tc.expectedStatus {
+				t.Fatalf("Expected status %d, got %d (%s)", tc.expectedStatus, w.Code, w.Body.String())
+			}
+			if tc.expectedStatus != http.StatusOK {
+				if files, _ := filepath.Glob(filepath.Join(dir, "menu-*")); len(files) > 0 {
+					t.Errorf("Expected no stored images, got %v", files)
+				}
+				return
+			}
+
+			if !strings.HasPrefix(key, "menu-3-") || filepath.Ext(key) != tc.expectedExt {
+				t.Fatalf("Expected a menu-3-*%s key, got %q", tc.expectedExt, key)
+			}
+			stored, err := os.Open(filepath.Join(dir, key))
+			if err != nil {
+				t.Fatalf("Expected the image to be stored: %v", err)
+			}
+			defer stored.Close()
+			cfg, _, err := image.DecodeConfig(stored)
+			if err != nil {
+				t.Fatalf("Failed to decode stored image: %v", err)
+			}
+			if [2]int{cfg.Width, cfg.Height} != tc.expectedSize {
+				t.Errorf("Expected a %v image, got %dx%d", tc.expectedSize, cfg.Width, cfg.Height)
+			}
+			if tc.previousKey != "" {
+				if _, err := os.Stat(filepath.Join(dir, tc.previousKey)); !os.IsNotExist(err) {
+					t.Error("Expected the replaced image to be deleted")
+				}
+			}
+
+			var item map[string]interface{}
+			json.NewDecoder(w.Body).Decode(&item)
+			if item["imageUrl"] != "http://localhost:8080/media/"+key {
+				t.Errorf("Expected the image URL in the response, got %v", item["imageUrl"])
+			}
+		})
+	}
+}
+
+func TestUploadMenuImageTooLarge(t *testing.T) {
+	mockRepo := &MockMenuRepository{}
+	handler, _ := newTestMenuHandler(t, mockRepo)
+
+	w := httptest.NewRecorder()
+	handler.UploadImage(w, imageRequest(t, "3", "image", make([]byte, 11<<20)))
+
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("Expected status %d, got %d (%s)", http.StatusRequestEntityTooLarge, w.Code, w.Body.String())
+	}
+	if len(mockRepo.SetImageKeys) > 0 {
+		t.Error("SetImage should not be called for a rejected upload")
+	}
+}
+
+func TestDeleteMenuImageHandler(t *testing.T) {
+	mockRepo := &MockMenuRepository{
+		SetImageFunc: func(ctx context.Context, id int, key string) (string, error) { return "menu-3-old.jpg", nil },
+	}
+	handler, dir := newTestMenuHandler(t, mockRepo)
+	os.WriteFile(filepath.Join(dir, "menu-3-old.jpg"), []byte("old"), 0o644)
+
+	req := httptest.NewRequest("DELETE", "/api/v1/menu/3/image", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "3")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+
+	handler.DeleteImage(w, req)
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("Expected status %d, got %d (%s)", http.StatusNoContent, w.Code, w.Body.String())
+	}
+	if len(mockRepo.SetImageKeys) != 1 || mockRepo.SetImageKeys[0] != "" {
+		t.Errorf("Expected the image to be cleared, got %v", mockRepo.SetImageKeys)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "menu-3-old.jpg")); !os.IsNotExist(err) {
+		t.Error("Expected the image file to be deleted")
+	}
+}
@@ -0,0 +1,235 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/auth"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/database"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// passwordResetExpiry is how long an emailed reset link stays valid
+const passwordResetExpiry = time.Hour
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"currentPassword"`
+	NewPassword     string `json:"newPassword"`
+}
+
+type ForgotPasswordRequest struct {
+	Username string `json:"username"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+// ChangePassword lets a signed-in user set a new password after confirming the
+// current one. All of the user's refresh tokens are revoked afterwards.
+func (h *AuthHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	claims, ok := auth.ExtractClaimsFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	var req ChangePasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.userRepo.GetByID(r.Context(), claims.UserID)
+	if err != nil {
+		writeUserError(w, r, err, "Failed to retrieve user")
+		return
+	}
+
+	// A stolen session must not be enough to take over the account, so wrong
+	// guesses count towards the login lockout
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.CurrentPassword)); err != nil {
+		h.recordFailedLogin(r, user)
+		http.Error(w, "Current password is incorrect", http.StatusBadRequest)
+		return
+	}
+
+	if req.NewPassword == req.CurrentPassword {
+		http.Error(w, "New password must be different from the current password", http.StatusBadRequest)
+		return
+	}
+
+	if err := auth.ValidatePasswordStrength(user.Username, req.NewPassword); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to hash password", "error", err)
+		http.Error(w, "Failed to change password", http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.userRepo.UpdatePassword(r.Context(), user.ID, string(hashedPassword)); err != nil {
+		writeUserError(w, r, err, "Failed to change password")
+		return
+	}
+
+	revoked := h.revokeSessionsAfterPasswordChange(r.Context(), user.ID)
+
+	slog.InfoContext(r.Context(), "password changed", "user_id", user.ID)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]int64{
+		"revoked": revoked,
+	})
+}
+
+// ForgotPassword emails a single-use reset link to the account's email address.
+// The response is the same whether or not the account exists, and is sent
+// before the account is looked up so its timing doesn't reveal that either.
+func (h *AuthHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var req ForgotPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if username := strings.TrimSpace(req.Username); username != "" {
+		if h.emailService == nil {
+			slog.WarnContext(r.Context(), "password reset requested, but email is not configured")
+		} else {
+			ctx := context.WithoutCancel(r.Context())
+			h.emailService.Async(func() {
+				h.sendPasswordReset(ctx, username)
+			})
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "If the account exists and has an email address, a reset link has been sent",
+	})
+}
+
+// ResetPassword redeems a reset token for a new password and signs the user
+// out of every existing session
+func (h *AuthHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var req ResetPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.Token == "" {
+		http.Error(w, "Reset token is required", http.StatusBadRequest)
+		return
+	}
+
+	stored, err := h.resetRepo.GetByTokenHash(r.Context(), auth.HashTokenHex(req.Token))
+	if err != nil || !stored.IsUsable(time.Now()) {
+		if err != nil && !errors.Is(err, database.ErrResetTokenInvalid) {
+			slog.ErrorContext(r.Context(), "password reset token lookup failed", "error", err)
+		}
+		http.Error(w, "Reset link is invalid or has expired", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.userRepo.GetByID(r.Context(), stored.UserID)
+	if err != nil {
+		http.Error(w, "Reset link is invalid or has expired", http.StatusBadRequest)
+		return
+	}
+
+	if err := auth.ValidatePasswordStrength(user.Username, req.Password); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to hash password", "error", err)
+		http.Error(w, "Failed to reset password", http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.resetRepo.Consume(r.Context(), stored.ID, string(hashedPassword)); err != nil {
+		if errors.Is(err, database.ErrResetTokenInvalid) {
+			http.Error(w, "Reset link is invalid or has expired", http.StatusBadRequest)
+			return
+		}
+		slog.ErrorContext(r.Context(), "failed to reset password", "user_id", user.ID, "error", err)
+		http.Error(w, "Failed to reset password", http.StatusInternalServerError)
+		return
+	}
+
+	h.revokeSessionsAfterPasswordChange(r.Context(), user.ID)
+
+	slog.WarnContext(r.Context(), "password reset completed", "user_id", user.ID)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]bool{
+		"success": true,
+	})
+}
+
+// sendPasswordReset creates a reset token for the user and emails the link.
+// It runs in the background; failures are only logged.
+func (h *AuthHandler) sendPasswordReset(ctx context.Context, username string) {
+	user, err := h.userRepo.GetByUsername(ctx, username)
+	if err != nil {
+		if !errors.Is(err, database.ErrUserNotFound) {
+			slog.ErrorContext(ctx, "password reset lookup failed", "error", err)
+		}
+		return
+	}
+
+	if user.Email == "" {
+		slog.InfoContext(ctx, "password reset requested, but no email address is on file", "user_id", user.ID)
+		return
+	}
+
+	token, err := auth.GeneratePasswordResetToken()
+	if err != nil {
+		slog.ErrorContext(ctx, "password reset token generation failed", "error", err)
+		return
+	}
+
+	record := &models.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: auth.HashTokenHex(token),
+		ExpiresAt: time.Now().Add(passwordResetExpiry),
+	}
+	if err := h.resetRepo.Create(ctx, record); err != nil {
+		slog.ErrorContext(ctx, "failed to store password reset token", "user_id", user.ID, "error", err)
+		return
+	}
+
+	slog.InfoContext(ctx, "password reset requested", "user_id", user.ID)
+
+	resetURL := h.passwordResetURL + "?token=" + url.QueryEscape(token)
+	h.emailService.SendPasswordReset(ctx, user.Email, user.Username, resetURL, passwordResetExpiry)
+}
+
+// revokeSessionsAfterPasswordChange signs the user out everywhere so a
+// compromised session can't outlive the old password
+func (h *AuthHandler) revokeSessionsAfterPasswordChange(ctx context.Context, userID int) int64 {
+	revoked, err := h.refreshRepo.RevokeAllForUser(ctx, userID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to revoke sessions after password change", "user_id", userID, "error", err)
+		return 0
+	}
+
+	slog.InfoContext(ctx, "sessions revoked after password change", "user_id", userID, "revoked", revoked)
+	return revoked
+}
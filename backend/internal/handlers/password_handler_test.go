@@ -0,0 +1,279 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/auth"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/config"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/database"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/handlers"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/services"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// MockPasswordResetRepository is an in-memory reset token store for testing
+type MockPasswordResetRepository struct {
+	nextID       int
+	tokens       map[int]*models.PasswordResetToken
+	passwordHash string
+}
+
+func NewMockPasswordResetRepository() *MockPasswordResetRepository {
+	return &MockPasswordResetRepository{tokens: map[int]*models.PasswordResetToken{}}
+}
+
+func (m *MockPasswordResetRepository) Create(ctx context.Context, token *models.PasswordResetToken) error {
+	now := time.Now()
+	for _, t := range m.tokens {
+		if t.UserID == token.UserID && t.UsedAt == nil {
+			t.UsedAt = &now
+		}
+	}
+	m.nextID++
+	token.ID = m.nextID
+	token.CreatedAt = now
+	m.tokens[token.ID] = token
+	return nil
+}
+
+func (m *MockPasswordResetRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*models.PasswordResetToken, error) {
+	for _, t := range m.tokens {
+		if t.TokenHash == tokenHash {
+			copied := *t
+			return &copied, nil
+		}
+	}
+	return nil, database.ErrResetTokenInvalid
+}
+
+func (m *MockPasswordResetRepository) Consume(ctx context.Context, id int, passwordHash string) error {
+	t, ok := m.tokens[id]
+	if !ok || !t.IsUsable(time.Now()) {
+		return database.ErrResetTokenInvalid
+	}
+	now := time.Now()
+	t.UsedAt = &now
+	m.passwordHash = passwordHash
+	return nil
+}
+
+// Verify interface implementation
+var _ database.PasswordResetRepositoryInterface = &MockPasswordResetRepository{}
+
+const newTestPassword = "Pour-Over-2026?"
+
+func newPasswordTestHandler(t *testing.T, user *models.User, emailService *services.EmailService) (*handlers.AuthHandler, *MockUserRepository, *MockRefreshTokenRepository, *MockPasswordResetRepository) {
+	t.Helper()
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(testPassword), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("Failed to hash password: %v", err)
+	}
+	user.Password = string(hash)
+
+	userRepo := &MockUserRepository{
+		GetByIDFunc: func(ctx context.Context, id int) (*models.User, error) {
+			if id != user.ID {
+				return nil, database.ErrUserNotFound
+			}
+			return user, nil
+		},
+		GetByUsernameFunc: func(ctx context.Context, username string) (*models.User, error) {
+			if username != user.Username {
+				return nil, database.ErrUserNotFound
+			}
+			return user, nil
+		},
+		RecordFailedLoginFunc: func(ctx context.Context, id int, lockAfter int, lockUntil time.Time) (int, bool, error) {
+			return 1, false, nil
+		},
+		ResetLoginFailuresFunc: func(ctx context.Context, id int) error {
+			return nil
+		},
+		UpdatePasswordFunc: func(ctx context.Context, id int, passwordHash string) error {
+			user.Password = passwordHash
+			return nil
+		},
+	}
+	refreshRepo := NewMockRefreshTokenRepository()
+	resetRepo := NewMockPasswordResetRepository()
+
+	h := handlers.NewAuthHandler(userRepo, refreshRepo, nil, resetRepo, newTestTokenService(t), emailService, handlers.AuthOptions{
+		PasswordResetURL: "http://localhost:5174/reset-password",
+	})
+	return h, userRepo, refreshRepo, resetRepo
+}
+
+func TestChangePassword(t *testing.T) {
+	user := &models.User{ID: 1, Username: "owner", Role: "owner"}
+	h, userRepo, refreshRepo, _ := newPasswordTestHandler(t, user, nil)
+	login(t, h)
+
+	tests := []struct {
+		name           string
+		request        handlers.ChangePasswordRequest
+		expectedStatus int
+	}{
+		{
+			name:           "Wrong current password",
+			request:        handlers.ChangePasswordRequest{CurrentPassword: "wrong", NewPassword: newTestPassword},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Same password",
+			request:        handlers.ChangePasswordRequest{CurrentPassword: testPassword, NewPassword: testPassword},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Weak password",
+			request:        handlers.ChangePasswordRequest{CurrentPassword: testPassword, NewPassword: "password"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Valid change",
+			request:        handlers.ChangePasswordRequest{CurrentPassword: testPassword, NewPassword: newTestPassword},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			payload, _ := json.Marshal(tc.request)
+			req := httptest.NewRequest("POST", "/api/v1/auth/password", bytes.NewBuffer(payload))
+			req = req.WithContext(context.WithValue(req.Context(), auth.ClaimsContextKey, &auth.Claims{UserID: 1, Role: "owner"}))
+			w := httptest.NewRecorder()
+
+			h.ChangePassword(w, req)
+
+			if w.Code != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tc.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	if !userRepo.RecordFailedLoginCalled {
+		t.Error("Expected a wrong current password to count as a failed login")
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(newTestPassword)); err != nil {
+		t.Error("Expected the new password to be stored")
+	}
+	if active, _ := refreshRepo.GetActiveForUser(context.Background(), 1); len(active) != 0 {
+		t.Errorf("Expected all sessions to be revoked, %d still active", len(active))
+	}
+}
+
+func TestForgotPassword(t *testing.T) {
+	tests := []struct {
+		name          string
+		username      string
+		email         string
+		noMail        bool
+		expectedToken bool
+	}{
+		{name: "Unknown user", username: "nobody", email: "owner@example.com", expectedToken: false},
+		{name: "User without email", username: "owner", email: "", expectedToken: false},
+		{name: "User with email", username: "owner", email: "owner@example.com", expectedToken: true},
+		{name: "Email not configured", username: "owner", email: "owner@example.com", noMail: true, expectedToken: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Nothing listens on port 1, so the reset email fails straight away
+			var emailService *services.EmailService
+			if !tc.noMail {
+				emailService = services.NewEmailService(config.MailConfig{SMTPHost: "127.0.0.1", SMTPPort: 1})
+			}
+			user := &models.User{ID: 1, Username: "owner", Email: tc.email, Role: "owner"}
+			h, _, _, resetRepo := newPasswordTestHandler(t, user, emailService)
+
+			w := doJSON(h.ForgotPassword, "/api/v1/auth/password/forgot", handlers.ForgotPasswordRequest{Username: tc.username})
+
+			// The response never reveals whether the account exists
+			if w.Code != http.StatusAccepted {
+				t.Errorf("Expected status %d, got %d", http.StatusAccepted, w.Code)
+			}
+			if emailService != nil {
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				if err := emailService.Drain(ctx); err != nil {
+					t.Fatalf("Password reset did not finish: %v", err)
+				}
+			}
+			if created := len(resetRepo.tokens) > 0; created != tc.expectedToken {
+				t.Errorf("Expected token created = %v, got %v", tc.expectedToken, created)
+			}
+		})
+	}
+}
+
+func TestResetPassword(t *testing.T) {
+	user := &models.User{ID: 1, Username: "owner", Role: "owner"}
+	h, _, refreshRepo, resetRepo := newPasswordTestHandler(t, user, nil)
+	login(t, h)
+
+	resetRepo.Create(context.Background(), &models.PasswordResetToken{
+		UserID:    1,
+		TokenHash: auth.HashTokenHex("expired-token"),
+		ExpiresAt: time.Now().Add(-time.Minute),
+	})
+	resetRepo.Create(context.Background(), &models.PasswordResetToken{
+		UserID:    1,
+		TokenHash: auth.HashTokenHex("valid-token"),
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+
+	tests := []struct {
+		name           string
+		request        handlers.ResetPasswordRequest
+		expectedStatus int
+	}{
+		{
+			name:           "Unknown token",
+			request:        handlers.ResetPasswordRequest{Token: "unknown-token", Password: newTestPassword},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Expired token",
+			request:        handlers.ResetPasswordRequest{Token: "expired-token", Password: newTestPassword},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Weak password",
+			request:        handlers.ResetPasswordRequest{Token: "valid-token", Password: "owner123"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Valid reset",
+			request:        handlers.ResetPasswordRequest{Token: "valid-token", Password: newTestPassword},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Token reuse",
+			request:        handlers.ResetPasswordRequest{Token: "valid-token", Password: newTestPassword},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := doJSON(h.ResetPassword, "/api/v1/auth/password/reset", tc.request)
+			if w.Code != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tc.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(resetRepo.passwordHash), []byte(newTestPassword)); err != nil {
+		t.Error("Expected the new password to be stored")
+	}
+	if active, _ := refreshRepo.GetActiveForUser(context.Background(), 1); len(active) != 0 {
+		t.Errorf("Expected all sessions to be revoked, %d still active", len(active))
+	}
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/services"
+)
+
+// maxReportYears limits how long a range a report may cover
+const maxReportYears = 5
+
+// ReportHandler handles HTTP requests for the booking reports
+type ReportHandler struct {
+	reports *services.ReportService
+}
+
+// NewReportHandler creates a new report handler
+func NewReportHandler(reports *services.ReportService) *ReportHandler {
+	return &ReportHandler{reports: reports}
+}
+
+// Volume returns bookings, headcount and cancellations per week or month
+func (h *ReportHandler) Volume(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	interval := query.Get("interval")
+	switch interval {
+	case "":
+		interval = models.ReportIntervalMonth
+	case models.ReportIntervalWeek, models.ReportIntervalMonth:
+	default:
+		http.Error(w, "interval must be week or month", http.StatusBadRequest)
+		return
+	}
+
+	rng, msg := reportRange(query)
+	if msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.reports.Volume(r.Context(), interval, rng)
+	h.writeReport(w, r, "volume", report, err)
+}
+
+// Revenue returns estimated revenue by package
+func (h *ReportHandler) Revenue(w http.ResponseWriter, r *http.Request) {
+	rng, msg := reportRange(r.URL.Query())
+	if msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.reports.Revenue(r.Context(), rng)
+	h.writeReport(w, r, "revenue", report, err)
+}
+
+// Menu returns the most popular coffee flavors and milk options
+func (h *ReportHandler) Menu(w http.ResponseWriter, r *http.Request) {
+	rng, msg := reportRange(r.URL.Query())
+	if msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.reports.Menu(r.Context(), rng)
+	h.writeReport(w, r, "menu", report, err)
+}
+
+// Summary returns totals, the outdoor split, lead times and the
+// cancellation rate
+func (h *ReportHandler) Summary(w http.ResponseWriter, r *http.Request) {
+	rng, msg := reportRange(r.URL.Query())
+	if msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.reports.Summary(r.Context(), rng)
+	h.writeReport(w, r, "summary", report, err)
+}
+
+// reportRange reads the from and to dates. By default a report covers the
+// last twelve months, up to the end of the current month.
+func reportRange(query url.Values) (models.ReportRange, string) {
+	today := time.Now()
+	monthStart := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+	from, to := monthStart.AddDate(0, -11, 0), monthStart.AddDate(0, 1, -1)
+
+	for _, p := range []struct {
+		name  string
+		value *time.Time
+	}{{"from", &from}, {"to", &to}} {
+		value := query.Get(p.name)
+		if value == "" {
+			continue
+		}
+		date, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return models.ReportRange{}, fmt.Sprintf("Invalid %s date format. Use YYYY-MM-DD", p.name)
+		}
+		*p.value = date
+	}
+
+	if to.Before(from) {
+		return models.ReportRange{}, "from must not be after to"
+	}
+	if to.After(from.AddDate(maxReportYears, 0, 0)) {
+		return models.ReportRange{}, fmt.Sprintf("A report may cover at most %d years", maxReportYears)
+	}
+	return models.ReportRange{From: from.Format("2006-01-02"), To: to.Format("2006-01-02")}, ""
+}
+
+// writeReport sends report, letting the browser reuse it for as long as the
+// server caches it
+func (h *ReportHandler) writeReport(w http.ResponseWriter, r *http.Request, name string, report interface{}, err error) {
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to build report", "report", name, "error", err)
+		http.Error(w, "Failed to build report", http.StatusInternalServerError)
+		return
+	}
+
+	if ttl := h.reports.CacheTTL(); ttl > 0 {
+		w.Header().Set("Cache-Control", fmt.Sprintf("private, max-age=%d", int(ttl.Seconds())))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,166 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/config"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/database"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/handlers"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/services"
+)
+
+// MockReportRepository returns canned reports and records the range asked for
+type MockReportRepository struct {
+	Err      error
+	Range    models.ReportRange
+	Interval string
+}
+
+func (m *MockReportRepository) Volume(ctx context.Context, interval string, rng models.ReportRange) (*models.VolumeReport, error) {
+	m.Interval, m.Range = interval, rng
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	return &models.VolumeReport{ReportRange: rng, Interval: interval, Periods: []models.VolumePeriod{
+		{Start: rng.From, Bookings: 4, Headcount: 120, Canceled: 1, CancellationRate: 0.25},
+	}}, nil
+}
+
+func (m *MockReportRepository) Revenue(ctx context.Context, rng models.ReportRange) (*models.RevenueReport, error) {
+	m.Range = rng
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	return &models.RevenueReport{ReportRange: rng, Total: 900, Packages: []models.PackageRevenue{
+		{Package: "Group", Bookings: 2, Headcount: 80, Revenue: 900, Priced: true},
+	}}, nil
+}
+
+func (m *MockReportRepository) Menu(ctx context.Context, rng models.ReportRange) (*models.MenuReport, error) {
+	m.Range = rng
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	return &models.MenuReport{ReportRange: rng, Bookings: 2,
+		CoffeeFlavors: []models.Popularity{{Value: "vanilla", Bookings: 2, Share: 1}},
+		MilkOptions:   []models.Popularity{},
+	}, nil
+}
+
+func (m *MockReportRepository) Summary(ctx context.Context, rng models.ReportRange) (*models.SummaryReport, error) {
+	m.Range = rng
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	return &models.SummaryReport{ReportRange: rng, Bookings: 4, Canceled: 1, CancellationRate: 0.25,
+		Headcount: 120, Outdoor: 2, OutdoorWithShade: 1, Indoor: 1,
+		LeadTime: models.LeadTime{Bookings: 3, AverageDays: 20, MedianDays: 14, P90Days: 40, MinDays: 2, MaxDays: 45},
+	}, nil
+}
+
+// Verify interface implementation
+var _ database.ReportRepositoryInterface = &MockReportRepository{}
+
+func newTestReportHandler(repo *MockReportRepository) *handlers.ReportHandler {
+	return handlers.NewReportHandler(services.NewReportService(repo, config.ReportsConfig{CacheTTL: 5 * time.Minute}))
+}
+
+func TestReportHandler(t *testing.T) {
+	tests := []struct {
+		name             string
+		report           string
+		query            string
+		repoErr          error
+		expectedStatus   int
+		expectedRange    models.ReportRange
+		expectedInterval string
+	}{
+		{
+			name:             "Volume by week",
+			report:           "volume",
+			query:            "?interval=week&from=2026-01-01&to=2026-03-31",
+			expectedStatus:   http.StatusOK,
+			expectedRange:    models.ReportRange{From: "2026-01-01", To: "2026-03-31"},
+			expectedInterval: models.ReportIntervalWeek,
+		},
+		{
+			name:             "Volume defaults to months",
+			report:           "volume",
+			query:            "?from=2026-01-01&to=2026-12-31",
+			expectedStatus:   http.StatusOK,
+			expectedRange:    models.ReportRange{From: "2026-01-01", To: "2026-12-31"},
+			expectedInterval: models.ReportIntervalMonth,
+		},
+		{name: "Unknown interval", report: "volume", query: "?interval=day", expectedStatus: http.StatusBadRequest},
+		{
+			name:           "Revenue",
+			report:         "revenue",
+			query:          "?from=2025-06-01&to=2025-06-30",
+			expectedStatus: http.StatusOK,
+			expectedRange:  models.ReportRange{From: "2025-06-01", To: "2025-06-30"},
+		},
+		{
+			name:           "Menu",
+			report:         "menu",
+			query:          "?from=2025-06-01&to=2025-06-01",
+			expectedStatus: http.StatusOK,
+			expectedRange:  models.ReportRange{From: "2025-06-01", To: "2025-06-01"},
+		},
+		{name: "Summary with the default range", report: "summary", expectedStatus: http.StatusOK},
+		{name: "Invalid date", report: "summary", query: "?from=June", expectedStatus: http.StatusBadRequest},
+		{name: "Reversed range", report: "revenue", query: "?from=2026-02-01&to=2026-01-01", expectedStatus: http.StatusBadRequest},
+		{name: "Range too long", report: "menu", query: "?from=2010-01-01&to=2026-01-01", expectedStatus: http.StatusBadRequest},
+		{name: "Database error", report: "summary", repoErr: errors.New("connection lost"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &MockReportRepository{Err: tc.repoErr}
+			handler := newTestReportHandler(repo)
+			serve := map[string]http.HandlerFunc{
+				"volume":  handler.Volume,
+				"revenue": handler.Revenue,
+				"menu":    handler.Menu,
+				"summary": handler.Summary,
+			}[tc.report]
+
+			w := httptest.NewRecorder()
+			serve(w, httptest.NewRequest("GET", "/api/v1/reports/"+tc.report+tc.query, nil))
+
+			if w.Code != tc.expectedStatus {
+				t.Fatalf("Expected status %d, got %d (%s)", tc.expectedStatus, w.Code, w.Body.String())
+			}
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+
+			if w.Header().Get("Cache-Control") != "private, max-age=300" {
+				t.Errorf("Expected the report to be cacheable, got Cache-Control %q", w.Header().Get("Cache-Control"))
+			}
+			if tc.expectedRange != (models.ReportRange{}) && repo.Range != tc.expectedRange {
+				t.Errorf("Expected range %+v, got %+v", tc.expectedRange, repo.Range)
+			}
+			if repo.Range.From == "" || repo.Range.To < repo.Range.From {
+				t.Errorf("Expected a valid range, got %+v", repo.Range)
+			}
+			if repo.Interval != tc.expectedInterval {
+				t.Errorf("Expected interval %q, got %q", tc.expectedInterval, repo.Interval)
+			}
+
+			var body map[string]interface{}
+			if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+				t.Fatalf("Failed to decode report: %v", err)
+			}
+			if body["from"] != repo.Range.From || body["to"] != repo.Range.To {
+				t.Errorf("Expected the report to echo its range, got %v to %v", body["from"], body["to"])
+			}
+		})
+	}
+}
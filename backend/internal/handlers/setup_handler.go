@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/auth"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/database"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// SetupHandler serves the one-time first-run endpoint that creates the initial admin
+type SetupHandler struct {
+	userRepo database.UserRepositoryInterface
+
+	mu        sync.Mutex
+	tokenHash []byte // nil once setup has completed or was never required
+}
+
+type SetupRequest struct {
+	SetupToken string `json:"setupToken"`
+	Username   string `json:"username"`
+	Password   string `json:"password"`
+}
+
+// NewSetupHandler creates a setup handler. An empty setupToken disables the endpoint.
+func NewSetupHandler(userRepo database.UserRepositoryInterface, setupToken string) *SetupHandler {
+	h := &SetupHandler{userRepo: userRepo}
+	if setupToken != "" {
+		h.tokenHash = auth.HashToken(setupToken)
+	}
+	return h
+}
+
+// Status reports whether the first-run setup is still pending
+func (h *SetupHandler) Status(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]bool{
+		"setupRequired": h.enabled(),
+	})
+}
+
+// CreateAdmin consumes the setup token and creates the first admin account
+func (h *SetupHandler) CreateAdmin(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.tokenHash == nil {
+		http.Error(w, "Setup has already been completed", http.StatusGone)
+		return
+	}
+
+	var req SetupRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if !auth.TokenMatchesHash(req.SetupToken, h.tokenHash) {
+		slog.WarnContext(r.Context(), "setup rejected: invalid setup token", "remote_ip", r.RemoteAddr)
+		http.Error(w, "Invalid setup token", http.StatusUnauthorized)
+		return
+	}
+
+	req.Username = strings.TrimSpace(req.Username)
+	if req.Username == "" {
+		http.Error(w, "Username is required", http.StatusBadRequest)
+		return
+	}
+
+	if err := auth.ValidatePasswordStrength(req.Username, req.Password); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to hash setup password", "error", err)
+		http.Error(w, "Failed to create admin user", http.StatusInternalServerError)
+		return
+	}
+
+	user := models.User{
+		Username: req.Username,
+		Password: string(hashedPassword),
+		Role:     "owner",
+	}
+
+	id, err := h.userRepo.CreateFirst(r.Context(), &user)
+	if err != nil {
+		if errors.Is(err, database.ErrUsersExist) {
+			// Another instance finished setup first; disable this one too
+			h.tokenHash = nil
+			http.Error(w, "Setup has already been completed", http.StatusGone)
+			return
+		}
+		slog.ErrorContext(r.Context(), "failed to create initial admin", "error", err)
+		http.Error(w, "Failed to create admin user", http.StatusInternalServerError)
+		return
+	}
+	user.ID = id
+
+	// The token is single use: disable the endpoint for the rest of the process lifetime
+	h.tokenHash = nil
+	slog.InfoContext(r.Context(), "initial admin created via setup endpoint; setup is now disabled", "username", user.Username)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(user)
+}
+
+func (h *SetupHandler) enabled() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.tokenHash != nil
+}
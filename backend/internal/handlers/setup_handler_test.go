@@ -0,0 +1,141 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/database"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/handlers"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
+)
+
+func postSetup(handler *handlers.SetupHandler, req handlers.SetupRequest) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(req)
+	r := httptest.NewRequest("POST", "/api/v1/setup", bytes.NewBuffer(body))
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler.CreateAdmin(w, r)
+	return w
+}
+
+func TestSetupCreateAdmin(t *testing.T) {
+	const setupToken = "one-time-token"
+	const strongPassword = "Cold-Brew-2025!"
+
+	tests := []struct {
+		name            string
+		setupToken      string
+		request         handlers.SetupRequest
+		createFirstFunc func(context.Context, *models.User) (int, error)
+		expectedStatus  int
+		expectCreate    bool
+	}{
+		{
+			name:       "Valid setup request",
+			setupToken: setupToken,
+			request:    handlers.SetupRequest{SetupToken: setupToken, Username: "owner", Password: strongPassword},
+			createFirstFunc: func(ctx context.Context, u *models.User) (int, error) {
+				return 1, nil
+			},
+			expectedStatus: http.StatusCreated,
+			expectCreate:   true,
+		},
+		{
+			name:           "Wrong setup token",
+			setupToken:     setupToken,
+			request:        handlers.SetupRequest{SetupToken: "guess", Username: "owner", Password: strongPassword},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Weak password",
+			setupToken:     setupToken,
+			request:        handlers.SetupRequest{SetupToken: setupToken, Username: "owner", Password: "admin"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Missing username",
+			setupToken:     setupToken,
+			request:        handlers.SetupRequest{SetupToken: setupToken, Password: strongPassword},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Setup not required",
+			setupToken:     "",
+			request:        handlers.SetupRequest{SetupToken: "", Username: "owner", Password: strongPassword},
+			expectedStatus: http.StatusGone,
+		},
+		{
+			name:       "Users created by another instance",
+			setupToken: setupToken,
+			request:    handlers.SetupRequest{SetupToken: setupToken, Username: "owner", Password: strongPassword},
+			createFirstFunc: func(ctx context.Context, u *models.User) (int, error) {
+				return 0, database.ErrUsersExist
+			},
+			expectedStatus: http.StatusGone,
+			expectCreate:   true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := &MockUserRepository{CreateFirstFunc: tc.createFirstFunc}
+			handler := handlers.NewSetupHandler(mockRepo, tc.setupToken)
+
+			w := postSetup(handler, tc.request)
+
+			if w.Code != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d (%s)", tc.expectedStatus, w.Code, w.Body.String())
+			}
+
+			if mockRepo.CreateFirstCalled != tc.expectCreate {
+				t.Errorf("Expected CreateFirst called = %v, got %v", tc.expectCreate, mockRepo.CreateFirstCalled)
+			}
+
+			if tc.expectedStatus == http.StatusCreated {
+				if mockRepo.CreateFirstUser.Password == tc.request.Password {
+					t.Error("Password was stored in plain text")
+				}
+				if mockRepo.CreateFirstUser.Role != "owner" {
+					t.Errorf("Expected role owner, got %q", mockRepo.CreateFirstUser.Role)
+				}
+			}
+		})
+	}
+}
+
+func TestSetupTokenIsSingleUse(t *testing.T) {
+	const setupToken = "one-time-token"
+
+	mockRepo := &MockUserRepository{
+		CreateFirstFunc: func(ctx context.Context, u *models.User) (int, error) {
+			return 1, nil
+		},
+	}
+	handler := handlers.NewSetupHandler(mockRepo, setupToken)
+
+	req := handlers.SetupRequest{SetupToken: setupToken, Username: "owner", Password: "Cold-Brew-2025!"}
+
+	if w := postSetup(handler, req); w.Code != http.StatusCreated {
+		t.Fatalf("Expected first setup to succeed, got %d", w.Code)
+	}
+
+	if w := postSetup(handler, req); w.Code != http.StatusGone {
+		t.Errorf("Expected second setup to be rejected with %d, got %d", http.StatusGone, w.Code)
+	}
+
+	// Status endpoint should report setup as complete
+	w := httptest.NewRecorder()
+	handler.Status(w, httptest.NewRequest("GET", "/api/v1/setup", nil))
+
+	var resp map[string]bool
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if resp["setupRequired"] {
+		t.Error("Expected setupRequired to be false after setup")
+	}
+}
@@ -0,0 +1,420 @@
+package handlers
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/auth"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/database"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
+)
+
+// TwoFactorVerifyRequest completes a login that was answered with a challenge token
+type TwoFactorVerifyRequest struct {
+	ChallengeToken string `json:"challengeToken"`
+	Code           string `json:"code"`
+	RecoveryCode   string `json:"recoveryCode"`
+}
+
+// TwoFactorEnrollRequest starts or confirms enrollment. ChallengeToken is
+// only used when enrollment is forced during login.
+type TwoFactorEnrollRequest struct {
+	ChallengeToken string `json:"challengeToken,omitempty"`
+	Code           string `json:"code,omitempty"`
+}
+
+// TwoFactorEnrollResponse carries what an authenticator app needs
+type TwoFactorEnrollResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauthUri"`
+	QRCodePNG  string `json:"qrCodePng"` // base64-encoded PNG
+}
+
+// TwoFactorCodeRequest proves possession of the second factor for sensitive changes
+type TwoFactorCodeRequest struct {
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recoveryCode"`
+}
+
+// errSecondFactorInvalid is returned when neither the code nor the recovery code is accepted
+var errSecondFactorInvalid = errors.New("invalid two-factor code")
+
+// writeChallenge answers the password step with a challenge token instead of a session
+func (h *AuthHandler) writeChallenge(w http.ResponseWriter, r *http.Request, user *models.User, purpose auth.ChallengePurpose) {
+	challenge, err := h.tokens.GenerateChallengeToken(user.ID, purpose)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "challenge token generation failed", "error", err)
+		http.Error(w, "Error generating token", http.StatusInternalServerError)
+		return
+	}
+
+	resp := LoginResponse{ChallengeToken: challenge}
+	if purpose == auth.ChallengeEnroll {
+		resp.EnrollmentRequired = true
+	} else {
+		resp.TwoFactorRequired = true
+	}
+
+	slog.InfoContext(r.Context(), "password verified, awaiting second factor", "user_id", user.ID)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// VerifyTwoFactor is the second login step: it exchanges a challenge token and
+// a TOTP or recovery code for a session
+func (h *AuthHandler) VerifyTwoFactor(w http.ResponseWriter, r *http.Request) {
+	var req TwoFactorVerifyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := h.tokens.ValidateChallengeToken(req.ChallengeToken, auth.ChallengeVerify)
+	if err != nil {
+		http.Error(w, "Invalid or expired challenge", http.StatusUnauthorized)
+		return
+	}
+
+	user, err := h.userRepo.GetByID(r.Context(), userID)
+	if err != nil || !user.TOTPEnabled {
+		http.Error(w, "Invalid or expired challenge", http.StatusUnauthorized)
+		return
+	}
+
+	// Codes are brute-forceable, so they count towards the same lockout as passwords
+	if retryAfter := h.loginRetryAfter(user, time.Now()); retryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		http.Error(w, "Too many failed login attempts, try again later", http.StatusTooManyRequests)
+		return
+	}
+
+	if err := h.checkSecondFactor(r.Context(), user, req.Code, req.RecoveryCode); err != nil {
+		if !errors.Is(err, errSecondFactorInvalid) {
+			slog.ErrorContext(r.Context(), "second factor check failed", "user_id", user.ID, "error", err)
+			http.Error(w, "Failed to verify code", http.StatusInternalServerError)
+			return
+		}
+		slog.WarnContext(r.Context(), "login failed: invalid two-factor code", "user_id", user.ID)
+		h.recordFailedLogin(r, user)
+		http.Error(w, "Invalid two-factor code", http.StatusUnauthorized)
+		return
+	}
+
+	resp, err := h.completeLogin(w, r, user)
+	if err != nil {
+		http.Error(w, "Error generating token", http.StatusInternalServerError)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "login successful", "user_id", user.ID, "role", user.Role, "two_factor", true)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// TwoFactorStatus reports whether the current user has 2FA enabled
+func (h *AuthHandler) TwoFactorStatus(w http.ResponseWriter, r *http.Request) {
+	claims, ok := auth.ExtractClaimsFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	user, err := h.userRepo.GetByID(r.Context(), claims.UserID)
+	if err != nil {
+		writeUserError(w, r, err, "Failed to retrieve user")
+		return
+	}
+
+	remaining := 0
+	if user.TOTPEnabled {
+		remaining, err = h.twoFactorRepo.CountRecoveryCodes(r.Context(), user.ID)
+		if err != nil {
+			slog.ErrorContext(r.Context(), "failed to count recovery codes", "user_id", user.ID, "error", err)
+			http.Error(w, "Failed to retrieve two-factor status", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"enabled":                user.TOTPEnabled,
+		"required":               h.requireTwoFactor,
+		"recoveryCodesRemaining": remaining,
+	})
+}
+
+// EnrollTwoFactor generates a new TOTP secret for the user and returns it as an
+// otpauth URI and QR code. 2FA is not active until ConfirmTwoFactor succeeds.
+func (h *AuthHandler) EnrollTwoFactor(w http.ResponseWriter, r *http.Request) {
+	var req TwoFactorEnrollRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	user, _, ok := h.enrollmentSubject(w, r, req.ChallengeToken)
+	if !ok {
+		return
+	}
+
+	secret, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		slog.ErrorContext(r.Context(), "TOTP secret generation failed", "error", err)
+		http.Error(w, "Failed to start enrollment", http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.twoFactorRepo.BeginEnrollment(r.Context(), user.ID, secret); err != nil {
+		writeTwoFactorError(w, r, err, "Failed to start enrollment")
+		return
+	}
+
+	uri := auth.TOTPURI(secret, user.Username)
+	png, err := auth.TOTPQRCode(uri)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "QR code generation failed", "error", err)
+		http.Error(w, "Failed to start enrollment", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(TwoFactorEnrollResponse{
+		Secret:     secret,
+		OTPAuthURI: uri,
+		QRCodePNG:  base64.StdEncoding.EncodeToString(png),
+	})
+}
+
+// ConfirmTwoFactor activates 2FA once the user proves their authenticator works
+// and returns one-time recovery codes. During forced enrollment it also
+// completes the login.
+func (h *AuthHandler) ConfirmTwoFactor(w http.ResponseWriter, r *http.Request) {
+	var req TwoFactorEnrollRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	user, viaChallenge, ok := h.enrollmentSubject(w, r, req.ChallengeToken)
+	if !ok {
+		return
+	}
+
+	if user.TOTPSecret == "" || user.TOTPEnabled {
+		writeTwoFactorError(w, r, database.ErrTwoFactorNotPending, "")
+		return
+	}
+
+	step, valid := auth.ValidateTOTP(user.TOTPSecret, req.Code, time.Now())
+	if !valid {
+		http.Error(w, "Invalid two-factor code", http.StatusBadRequest)
+		return
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		slog.ErrorContext(r.Context(), "recovery code generation failed", "error", err)
+		http.Error(w, "Failed to enable two-factor authentication", http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.twoFactorRepo.Enable(r.Context(), user.ID, step, hashes); err != nil {
+		writeTwoFactorError(w, r, err, "Failed to enable two-factor authentication")
+		return
+	}
+	user.TOTPEnabled = true
+
+	slog.InfoContext(r.Context(), "two-factor authentication enabled", "user_id", user.ID)
+
+	resp := &LoginResponse{}
+	if viaChallenge {
+		resp, err = h.completeLogin(w, r, user)
+		if err != nil {
+			http.Error(w, "Error generating token", http.StatusInternalServerError)
+			return
+		}
+	}
+	resp.RecoveryCodes = codes
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// DisableTwoFactor turns 2FA off for the current user after checking a code
+func (h *AuthHandler) DisableTwoFactor(w http.ResponseWriter, r *http.Request) {
+	if h.requireTwoFactor {
+		http.Error(w, "Two-factor authentication is required for all accounts", http.StatusForbidden)
+		return
+	}
+
+	user, ok := h.verifyCurrentUserSecondFactor(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.twoFactorRepo.Disable(r.Context(), user.ID); err != nil {
+		writeTwoFactorError(w, r, err, "Failed to disable two-factor authentication")
+		return
+	}
+
+	slog.InfoContext(r.Context(), "two-factor authentication disabled", "user_id", user.ID)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]bool{
+		"success": true,
+	})
+}
+
+// RegenerateRecoveryCodes replaces the current user's recovery codes
+func (h *AuthHandler) RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.verifyCurrentUserSecondFactor(w, r)
+	if !ok {
+		return
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		slog.ErrorContext(r.Context(), "recovery code generation failed", "error", err)
+		http.Error(w, "Failed to generate recovery codes", http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.twoFactorRepo.ReplaceRecoveryCodes(r.Context(), user.ID, hashes); err != nil {
+		writeTwoFactorError(w, r, err, "Failed to generate recovery codes")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(LoginResponse{RecoveryCodes: codes})
+}
+
+// checkSecondFactor accepts either a current TOTP code or an unused recovery code
+func (h *AuthHandler) checkSecondFactor(ctx context.Context, user *models.User, code, recoveryCode string) error {
+	if recoveryCode != "" {
+		hash := auth.HashTokenHex(auth.NormalizeRecoveryCode(recoveryCode))
+		err := h.twoFactorRepo.UseRecoveryCode(ctx, user.ID, hash)
+		if errors.Is(err, database.ErrRecoveryCodeInvalid) {
+			return errSecondFactorInvalid
+		}
+		if err == nil {
+			slog.WarnContext(ctx, "recovery code used", "user_id", user.ID)
+		}
+		return err
+	}
+
+	step, valid := auth.ValidateTOTP(user.TOTPSecret, code, time.Now())
+	if !valid {
+		return errSecondFactorInvalid
+	}
+
+	err := h.twoFactorRepo.RecordStep(ctx, user.ID, step)
+	if errors.Is(err, database.ErrTOTPCodeReused) {
+		return errSecondFactorInvalid
+	}
+	return err
+}
+
+// verifyCurrentUserSecondFactor loads the authenticated user and checks the
+// code in the request body, writing an error response on failure
+func (h *AuthHandler) verifyCurrentUserSecondFactor(w http.ResponseWriter, r *http.Request) (*models.User, bool) {
+	claims, ok := auth.ExtractClaimsFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return nil, false
+	}
+
+	var req TwoFactorCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return nil, false
+	}
+
+	user, err := h.userRepo.GetByID(r.Context(), claims.UserID)
+	if err != nil {
+		writeUserError(w, r, err, "Failed to retrieve user")
+		return nil, false
+	}
+
+	if !user.TOTPEnabled {
+		http.Error(w, "Two-factor authentication is not enabled", http.StatusConflict)
+		return nil, false
+	}
+
+	if err := h.checkSecondFactor(r.Context(), user, req.Code, req.RecoveryCode); err != nil {
+		if errors.Is(err, errSecondFactorInvalid) {
+			http.Error(w, "Invalid two-factor code", http.StatusBadRequest)
+		} else {
+			slog.ErrorContext(r.Context(), "second factor check failed", "user_id", user.ID, "error", err)
+			http.Error(w, "Failed to verify code", http.StatusInternalServerError)
+		}
+		return nil, false
+	}
+
+	return user, true
+}
+
+// enrollmentSubject resolves the user enrolling: the authenticated user, or
+// the holder of an enrollment challenge token during forced enrollment
+func (h *AuthHandler) enrollmentSubject(w http.ResponseWriter, r *http.Request, challengeToken string) (*models.User, bool, bool) {
+	var userID int
+	viaChallenge := false
+
+	if claims, ok := auth.ExtractClaimsFromContext(r.Context()); ok {
+		userID = claims.UserID
+	} else {
+		id, err := h.tokens.ValidateChallengeToken(challengeToken, auth.ChallengeEnroll)
+		if err != nil {
+			http.Error(w, "Invalid or expired challenge", http.StatusUnauthorized)
+			return nil, false, false
+		}
+		userID, viaChallenge = id, true
+	}
+
+	user, err := h.userRepo.GetByID(r.Context(), userID)
+	if err != nil {
+		if viaChallenge {
+			http.Error(w, "Invalid or expired challenge", http.StatusUnauthorized)
+		} else {
+			writeUserError(w, r, err, "Failed to retrieve user")
+		}
+		return nil, false, false
+	}
+
+	return user, viaChallenge, true
+}
+
+// newRecoveryCodes returns fresh recovery codes and their hashes for storage
+func newRecoveryCodes() ([]string, []string, error) {
+	codes, err := auth.GenerateRecoveryCodes()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	hashes := make([]string, len(codes))
+	for i, code := range codes {
+		hashes[i] = auth.HashTokenHex(auth.NormalizeRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
+
+// writeTwoFactorError maps two-factor repository errors to HTTP responses
+func writeTwoFactorError(w http.ResponseWriter, r *http.Request, err error, fallback string) {
+	switch {
+	case errors.Is(err, database.ErrTwoFactorEnabled):
+		http.Error(w, "Two-factor authentication is already enabled", http.StatusConflict)
+	case errors.Is(err, database.ErrTwoFactorNotPending):
+		http.Error(w, "Start two-factor enrollment first", http.StatusConflict)
+	default:
+		writeUserError(w, r, err, fallback)
+	}
+}
@@ -0,0 +1,240 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/auth"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/database"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/handlers"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// MockTwoFactorRepository implements the two-factor repository interface for testing
+type MockTwoFactorRepository struct {
+	lastStep      int64
+	recoveryCodes map[string]bool // hash -> used
+}
+
+func (m *MockTwoFactorRepository) BeginEnrollment(ctx context.Context, userID int, secret string) error {
+	return nil
+}
+
+func (m *MockTwoFactorRepository) Enable(ctx context.Context, userID int, step int64, codeHashes []string) error {
+	m.lastStep = step
+	return m.ReplaceRecoveryCodes(ctx, userID, codeHashes)
+}
+
+func (m *MockTwoFactorRepository) Disable(ctx context.Context, userID int) error {
+	return nil
+}
+
+func (m *MockTwoFactorRepository) RecordStep(ctx context.Context, userID int, step int64) error {
+	if step <= m.lastStep {
+		return database.ErrTOTPCodeReused
+	}
+	m.lastStep = step
+	return nil
+}
+
+func (m *MockTwoFactorRepository) UseRecoveryCode(ctx context.Context, userID int, codeHash string) error {
+	used, ok := m.recoveryCodes[codeHash]
+	if !ok || used {
+		return database.ErrRecoveryCodeInvalid
+	}
+	m.recoveryCodes[codeHash] = true
+	return nil
+}
+
+func (m *MockTwoFactorRepository) ReplaceRecoveryCodes(ctx context.Context, userID int, codeHashes []string) error {
+	m.recoveryCodes = map[string]bool{}
+	for _, hash := range codeHashes {
+		m.recoveryCodes[hash] = false
+	}
+	return nil
+}
+
+func (m *MockTwoFactorRepository) CountRecoveryCodes(ctx context.Context, userID int) (int, error) {
+	count := 0
+	for _, used := range m.recoveryCodes {
+		if !used {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// Verify interface implementation
+var _ database.TwoFactorRepositoryInterface = &MockTwoFactorRepository{}
+
+const testTOTPSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func newTwoFactorTestHandler(t *testing.T, user *models.User, opts handlers.AuthOptions) (*handlers.AuthHandler, *MockUserRepository, *MockTwoFactorRepository) {
+	t.Helper()
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(testPassword), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("Failed to hash password: %v", err)
+	}
+	user.Password = string(hash)
+
+	userRepo := &MockUserRepository{
+		GetByIDFunc: func(ctx context.Context, id int) (*models.User, error) {
+			return user, nil
+		},
+		GetByUsernameFunc: func(ctx context.Context, username string) (*models.User, error) {
+			return user, nil
+		},
+		RecordFailedLoginFunc: func(ctx context.Context, id int, lockAfter int, lockUntil time.Time) (int, bool, error) {
+			return 1, false, nil
+		},
+		ResetLoginFailuresFunc: func(ctx context.Context, id int) error {
+			return nil
+		},
+	}
+	twoFactorRepo := &MockTwoFactorRepository{recoveryCodes: map[string]bool{}}
+
+	return handlers.NewAuthHandler(userRepo, NewMockRefreshTokenRepository(), twoFactorRepo, nil, newTestTokenService(t), nil, opts), userRepo, twoFactorRepo
+}
+
+func TestTwoFactorLogin(t *testing.T) {
+	user := &models.User{ID: 1, Username: "owner", Role: "owner", TOTPEnabled: true, TOTPSecret: testTOTPSecret}
+	h, userRepo, twoFactorRepo := newTwoFactorTestHandler(t, user, handlers.AuthOptions{})
+
+	loginResp := login(t, h)
+	if loginResp.Token != "" || loginResp.RefreshToken != "" {
+		t.Fatal("No session may be issued before the second factor is verified")
+	}
+	if !loginResp.TwoFactorRequired || loginResp.ChallengeToken == "" {
+		t.Fatalf("Expected a two-factor challenge, got %+v", loginResp)
+	}
+
+	// Wrong code counts as a failed login
+	w := doJSON(h.VerifyTwoFactor, "/api/v1/auth/2fa/verify", handlers.TwoFactorVerifyRequest{
+		ChallengeToken: loginResp.ChallengeToken,
+		Code:           "000000",
+	})
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected wrong code to be rejected, got %d", w.Code)
+	}
+	if !userRepo.RecordFailedLoginCalled {
+		t.Error("Expected a wrong code to be recorded as a failed login")
+	}
+
+	code, _ := auth.TOTPCode(testTOTPSecret, auth.TOTPStep(time.Now()))
+	verify := handlers.TwoFactorVerifyRequest{ChallengeToken: loginResp.ChallengeToken, Code: code}
+
+	w = doJSON(h.VerifyTwoFactor, "/api/v1/auth/2fa/verify", verify)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected valid code to complete login, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp handlers.LoginResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if resp.Token == "" || resp.RefreshToken == "" {
+		t.Error("Expected a session after verifying the second factor")
+	}
+
+	// The same code can't be replayed
+	w = doJSON(h.VerifyTwoFactor, "/api/v1/auth/2fa/verify", verify)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected replayed code to be rejected, got %d", w.Code)
+	}
+
+	// Recovery codes are single use
+	twoFactorRepo.ReplaceRecoveryCodes(context.Background(), 1, []string{auth.HashTokenHex("abcdefghij")})
+	recovery := handlers.TwoFactorVerifyRequest{ChallengeToken: loginResp.ChallengeToken, RecoveryCode: "ABCDE-FGHIJ"}
+	if w := doJSON(h.VerifyTwoFactor, "/api/v1/auth/2fa/verify", recovery); w.Code != http.StatusOK {
+		t.Errorf("Expected recovery code to complete login, got %d", w.Code)
+	}
+	if w := doJSON(h.VerifyTwoFactor, "/api/v1/auth/2fa/verify", recovery); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected used recovery code to be rejected, got %d", w.Code)
+	}
+}
+
+func TestForcedTwoFactorEnrollment(t *testing.T) {
+	user := &models.User{ID: 1, Username: "owner", Role: "owner"}
+	h, _, _ := newTwoFactorTestHandler(t, user, handlers.AuthOptions{RequireTwoFactor: true})
+
+	loginResp := login(t, h)
+	if !loginResp.EnrollmentRequired || loginResp.ChallengeToken == "" || loginResp.Token != "" {
+		t.Fatalf("Expected an enrollment challenge, got %+v", loginResp)
+	}
+
+	// A verify challenge is not accepted for enrollment and vice versa
+	w := doJSON(h.VerifyTwoFactor, "/api/v1/auth/2fa/verify", handlers.TwoFactorVerifyRequest{
+		ChallengeToken: loginResp.ChallengeToken,
+		Code:           "000000",
+	})
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected enrollment challenge to be rejected by verify, got %d", w.Code)
+	}
+
+	w = doJSON(h.EnrollTwoFactor, "/api/v1/auth/2fa/setup", handlers.TwoFactorEnrollRequest{ChallengeToken: loginResp.ChallengeToken})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected enrollment to start, got %d: %s", w.Code, w.Body.String())
+	}
+	var enroll handlers.TwoFactorEnrollResponse
+	json.Unmarshal(w.Body.Bytes(), &enroll)
+	if enroll.Secret == "" || enroll.OTPAuthURI == "" || enroll.QRCodePNG == "" {
+		t.Fatalf("Incomplete enrollment response: %+v", enroll)
+	}
+
+	// The mock repository doesn't persist, so mirror the pending secret
+	user.TOTPSecret = enroll.Secret
+	code, _ := auth.TOTPCode(enroll.Secret, auth.TOTPStep(time.Now()))
+
+	w = doJSON(h.ConfirmTwoFactor, "/api/v1/auth/2fa/setup/confirm", handlers.TwoFactorEnrollRequest{
+		ChallengeToken: loginResp.ChallengeToken,
+		Code:           code,
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected enrollment to be confirmed, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp handlers.LoginResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if len(resp.RecoveryCodes) != auth.RecoveryCodeCount {
+		t.Errorf("Expected %d recovery codes, got %d", auth.RecoveryCodeCount, len(resp.RecoveryCodes))
+	}
+	if resp.Token == "" {
+		t.Error("Expected forced enrollment to complete the login")
+	}
+}
+
+func TestRefreshRequiresTwoFactorEnrollment(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(testPassword), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("Failed to hash password: %v", err)
+	}
+	user := &models.User{ID: 1, Username: "owner", Password: string(hash), Role: "owner"}
+	userRepo := &MockUserRepository{
+		GetByIDFunc: func(ctx context.Context, id int) (*models.User, error) {
+			return user, nil
+		},
+		GetByUsernameFunc: func(ctx context.Context, username string) (*models.User, error) {
+			return user, nil
+		},
+	}
+	refreshRepo := NewMockRefreshTokenRepository()
+	tokens := newTestTokenService(t)
+
+	// The session starts while 2FA is still optional
+	optional := handlers.NewAuthHandler(userRepo, refreshRepo, nil, nil, tokens, nil, handlers.AuthOptions{})
+	loginResp := login(t, optional)
+
+	required := handlers.NewAuthHandler(userRepo, refreshRepo, &MockTwoFactorRepository{}, nil, tokens, nil,
+		handlers.AuthOptions{RequireTwoFactor: true})
+
+	w, resp := refresh(required, loginResp.RefreshToken)
+	if w.Code != http.StatusForbidden || resp.AccessToken != "" {
+		t.Fatalf("Expected refresh without 2FA to be refused, got %d: %s", w.Code, w.Body.String())
+	}
+
+	user.TOTPEnabled = true
+	if w, _ := refresh(required, loginResp.RefreshToken); w.Code != http.StatusOK {
+		t.Errorf("Expected refresh with 2FA enabled to succeed, got %d: %s", w.Code, w.Body.String())
+	}
+}
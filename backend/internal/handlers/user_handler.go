@@ -0,0 +1,334 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/auth"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/database"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// UserHandler handles HTTP requests for managing admin users
+type UserHandler struct {
+	repo          database.UserRepositoryInterface
+	refreshRepo   database.RefreshTokenRepositoryInterface
+	twoFactorRepo database.TwoFactorRepositoryInterface
+}
+
+// NewUserHandler creates a new user handler
+func NewUserHandler(repo database.UserRepositoryInterface, refreshRepo database.RefreshTokenRepositoryInterface,
+	twoFactorRepo database.TwoFactorRepositoryInterface) *UserHandler {
+	return &UserHandler{
+		repo:          repo,
+		refreshRepo:   refreshRepo,
+		twoFactorRepo: twoFactorRepo,
+	}
+}
+
+// GetAll returns all admin users
+func (h *UserHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	users, err := h.repo.GetAll(r.Context())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to retrieve users", "error", err)
+		http.Error(w, "Failed to retrieve users", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(users)
+}
+
+// GetByID returns a single admin user
+func (h *UserHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.repo.GetByID(r.Context(), id)
+	if err != nil {
+		writeUserError(w, r, err, "Failed to retrieve user")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
+
+// Roles returns the roles and the permissions each one grants
+func (h *UserHandler) Roles(w http.ResponseWriter, r *http.Request) {
+	roles := map[auth.Role][]auth.Permission{}
+	for _, role := range auth.Roles() {
+		roles[role] = auth.PermissionsForRole(string(role))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(roles)
+}
+
+// Create adds a new admin user
+func (h *UserHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var input models.UserInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	input.Username = strings.TrimSpace(input.Username)
+	if msg := validateUserInput(&input); msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	if err := auth.ValidatePasswordStrength(input.Username, input.Password); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to hash password", "error", err)
+		http.Error(w, "Failed to create user", http.StatusInternalServerError)
+		return
+	}
+
+	user := models.User{
+		Username: input.Username,
+		Email:    input.Email,
+		Password: string(hashedPassword),
+		Role:     input.Role,
+	}
+
+	id, err := h.repo.Create(r.Context(), &user)
+	if err != nil {
+		writeUserError(w, r, err, "Failed to create user")
+		return
+	}
+
+	created, err := h.repo.GetByID(r.Context(), id)
+	if err != nil {
+		writeUserError(w, r, err, "Failed to retrieve user")
+		return
+	}
+
+	slog.InfoContext(r.Context(), "user created", "user_id", id, "role", user.Role)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+// Update modifies an admin user's username, email and role
+func (h *UserHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	var input models.UserInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	input.Username = strings.TrimSpace(input.Username)
+	if msg := validateUserInput(&input); msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	existing, err := h.repo.GetByID(r.Context(), id)
+	if err != nil {
+		writeUserError(w, r, err, "Failed to retrieve user")
+		return
+	}
+
+	user := models.User{
+		Username: input.Username,
+		Email:    input.Email,
+		Role:     input.Role,
+	}
+
+	if err := h.repo.Update(r.Context(), id, &user); err != nil {
+		writeUserError(w, r, err, "Failed to update user")
+		return
+	}
+
+	// Access tokens pick up the new role on their next request; refresh
+	// tokens are revoked so the user signs in again under it
+	if existing.Role != input.Role {
+		revoked, err := h.refreshRepo.RevokeAllForUser(r.Context(), id)
+		if err != nil {
+			slog.ErrorContext(r.Context(), "failed to revoke sessions", "user_id", id, "error", err)
+			http.Error(w, "Failed to revoke sessions", http.StatusInternalServerError)
+			return
+		}
+		slog.InfoContext(r.Context(), "user role changed", "user_id", id,
+			"from", existing.Role, "to", input.Role, "revoked", revoked)
+	}
+
+	updated, err := h.repo.GetByID(r.Context(), id)
+	if err != nil {
+		writeUserError(w, r, err, "Failed to retrieve user")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+// Delete removes an admin user
+func (h *UserHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	if claims, ok := auth.ExtractClaimsFromContext(r.Context()); ok && claims.UserID == id {
+		http.Error(w, "You cannot delete your own account", http.StatusBadRequest)
+		return
+	}
+
+	// Refresh tokens go with the user; access tokens already issued are
+	// refused by JWTAuth once the user no longer exists
+	if err := h.repo.Delete(r.Context(), id); err != nil {
+		writeUserError(w, r, err, "Failed to delete user")
+		return
+	}
+
+	slog.InfoContext(r.Context(), "user deleted", "user_id", id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetSessions lists a user's active refresh-token sessions
+func (h *UserHandler) GetSessions(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	sessions, err := h.refreshRepo.GetActiveForUser(r.Context(), id)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to retrieve sessions", "user_id", id, "error", err)
+		http.Error(w, "Failed to retrieve sessions", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sessions)
+}
+
+// RevokeSessions signs a user out of all sessions by revoking their refresh tokens
+func (h *UserHandler) RevokeSessions(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := h.repo.GetByID(r.Context(), id); err != nil {
+		writeUserError(w, r, err, "Failed to retrieve user")
+		return
+	}
+
+	revoked, err := h.refreshRepo.RevokeAllForUser(r.Context(), id)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to revoke sessions", "user_id", id, "error", err)
+		http.Error(w, "Failed to revoke sessions", http.StatusInternalServerError)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "sessions revoked", "user_id", id, "revoked", revoked)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]int64{
+		"revoked": revoked,
+	})
+}
+
+// Unlock clears a user's failed login attempts and lifts any temporary lockout
+func (h *UserHandler) Unlock(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.repo.ResetLoginFailures(r.Context(), id); err != nil {
+		writeUserError(w, r, err, "Failed to unlock user")
+		return
+	}
+
+	user, err := h.repo.GetByID(r.Context(), id)
+	if err != nil {
+		writeUserError(w, r, err, "Failed to retrieve user")
+		return
+	}
+
+	slog.InfoContext(r.Context(), "user unlocked", "user_id", id)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
+
+// ResetTwoFactor removes a user's 2FA enrollment, e.g. after a lost device.
+// Their sessions are revoked so the next login goes through enrollment again.
+func (h *UserHandler) ResetTwoFactor(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.twoFactorRepo.Disable(r.Context(), id); err != nil {
+		writeUserError(w, r, err, "Failed to reset two-factor authentication")
+		return
+	}
+
+	if _, err := h.refreshRepo.RevokeAllForUser(r.Context(), id); err != nil {
+		slog.ErrorContext(r.Context(), "failed to revoke sessions", "user_id", id, "error", err)
+	}
+
+	slog.InfoContext(r.Context(), "two-factor authentication reset", "user_id", id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// validateUserInput returns a client-facing message if the input is invalid
+func validateUserInput(input *models.UserInput) string {
+	if input.Username == "" {
+		return "Username is required"
+	}
+	if !auth.IsValidRole(input.Role) {
+		return "Role must be one of: owner, manager, barista, bookkeeper"
+	}
+	if input.Email != "" && !strings.Contains(input.Email, "@") {
+		return "Invalid email address"
+	}
+	return ""
+}
+
+// writeUserError maps repository errors to HTTP responses
+func writeUserError(w http.ResponseWriter, r *http.Request, err error, fallback string) {
+	switch {
+	case errors.Is(err, database.ErrUserNotFound):
+		http.Error(w, "User not found", http.StatusNotFound)
+	case errors.Is(err, database.ErrUsernameTaken):
+		http.Error(w, "Username already taken", http.StatusConflict)
+	case errors.Is(err, database.ErrLastOwner):
+		http.Error(w, "At least one owner account is required", http.StatusConflict)
+	default:
+		slog.ErrorContext(r.Context(), "user handler failed", "error", err)
+		http.Error(w, fallback, http.StatusInternalServerError)
+	}
+}
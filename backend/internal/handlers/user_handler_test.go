@@ -0,0 +1,285 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/auth"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/database"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/handlers"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
+)
+
+// MockUserRepository implements the user repository interface for testing
+type MockUserRepository struct {
+	// GetByID
+	GetByIDFunc func(context.Context, int) (*models.User, error)
+
+	// GetByUsername
+	GetByUsernameFunc func(context.Context, string) (*models.User, error)
+
+	// GetAll
+	GetAllFunc func(context.Context) ([]*models.User, error)
+
+	// Count
+	CountFunc func(context.Context) (int, error)
+
+	// Create
+	CreateFunc   func(context.Context, *models.User) (int, error)
+	CreateCalled bool
+	CreateUser   *models.User
+
+	// CreateFirst
+	CreateFirstFunc   func(context.Context, *models.User) (int, error)
+	CreateFirstCalled bool
+	CreateFirstUser   *models.User
+
+	// Update
+	UpdateFunc   func(context.Context, int, *models.User) error
+	UpdateCalled bool
+	UpdateID     int
+	UpdateUser   *models.User
+
+	// Delete
+	DeleteFunc   func(context.Context, int) error
+	DeleteCalled bool
+	DeleteArg    int
+
+	// RecordFailedLogin
+	RecordFailedLoginFunc   func(context.Context, int, int, time.Time) (int, bool, error)
+	RecordFailedLoginCalled bool
+
+	// ResetLoginFailures
+	ResetLoginFailuresFunc   func(context.Context, int) error
+	ResetLoginFailuresCalled bool
+
+	// UpdatePassword
+	UpdatePasswordFunc   func(context.Context, int, string) error
+	UpdatePasswordCalled bool
+}
+
+func (m *MockUserRepository) GetByID(ctx context.Context, id int) (*models.User, error) {
+	return m.GetByIDFunc(ctx, id)
+}
+
+func (m *MockUserRepository) GetByUsername(ctx context.Context, username string) (*models.User, error) {
+	return m.GetByUsernameFunc(ctx, username)
+}
+
+func (m *MockUserRepository) GetAll(ctx context.Context) ([]*models.User, error) {
+	return m.GetAllFunc(ctx)
+}
+
+func (m *MockUserRepository) Count(ctx context.Context) (int, error) {
+	return m.CountFunc(ctx)
+}
+
+func (m *MockUserRepository) Create(ctx context.Context, user *models.User) (int, error) {
+	m.CreateCalled = true
+	m.CreateUser = user
+	return m.CreateFunc(ctx, user)
+}
+
+func (m *MockUserRepository) CreateFirst(ctx context.Context, user *models.User) (int, error) {
+	m.CreateFirstCalled = true
+	m.CreateFirstUser = user
+	return m.CreateFirstFunc(ctx, user)
+}
+
+func (m *MockUserRepository) Update(ctx context.Context, id int, user *models.User) error {
+	m.UpdateCalled = true
+	m.UpdateID = id
+	m.UpdateUser = user
+	return m.UpdateFunc(ctx, id, user)
+}
+
+func (m *MockUserRepository) Delete(ctx context.Context, id int) error {
+	m.DeleteCalled = true
+	m.DeleteArg = id
+	return m.DeleteFunc(ctx, id)
+}
+
+func (m *MockUserRepository) RecordFailedLogin(ctx context.Context, id int, lockAfter int, lockUntil time.Time) (int, bool, error) {
+	m.RecordFailedLoginCalled = true
+	return m.RecordFailedLoginFunc(ctx, id, lockAfter, lockUntil)
+}
+
+func (m *MockUserRepository) ResetLoginFailures(ctx context.Context, id int) error {
+	m.ResetLoginFailuresCalled = true
+	return m.ResetLoginFailuresFunc(ctx, id)
+}
+
+func (m *MockUserRepository) UpdatePassword(ctx context.Context, id int, passwordHash string) error {
+	m.UpdatePasswordCalled = true
+	return m.UpdatePasswordFunc(ctx, id, passwordHash)
+}
+
+// Verify interface implementation
+var _ database.UserRepositoryInterface = &MockUserRepository{}
+
+func TestCreateUserHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          models.UserInput
+		createFunc     func(context.Context, *models.User) (int, error)
+		expectedStatus int
+	}{
+		{
+			name:  "Valid barista",
+			input: models.UserInput{Username: "sam", Email: "sam@example.com", Password: "Cold-Brew-2025!", Role: "barista"},
+			createFunc: func(ctx context.Context, u *models.User) (int, error) {
+				return 7, nil
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "Unknown role",
+			input:          models.UserInput{Username: "sam", Password: "Cold-Brew-2025!", Role: "admin"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Weak password",
+			input:          models.UserInput{Username: "sam", Password: "password", Role: "barista"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "Duplicate username",
+			input: models.UserInput{Username: "sam", Password: "Cold-Brew-2025!", Role: "manager"},
+			createFunc: func(ctx context.Context, u *models.User) (int, error) {
+				return 0, database.ErrUsernameTaken
+			},
+			expectedStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := &MockUserRepository{
+				CreateFunc: tc.createFunc,
+				GetByIDFunc: func(ctx context.Context, id int) (*models.User, error) {
+					return &models.User{ID: id, Username: tc.input.Username, Role: tc.input.Role}, nil
+				},
+			}
+			handler := handlers.NewUserHandler(mockRepo, nil, nil)
+
+			body, _ := json.Marshal(tc.input)
+			req := httptest.NewRequest("POST", "/api/v1/users", bytes.NewBuffer(body))
+			w := httptest.NewRecorder()
+
+			handler.Create(w, req)
+
+			if w.Code != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d (%s)", tc.expectedStatus, w.Code, w.Body.String())
+			}
+
+			if tc.expectedStatus == http.StatusCreated {
+				if mockRepo.CreateUser.Password == tc.input.Password {
+					t.Error("Password was stored in plain text")
+				}
+				if bytes.Contains(w.Body.Bytes(), []byte(mockRepo.CreateUser.Password)) {
+					t.Error("Password hash leaked in response")
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateUserHandlerLastOwner(t *testing.T) {
+	mockRepo := &MockUserRepository{
+		GetByIDFunc: func(ctx context.Context, id int) (*models.User, error) {
+			return &models.User{ID: id, Username: "owner", Role: "owner"}, nil
+		},
+		UpdateFunc: func(ctx context.Context, id int, u *models.User) error {
+			return database.ErrLastOwner
+		},
+	}
+	handler := handlers.NewUserHandler(mockRepo, nil, nil)
+
+	body, _ := json.Marshal(models.UserInput{Username: "owner", Role: "manager"})
+	req := httptest.NewRequest("PUT", "/api/v1/users/1", bytes.NewBuffer(body))
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "1")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+
+	handler.Update(w, req)
+
+	if w.Code != http.StatusConflict {
+		t.Errorf("Expected status %d, got %d", http.StatusConflict, w.Code)
+	}
+}
+
+func TestUpdateUserHandlerRoleChange(t *testing.T) {
+	tests := []struct {
+		name            string
+		role            string
+		expectedRevoked bool
+	}{
+		{name: "Same role keeps sessions", role: "manager", expectedRevoked: false},
+		{name: "New role revokes sessions", role: "barista", expectedRevoked: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			user := &models.User{ID: 4, Username: "sam", Role: "manager"}
+			mockRepo := &MockUserRepository{
+				GetByIDFunc: func(ctx context.Context, id int) (*models.User, error) {
+					copied := *user
+					return &copied, nil
+				},
+				UpdateFunc: func(ctx context.Context, id int, u *models.User) error {
+					user.Role = u.Role
+					return nil
+				},
+			}
+			refreshRepo := NewMockRefreshTokenRepository()
+			refreshRepo.Create(context.Background(), &models.RefreshToken{UserID: 4, ExpiresAt: time.Now().Add(time.Hour)})
+			handler := handlers.NewUserHandler(mockRepo, refreshRepo, nil)
+
+			body, _ := json.Marshal(models.UserInput{Username: "sam", Role: tc.role})
+			req := httptest.NewRequest("PUT", "/api/v1/users/4", bytes.NewBuffer(body))
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", "4")
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+			w := httptest.NewRecorder()
+
+			handler.Update(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status %d, got %d (%s)", http.StatusOK, w.Code, w.Body.String())
+			}
+			active, _ := refreshRepo.GetActiveForUser(context.Background(), 4)
+			if revoked := len(active) == 0; revoked != tc.expectedRevoked {
+				t.Errorf("Expected sessions revoked = %v, got %v", tc.expectedRevoked, revoked)
+			}
+		})
+	}
+}
+
+func TestDeleteUserHandlerSelf(t *testing.T) {
+	mockRepo := &MockUserRepository{}
+	handler := handlers.NewUserHandler(mockRepo, nil, nil)
+
+	req := httptest.NewRequest("DELETE", "/api/v1/users/3", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "3")
+	ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+	ctx = context.WithValue(ctx, auth.ClaimsContextKey, &auth.Claims{UserID: 3, Role: "owner"})
+	req = req.WithContext(ctx)
+	w := httptest.NewRecorder()
+
+	handler.Delete(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	if mockRepo.DeleteCalled {
+		t.Error("Delete should not be called when removing your own account")
+	}
+}
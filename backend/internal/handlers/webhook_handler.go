@@ -0,0 +1,283 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/database"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/services"
+)
+
+// Number of delivery attempts listed by default, and at most
+const (
+	defaultDeliveryLimit = 50
+	maxDeliveryLimit     = 200
+)
+
+// WebhookHandler handles HTTP requests for managing webhook subscriptions
+type WebhookHandler struct {
+	repo     database.WebhookRepositoryInterface
+	webhooks *services.WebhookService
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler(repo database.WebhookRepositoryInterface, webhooks *services.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		repo:     repo,
+		webhooks: webhooks,
+	}
+}
+
+// WebhookWithSecret is returned when a webhook is created, the only time its
+// signing secret is shown
+type WebhookWithSecret struct {
+	*models.Webhook
+	Secret string `json:"secret"`
+}
+
+// GetAll returns all webhooks
+func (h *WebhookHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	hooks, err := h.repo.GetAll(r.Context())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to list webhooks", "error", err)
+		http.Error(w, "Failed to retrieve webhooks", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(hooks)
+}
+
+// GetByID returns a single webhook
+func (h *WebhookHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid webhook ID", http.StatusBadRequest)
+		return
+	}
+
+	hook, err := h.repo.GetByID(r.Context(), id)
+	if err != nil {
+		writeWebhookError(w, r, err, "Failed to retrieve webhook")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(hook)
+}
+
+// Create adds a webhook, generating its signing secret unless one is given
+func (h *WebhookHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var input models.WebhookInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if msg := validateWebhookInput(&input); msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+	if err := h.webhooks.CheckURL(r.Context(), input.URL); err != nil {
+		http.Error(w, "URL must not point at a loopback, private or link-local address", http.StatusBadRequest)
+		return
+	}
+
+	if input.Secret == "" {
+		secret, err := services.GenerateWebhookSecret()
+		if err != nil {
+			slog.ErrorContext(r.Context(), "failed to generate webhook secret", "error", err)
+			http.Error(w, "Failed to create webhook", http.StatusInternalServerError)
+			return
+		}
+		input.Secret = secret
+	}
+
+	hook := &models.Webhook{
+		URL:         input.URL,
+		Secret:      input.Secret,
+		Events:      input.Events,
+		Description: input.Description,
+		Active:      input.Active,
+		IncludePII:  input.IncludePII,
+	}
+	if err := h.repo.Create(r.Context(), hook); err != nil {
+		writeWebhookError(w, r, err, "Failed to create webhook")
+		return
+	}
+
+	slog.InfoContext(r.Context(), "webhook created", "webhook_id", hook.ID, "events", hook.Events)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(WebhookWithSecret{Webhook: hook, Secret: hook.Secret})
+}
+
+// Update replaces a webhook's settings, keeping its secret unless a new one is given
+func (h *WebhookHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid webhook ID", http.StatusBadRequest)
+		return
+	}
+
+	var input models.WebhookInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if msg := validateWebhookInput(&input); msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+	if err := h.webhooks.CheckURL(r.Context(), input.URL); err != nil {
+		http.Error(w, "URL must not point at a loopback, private or link-local address", http.StatusBadRequest)
+		return
+	}
+
+	hook, err := h.repo.GetByID(r.Context(), id)
+	if err != nil {
+		writeWebhookError(w, r, err, "Failed to retrieve webhook")
+		return
+	}
+
+	hook.URL = input.URL
+	hook.Events = input.Events
+	hook.Description = input.Description
+	hook.Active = input.Active
+	hook.IncludePII = input.IncludePII
+	if input.Secret != "" {
+		hook.Secret = input.Secret
+	}
+
+	if err := h.repo.Update(r.Context(), id, hook); err != nil {
+		writeWebhookError(w, r, err, "Failed to update webhook")
+		return
+	}
+
+	updated, err := h.repo.GetByID(r.Context(), id)
+	if err != nil {
+		writeWebhookError(w, r, err, "Failed to retrieve webhook")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+// Delete removes a webhook and its delivery log
+func (h *WebhookHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid webhook ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.repo.Delete(r.Context(), id); err != nil {
+		writeWebhookError(w, r, err, "Failed to delete webhook")
+		return
+	}
+
+	slog.InfoContext(r.Context(), "webhook deleted", "webhook_id", id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Test sends a webhook.test event to the webhook right away and returns the
+// logged attempt. A delivery the receiver rejects is still a 200 response.
+func (h *WebhookHandler) Test(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid webhook ID", http.StatusBadRequest)
+		return
+	}
+
+	hook, err := h.repo.GetByID(r.Context(), id)
+	if err != nil {
+		writeWebhookError(w, r, err, "Failed to retrieve webhook")
+		return
+	}
+
+	delivery, err := h.webhooks.Test(r.Context(), hook)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to send test webhook", "webhook_id", id, "error", err)
+		http.Error(w, "Failed to send test event", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(delivery)
+}
+
+// GetDeliveries returns a webhook's recent delivery attempts, newest first
+func (h *WebhookHandler) GetDeliveries(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid webhook ID", http.StatusBadRequest)
+		return
+	}
+
+	limit := defaultDeliveryLimit
+	if s := r.URL.Query().Get("limit"); s != "" {
+		limit, err = strconv.Atoi(s)
+		if err != nil || limit < 1 || limit > maxDeliveryLimit {
+			http.Error(w, "limit must be between 1 and 200", http.StatusBadRequest)
+			return
+		}
+	}
+
+	if _, err := h.repo.GetByID(r.Context(), id); err != nil {
+		writeWebhookError(w, r, err, "Failed to retrieve webhook")
+		return
+	}
+
+	deliveries, err := h.repo.GetDeliveries(r.Context(), id, limit)
+	if err != nil {
+		writeWebhookError(w, r, err, "Failed to retrieve deliveries")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(deliveries)
+}
+
+// validateWebhookInput returns a message describing the first problem with input
+func validateWebhookInput(input *models.WebhookInput) string {
+	input.URL = strings.TrimSpace(input.URL)
+	u, err := url.Parse(input.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "URL must be an absolute http or https URL"
+	}
+	if len(input.Events) == 0 {
+		return "At least one event is required"
+	}
+	for _, event := range input.Events {
+		if !models.IsValidWebhookEvent(event) {
+			return "Unknown event " + strconv.Quote(event) + ", must be one of: * " + strings.Join(models.WebhookEvents(), " ")
+		}
+	}
+	if input.Secret != "" && (len(input.Secret) < 16 || len(input.Secret) > 255) {
+		return "Secret must be between 16 and 255 characters"
+	}
+	if len(input.Description) > 255 {
+		return "Description must be at most 255 characters"
+	}
+	return ""
+}
+
+// writeWebhookError maps repository errors to HTTP responses
+func writeWebhookError(w http.ResponseWriter, r *http.Request, err error, fallback string) {
+	if errors.Is(err, database.ErrWebhookNotFound) {
+		http.Error(w, "Webhook not found", http.StatusNotFound)
+		return
+	}
+	slog.ErrorContext(r.Context(), "webhook handler error", "error", err)
+	http.Error(w, fallback, http.StatusInternalServerError)
+}
@@ -0,0 +1,417 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/config"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/database"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/handlers"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/services"
+)
+
+// MockWebhookRepository implements the webhook repository interface for testing
+type MockWebhookRepository struct {
+	// GetAll
+	GetAllFunc func(context.Context) ([]*models.Webhook, error)
+
+	// GetActiveForEvent
+	GetActiveForEventFunc func(context.Context, string) ([]*models.Webhook, error)
+
+	// GetByID
+	GetByIDFunc func(context.Context, int) (*models.Webhook, error)
+
+	// Create
+	CreateFunc    func(context.Context, *models.Webhook) error
+	CreateCalled  bool
+	CreateWebhook *models.Webhook
+
+	// Update
+	UpdateFunc    func(context.Context, int, *models.Webhook) error
+	UpdateCalled  bool
+	UpdateWebhook *models.Webhook
+
+	// Delete
+	DeleteFunc func(context.Context, int) error
+
+	// LogDelivery
+	LogDeliveryFunc func(context.Context, *models.WebhookDelivery) error
+
+	// GetDeliveries
+	GetDeliveriesFunc  func(context.Context, int, int) ([]*models.WebhookDelivery, error)
+	GetDeliveriesLimit int
+}
+
+func (m *MockWebhookRepository) GetAll(ctx context.Context) ([]*models.Webhook, error) {
+	return m.GetAllFunc(ctx)
+}
+
+func (m *MockWebhookRepository) GetActiveForEvent(ctx context.Context, event string) ([]*models.Webhook, error) {
+	return m.GetActiveForEventFunc(ctx, event)
+}
+
+func (m *MockWebhookRepository) GetByID(ctx context.Context, id int) (*models.Webhook, error) {
+	return m.GetByIDFunc(ctx, id)
+}
+
+func (m *MockWebhookRepository) Create(ctx context.Context, hook *models.Webhook) error {
+	m.CreateCalled = true
+	m.CreateWebhook = hook
+	return m.CreateFunc(ctx, hook)
+}
+
+func (m *MockWebhookRepository) Update(ctx context.Context, id int, hook *models.Webhook) error {
+	m.UpdateCalled = true
+	m.UpdateWebhook = hook
+	return m.UpdateFunc(ctx, id, hook)
+}
+
+func (m *MockWebhookRepository) Delete(ctx context.Context, id int) error {
+	return m.DeleteFunc(ctx, id)
+}
+
+func (m *MockWebhookRepository) LogDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	return m.LogDeliveryFunc(ctx, delivery)
+}
+
+func (m *MockWebhookRepository) GetDeliveries(ctx context.Context, webhookID int, limit int) ([]*models.WebhookDelivery, error) {
+	m.GetDeliveriesLimit = limit
+	return m.GetDeliveriesFunc(ctx, webhookID, limit)
+}
+
+// Verify interface implementation
+var _ database.WebhookRepositoryInterface = &MockWebhookRepository{}
+
+func TestCreateWebhookHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          models.WebhookInput
+		expectedStatus int
+		expectedSecret string // empty when one should be generated
+	}{
+		{
+			name:           "Generated secret",
+			input:          models.WebhookInput{URL: "https://hooks.zapier.com/hooks/catch/1/abc", Events: []string{"booking.created"}, Active: true},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "Own secret and every event",
+			input:          models.WebhookInput{URL: "https://slack-bot.internal/hooks", Secret: "a-long-enough-secret", Events: []string{"*"}, Active: true},
+			expectedStatus: http.StatusCreated,
+			expectedSecret: "a-long-enough-secret",
+		},
+		{
+			name:           "Relative URL",
+			input:          models.WebhookInput{URL: "/hooks", Events: []string{"booking.created"}},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Unsupported scheme",
+			input:          models.WebhookInput{URL: "ftp://example.com/hooks", Events: []string{"booking.created"}},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "No events",
+			input:          models.WebhookInput{URL: "https://example.com/hooks"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Unknown event",
+			input:          models.WebhookInput{URL: "https://example.com/hooks", Events: []string{"booking.deleted"}},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Cloud metadata address",
+			input:          models.WebhookInput{URL: "http://169.254.169.254/latest/meta-data/", Events: []string{"*"}},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Localhost",
+			input:          models.WebhookInput{URL: "http://localhost:8080/hooks", Events: []string{"*"}},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Short secret",
+			input:          models.WebhookInput{URL: "https://example.com/hooks", Secret: "short", Events: []string{"*"}},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := &MockWebhookRepository{
+				CreateFunc: func(ctx context.Context, hook *models.Webhook) error {
+					hook.ID = 5
+					return nil
+				},
+			}
+			handler := handlers.NewWebhookHandler(mockRepo, nil)
+
+			body, _ := json.Marshal(tc.input)
+			req := httptest.NewRequest("POST", "/api/v1/webhooks", bytes.NewBuffer(body))
+			w := httptest.NewRecorder()
+
+			handler.Create(w, req)
+
+			if w.Code != tc.expectedStatus {
+				t.Fatalf("Expected status %d, got %d (%s)", tc.expectedStatus, w.Code, w.Body.String())
+			}
+			if tc.expectedStatus != http.StatusCreated {
+				if mockRepo.CreateCalled {
+					t.Error("Create should not be called for invalid input")
+				}
+				return
+			}
+
+			var response map[string]interface{}
+			json.Unmarshal(w.Body.Bytes(), &response)
+			secret := mockRepo.CreateWebhook.Secret
+			if tc.expectedSecret != "" && secret != tc.expectedSecret {
+				t.Errorf("Expected secret %q to be stored, got %q", tc.expectedSecret, secret)
+			}
+			if tc.expectedSecret == "" && !strings.HasPrefix(secret, "whsec_") {
+				t.Errorf("Expected a generated secret, got %q", secret)
+			}
+			if response["secret"] != secret {
+				t.Errorf("Expected the secret in the create response, got %v", response["secret"])
+			}
+		})
+	}
+}
+
+func TestWebhookSecretIsNotListed(t *testing.T) {
+	mockRepo := &MockWebhookRepository{
+		GetAllFunc: func(ctx context.Context) ([]*models.Webhook, error) {
+			return []*models.Webhook{{ID: 1, URL: "https://example.com", Secret: "whsec_hidden", Events: []string{"*"}}}, nil
+		},
+	}
+	handler := handlers.NewWebhookHandler(mockRepo, nil)
+
+	req := httptest.NewRequest("GET", "/api/v1/webhooks", nil)
+	w := httptest.NewRecorder()
+
+	handler.GetAll(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if strings.Contains(w.Body.String(), "whsec_hidden") {
+		t.Error("Webhook secret leaked in list response")
+	}
+}
+
+func TestUpdateWebhookHandlerKeepsSecret(t *testing.T) {
+	existing := &models.Webhook{ID: 2, URL: "https://example.com/old", Secret: "whsec_current", Events: []string{"booking.created"}}
+	mockRepo := &MockWebhookRepository{
+		GetByIDFunc: func(ctx context.Context, id int) (*models.Webhook, error) {
+			if id != 2 {
+				return nil, database.ErrWebhookNotFound
+			}
+			copy := *existing
+			return &copy, nil
+		},
+		UpdateFunc: func(ctx context.Context, id int, hook *models.Webhook) error { return nil },
+	}
+	handler := handlers.NewWebhookHandler(mockRepo, nil)
+
+	tests := []struct {
+		name           string
+		id             string
+		expectedStatus int
+	}{
+		{name: "Existing webhook", id: "2", expectedStatus: http.StatusOK},
+		{name: "Missing webhook", id: "3", expectedStatus: http.StatusNotFound},
+		{name: "Invalid ID", id: "abc", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo.UpdateWebhook = nil
+			body, _ := json.Marshal(models.WebhookInput{URL: "https://example.com/new", Events: []string{"inquiry.created"}, Active: true})
+			req := httptest.NewRequest("PUT", "/api/v1/webhooks/"+tc.id, bytes.NewBuffer(body))
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tc.id)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+			w := httptest.NewRecorder()
+
+			handler.Update(w, req)
+
+			if w.Code != tc.expectedStatus {
+				t.Fatalf("Expected status %d, got %d (%s)", tc.expectedStatus, w.Code, w.Body.String())
+			}
+			if tc.expectedStatus == http.StatusOK {
+				if mockRepo.UpdateWebhook.Secret != "whsec_current" || mockRepo.UpdateWebhook.URL != "https://example.com/new" {
+					t.Errorf("Unexpected update %+v", mockRepo.UpdateWebhook)
+				}
+			}
+		})
+	}
+}
+
+func TestGetWebhookDeliveriesHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		target         string
+		expectedStatus int
+		expectedLimit  int
+	}{
+		{name: "Default limit", target: "/api/v1/webhooks/1/deliveries", expectedStatus: http.StatusOK, expectedLimit: 50},
+		{name: "Custom limit", target: "/api/v1/webhooks/1/deliveries?limit=10", expectedStatus: http.StatusOK, expectedLimit: 10},
+		{name: "Limit too large", target: "/api/v1/webhooks/1/deliveries?limit=1000", expectedStatus: http.StatusBadRequest},
+		{name: "Missing webhook", target: "/api/v1/webhooks/9/deliveries", expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := &MockWebhookRepository{
+				GetByIDFunc: func(ctx context.Context, id int) (*models.Webhook, error) {
+					if id != 1 {
+						return nil, database.ErrWebhookNotFound
+					}
+					return &models.Webhook{ID: 1}, nil
+				},
+				GetDeliveriesFunc: func(ctx context.Context, webhookID int, limit int) ([]*models.WebhookDelivery, error) {
+					return []*models.WebhookDelivery{}, nil
+				},
+			}
+			handler := handlers.NewWebhookHandler(mockRepo, nil)
+
+			router := chi.NewRouter()
+			router.Get("/api/v1/webhooks/{id}/deliveries", handler.GetDeliveries)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, httptest.NewRequest("GET", tc.target, nil))
+
+			if w.Code != tc.expectedStatus {
+				t.Fatalf("Expected status %d, got %d (%s)", tc.expectedStatus, w.Code, w.Body.String())
+			}
+			if mockRepo.GetDeliveriesLimit != tc.expectedLimit {
+				t.Errorf("Expected limit %d, got %d", tc.expectedLimit, mockRepo.GetDeliveriesLimit)
+			}
+		})
+	}
+}
+
+// TestHandlersEmitWebhookEvents checks that booking and inquiry handlers
+// deliver the matching event to subscribers
+func TestHandlersEmitWebhookEvents(t *testing.T) {
+	var mu sync.Mutex
+	var received []string
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		received = append(received, r.Header.Get(services.WebhookEventHeader))
+		mu.Unlock()
+	}))
+	defer receiver.Close()
+
+	webhookRepo := &MockWebhookRepository{
+		GetActiveForEventFunc: func(ctx context.Context, event string) ([]*models.Webhook, error) {
+			return []*models.Webhook{{ID: 1, URL: receiver.URL, Secret: "whsec_test", Events: []string{"*"}, Active: true}}, nil
+		},
+		LogDeliveryFunc: func(ctx context.Context, delivery *models.WebhookDelivery) error { return nil },
+	}
+
+	booking := &models.Booking{
+		ID: 7, Name: "Ada", Email: "ada@example.com", Date: "2026-05-01", Time: "09:00", People: 10,
+		Location: "Town Hall", CoffeeFlavors: []string{"vanilla"}, MilkOptions: []string{"oat"},
+	}
+	bookingRepo := &MockBookingRepository{
+		GetByIDFunc: func(ctx context.Context, id int) (*models.Booking, error) {
+			copy := *booking
+			return &copy, nil
+		},
+		UpdateFunc:  func(ctx context.Context, id int, b *models.Booking) error { return nil },
+		ArchiveFunc: func(ctx context.Context, id int) error { return nil },
+		DeleteFunc:  func(ctx context.Context, id int) error { return nil },
+	}
+
+	tests := []struct {
+		name          string
+		method        string
+		body          interface{}
+		handler       func(*handlers.BookingHandler) http.HandlerFunc
+		expectedEvent []string
+	}{
+		{
+			name:          "Update",
+			method:        "PUT",
+			body:          booking,
+			handler:       func(h *handlers.BookingHandler) http.HandlerFunc { return h.Update },
+			expectedEvent: []string{models.EventBookingUpdated},
+		},
+		{
+			name:   "Update that archives",
+			method: "PUT",
+			body: func() *models.Booking {
+				archived := *booking
+				archived.Archived = true
+				return &archived
+			}(),
+			handler:       func(h *handlers.BookingHandler) http.HandlerFunc { return h.Update },
+			expectedEvent: []string{models.EventBookingArchived, models.EventBookingUpdated},
+		},
+		{
+			name:          "Archive",
+			method:        "POST",
+			handler:       func(h *handlers.BookingHandler) http.HandlerFunc { return h.Archive },
+			expectedEvent: []string{models.EventBookingArchived},
+		},
+		{
+			name:          "Delete",
+			method:        "DELETE",
+			handler:       func(h *handlers.BookingHandler) http.HandlerFunc { return h.Delete },
+			expectedEvent: []string{models.EventBookingCanceled},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			received = nil
+			// The receiver listens on loopback
+			cfg := config.Default().Webhooks
+			cfg.AllowPrivateNetworks = true
+			webhooks := services.NewWebhookService(webhookRepo, cfg)
+			handler := handlers.NewBookingHandler(bookingRepo, nil, webhooks, nil)
+
+			var body bytes.Buffer
+			if tc.body != nil {
+				json.NewEncoder(&body).Encode(tc.body)
+			}
+			req := httptest.NewRequest(tc.method, "/api/v1/bookings/7", &body)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", "7")
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+			w := httptest.NewRecorder()
+
+			tc.handler(handler)(w, req)
+
+			if w.Code >= 300 {
+				t.Fatalf("Unexpected status %d (%s)", w.Code, w.Body.String())
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := webhooks.Drain(ctx); err != nil {
+				t.Fatalf("Failed to drain webhooks: %v", err)
+			}
+
+			// Events are delivered concurrently, so compare them in order
+			mu.Lock()
+			defer mu.Unlock()
+			sort.Strings(received)
+			if strings.Join(received, ",") != strings.Join(tc.expectedEvent, ",") {
+				t.Errorf("Expected events %v, got %v", tc.expectedEvent, received)
+			}
+		})
+	}
+}
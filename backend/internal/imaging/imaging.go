@@ -0,0 +1,92 @@
+// Package imaging resizes uploaded images before they are stored
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	_ "image/gif" // registers GIF decoding
+	"image/jpeg"
+	"image/png"
+	"io"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // registers WebP decoding
+)
+
+// maxPixels caps the size of an image before it is decoded, so a small file
+// can't claim dimensions that would exhaust memory
+const maxPixels = 50_000_000
+
+// jpegQuality is used for every resized JPEG
+const jpegQuality = 85
+
+// Errors returned by Resize
+var (
+	ErrUnsupported = errors.New("image must be a JPEG, PNG, GIF or WebP")
+	ErrTooLarge    = errors.New("image dimensions are too large")
+)
+
+// Image is a resized image ready to store
+type Image struct {
+	Data        []byte
+	ContentType string
+	Ext         string
+	Width       int
+	Height      int
+}
+
+// Resize decodes an image and scales it down so neither side is longer than
+// maxSize, keeping its aspect ratio; smaller images keep their size. Images
+// with transparency are encoded as PNG and everything else as JPEG, which
+// also drops any metadata the upload carried.
+func Resize(r io.Reader, maxSize int) (*Image, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupported
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxPixels {
+		return nil, ErrTooLarge
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupported
+	}
+
+	width, height := fit(src.Bounds().Dx(), src.Bounds().Dy(), maxSize)
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, src.Bounds(), draw.Src, nil)
+
+	var buf bytes.Buffer
+	out := &Image{Width: width, Height: height}
+	if dst.Opaque() {
+		if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: jpegQuality}); err != nil {
+			return nil, err
+		}
+		out.ContentType, out.Ext = "image/jpeg", ".jpg"
+	} else {
+		if err := png.Encode(&buf, dst); err != nil {
+			return nil, err
+		}
+		out.ContentType, out.Ext = "image/png", ".png"
+	}
+	out.Data = buf.Bytes()
+	return out, nil
+}
+
+// fit scales width and height down to fit within maxSize
+func fit(width, height, maxSize int) (int, int) {
+	if width <= maxSize && height <= maxSize {
+		return width, height
+	}
+	if width >= height {
+		return maxSize, max(1, height*maxSize/width)
+	}
+	return max(1, width*maxSize/height), maxSize
+}
@@ -0,0 +1,81 @@
+package imaging_test
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"testing"
+
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/imaging"
+)
+
+func encodeJPEG(t *testing.T, width, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		t.Fatalf("Failed to encode test image: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func encodeGIF(t *testing.T, width, height int) []byte {
+	palette := color.Palette{color.Transparent, color.Black}
+	var buf bytes.Buffer
+	if err := gif.Encode(&buf, image.NewPaletted(image.Rect(0, 0, width, height), palette), nil); err != nil {
+		t.Fatalf("Failed to encode test image: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestResize(t *testing.T) {
+	tests := []struct {
+		name         string
+		data         []byte
+		maxSize      int
+		expectedSize [2]int
+		expectedType string
+		expectedExt  string
+		expectedErr  error
+	}{
+		{name: "Landscape is scaled by width", data: encodeJPEG(t, 1000, 500), maxSize: 200, expectedSize: [2]int{200, 100}, expectedType: "image/jpeg", expectedExt: ".jpg"},
+		{name: "Portrait is scaled by height", data: encodeJPEG(t, 300, 900), maxSize: 300, expectedSize: [2]int{100, 300}, expectedType: "image/jpeg", expectedExt: ".jpg"},
+		{name: "Small image keeps its size", data: encodeJPEG(t, 40, 30), maxSize: 300, expectedSize: [2]int{40, 30}, expectedType: "image/jpeg", expectedExt: ".jpg"},
+		{name: "Transparent GIF becomes PNG", data: encodeGIF(t, 64, 64), maxSize: 32, expectedSize: [2]int{32, 32}, expectedType: "image/png", expectedExt: ".png"},
+		{name: "Not an image", data: []byte("<svg></svg>"), maxSize: 300, expectedErr: imaging.ErrUnsupported},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			img, err := imaging.Resize(bytes.NewReader(tc.data), tc.maxSize)
+			if tc.expectedErr != nil {
+				if !errors.Is(err, tc.expectedErr) {
+					t.Fatalf("Expected %v, got %v", tc.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if [2]int{img.Width, img.Height} != tc.expectedSize {
+				t.Errorf("Expected %v, got %dx%d", tc.expectedSize, img.Width, img.Height)
+			}
+			if img.ContentType != tc.expectedType || img.Ext != tc.expectedExt {
+				t.Errorf("Expected %s, got %s (%s)", tc.expectedType, img.ContentType, img.Ext)
+			}
+			cfg, format, err := image.DecodeConfig(bytes.NewReader(img.Data))
+			if err != nil {
+				t.Fatalf("Failed to decode result: %v", err)
+			}
+			if "image/"+format != img.ContentType || cfg.Width != img.Width || cfg.Height != img.Height {
+				t.Errorf("Encoded %s %dx%d doesn't match %s %dx%d", format, cfg.Width, cfg.Height, img.ContentType, img.Width, img.Height)
+			}
+		})
+	}
+}
@@ -0,0 +1,336 @@
+// Package importer reads bookings from CSV files, such as the spreadsheets
+// kept before bookings were taken online, and saves them. Each row is checked
+// with the same rules as a booking made through the API.
+package importer
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/database"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/export"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
+)
+
+// ErrInvalidFile is wrapped by errors for a file or mapping that can't be
+// imported at all, as opposed to a row that fails validation
+var ErrInvalidFile = errors.New("invalid import file")
+
+// Mapping maps booking fields, by their JSON names, to the CSV column holding
+// each. A field mapped to "" is not imported.
+type Mapping map[string]string
+
+// Row is one booking read from a file. Line is where the row starts in the
+// file, counting the header as line 1. Errors lists why the row can't be
+// imported.
+type Row struct {
+	Line    int
+	Booking *models.Booking
+	Errors  []string
+}
+
+// field is a booking field an import can set. set parses a trimmed cell into
+// the booking.
+type field struct {
+	key string
+	set func(b *models.Booking, value string) error
+}
+
+var fields = []field{
+	{"name", func(b *models.Booking, v string) error { b.Name = v; return nil }},
+	{"email", func(b *models.Booking, v string) error { b.Email = v; return nil }},
+	{"phone", func(b *models.Booking, v string) error { b.Phone = v; return nil }},
+	{"date", func(b *models.Booking, v string) error { b.Date = v; return nil }},
+	{"time", func(b *models.Booking, v string) error { b.Time = v; return nil }},
+	{"people", func(b *models.Booking, v string) error {
+		if v == "" {
+			return nil
+		}
+		people, err := strconv.Atoi(v)
+		if err != nil || people < 0 {
+			return errors.New("must be a whole number")
+		}
+		b.People = people
+		return nil
+	}},
+	{"location", func(b *models.Booking, v string) error { b.Location = v; return nil }},
+	{"notes", func(b *models.Booking, v string) error { b.Notes = v; return nil }},
+	{"coffeeFlavors", func(b *models.Booking, v string) error { b.CoffeeFlavors = splitList(v); return nil }},
+	{"milkOptions", func(b *models.Booking, v string) error { b.MilkOptions = splitList(v); return nil }},
+	{"package", func(b *models.Booking, v string) error { b.Package = v; return nil }},
+	{"status", func(b *models.Booking, v string) error {
+		if v == "" {
+			return nil
+		}
+		status := strings.ToLower(v)
+		if !models.IsValidBookingStatus(status) {
+			return fmt.Errorf("must be one of: %s", strings.Join(models.BookingStatuses(), ", "))
+		}
+		b.Status = status
+		return nil
+	}},
+	{"archived", func(b *models.Booking, v string) (err error) { b.Archived, err = parseBool(v); return }},
+	{"isOutdoor", func(b *models.Booking, v string) (err error) { b.IsOutdoor, err = parseBool(v); return }},
+	{"hasShade", func(b *models.Booking, v string) (err error) { b.HasShade, err = parseBool(v); return }},
+}
+
+// FieldKeys lists the booking fields an import can set
+func FieldKeys() []string {
+	keys := make([]string, len(fields))
+	for i, f := range fields {
+		keys[i] = f.key
+	}
+	return keys
+}
+
+// ParseMapping reads a mapping written as a comma separated list of
+// field=Column pairs
+func ParseMapping(spec string) (Mapping, error) {
+	mapping := Mapping{}
+	for _, pair := range strings.Split(spec, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		key, column, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("mapping %q must be field=Column", pair)
+		}
+		mapping[strings.TrimSpace(key)] = strings.TrimSpace(column)
+	}
+	return mapping, nil
+}
+
+// Parse reads the bookings in a CSV file with a header row. Fields not in
+// mapping are read from a column with the same name or export header,
+// ignoring case, spaces and punctuation, so an export can be imported again.
+// Columns matching no field are ignored, as are blank rows.
+func Parse(r io.Reader, mapping Mapping) ([]Row, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("%w: the file is empty", ErrInvalidFile)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+
+	columns, err := resolveColumns(header, mapping)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("%w: no columns match a booking field", ErrInvalidFile)
+	}
+
+	var rows []Row
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+		}
+		if isBlank(record) {
+			continue
+		}
+		if len(rows) == models.MaxImportRows {
+			return nil, fmt.Errorf("%w: more than %d rows", ErrInvalidFile, models.MaxImportRows)
+		}
+
+		line, _ := reader.FieldPos(0)
+		rows = append(rows, parseRow(line, record, columns))
+	}
+	return rows, nil
+}
+
+// Import reads the bookings in r and saves the valid ones in one
+// transaction, skipping duplicates of existing bookings. Imported bookings
+// don't send confirmation emails, webhooks or admin events. A file that can't
+// be read returns an error wrapping ErrInvalidFile.
+func Import(ctx context.Context, repo database.BookingRepositoryInterface, r io.Reader, mapping Mapping, dryRun bool) (*models.BookingImportResponse, error) {
+	rows, err := Parse(r, mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.BookingImportResponse{
+		DryRun:  dryRun,
+		Rows:    len(rows),
+		Results: make([]models.BookingImportResult, len(rows)),
+	}
+	var valid []*models.Booking
+	var validRows []int
+	for i, row := range rows {
+		response.Results[i].Row = row.Line
+		if len(row.Errors) > 0 {
+			response.Results[i].Result = models.ImportResultInvalid
+			response.Results[i].Errors = row.Errors
+			response.Invalid++
+			continue
+		}
+		valid = append(valid, row.Booking)
+		validRows = append(validRows, i)
+	}
+	if len(valid) == 0 {
+		return response, nil
+	}
+
+	results, err := repo.Import(ctx, valid, dryRun)
+	if err != nil {
+		return nil, err
+	}
+	for i, result := range results {
+		row := &response.Results[validRows[i]]
+		row.Result = result.Result
+		row.ID = result.ID
+		if result.Result == models.ImportResultDuplicate {
+			response.Duplicates++
+		} else {
+			response.Created++
+		}
+	}
+	return response, nil
+}
+
+// column is a booking field and the index of the CSV column it's read from
+type column struct {
+	field field
+	index int
+}
+
+func resolveColumns(header []string, mapping Mapping) ([]column, error) {
+	for key := range mapping {
+		if _, ok := findField(key); !ok {
+			return nil, fmt.Errorf("unknown field %q in mapping, must be one of: %s", key, strings.Join(FieldKeys(), " "))
+		}
+	}
+
+	var columns []column
+	for _, f := range fields {
+		name, mapped := mapping[f.key]
+		if mapped && name == "" {
+			continue
+		}
+
+		index := -1
+		for i, h := range header {
+			if mapped && strings.EqualFold(strings.TrimSpace(h), name) {
+				index = i
+				break
+			}
+			if !mapped && (normalize(h) == normalize(f.key) || normalize(h) == normalize(exportHeader(f.key))) {
+				index = i
+				break
+			}
+		}
+		if index == -1 {
+			if mapped {
+				return nil, fmt.Errorf("column %q mapped to %s is not in the file", name, f.key)
+			}
+			continue
+		}
+		columns = append(columns, column{field: f, index: index})
+	}
+	return columns, nil
+}
+
+func parseRow(line int, record []string, columns []column) Row {
+	booking := &models.Booking{CoffeeFlavors: []string{}, MilkOptions: []string{}}
+	row := Row{Line: line, Booking: booking}
+	for _, c := range columns {
+		value := ""
+		if c.index < len(record) {
+			value = unescape(strings.TrimSpace(record[c.index]))
+		}
+		if err := c.field.set(booking, value); err != nil {
+			row.Errors = append(row.Errors, fmt.Sprintf("%s: %v", c.field.key, err))
+		}
+	}
+	if err := models.ValidateNewBooking(booking); err != nil {
+		row.Errors = append(row.Errors, err.Error())
+	}
+	return row
+}
+
+func findField(key string) (field, bool) {
+	for _, f := range fields {
+		if f.key == key {
+			return f, true
+		}
+	}
+	return field{}, false
+}
+
+// exportHeader returns the header an export gives the field's column
+func exportHeader(key string) string {
+	for _, c := range export.BookingColumns() {
+		if c.Key == key {
+			return c.Header
+		}
+	}
+	return key
+}
+
+// normalize reduces a column name to lower case letters and digits, so
+// "Coffee Flavors", "coffee_flavors" and "coffeeFlavors" all match
+func normalize(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(unicode.ToLower(r))
+		}
+	}
+	return b.String()
+}
+
+// unescape removes the quote an export puts before text a spreadsheet would
+// run as a formula
+func unescape(value string) string {
+	if len(value) > 1 && value[0] == '\'' && strings.ContainsRune("=+-@", rune(value[1])) {
+		return value[1:]
+	}
+	return value
+}
+
+// splitList reads a list cell, with values separated by semicolons or commas
+func splitList(value string) []string {
+	list := []string{}
+	for _, v := range strings.FieldsFunc(value, func(r rune) bool { return r == ';' || r == ',' }) {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+func parseBool(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "", "false", "no", "n", "0":
+		return false, nil
+	case "true", "yes", "y", "1":
+		return true, nil
+	default:
+		return false, errors.New("must be true or false")
+	}
+}
+
+func isBlank(record []string) bool {
+	for _, v := range record {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,126 @@
+package importer_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/importer"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name           string
+		csv            string
+		mapping        importer.Mapping
+		expectError    bool
+		expectedLines  []int
+		expectedErrors [][]string
+		check          func(t *testing.T, rows []importer.Row)
+	}{
+		{
+			name: "Export headers are matched",
+			csv: "ID,Name,Email,Phone,Date,Time,People,Coffee Flavors,Milk Options,Status,Archived,Outdoor,Created At (UTC)\n" +
+				"3,Ada,ada@example.com,'+1 555 0100,2025-06-01,14:00,40,vanilla; mocha,oat,completed,true,yes,2025-05-01T08:00:00Z\n",
+			expectedLines:  []int{2},
+			expectedErrors: [][]string{nil},
+			check: func(t *testing.T, rows []importer.Row) {
+				expected := &models.Booking{
+					Name: "Ada", Email: "ada@example.com", Phone: "+1 555 0100", Date: "2025-06-01", Time: "14:00",
+					People: 40, CoffeeFlavors: []string{"vanilla", "mocha"}, MilkOptions: []string{"oat"},
+					Status: models.BookingStatusCompleted, Archived: true, IsOutdoor: true,
+				}
+				if !reflect.DeepEqual(rows[0].Booking, expected) {
+					t.Errorf("Expected %+v, got %+v", expected, rows[0].Booking)
+				}
+			},
+		},
+		{
+			name:           "Mapping picks columns",
+			csv:            "Customer,Contact,Event Date,Name\nAda,ada@example.com,2025-06-01,ignored\n",
+			mapping:        importer.Mapping{"name": "customer", "email": "Contact", "date": "Event Date"},
+			expectedLines:  []int{2},
+			expectedErrors: [][]string{nil},
+			check: func(t *testing.T, rows []importer.Row) {
+				if b := rows[0].Booking; b.Name != "Ada" || b.Email != "ada@example.com" || b.Date != "2025-06-01" {
+					t.Errorf("Unexpected booking %+v", b)
+				}
+			},
+		},
+		{
+			name:    "Rows are validated like Create",
+			csv:     "name,email,phone,date,people,hasShade\nAda,,,2025-06-01,5,no\n\nGrace,g@example.com,,June 1,many,maybe\n",
+			mapping: importer.Mapping{"phone": ""},
+			// Blank lines are skipped but still counted
+			expectedLines: []int{2, 4},
+			expectedErrors: [][]string{
+				{"Email or phone number is required"},
+				{"people: must be a whole number", "hasShade: must be true or false", "Invalid date format. Use YYYY-MM-DD"},
+			},
+		},
+		{
+			name:           "Quoted cells can span lines",
+			csv:            "name,email,date,notes\nAda,ada@example.com,2025-06-01,\"Two\nlines\"\nGrace,g@example.com,2025-06-02,\n",
+			expectedLines:  []int{2, 4},
+			expectedErrors: [][]string{nil, nil},
+		},
+		{name: "Empty file", csv: "", expectError: true},
+		{name: "No known columns", csv: "a,b\n1,2\n", expectError: true},
+		{name: "Unknown field in mapping", csv: "name,date\n", mapping: importer.Mapping{"price": "Price"}, expectError: true},
+		{name: "Mapped column missing", csv: "name,date\n", mapping: importer.Mapping{"email": "Email Address"}, expectError: true},
+		{name: "Malformed CSV", csv: "name,date\n\"Ada,2025-06-01\n", expectError: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rows, err := importer.Parse(strings.NewReader(tc.csv), tc.mapping)
+			if tc.expectError {
+				if !errors.Is(err, importer.ErrInvalidFile) {
+					t.Fatalf("Expected ErrInvalidFile, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(rows) != len(tc.expectedLines) {
+				t.Fatalf("Expected %d rows, got %d", len(tc.expectedLines), len(rows))
+			}
+			for i, row := range rows {
+				if row.Line != tc.expectedLines[i] {
+					t.Errorf("Expected row %d on line %d, got %d", i, tc.expectedLines[i], row.Line)
+				}
+				if !reflect.DeepEqual(row.Errors, tc.expectedErrors[i]) {
+					t.Errorf("Expected row %d errors %q, got %q", i, tc.expectedErrors[i], row.Errors)
+				}
+			}
+			if tc.check != nil {
+				tc.check(t, rows)
+			}
+		})
+	}
+}
+
+func TestParseTooManyRows(t *testing.T) {
+	csv := "name,email,date\n" + strings.Repeat("Ada,ada@example.com,2025-06-01\n", models.MaxImportRows+1)
+	if _, err := importer.Parse(strings.NewReader(csv), nil); !errors.Is(err, importer.ErrInvalidFile) {
+		t.Errorf("Expected ErrInvalidFile, got %v", err)
+	}
+}
+
+func TestParseMapping(t *testing.T) {
+	mapping, err := importer.ParseMapping("name=Customer Name, date = Event Date,phone=")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := importer.Mapping{"name": "Customer Name", "date": "Event Date", "phone": ""}
+	if !reflect.DeepEqual(mapping, expected) {
+		t.Errorf("Expected %v, got %v", expected, mapping)
+	}
+
+	if _, err := importer.ParseMapping("name"); err == nil {
+		t.Error("Expected an error for a pair without =")
+	}
+}
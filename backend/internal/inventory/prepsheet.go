@@ -0,0 +1,179 @@
+// Package inventory works out what bookings need from the stock of
+// ingredients, using the recipe of each coffee flavor and milk option.
+package inventory
+
+import (
+	"math"
+	"sort"
+
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
+)
+
+// BuildPrepSheet works out the prep sheet for date from bookings, which
+// should be every booking from today through date. Bookings before date use
+// stock first, so they count as committed; canceled bookings are ignored.
+// Each guest has servingsPerGuest drinks, split evenly between the flavors,
+// and the milk options, their booking chose.
+func BuildPrepSheet(date string, bookings []*models.Booking, recipes []*models.Recipe,
+	ingredients []*models.Ingredient, servingsPerGuest float64) *models.PrepSheet {
+	sheet := &models.PrepSheet{
+		Date:           date,
+		Events:         []models.PrepEvent{},
+		Prep:           []models.PrepItem{},
+		Ingredients:    []models.IngredientNeed{},
+		Shopping:       []models.IngredientNeed{},
+		MissingRecipes: []models.MissingRecipe{},
+	}
+
+	recipeFor := make(map[models.ItemType]map[string]*models.Recipe)
+	for _, recipe := range recipes {
+		if recipeFor[recipe.Type] == nil {
+			recipeFor[recipe.Type] = make(map[string]*models.Recipe)
+		}
+		recipeFor[recipe.Type][recipe.Value] = recipe
+	}
+
+	required := make(map[int]float64)
+	committed := make(map[int]float64)
+	prep := make(map[models.ItemType]map[string]*models.PrepItem)
+	// Booked values without ingredients, by type and value, whether or not
+	// they have a recipe row
+	missing := make(map[models.ItemType]map[string]models.MissingRecipe)
+
+	for _, booking := range bookings {
+		if booking.Status == models.BookingStatusCanceled || booking.Date > date {
+			continue
+		}
+		onDate := booking.Date == date
+		if onDate {
+			sheet.Bookings++
+			sheet.Headcount += booking.People
+			sheet.Events = append(sheet.Events, models.PrepEvent{
+				BookingID:     booking.ID,
+				Time:          booking.Time,
+				People:        booking.People,
+				Location:      booking.Location,
+				Package:       booking.Package,
+				CoffeeFlavors: booking.CoffeeFlavors,
+				MilkOptions:   booking.MilkOptions,
+			})
+		}
+
+		uses := committed
+		if onDate {
+			uses = required
+		}
+		servings := float64(booking.People) * servingsPerGuest
+		for _, choice := range []struct {
+			itemType models.ItemType
+			values   []string
+		}{
+			{models.CoffeeFlavor, booking.CoffeeFlavors},
+			{models.MilkOption, booking.MilkOptions},
+		} {
+			for _, value := range choice.values {
+				share := servings / float64(len(choice.values))
+				recipe := recipeFor[choice.itemType][value]
+				hasRecipe := recipe != nil && len(recipe.Ingredients) > 0
+
+				if onDate {
+					item := prepItem(prep, choice.itemType, value, recipe)
+					item.Servings += share
+					item.HasRecipe = hasRecipe
+					if !hasRecipe {
+						if missing[choice.itemType] == nil {
+							missing[choice.itemType] = make(map[string]models.MissingRecipe)
+						}
+						entry := models.MissingRecipe{Value: value, Label: item.Label, Type: choice.itemType}
+						if recipe != nil {
+							entry.MenuItemID = recipe.MenuItemID
+						}
+						missing[choice.itemType][value] = entry
+					}
+				}
+				if hasRecipe {
+					for _, ingredient := range recipe.Ingredients {
+						uses[ingredient.IngredientID] += share * ingredient.Quantity
+					}
+				}
+			}
+		}
+	}
+
+	for _, items := range prep {
+		for _, item := range items {
+			item.Servings = round(item.Servings)
+			sheet.Prep = append(sheet.Prep, *item)
+		}
+	}
+	sort.Slice(sheet.Prep, func(i, j int) bool {
+		a, b := sheet.Prep[i], sheet.Prep[j]
+		if a.Type != b.Type {
+			return a.Type == models.CoffeeFlavor
+		}
+		if a.Servings != b.Servings {
+			return a.Servings > b.Servings
+		}
+		return a.Value < b.Value
+	})
+
+	for _, ingredient := range ingredients {
+		if required[ingredient.ID] == 0 {
+			continue
+		}
+		need := models.IngredientNeed{
+			IngredientID: ingredient.ID,
+			Name:         ingredient.Name,
+			Unit:         ingredient.Unit,
+			Required:     round(required[ingredient.ID]),
+			Committed:    round(committed[ingredient.ID]),
+			Stock:        ingredient.Stock,
+			Remaining:    round(ingredient.Stock - committed[ingredient.ID] - required[ingredient.ID]),
+		}
+		if need.Remaining < 0 {
+			need.Short = true
+			need.ToBuy = -need.Remaining
+			sheet.Shopping = append(sheet.Shopping, need)
+		}
+		sheet.Ingredients = append(sheet.Ingredients, need)
+	}
+
+	for _, items := range missing {
+		for _, item := range items {
+			sheet.MissingRecipes = append(sheet.MissingRecipes, item)
+		}
+	}
+	sort.Slice(sheet.MissingRecipes, func(i, j int) bool {
+		a, b := sheet.MissingRecipes[i], sheet.MissingRecipes[j]
+		if a.Type != b.Type {
+			return a.Type == models.CoffeeFlavor
+		}
+		return a.Value < b.Value
+	})
+
+	return sheet
+}
+
+// prepItem returns the prep sheet line for a flavor or milk option, adding
+// it the first time it's booked
+func prepItem(prep map[models.ItemType]map[string]*models.PrepItem, itemType models.ItemType,
+	value string, recipe *models.Recipe) *models.PrepItem {
+	if prep[itemType] == nil {
+		prep[itemType] = make(map[string]*models.PrepItem)
+	}
+	item, ok := prep[itemType][value]
+	if !ok {
+		item = &models.PrepItem{Value: value, Label: value, Type: itemType}
+		if recipe != nil {
+			item.Label = recipe.Label
+		}
+		prep[itemType][value] = item
+	}
+	return item
+}
+
+// round keeps quantities to three decimal places, hiding floating point
+// noise from splitting servings
+func round(quantity float64) float64 {
+	return math.Round(quantity*1000) / 1000
+}
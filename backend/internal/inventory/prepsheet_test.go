@@ -0,0 +1,182 @@
+package inventory_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/inventory"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
+)
+
+func TestBuildPrepSheet(t *testing.T) {
+	recipes := []*models.Recipe{
+		{MenuItemID: 1, Value: "vanilla", Label: "Vanilla", Type: models.CoffeeFlavor, Ingredients: []models.RecipeIngredient{
+			{IngredientID: 1, Quantity: 18},
+			{IngredientID: 2, Quantity: 15},
+		}},
+		{MenuItemID: 2, Value: "mocha", Label: "Mocha", Type: models.CoffeeFlavor, Ingredients: []models.RecipeIngredient{
+			{IngredientID: 1, Quantity: 18},
+		}},
+		{MenuItemID: 3, Value: "oat", Label: "Oat", Type: models.MilkOption, Ingredients: []models.RecipeIngredient{
+			{IngredientID: 3, Quantity: 100},
+		}},
+		{MenuItemID: 4, Value: "whole", Label: "Whole", Type: models.MilkOption, Ingredients: []models.RecipeIngredient{}},
+		{MenuItemID: 5, Value: "coconut", Label: "Coconut", Type: models.CoffeeFlavor, Ingredients: []models.RecipeIngredient{}},
+		{MenuItemID: 6, Value: "coconut", Label: "Coconut Milk", Type: models.MilkOption, Ingredients: []models.RecipeIngredient{}},
+	}
+	ingredients := []*models.Ingredient{
+		{ID: 1, Name: "Espresso beans", Unit: "g", Stock: 2000},
+		{ID: 2, Name: "Vanilla syrup", Unit: "ml", Stock: 500},
+		{ID: 3, Name: "Oat milk", Unit: "ml", Stock: 3000},
+	}
+
+	tests := []struct {
+		name             string
+		servingsPerGuest float64
+		bookings         []*models.Booking
+		expectedPrep     map[string]float64
+		expectedNeeds    map[string][2]float64 // name: required, remaining
+		expectedShopping []string
+		expectedMissing  []models.MissingRecipe
+	}{
+		{
+			name:             "Servings split between choices",
+			servingsPerGuest: 1,
+			bookings: []*models.Booking{
+				{ID: 1, Date: "2026-06-01", People: 20, CoffeeFlavors: []string{"vanilla", "mocha"}, MilkOptions: []string{"oat"}},
+			},
+			expectedPrep: map[string]float64{"vanilla": 10, "mocha": 10, "oat": 20},
+			expectedNeeds: map[string][2]float64{
+				"Espresso beans": {360, 1640},
+				"Vanilla syrup":  {150, 350},
+				"Oat milk":       {2000, 1000},
+			},
+			expectedShopping: []string{},
+			expectedMissing:  []models.MissingRecipe{},
+		},
+		{
+			name:             "Earlier bookings use stock first",
+			servingsPerGuest: 1,
+			bookings: []*models.Booking{
+				{ID: 1, Date: "2026-05-30", People: 20, MilkOptions: []string{"oat"}},
+				{ID: 2, Date: "2026-06-01", People: 15, MilkOptions: []string{"oat"}},
+			},
+			expectedPrep:     map[string]float64{"oat": 15},
+			expectedNeeds:    map[string][2]float64{"Oat milk": {1500, -500}},
+			expectedShopping: []string{"Oat milk"},
+			expectedMissing:  []models.MissingRecipe{},
+		},
+		{
+			name:             "Canceled and later bookings are ignored",
+			servingsPerGuest: 1,
+			bookings: []*models.Booking{
+				{ID: 1, Date: "2026-05-30", People: 50, MilkOptions: []string{"oat"}, Status: models.BookingStatusCanceled},
+				{ID: 2, Date: "2026-06-01", People: 10, MilkOptions: []string{"oat"}},
+				{ID: 3, Date: "2026-06-02", People: 50, MilkOptions: []string{"oat"}},
+			},
+			expectedPrep:     map[string]float64{"oat": 10},
+			expectedNeeds:    map[string][2]float64{"Oat milk": {1000, 2000}},
+			expectedShopping: []string{},
+			expectedMissing:  []models.MissingRecipe{},
+		},
+		{
+			name:             "Choices without a recipe",
+			servingsPerGuest: 2,
+			bookings: []*models.Booking{
+				{ID: 1, Date: "2026-06-01", People: 10, CoffeeFlavors: []string{"caramel"}, MilkOptions: []string{"whole"}},
+			},
+			expectedPrep:     map[string]float64{"caramel": 20, "whole": 20},
+			expectedNeeds:    map[string][2]float64{},
+			expectedShopping: []string{},
+			expectedMissing: []models.MissingRecipe{
+				{Value: "caramel", Label: "caramel", Type: models.CoffeeFlavor},
+				{MenuItemID: 4, Value: "whole", Label: "Whole", Type: models.MilkOption},
+			},
+		},
+		{
+			name:             "Values not on the menu",
+			servingsPerGuest: 1,
+			bookings: []*models.Booking{
+				{ID: 1, Date: "2026-06-01", People: 10, CoffeeFlavors: []string{"hazelnut", "vanilla"}, MilkOptions: []string{"hazelnut"}},
+			},
+			expectedPrep: map[string]float64{"hazelnut": 10, "vanilla": 5},
+			expectedNeeds: map[string][2]float64{
+				"Espresso beans": {90, 1910},
+				"Vanilla syrup":  {75, 425},
+			},
+			expectedShopping: []string{},
+			expectedMissing: []models.MissingRecipe{
+				{Value: "hazelnut", Label: "hazelnut", Type: models.CoffeeFlavor},
+				{Value: "hazelnut", Label: "hazelnut", Type: models.MilkOption},
+			},
+		},
+		{
+			name:             "Flavor and milk option with the same value",
+			servingsPerGuest: 1,
+			bookings: []*models.Booking{
+				{ID: 1, Date: "2026-06-01", People: 10, CoffeeFlavors: []string{"coconut"}, MilkOptions: []string{"coconut"}},
+			},
+			expectedPrep:     map[string]float64{"coconut": 10},
+			expectedNeeds:    map[string][2]float64{},
+			expectedShopping: []string{},
+			expectedMissing: []models.MissingRecipe{
+				{MenuItemID: 5, Value: "coconut", Label: "Coconut", Type: models.CoffeeFlavor},
+				{MenuItemID: 6, Value: "coconut", Label: "Coconut Milk", Type: models.MilkOption},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			sheet := inventory.BuildPrepSheet("2026-06-01", tc.bookings, recipes, ingredients, tc.servingsPerGuest)
+
+			prep := make(map[string]float64)
+			for _, item := range sheet.Prep {
+				prep[item.Value] = item.Servings
+			}
+			if !reflect.DeepEqual(prep, tc.expectedPrep) {
+				t.Errorf("Expected prep %v, got %v", tc.expectedPrep, prep)
+			}
+
+			needs := make(map[string][2]float64)
+			for _, need := range sheet.Ingredients {
+				needs[need.Name] = [2]float64{need.Required, need.Remaining}
+				if need.Short != (need.Remaining < 0) || (need.Short && need.ToBuy != -need.Remaining) {
+					t.Errorf("Expected %s to be short only when stock runs out, got %+v", need.Name, need)
+				}
+			}
+			if !reflect.DeepEqual(needs, tc.expectedNeeds) {
+				t.Errorf("Expected ingredients %v, got %v", tc.expectedNeeds, needs)
+			}
+
+			shopping := []string{}
+			for _, need := range sheet.Shopping {
+				shopping = append(shopping, need.Name)
+			}
+			if !reflect.DeepEqual(shopping, tc.expectedShopping) {
+				t.Errorf("Expected shopping list %v, got %v", tc.expectedShopping, shopping)
+			}
+			if !reflect.DeepEqual(sheet.MissingRecipes, tc.expectedMissing) {
+				t.Errorf("Expected missing recipes %v, got %v", tc.expectedMissing, sheet.MissingRecipes)
+			}
+		})
+	}
+}
+
+func TestBuildPrepSheetEvents(t *testing.T) {
+	bookings := []*models.Booking{
+		{ID: 1, Date: "2026-05-31", People: 5},
+		{ID: 2, Date: "2026-06-01", Time: "09:00", People: 20, Location: "Town Hall"},
+		{ID: 3, Date: "2026-06-01", Time: "14:00", People: 30, Status: models.BookingStatusCanceled},
+		{ID: 4, Date: "2026-06-01", Time: "16:00", People: 12},
+	}
+
+	sheet := inventory.BuildPrepSheet("2026-06-01", bookings, nil, nil, 1)
+
+	if sheet.Bookings != 2 || sheet.Headcount != 32 {
+		t.Errorf("Expected 2 bookings for 32 guests, got %d for %d", sheet.Bookings, sheet.Headcount)
+	}
+	if len(sheet.Events) != 2 || sheet.Events[0].BookingID != 2 || sheet.Events[1].BookingID != 4 {
+		t.Errorf("Expected events for bookings 2 and 4, got %+v", sheet.Events)
+	}
+}
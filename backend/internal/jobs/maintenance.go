@@ -0,0 +1,146 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/blob"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/config"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/database"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/events"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/services"
+)
+
+// Names of the maintenance jobs in the jobs table
+const (
+	JobArchiveBookings   = "archive_bookings"
+	JobPurgeDeleted      = "purge_deleted"
+	JobExpireTokens      = "expire_tokens"
+	JobAnonymizeBookings = "anonymize_bookings"
+)
+
+// Maintenance holds the nightly clean-up jobs
+type Maintenance struct {
+	repo     database.MaintenanceRepositoryInterface
+	cfg      config.JobsConfig
+	location *time.Location
+	webhooks *services.WebhookService
+	events   *events.Broker
+	images   blob.Store
+
+	// now is replaced in tests
+	now func() time.Time
+}
+
+// NewMaintenance creates the maintenance jobs. Bookings they archive are
+// announced through webhooks and the event stream like manual archives.
+// Images of purged menu items are deleted from images.
+func NewMaintenance(repo database.MaintenanceRepositoryInterface, cfg config.JobsConfig,
+	webhooks *services.WebhookService, broker *events.Broker, images blob.Store) (*Maintenance, error) {
+	location, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid job timezone: %w", err)
+	}
+	return &Maintenance{
+		repo:     repo,
+		cfg:      cfg,
+		location: location,
+		webhooks: webhooks,
+		events:   broker,
+		images:   images,
+		now:      time.Now,
+	}, nil
+}
+
+// SetClock replaces the time source used to work out cutoffs
+func (m *Maintenance) SetClock(now func() time.Time) {
+	m.now = now
+}
+
+// Register adds the maintenance jobs to s on the configured schedule.
+// Archiving, purging and anonymizing change or remove business data, so each
+// is only scheduled when its window is set.
+func (m *Maintenance) Register(s *Scheduler) error {
+	jobs := []job{
+		{name: JobExpireTokens, run: m.ExpireTokens},
+	}
+	if m.cfg.ArchiveAfterDays > 0 {
+		jobs = append(jobs, job{name: JobArchiveBookings, run: m.ArchiveBookings})
+	}
+	if m.cfg.PurgeDeletedAfterDays > 0 {
+		jobs = append(jobs, job{name: JobPurgeDeleted, run: m.PurgeDeleted})
+	}
+	if m.cfg.BookingRetentionDays > 0 {
+		jobs = append(jobs, job{name: JobAnonymizeBookings, run: m.AnonymizeBookings})
+	}
+
+	for _, j := range jobs {
+		if err := s.Add(j.name, m.cfg.Schedule, j.run); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ArchiveBookings archives bookings dated more than ArchiveAfterDays days ago
+func (m *Maintenance) ArchiveBookings(ctx context.Context) (string, error) {
+	bookings, err := m.repo.ArchivePastBookings(ctx, m.daysAgo(m.cfg.ArchiveAfterDays))
+	if err != nil {
+		return "", err
+	}
+	for _, booking := range bookings {
+		m.webhooks.Emit(ctx, models.EventBookingArchived, booking)
+		m.events.Publish(ctx, models.EventBookingArchived, booking)
+	}
+	return fmt.Sprintf("archived %d bookings", len(bookings)), nil
+}
+
+// PurgeDeleted permanently removes menu items and packages deleted more than
+// PurgeDeletedAfterDays days ago, along with the menu items' images. An image
+// that can't be deleted is only logged; nothing refers to it any more.
+func (m *Maintenance) PurgeDeleted(ctx context.Context) (string, error) {
+	before := m.now().AddDate(0, 0, -m.cfg.PurgeDeletedAfterDays)
+	purged, imageKeys, err := m.repo.PurgeDeleted(ctx, before)
+	if err != nil {
+		return "", err
+	}
+
+	deleted := 0
+	for _, key := range imageKeys {
+		if err := m.images.Delete(ctx, key); err != nil {
+			slog.WarnContext(ctx, "failed to delete purged menu image", "key", key, "error", err)
+			continue
+		}
+		deleted++
+	}
+	return fmt.Sprintf("purged %d menu items and packages, deleted %d images", purged, deleted), nil
+}
+
+// ExpireTokens removes expired refresh and password reset tokens
+func (m *Maintenance) ExpireTokens(ctx context.Context) (string, error) {
+	deleted, err := m.repo.DeleteExpiredTokens(ctx)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("deleted %d expired tokens", deleted), nil
+}
+
+// AnonymizeBookings removes customer details from bookings dated more than
+// BookingRetentionDays days ago
+func (m *Maintenance) AnonymizeBookings(ctx context.Context) (string, error) {
+	anonymized, err := m.repo.AnonymizeBookings(ctx, m.daysAgo(m.cfg.BookingRetentionDays))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("anonymized %d bookings", anonymized), nil
+}
+
+// daysAgo returns midnight, in the job timezone, of the day the given number
+// of days before today. Bookings dated before it are older than days.
+func (m *Maintenance) daysAgo(days int) time.Time {
+	now := m.now().In(m.location)
+	return time.Date(now.Year(), now.Month(), now.Day()-days, 0, 0, 0, 0, m.location)
+}
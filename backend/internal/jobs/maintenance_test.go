@@ -0,0 +1,205 @@
+package jobs_test
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/blob"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/events"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/jobs"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
+)
+
+// MockMaintenanceRepository records the cutoffs each query was called with
+type MockMaintenanceRepository struct {
+	ArchivedBefore   time.Time
+	PurgedBefore     time.Time
+	AnonymizedBefore time.Time
+	Archived         []*models.Booking
+	PurgedImages     []string
+}
+
+func (m *MockMaintenanceRepository) ArchivePastBookings(ctx context.Context, before time.Time) ([]*models.Booking, error) {
+	m.ArchivedBefore = before
+	return m.Archived, nil
+}
+
+func (m *MockMaintenanceRepository) PurgeDeleted(ctx context.Context, before time.Time) (int64, []string, error) {
+	m.PurgedBefore = before
+	return 2, m.PurgedImages, nil
+}
+
+func (m *MockMaintenanceRepository) DeleteExpiredTokens(ctx context.Context) (int64, error) {
+	return 3, nil
+}
+
+func (m *MockMaintenanceRepository) AnonymizeBookings(ctx context.Context, before time.Time) (int64, error) {
+	m.AnonymizedBefore = before
+	return 4, nil
+}
+
+func TestMaintenanceJobs(t *testing.T) {
+	// Early morning UTC is still the previous evening in Los Angeles
+	now := time.Date(2026, 10, 18, 2, 30, 0, 0, time.UTC)
+	cfg := testJobsConfig("America/Los_Angeles")
+	cfg.ArchiveAfterDays = 7
+	cfg.PurgeDeletedAfterDays = 30
+	cfg.BookingRetentionDays = 365
+
+	repo := &MockMaintenanceRepository{Archived: []*models.Booking{{ID: 1}, {ID: 2}}}
+	broker := events.NewBroker(10)
+	sub, _, _ := broker.Subscribe("")
+	maintenance, err := jobs.NewMaintenance(repo, cfg, nil, broker, nil)
+	if err != nil {
+		t.Fatalf("Failed to create maintenance jobs: %v", err)
+	}
+	maintenance.SetClock(func() time.Time { return now })
+
+	tests := []struct {
+		name           string
+		run            jobs.RunFunc
+		expectedResult string
+		cutoff         *time.Time
+		expectedCutoff string
+	}{
+		{
+			name:           "Archive uses local days",
+			run:            maintenance.ArchiveBookings,
+			expectedResult: "archived 2 bookings",
+			cutoff:         &repo.ArchivedBefore,
+			expectedCutoff: "2026-10-10T00:00:00-07:00",
+		},
+		{
+			name:           "Purge counts from now",
+			run:            maintenance.PurgeDeleted,
+			expectedResult: "purged 2 menu items and packages, deleted 0 images",
+			cutoff:         &repo.PurgedBefore,
+			expectedCutoff: "2026-09-18T02:30:00Z",
+		},
+		{
+			name:           "Expire tokens",
+			run:            maintenance.ExpireTokens,
+			expectedResult: "deleted 3 expired tokens",
+		},
+		{
+			name:           "Anonymize after the retention window",
+			run:            maintenance.AnonymizeBookings,
+			expectedResult: "anonymized 4 bookings",
+			cutoff:         &repo.AnonymizedBefore,
+			expectedCutoff: "2025-10-17T00:00:00-07:00",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := tc.run(context.Background())
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result != tc.expectedResult {
+				t.Errorf("Expected result %q, got %q", tc.expectedResult, result)
+			}
+			if tc.cutoff != nil && tc.cutoff.Format(time.RFC3339) != tc.expectedCutoff {
+				t.Errorf("Expected cutoff %s, got %s", tc.expectedCutoff, tc.cutoff.Format(time.RFC3339))
+			}
+		})
+	}
+
+	// Each archived booking is announced like a manual archive
+	if len(sub.Events()) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(sub.Events()))
+	}
+	if event := <-sub.Events(); event.Type != models.EventBookingArchived {
+		t.Errorf("Expected a %s event, got %s", models.EventBookingArchived, event.Type)
+	}
+}
+
+func TestPurgeDeletedRemovesImages(t *testing.T) {
+	dir := t.TempDir()
+	images := blob.NewLocalStore(dir, "http://localhost:8080/media")
+	ctx := context.Background()
+	if err := images.Put(ctx, "menu-3-ab.jpg", strings.NewReader("jpeg data"), "image/jpeg"); err != nil {
+		t.Fatalf("Failed to store image: %v", err)
+	}
+
+	cfg := testJobsConfig("UTC")
+	cfg.PurgeDeletedAfterDays = 30
+	repo := &MockMaintenanceRepository{PurgedImages: []string{"menu-3-ab.jpg"}}
+	maintenance, err := jobs.NewMaintenance(repo, cfg, nil, nil, images)
+	if err != nil {
+		t.Fatalf("Failed to create maintenance jobs: %v", err)
+	}
+
+	result, err := maintenance.PurgeDeleted(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result != "purged 2 menu items and packages, deleted 1 images" {
+		t.Errorf("Unexpected result %q", result)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "menu-3-ab.jpg")); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Expected the purged item's image to be deleted, got %v", err)
+	}
+}
+
+func TestMaintenanceRegister(t *testing.T) {
+	created := time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		archiveDays   int
+		purgeDays     int
+		retentionDays int
+		expectedJobs  []string
+	}{
+		{
+			name:         "Defaults only expire tokens",
+			expectedJobs: []string{jobs.JobExpireTokens},
+		},
+		{
+			name:         "Archive and purge enabled",
+			archiveDays:  7,
+			purgeDays:    30,
+			expectedJobs: []string{jobs.JobExpireTokens, jobs.JobArchiveBookings, jobs.JobPurgeDeleted},
+		},
+		{
+			name:          "Retention enabled",
+			archiveDays:   7,
+			retentionDays: 365,
+			expectedJobs:  []string{jobs.JobExpireTokens, jobs.JobArchiveBookings, jobs.JobAnonymizeBookings},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := testJobsConfig("UTC")
+			cfg.ArchiveAfterDays = tc.archiveDays
+			cfg.PurgeDeletedAfterDays = tc.purgeDays
+			cfg.BookingRetentionDays = tc.retentionDays
+
+			repo := newFakeJobRepository(created, created)
+			scheduler, _ := jobs.NewScheduler(repo, cfg)
+			maintenance, _ := jobs.NewMaintenance(&MockMaintenanceRepository{}, cfg, nil, nil, nil)
+			if err := maintenance.Register(scheduler); err != nil {
+				t.Fatalf("Failed to register jobs: %v", err)
+			}
+
+			// A first pass records every job without running any
+			scheduler.RunDue(context.Background(), created)
+			if len(repo.states) != len(tc.expectedJobs) {
+				t.Fatalf("Expected jobs %v, got %d", tc.expectedJobs, len(repo.states))
+			}
+			for _, name := range tc.expectedJobs {
+				if state := repo.states[name]; state == nil || state.LastRunAt != nil {
+					t.Errorf("Expected %s to be registered and not yet run, got %+v", name, state)
+				}
+			}
+		})
+	}
+}
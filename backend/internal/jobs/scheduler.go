@@ -0,0 +1,202 @@
+// Package jobs runs maintenance tasks on cron schedules. Every instance runs
+// a Scheduler; the jobs table records when each job last ran and which
+// instance holds it, so each scheduled run happens on exactly one instance.
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/config"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/database"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/metrics"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/tracing"
+	"github.com/robfig/cron/v3"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RunFunc does a job's work and returns a short summary for the jobs table
+type RunFunc func(ctx context.Context) (string, error)
+
+type job struct {
+	name     string
+	schedule cron.Schedule
+	run      RunFunc
+}
+
+// Scheduler runs registered jobs when their schedule comes due
+type Scheduler struct {
+	repo     database.JobRepositoryInterface
+	owner    string
+	location *time.Location
+	lease    time.Duration
+	poll     time.Duration
+	jobs     []*job
+
+	metrics *metrics.Metrics
+	running sync.WaitGroup
+}
+
+// NewScheduler creates a scheduler with no jobs
+func NewScheduler(repo database.JobRepositoryInterface, cfg config.JobsConfig) (*Scheduler, error) {
+	location, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid job timezone: %w", err)
+	}
+	owner, err := newOwnerID()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Scheduler{
+		repo:     repo,
+		owner:    owner,
+		location: location,
+		lease:    cfg.LockTimeout,
+		poll:     cfg.PollInterval,
+	}, nil
+}
+
+// SetMetrics counts job runs in m
+func (s *Scheduler) SetMetrics(m *metrics.Metrics) {
+	s.metrics = m
+}
+
+// Add registers run under name on a five-field cron schedule. A job that
+// has never run first runs at the schedule's next time after it is added.
+func (s *Scheduler) Add(name, spec string, run RunFunc) error {
+	schedule, err := cron.ParseStandard(spec)
+	if err != nil {
+		return fmt.Errorf("invalid schedule for job %s: %w", name, err)
+	}
+	s.jobs = append(s.jobs, &job{name: name, schedule: schedule, run: run})
+	return nil
+}
+
+// Start checks for due jobs every poll interval until ctx is canceled
+func (s *Scheduler) Start(ctx context.Context) {
+	s.running.Add(1)
+	go func() {
+		defer s.running.Done()
+
+		ticker := time.NewTicker(s.poll)
+		defer ticker.Stop()
+		for {
+			s.RunDue(ctx, time.Now())
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Wait blocks until the loop started by Start has returned, or ctx expires
+func (s *Scheduler) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.running.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("scheduled jobs still running: %w", ctx.Err())
+	}
+}
+
+// RunDue runs, one after another, every job whose next run is at or before
+// now and that this instance manages to claim
+func (s *Scheduler) RunDue(ctx context.Context, now time.Time) {
+	for _, j := range s.jobs {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := s.runIfDue(ctx, j, now); err != nil {
+			slog.ErrorContext(ctx, "failed to schedule job", "job", j.name, "error", err)
+		}
+	}
+}
+
+func (s *Scheduler) runIfDue(ctx context.Context, j *job, now time.Time) error {
+	state, err := s.repo.Get(ctx, j.name)
+	if errors.Is(err, database.ErrJobNotFound) {
+		if err := s.repo.Ensure(ctx, j.name); err != nil {
+			return err
+		}
+		state, err = s.repo.Get(ctx, j.name)
+	}
+	if err != nil {
+		return err
+	}
+
+	from := state.CreatedAt
+	if state.LastRunAt != nil {
+		from = *state.LastRunAt
+	}
+	if now.Before(j.schedule.Next(from.In(s.location))) {
+		return nil
+	}
+
+	claimed, err := s.repo.Claim(ctx, j.name, s.owner, state.LastRunAt, s.lease)
+	if err != nil || !claimed {
+		return err
+	}
+
+	run := s.run(ctx, j, now)
+	// Record the run even if shutdown canceled it part way
+	if err := s.repo.Finish(context.WithoutCancel(ctx), j.name, s.owner, run); err != nil {
+		return fmt.Errorf("failed to record run: %w", err)
+	}
+	return nil
+}
+
+// run calls the job, bounded by the lease so it can't outlive its lock
+func (s *Scheduler) run(ctx context.Context, j *job, startedAt time.Time) *models.JobRun {
+	ctx, cancel := context.WithTimeout(ctx, s.lease)
+	defer cancel()
+	ctx, span := tracing.Tracer().Start(ctx, "job "+j.name,
+		trace.WithAttributes(attribute.String("job.name", j.name)))
+	defer span.End()
+
+	start := time.Now()
+	result, err := j.run(ctx)
+	run := &models.JobRun{
+		StartedAt: startedAt,
+		Duration:  time.Since(start),
+		Result:    result,
+	}
+
+	s.metrics.JobRan(j.name, err)
+	tracing.RecordError(span, err)
+	if err != nil {
+		run.Error = err.Error()
+		slog.ErrorContext(ctx, "job failed", "job", j.name, "duration_ms", run.Duration.Milliseconds(), "error", err)
+	} else {
+		slog.InfoContext(ctx, "job finished", "job", j.name, "duration_ms", run.Duration.Milliseconds(), "result", result)
+	}
+	return run
+}
+
+// newOwnerID identifies this instance in job locks
+func newOwnerID() (string, error) {
+	host, _ := os.Hostname()
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s-%d-%s", host, os.Getpid(), hex.EncodeToString(b)), nil
+}
@@ -0,0 +1,192 @@
+package jobs_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/config"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/database"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/jobs"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
+)
+
+// fakeJobRepository keeps job state in memory with the same claim rules as
+// the jobs table. now is the database clock used for lock expiry.
+type fakeJobRepository struct {
+	mu      sync.Mutex
+	states  map[string]*models.JobState
+	created time.Time
+	now     time.Time
+}
+
+func newFakeJobRepository(created, now time.Time) *fakeJobRepository {
+	return &fakeJobRepository{states: map[string]*models.JobState{}, created: created, now: now}
+}
+
+func (r *fakeJobRepository) Ensure(ctx context.Context, name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.states[name]; !ok {
+		r.states[name] = &models.JobState{Name: name, CreatedAt: r.created}
+	}
+	return nil
+}
+
+func (r *fakeJobRepository) Get(ctx context.Context, name string) (*models.JobState, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	state, ok := r.states[name]
+	if !ok {
+		return nil, database.ErrJobNotFound
+	}
+	copied := *state
+	return &copied, nil
+}
+
+func (r *fakeJobRepository) Claim(ctx context.Context, name, owner string, lastRunAt *time.Time, lease time.Duration) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	state := r.states[name]
+	sameRun := (state.LastRunAt == nil && lastRunAt == nil) ||
+		(state.LastRunAt != nil && lastRunAt != nil && state.LastRunAt.Equal(*lastRunAt))
+	if !sameRun || (state.LockedUntil != nil && !state.LockedUntil.Before(r.now)) {
+		return false, nil
+	}
+	until := r.now.Add(lease)
+	state.LockedBy, state.LockedUntil = owner, &until
+	return true, nil
+}
+
+func (r *fakeJobRepository) Finish(ctx context.Context, name, owner string, run *models.JobRun) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	state := r.states[name]
+	if state.LockedBy != owner {
+		return database.ErrJobLockLost
+	}
+	startedAt := run.StartedAt
+	state.LastRunAt, state.LastResult, state.LastError = &startedAt, run.Result, run.Error
+	state.LockedBy, state.LockedUntil = "", nil
+	return nil
+}
+
+func testJobsConfig(timezone string) config.JobsConfig {
+	cfg := config.Default().Jobs
+	cfg.Timezone = timezone
+	return cfg
+}
+
+func TestSchedulerRunDue(t *testing.T) {
+	created := time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC)
+	at := func(day, hour, min int) time.Time {
+		return time.Date(2026, 10, day, hour, min, 0, 0, time.UTC)
+	}
+	ptr := func(t time.Time) *time.Time { return &t }
+
+	tests := []struct {
+		name        string
+		timezone    string
+		lastRunAt   *time.Time
+		lockedUntil *time.Time
+		now         time.Time
+		runErr      error
+		expectRun   bool
+	}{
+		{name: "Not due before the first scheduled time", now: at(18, 2, 59)},
+		{name: "Due at the scheduled time", now: at(18, 3, 0), expectRun: true},
+		{name: "Missed runs catch up once", lastRunAt: ptr(at(15, 3, 0)), now: at(18, 12, 0), expectRun: true},
+		{name: "Already ran today", lastRunAt: ptr(at(18, 3, 0)), now: at(18, 12, 0)},
+		{name: "Locked by another instance", lockedUntil: ptr(at(18, 3, 30)), now: at(18, 3, 10)},
+		{name: "Expired lock is taken over", lockedUntil: ptr(at(18, 2, 0)), now: at(18, 3, 10), expectRun: true},
+		{name: "Schedule follows the timezone", timezone: "America/New_York", now: at(18, 3, 0)},
+		{name: "Timezone run is due", timezone: "America/New_York", now: at(18, 7, 0), expectRun: true},
+		{name: "Failed run is recorded", now: at(18, 3, 0), runErr: errors.New("boom"), expectRun: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := newFakeJobRepository(created, tc.now)
+			repo.states["nightly"] = &models.JobState{
+				Name:        "nightly",
+				CreatedAt:   created,
+				LastRunAt:   tc.lastRunAt,
+				LockedBy:    "other",
+				LockedUntil: tc.lockedUntil,
+			}
+
+			timezone := tc.timezone
+			if timezone == "" {
+				timezone = "UTC"
+			}
+			scheduler, err := jobs.NewScheduler(repo, testJobsConfig(timezone))
+			if err != nil {
+				t.Fatalf("Failed to create scheduler: %v", err)
+			}
+
+			runs := 0
+			scheduler.Add("nightly", "0 3 * * *", func(ctx context.Context) (string, error) {
+				runs++
+				return "done", tc.runErr
+			})
+			scheduler.RunDue(context.Background(), tc.now)
+
+			if (runs == 1) != tc.expectRun || runs > 1 {
+				t.Fatalf("Expected run=%v, ran %d times", tc.expectRun, runs)
+			}
+			state, _ := repo.Get(context.Background(), "nightly")
+			if !tc.expectRun {
+				return
+			}
+			if state.LastRunAt == nil || !state.LastRunAt.Equal(tc.now) || state.LockedUntil != nil {
+				t.Errorf("Expected the run to be recorded and unlocked, got %+v", state)
+			}
+			if tc.runErr != nil && state.LastError != tc.runErr.Error() {
+				t.Errorf("Expected error %q to be recorded, got %q", tc.runErr, state.LastError)
+			}
+		})
+	}
+}
+
+func TestSchedulerRunsOnceAcrossInstances(t *testing.T) {
+	created := time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC)
+	now := time.Date(2026, 10, 18, 3, 0, 0, 0, time.UTC)
+	repo := newFakeJobRepository(created, now)
+
+	var runs atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		scheduler, err := jobs.NewScheduler(repo, testJobsConfig("UTC"))
+		if err != nil {
+			t.Fatalf("Failed to create scheduler: %v", err)
+		}
+		scheduler.Add("nightly", "0 3 * * *", func(ctx context.Context) (string, error) {
+			runs.Add(1)
+			return "", nil
+		})
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			scheduler.RunDue(context.Background(), now)
+		}()
+	}
+	wg.Wait()
+
+	if runs.Load() != 1 {
+		t.Errorf("Expected exactly one run, got %d", runs.Load())
+	}
+}
+
+func TestSchedulerRejectsInvalidSchedule(t *testing.T) {
+	scheduler, err := jobs.NewScheduler(newFakeJobRepository(time.Now(), time.Now()), testJobsConfig("UTC"))
+	if err != nil {
+		t.Fatalf("Failed to create scheduler: %v", err)
+	}
+	if err := scheduler.Add("nightly", "every night", nil); err == nil {
+		t.Error("Expected an invalid schedule to be rejected")
+	}
+}
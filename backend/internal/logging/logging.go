@@ -0,0 +1,66 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/config"
+)
+
+type requestIDKey struct{}
+
+// WithRequestID returns a context whose log records carry the request ID
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID stored in ctx, if any
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// ParseLevel converts a configured level name to a slog level
+func ParseLevel(level string) (slog.Level, error) {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(level)); err != nil {
+		return 0, fmt.Errorf("invalid log level %q", level)
+	}
+	return l, nil
+}
+
+// New builds a logger writing JSON (or text) records to w, with request IDs
+// from the context and personal data masked
+func New(w io.Writer, cfg config.LogConfig) (*slog.Logger, error) {
+	level, err := ParseLevel(cfg.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	// The inner handler accepts everything, the redacting handler filters
+	opts := &slog.HandlerOptions{Level: slog.LevelDebug}
+	var inner slog.Handler
+	switch strings.ToLower(cfg.Format) {
+	case "text":
+		inner = slog.NewTextHandler(w, opts)
+	default:
+		inner = slog.NewJSONHandler(w, opts)
+	}
+
+	return slog.New(NewHandler(inner, level)), nil
+}
+
+// Setup installs the logger as the slog default. Output from the standard log
+// package, e.g. from dependencies, is routed through it at info level.
+func Setup(cfg config.LogConfig) error {
+	logger, err := New(os.Stderr, cfg)
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(logger)
+	return nil
+}
@@ -0,0 +1,145 @@
+package logging_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/config"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/logging"
+)
+
+func newTestLogger(t *testing.T, level string) (*slog.Logger, *bytes.Buffer) {
+	t.Helper()
+	var buf bytes.Buffer
+	logger, err := logging.New(&buf, config.LogConfig{Level: level, Format: "json"})
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	return logger, &buf
+}
+
+func decode(t *testing.T, buf *bytes.Buffer) map[string]interface{} {
+	t.Helper()
+	var record map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("Expected one JSON record, got %q: %v", buf.String(), err)
+	}
+	return record
+}
+
+func TestRedaction(t *testing.T) {
+	tests := []struct {
+		name     string
+		log      func(l *slog.Logger)
+		key      string
+		expected string
+	}{
+		{
+			name:     "Email attribute",
+			log:      func(l *slog.Logger) { l.Info("booking", "email", "jane.doe@example.com") },
+			key:      "email",
+			expected: "j***@example.com",
+		},
+		{
+			name:     "Suffixed phone attribute",
+			log:      func(l *slog.Logger) { l.Info("booking", "customer_phone", "(555) 123-4567") },
+			key:      "customer_phone",
+			expected: "***67",
+		},
+		{
+			name:     "Name attribute",
+			log:      func(l *slog.Logger) { l.Info("booking", "name", "Jane Doe") },
+			key:      "name",
+			expected: "J***",
+		},
+		{
+			name:     "Setup token attribute",
+			log:      func(l *slog.Logger) { l.Warn("no admin account exists", "setup_token", "3f9c2a7b1e") },
+			key:      "setup_token",
+			expected: "***",
+		},
+		{
+			name:     "Email inside message",
+			log:      func(l *slog.Logger) { l.Info("sent confirmation to jane.doe@example.com") },
+			key:      "msg",
+			expected: "sent confirmation to j***@example.com",
+		},
+		{
+			name:     "Phone inside error",
+			log:      func(l *slog.Logger) { l.Info("failed", "error", errors.New("bad number 555-123-4567")) },
+			key:      "error",
+			expected: "bad number ***67",
+		},
+		{
+			name:     "Other values untouched",
+			log:      func(l *slog.Logger) { l.Info("booking created", "booking_id", "42", "date", "2026-10-18") },
+			key:      "date",
+			expected: "2026-10-18",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			logger, buf := newTestLogger(t, "info")
+			tc.log(logger)
+
+			if got := decode(t, buf)[tc.key]; got != tc.expected {
+				t.Errorf("Expected %s = %q, got %q", tc.key, tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestRequestIDFromContext(t *testing.T) {
+	logger, buf := newTestLogger(t, "info")
+
+	ctx := logging.WithRequestID(context.Background(), "req-123")
+	logger.InfoContext(ctx, "handled")
+
+	if got := decode(t, buf)["request_id"]; got != "req-123" {
+		t.Errorf("Expected request_id req-123, got %v", got)
+	}
+}
+
+func TestLevelFilter(t *testing.T) {
+	tests := []struct {
+		name          string
+		level         string
+		record        slog.Level
+		expectedLevel string
+	}{
+		{name: "Info at info level", level: "info", record: slog.LevelInfo, expectedLevel: "INFO"},
+		{name: "Debug dropped at info level", level: "info", record: slog.LevelDebug},
+		{name: "Info dropped at warn level", level: "warn", record: slog.LevelInfo},
+		{name: "Error kept at warn level", level: "warn", record: slog.LevelError, expectedLevel: "ERROR"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			logger, buf := newTestLogger(t, tc.level)
+			logger.Log(context.Background(), tc.record, "server starting")
+
+			if tc.expectedLevel == "" {
+				if buf.Len() != 0 {
+					t.Errorf("Expected record to be dropped, got %s", buf.String())
+				}
+				return
+			}
+
+			if got := decode(t, buf)["level"]; got != tc.expectedLevel {
+				t.Errorf("Expected level %s, got %v", tc.expectedLevel, got)
+			}
+		})
+	}
+}
+
+func TestNewRejectsUnknownLevel(t *testing.T) {
+	if _, err := logging.New(&strings.Builder{}, config.LogConfig{Level: "loud", Format: "json"}); err == nil {
+		t.Error("Expected an unknown level to be rejected")
+	}
+}
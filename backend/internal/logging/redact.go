@@ -0,0 +1,105 @@
+package logging
+
+import (
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	// North American style numbers, with or without separators and country code
+	phonePattern = regexp.MustCompile(`(?:\+?1[\s.-]?)?\(?\b\d{3}\)?[\s.-]?\d{3}[\s.-]?\d{4}\b`)
+)
+
+// sensitiveKeys are attribute keys (or key suffixes after "_") whose values
+// are masked, e.g. "email", "customer_email", "username" or "setup_token"
+var sensitiveKeys = map[string]func(string) string{
+	"email":    MaskEmail,
+	"phone":    MaskPhone,
+	"name":     MaskName,
+	"username": MaskName,
+	"token":    MaskSecret,
+}
+
+// MaskEmail keeps the first character and the domain: j***@example.com
+func MaskEmail(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at < 1 {
+		return "***"
+	}
+	return email[:1] + "***" + email[at:]
+}
+
+// MaskPhone keeps the last two digits: ***67
+func MaskPhone(phone string) string {
+	digits := make([]rune, 0, len(phone))
+	for _, r := range phone {
+		if r >= '0' && r <= '9' {
+			digits = append(digits, r)
+		}
+	}
+	if len(digits) < 4 {
+		return "***"
+	}
+	return "***" + string(digits[len(digits)-2:])
+}
+
+// MaskSecret hides the whole value
+func MaskSecret(string) string {
+	return "***"
+}
+
+// MaskName keeps the first character: J***
+func MaskName(name string) string {
+	if name == "" {
+		return ""
+	}
+	return string([]rune(name)[:1]) + "***"
+}
+
+// scrub masks email addresses and phone numbers inside free text
+func scrub(s string) string {
+	if !strings.ContainsAny(s, "0123456789@") {
+		return s
+	}
+	s = emailPattern.ReplaceAllStringFunc(s, MaskEmail)
+	return phonePattern.ReplaceAllStringFunc(s, MaskPhone)
+}
+
+func maskerFor(key string) func(string) string {
+	key = strings.ToLower(key)
+	if mask, ok := sensitiveKeys[key]; ok {
+		return mask
+	}
+	if i := strings.LastIndex(key, "_"); i >= 0 {
+		return sensitiveKeys[key[i+1:]]
+	}
+	return nil
+}
+
+// redactAttr masks sensitive attributes and scrubs every other string value
+func redactAttr(a slog.Attr) slog.Attr {
+	a.Value = a.Value.Resolve()
+
+	switch a.Value.Kind() {
+	case slog.KindGroup:
+		group := a.Value.Group()
+		redacted := make([]slog.Attr, len(group))
+		for i, ga := range group {
+			redacted[i] = redactAttr(ga)
+		}
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(redacted...)}
+	case slog.KindString:
+		if mask := maskerFor(a.Key); mask != nil {
+			return slog.String(a.Key, mask(a.Value.String()))
+		}
+		return slog.String(a.Key, scrub(a.Value.String()))
+	case slog.KindAny:
+		// Errors and other values are rendered as text, which may embed PII
+		if err, ok := a.Value.Any().(error); ok {
+			return slog.String(a.Key, scrub(err.Error()))
+		}
+	}
+	return a
+}
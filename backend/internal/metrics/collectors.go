@@ -0,0 +1,120 @@
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// poolCollector exports pgxpool statistics at scrape time
+type poolCollector struct {
+	pool *pgxpool.Pool
+
+	acquiredConns   *prometheus.Desc
+	idleConns       *prometheus.Desc
+	totalConns      *prometheus.Desc
+	maxConns        *prometheus.Desc
+	acquireCount    *prometheus.Desc
+	acquireDuration *prometheus.Desc
+	emptyAcquire    *prometheus.Desc
+	canceledAcquire *prometheus.Desc
+}
+
+// NewPoolCollector exports connection pool usage, so pool exhaustion shows up
+// before requests start timing out
+func NewPoolCollector(pool *pgxpool.Pool) prometheus.Collector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "db_pool", name), help, nil, nil)
+	}
+	return &poolCollector{
+		pool:            pool,
+		acquiredConns:   desc("acquired_connections", "Connections currently in use."),
+		idleConns:       desc("idle_connections", "Idle connections in the pool."),
+		totalConns:      desc("total_connections", "Open connections in the pool."),
+		maxConns:        desc("max_connections", "Maximum size of the pool."),
+		acquireCount:    desc("acquires_total", "Successful connection acquires."),
+		acquireDuration: desc("acquire_duration_seconds_total", "Total time spent waiting to acquire connections."),
+		emptyAcquire:    desc("empty_acquires_total", "Acquires that had to wait because no connection was idle."),
+		canceledAcquire: desc("canceled_acquires_total", "Acquires canceled before a connection was available."),
+	}
+}
+
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.acquiredConns
+	ch <- c.idleConns
+	ch <- c.totalConns
+	ch <- c.maxConns
+	ch <- c.acquireCount
+	ch <- c.acquireDuration
+	ch <- c.emptyAcquire
+	ch <- c.canceledAcquire
+}
+
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	stat := c.pool.Stat()
+	ch <- prometheus.MustNewConstMetric(c.acquiredConns, prometheus.GaugeValue, float64(stat.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(c.idleConns, prometheus.GaugeValue, float64(stat.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(c.totalConns, prometheus.GaugeValue, float64(stat.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(c.maxConns, prometheus.GaugeValue, float64(stat.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(c.acquireCount, prometheus.CounterValue, float64(stat.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.acquireDuration, prometheus.CounterValue, stat.AcquireDuration().Seconds())
+	ch <- prometheus.MustNewConstMetric(c.emptyAcquire, prometheus.CounterValue, float64(stat.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.canceledAcquire, prometheus.CounterValue, float64(stat.CanceledAcquireCount()))
+}
+
+// BookingCounter answers the booking questions exported as business gauges
+type BookingCounter interface {
+	CountCreatedSince(ctx context.Context, since time.Time) (int, error)
+	CountUpcoming(ctx context.Context, from time.Time) (int, error)
+}
+
+// bookingCollector queries booking counts at scrape time
+type bookingCollector struct {
+	bookings BookingCounter
+	timeout  time.Duration
+
+	createdToday *prometheus.Desc
+	upcoming     *prometheus.Desc
+}
+
+// NewBookingCollector exports the bookings created today and the upcoming,
+// unarchived events. Each scrape runs two count queries.
+func NewBookingCollector(bookings BookingCounter) prometheus.Collector {
+	return &bookingCollector{
+		bookings: bookings,
+		timeout:  2 * time.Second,
+		createdToday: prometheus.NewDesc(prometheus.BuildFQName(namespace, "bookings", "created_today"),
+			"Bookings created since midnight (server time).", nil, nil),
+		upcoming: prometheus.NewDesc(prometheus.BuildFQName(namespace, "bookings", "upcoming"),
+			"Unarchived bookings with an event date today or later.", nil, nil),
+	}
+}
+
+func (c *bookingCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.createdToday
+	ch <- c.upcoming
+}
+
+func (c *bookingCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	now := time.Now()
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	// A failed query leaves the gauge out of this scrape rather than reporting zero
+	if created, err := c.bookings.CountCreatedSince(ctx, midnight); err != nil {
+		slog.ErrorContext(ctx, "failed to count bookings for metrics", "error", err)
+	} else {
+		ch <- prometheus.MustNewConstMetric(c.createdToday, prometheus.GaugeValue, float64(created))
+	}
+
+	if upcoming, err := c.bookings.CountUpcoming(ctx, midnight); err != nil {
+		slog.ErrorContext(ctx, "failed to count upcoming bookings for metrics", "error", err)
+	} else {
+		ch <- prometheus.MustNewConstMetric(c.upcoming, prometheus.GaugeValue, float64(upcoming))
+	}
+}
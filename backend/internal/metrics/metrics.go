@@ -0,0 +1,190 @@
+package metrics
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace prefixes every metric we export
+const namespace = "toasted"
+
+// Metrics holds the Prometheus registry and the application's collectors.
+// A nil *Metrics is valid and records nothing, which keeps tests simple.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests *prometheus.CounterVec
+	httpDuration *prometheus.HistogramVec
+	emailsSent   *prometheus.CounterVec
+	rateLimited  *prometheus.CounterVec
+	webhooks     *prometheus.CounterVec
+	jobRuns      *prometheus.CounterVec
+	jobSuccess   *prometheus.GaugeVec
+}
+
+// New creates a registry with Go runtime and process metrics plus the
+// application's HTTP, email, rate limit, webhook and job metrics
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests by method, chi route pattern and status code.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by method and chi route pattern.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		emailsSent: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "emails_sent_total",
+			Help:      "Emails sent by kind and result (success or failure).",
+		}, []string{"kind", "result"}),
+		rateLimited: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "rate_limited_requests_total",
+			Help:      "Requests rejected by the rate limiter, by route group.",
+		}, []string{"group"}),
+		webhooks: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "webhook_deliveries_total",
+			Help:      "Webhook delivery attempts by event and result (success or failure).",
+		}, []string{"event", "result"}),
+		jobRuns: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "job_runs_total",
+			Help:      "Scheduled job runs by job and result (success or failure).",
+		}, []string{"job", "result"}),
+		jobSuccess: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "job_last_success_timestamp_seconds",
+			Help:      "Unix time each scheduled job last finished without error.",
+		}, []string{"job"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpDuration,
+		m.emailsSent,
+		m.rateLimited,
+		m.webhooks,
+		m.jobRuns,
+		m.jobSuccess,
+	)
+	return m
+}
+
+// Register adds extra collectors, such as the pool and booking collectors
+func (m *Metrics) Register(cs ...prometheus.Collector) {
+	m.registry.MustRegister(cs...)
+}
+
+// Middleware records request counts and latency per route pattern. Patterns
+// rather than raw paths keep label cardinality bounded, so it must wrap the
+// top-level router to see the full pattern once routing is done.
+func (m *Metrics) Middleware(next http.Handler) http.Handler {
+	if m == nil {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+		next.ServeHTTP(ww, r)
+
+		route := "unmatched"
+		if rctx := chi.RouteContext(r.Context()); rctx != nil {
+			if pattern := rctx.RoutePattern(); pattern != "" {
+				route = pattern
+			}
+		}
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		m.httpRequests.WithLabelValues(r.Method, route, strconv.Itoa(status)).Inc()
+		m.httpDuration.WithLabelValues(r.Method, route).Observe(time.Since(start).Seconds())
+	})
+}
+
+// EmailSent counts an email send attempt of the given kind
+func (m *Metrics) EmailSent(kind string, err error) {
+	if m == nil {
+		return
+	}
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	m.emailsSent.WithLabelValues(kind, result).Inc()
+}
+
+// RateLimited counts a request rejected by the limiter of a route group
+func (m *Metrics) RateLimited(group string) {
+	if m == nil {
+		return
+	}
+	m.rateLimited.WithLabelValues(group).Inc()
+}
+
+// WebhookDelivered counts a webhook delivery attempt for event
+func (m *Metrics) WebhookDelivered(event string, success bool) {
+	if m == nil {
+		return
+	}
+	result := "success"
+	if !success {
+		result = "failure"
+	}
+	m.webhooks.WithLabelValues(event, result).Inc()
+}
+
+// JobRan counts a run of a scheduled job, recording when it last succeeded
+func (m *Metrics) JobRan(job string, err error) {
+	if m == nil {
+		return
+	}
+	result := "success"
+	if err != nil {
+		result = "failure"
+	} else {
+		m.jobSuccess.WithLabelValues(job).SetToCurrentTime()
+	}
+	m.jobRuns.WithLabelValues(job, result).Inc()
+}
+
+// Handler serves the registry in the Prometheus text format. With a token,
+// scrapers must send it as a bearer token.
+func (m *Metrics) Handler(token string) http.Handler {
+	handler := promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+	if token == "" {
+		return handler
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		provided, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="metrics"`)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
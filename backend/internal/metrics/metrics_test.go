@@ -0,0 +1,161 @@
+package metrics_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/database"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/metrics"
+)
+
+// MockBookingStatsRepository is a mock implementation of BookingStatsRepositoryInterface
+type MockBookingStatsRepository struct {
+	CountCreatedSinceFunc func(context.Context, time.Time) (int, error)
+	CountUpcomingFunc     func(context.Context, time.Time) (int, error)
+}
+
+func (m *MockBookingStatsRepository) CountCreatedSince(ctx context.Context, since time.Time) (int, error) {
+	if m.CountCreatedSinceFunc != nil {
+		return m.CountCreatedSinceFunc(ctx, since)
+	}
+	return 0, nil
+}
+
+func (m *MockBookingStatsRepository) CountUpcoming(ctx context.Context, from time.Time) (int, error) {
+	if m.CountUpcomingFunc != nil {
+		return m.CountUpcomingFunc(ctx, from)
+	}
+	return 0, nil
+}
+
+var _ database.BookingStatsRepositoryInterface = &MockBookingStatsRepository{}
+
+func scrape(t *testing.T, m *metrics.Metrics) string {
+	t.Helper()
+	w := httptest.NewRecorder()
+	m.Handler("").ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected scrape to succeed, got %d", w.Code)
+	}
+	return w.Body.String()
+}
+
+func TestMiddlewareRecordsRoutePattern(t *testing.T) {
+	m := metrics.New()
+
+	router := chi.NewRouter()
+	router.Use(m.Middleware)
+	router.Route("/api/v1", func(r chi.Router) {
+		r.Get("/bookings/{id}", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		})
+	})
+
+	for _, path := range []string{"/api/v1/bookings/1", "/api/v1/bookings/2", "/nowhere"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+	}
+
+	body := scrape(t, m)
+	for _, expected := range []string{
+		`toasted_http_requests_total{method="GET",route="/api/v1/bookings/{id}",status="404"} 2`,
+		`toasted_http_requests_total{method="GET",route="unmatched",status="404"} 1`,
+		`toasted_http_request_duration_seconds_count{method="GET",route="/api/v1/bookings/{id}"} 2`,
+	} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("Expected metrics to contain %s", expected)
+		}
+	}
+}
+
+func TestCounters(t *testing.T) {
+	m := metrics.New()
+	m.EmailSent("inquiry", nil)
+	m.EmailSent("inquiry", errors.New("smtp down"))
+	m.RateLimited("auth")
+	m.WebhookDelivered("booking.created", false)
+	m.JobRan("archive_bookings", nil)
+	m.JobRan("purge_tokens", errors.New("connection reset"))
+
+	body := scrape(t, m)
+	for _, expected := range []string{
+		`toasted_emails_sent_total{kind="inquiry",result="success"} 1`,
+		`toasted_emails_sent_total{kind="inquiry",result="failure"} 1`,
+		`toasted_rate_limited_requests_total{group="auth"} 1`,
+		`toasted_webhook_deliveries_total{event="booking.created",result="failure"} 1`,
+		`toasted_job_runs_total{job="archive_bookings",result="success"} 1`,
+		`toasted_job_runs_total{job="purge_tokens",result="failure"} 1`,
+		`toasted_job_last_success_timestamp_seconds{job="archive_bookings"}`,
+	} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("Expected metrics to contain %s", expected)
+		}
+	}
+
+	// A nil registry is a no-op
+	var disabled *metrics.Metrics
+	disabled.EmailSent("inquiry", nil)
+	disabled.RateLimited("auth")
+	disabled.WebhookDelivered("booking.created", true)
+	disabled.JobRan("archive_bookings", nil)
+}
+
+func TestHandlerToken(t *testing.T) {
+	m := metrics.New()
+	handler := m.Handler("scrape-secret")
+
+	tests := []struct {
+		name           string
+		authorization  string
+		expectedStatus int
+	}{
+		{name: "No token", authorization: "", expectedStatus: http.StatusUnauthorized},
+		{name: "Wrong token", authorization: "Bearer nope", expectedStatus: http.StatusUnauthorized},
+		{name: "Valid token", authorization: "Bearer scrape-secret", expectedStatus: http.StatusOK},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/metrics", nil)
+			if tc.authorization != "" {
+				req.Header.Set("Authorization", tc.authorization)
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			if w.Code != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tc.expectedStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestBookingCollector(t *testing.T) {
+	m := metrics.New()
+	m.Register(metrics.NewBookingCollector(&MockBookingStatsRepository{
+		CountCreatedSinceFunc: func(ctx context.Context, since time.Time) (int, error) {
+			if since.Hour() != 0 || since.Minute() != 0 {
+				t.Errorf("Expected bookings to be counted from midnight, got %v", since)
+			}
+			return 4, nil
+		},
+		CountUpcomingFunc: func(ctx context.Context, from time.Time) (int, error) {
+			return 0, errors.New("connection reset")
+		},
+	}))
+
+	body := scrape(t, m)
+	if !strings.Contains(body, "toasted_bookings_created_today 4") {
+		t.Error("Expected the bookings created today gauge")
+	}
+	// A failed count leaves its gauge out rather than reporting zero
+	if strings.Contains(body, "toasted_bookings_upcoming") {
+		t.Error("Expected no upcoming bookings gauge after a failed count")
+	}
+}
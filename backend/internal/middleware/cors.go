@@ -1,23 +1,24 @@
 package middleware
 
 import (
+	"context"
+	"log/slog"
 	"net/http"
-	"os"
 	"strings"
-	"log"
 )
 
 // CORS adds CORS headers to responses
-func CORS(allowOrigins string) func(next http.Handler) http.Handler {
-	origins := strings.Split(allowOrigins, ",")
+func CORS(allowOrigins []string, production bool) func(next http.Handler) http.Handler {
+	origins := make([]string, len(allowOrigins))
+	copy(origins, allowOrigins)
 
 	// Force HTTPS for non-localhost origins in production
-	if os.Getenv("ENVIRONMENT") == "production" {
+	if production {
 		for i, origin := range origins {
 			origin = strings.TrimSpace(origin)
 			if !strings.Contains(origin, "localhost") && strings.HasPrefix(origin, "http:") {
 				origins[i] = "https:" + strings.TrimPrefix(origin, "http:")
-				log.Printf("Converted origin from HTTP to HTTPS: %s -> %s", origin, origins[i])
+				slog.InfoContext(context.Background(), "converted CORS origin to HTTPS", "from", origin, "to", origins[i])
 			} else {
 				origins[i] = origin
 			}
@@ -46,7 +47,8 @@ func CORS(allowOrigins string) func(next http.Handler) http.Handler {
 			if allowOrigin != "" {
 				w.Header().Set("Access-Control-Allow-Origin", allowOrigin)
 				w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-				w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+				w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-CSRF-Token, X-Request-ID")
+				w.Header().Set("Access-Control-Expose-Headers", "X-Request-ID")
 				w.Header().Set("Access-Control-Allow-Credentials", "true")
 				w.Header().Set("Access-Control-Max-Age", "3600")
 			}
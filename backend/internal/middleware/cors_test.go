@@ -3,6 +3,7 @@ package middleware_test
 import (
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/joshuagudgel/toasted-coffee/backend/internal/middleware"
@@ -60,7 +61,7 @@ func TestCORS(t *testing.T) {
 			})
 
 			// Wrap with CORS middleware
-			handler := middleware.CORS(tc.allowedOrigins)(testHandler)
+			handler := middleware.CORS(strings.Split(tc.allowedOrigins, ","), false)(testHandler)
 
 			// Create test request
 			req := httptest.NewRequest("GET", "/api/v1/bookings", nil)
@@ -102,7 +103,7 @@ func TestCORS_OptionsRequest(t *testing.T) {
 	})
 
 	// Wrap with CORS middleware
-	handler := middleware.CORS("http://localhost:5173")(testHandler)
+	handler := middleware.CORS([]string{"http://localhost:5173"}, false)(testHandler)
 
 	// Create OPTIONS request
 	req := httptest.NewRequest("OPTIONS", "/api/v1/bookings", nil)
@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/auth"
+)
+
+// CSRFProtect enforces the double-submit CSRF check for state-changing requests
+// authenticated by the session cookie. Bearer-token requests are not exposed to
+// CSRF and pass through unchanged. It must run after JWTAuth.
+func CSRFProtect(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !auth.IsSafeMethod(r.Method) && auth.AuthenticatedByCookie(r.Context()) && !auth.VerifyCSRF(r) {
+			slog.WarnContext(r.Context(), "missing or invalid CSRF token", "method", r.Method, "path", r.URL.Path)
+			http.Error(w, "Invalid CSRF token", http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
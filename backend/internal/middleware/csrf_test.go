@@ -0,0 +1,90 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/auth"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/middleware"
+)
+
+func TestCSRFProtect(t *testing.T) {
+	tokens := newTestTokenService(t)
+	token, err := tokens.GenerateToken(1, "owner")
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		useCookie      bool
+		csrfCookie     string
+		csrfHeader     string
+		expectedStatus int
+	}{
+		{
+			name:           "Bearer request needs no CSRF token",
+			method:         "POST",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Cookie GET needs no CSRF token",
+			method:         "GET",
+			useCookie:      true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Cookie POST without CSRF token",
+			method:         "POST",
+			useCookie:      true,
+			csrfCookie:     "abc",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Cookie POST with mismatched CSRF token",
+			method:         "DELETE",
+			useCookie:      true,
+			csrfCookie:     "abc",
+			csrfHeader:     "xyz",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Cookie POST with matching CSRF token",
+			method:         "PUT",
+			useCookie:      true,
+			csrfCookie:     "abc",
+			csrfHeader:     "abc",
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	handler := middleware.JWTAuth(tokens, testUsers)(middleware.CSRFProtect(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})))
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, "/api/v1/bookings", nil)
+			if tc.useCookie {
+				req.AddCookie(&http.Cookie{Name: auth.AccessTokenCookie, Value: token})
+			} else {
+				req.Header.Set("Authorization", "Bearer "+token)
+			}
+			if tc.csrfCookie != "" {
+				req.AddCookie(&http.Cookie{Name: auth.CSRFCookie, Value: tc.csrfCookie})
+			}
+			if tc.csrfHeader != "" {
+				req.Header.Set(auth.CSRFHeader, tc.csrfHeader)
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			if w.Code != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tc.expectedStatus, w.Code)
+			}
+		})
+	}
+}
@@ -2,56 +2,74 @@ package middleware
 
 import (
 	"context"
-	"log"
+	"errors"
+	"log/slog"
 	"net/http"
 	"strings"
-	"time"
 
 	"github.com/joshuagudgel/toasted-coffee/backend/internal/auth"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/database"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
 )
 
-// JWTAuth middleware intercepts requests to validate JWT tokens
-func JWTAuth(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		startTime := time.Now()
-		log.Printf("JWT VALIDATION START: Request to %s", r.URL.Path)
-
-		// Get token from Authorization header
-		authHeader := r.Header.Get("Authorization")
-		if authHeader == "" {
-			log.Printf("JWT VALIDATION: No token found for %s", r.URL.Path)
-			http.Error(w, "Authentication required", http.StatusUnauthorized)
-			return
-		}
-
-		// Extract token from Bearer scheme
-		tokenParts := strings.Split(authHeader, " ")
-		if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
-			log.Printf("JWT VALIDATION: Invalid authorization format for %s", r.URL.Path)
-			http.Error(w, "Invalid authorization format", http.StatusUnauthorized)
-			return
-		}
-
-		tokenString := tokenParts[1]
-
-		// Validate token using the auth package
-		validateStart := time.Now()
-		claims, err := auth.ValidateToken(tokenString)
-		validationTime := time.Since(validateStart)
-		log.Printf("JWT VALIDATION TIMING: Token validation took %v for %s", validationTime, r.URL.Path)
-
-		if err != nil {
-			log.Printf("JWT VALIDATION: Invalid token for %s: %v", r.URL.Path, err)
-			http.Error(w, "Invalid token", http.StatusUnauthorized)
-			return
-		}
-
-		// Add claims to context using the exported key from auth
-		ctx := context.WithValue(r.Context(), auth.ClaimsContextKey, claims)
-
-		totalTime := time.Since(startTime)
-		log.Printf("JWT VALIDATION COMPLETE: Total processing time %v for %s", totalTime, r.URL.Path)
-
-		next.ServeHTTP(w, r.WithContext(ctx))
-	})
+// UserLookup loads the user an access token was issued to
+type UserLookup interface {
+	GetByID(ctx context.Context, id int) (*models.User, error)
+}
+
+// JWTAuth returns middleware that validates JWT tokens with the given token
+// service. The token's user is looked up on every request: tokens of deleted
+// users are refused and the claims carry the user's current role, so role
+// changes apply before the token expires.
+func JWTAuth(tokens *auth.TokenService, users UserLookup) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Get token from Authorization header, falling back to the session cookie
+			var tokenString, method string
+			if authHeader := r.Header.Get("Authorization"); authHeader != "" {
+				// Extract token from Bearer scheme
+				tokenParts := strings.Split(authHeader, " ")
+				if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
+					slog.InfoContext(r.Context(), "invalid authorization format", "path", r.URL.Path)
+					http.Error(w, "Invalid authorization format", http.StatusUnauthorized)
+					return
+				}
+				tokenString, method = tokenParts[1], auth.AuthMethodBearer
+			} else if cookie, err := r.Cookie(auth.AccessTokenCookie); err == nil && cookie.Value != "" {
+				tokenString, method = cookie.Value, auth.AuthMethodCookie
+			} else {
+				slog.InfoContext(r.Context(), "no access token", "path", r.URL.Path)
+				http.Error(w, "Authentication required", http.StatusUnauthorized)
+				return
+			}
+
+			// Validate token using the token service
+			claims, err := tokens.ValidateToken(tokenString)
+			if err != nil {
+				slog.InfoContext(r.Context(), "invalid access token", "path", r.URL.Path, "error", err)
+				http.Error(w, "Invalid token", http.StatusUnauthorized)
+				return
+			}
+
+			user, err := users.GetByID(r.Context(), claims.UserID)
+			if err != nil {
+				if errors.Is(err, database.ErrUserNotFound) {
+					slog.InfoContext(r.Context(), "access token for deleted user", "user_id", claims.UserID, "path", r.URL.Path)
+					http.Error(w, "Invalid token", http.StatusUnauthorized)
+					return
+				}
+				slog.ErrorContext(r.Context(), "failed to look up token user", "user_id", claims.UserID, "error", err)
+				http.Error(w, "Internal server error", http.StatusInternalServerError)
+				return
+			}
+			current := *claims
+			current.Role = user.Role
+
+			// Add claims to context using the exported key from auth
+			ctx := context.WithValue(r.Context(), auth.ClaimsContextKey, &current)
+			ctx = context.WithValue(ctx, auth.AuthMethodContextKey, method)
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
 }
@@ -0,0 +1,50 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/logging"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/middleware"
+)
+
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name       string
+		incoming   string
+		expectSame bool
+	}{
+		{name: "No incoming ID", incoming: ""},
+		{name: "Valid incoming ID", incoming: "lb-4f2a9c", expectSame: true},
+		{name: "Log injection attempt", incoming: "abc\nlevel=ERROR msg=forged"},
+		{name: "Oversized ID", incoming: string(make([]byte, 100))},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var seen string
+			handler := middleware.RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				seen = logging.RequestID(r.Context())
+			}))
+
+			req := httptest.NewRequest("GET", "/api/v1/menu", nil)
+			if tc.incoming != "" {
+				req.Header.Set(middleware.RequestIDHeader, tc.incoming)
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			if seen == "" {
+				t.Fatal("Expected a request ID in the context")
+			}
+			if w.Header().Get(middleware.RequestIDHeader) != seen {
+				t.Errorf("Expected response header %q, got %q", seen, w.Header().Get(middleware.RequestIDHeader))
+			}
+			if (seen == tc.incoming) != tc.expectSame {
+				t.Errorf("Expected incoming ID reused = %v, got ID %q", tc.expectSame, seen)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/auth"
+)
+
+// RequirePermission rejects requests whose JWT role does not grant perm.
+// It must be mounted after JWTAuth so the claims are in the request context.
+func RequirePermission(perm auth.Permission) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, ok := auth.ExtractClaimsFromContext(r.Context())
+			if !ok {
+				http.Error(w, "Authentication required", http.StatusUnauthorized)
+				return
+			}
+
+			if !auth.HasPermission(claims, perm) {
+				slog.WarnContext(r.Context(), "permission denied", "user_id", claims.UserID, "role", claims.Role,
+					"permission", perm, "method", r.Method, "path", r.URL.Path)
+				http.Error(w, "Insufficient permissions", http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
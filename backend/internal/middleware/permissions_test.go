@@ -0,0 +1,85 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/auth"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/middleware"
+)
+
+func TestRequirePermission(t *testing.T) {
+	tests := []struct {
+		name           string
+		claims         *auth.Claims
+		permission     auth.Permission
+		expectedStatus int
+	}{
+		{
+			name:           "Owner can manage users",
+			claims:         &auth.Claims{UserID: 1, Role: "owner"},
+			permission:     auth.PermUsersManage,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Manager cannot manage users",
+			claims:         &auth.Claims{UserID: 2, Role: "manager"},
+			permission:     auth.PermUsersManage,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Barista can read bookings",
+			claims:         &auth.Claims{UserID: 3, Role: "barista"},
+			permission:     auth.PermBookingsRead,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Barista cannot modify bookings",
+			claims:         &auth.Claims{UserID: 3, Role: "barista"},
+			permission:     auth.PermBookingsWrite,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Bookkeeper can read packages",
+			claims:         &auth.Claims{UserID: 4, Role: "bookkeeper"},
+			permission:     auth.PermPackagesRead,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Permissions in token are not trusted over the matrix",
+			claims:         &auth.Claims{UserID: 3, Role: "barista", Permissions: []auth.Permission{auth.PermUsersManage}},
+			permission:     auth.PermUsersManage,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Missing claims",
+			claims:         nil,
+			permission:     auth.PermBookingsRead,
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+
+			handler := middleware.RequirePermission(tc.permission)(testHandler)
+
+			req := httptest.NewRequest("GET", "/api/v1/protected", nil)
+			if tc.claims != nil {
+				req = req.WithContext(context.WithValue(req.Context(), auth.ClaimsContextKey, tc.claims))
+			}
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			if w.Code != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tc.expectedStatus, w.Code)
+			}
+		})
+	}
+}
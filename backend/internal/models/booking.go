@@ -1,6 +1,7 @@
 package models
 
 import (
+	"errors"
 	"time"
 )
 
@@ -22,6 +23,60 @@ type Booking struct {
 	Archived      bool      `json:"archived"`
 	IsOutdoor     bool      `json:"isOutdoor"`
 	HasShade      bool      `json:"hasShade"`
+
+	// Status and AssignedUserID are changed with bulk operations, not Update
+	Status         string `json:"status"`
+	AssignedUserID *int   `json:"assignedUserId,omitempty"`
+}
+
+// Errors from ValidateNewBooking. The messages are shown to customers.
+var (
+	ErrBookingContactRequired = errors.New("Email or phone number is required")
+	ErrInvalidBookingDate     = errors.New("Invalid date format. Use YYYY-MM-DD")
+)
+
+// ValidateNewBooking checks the fields a new booking must have: a way to
+// contact the customer and a YYYY-MM-DD date
+func ValidateNewBooking(booking *Booking) error {
+	if booking.Email == "" && booking.Phone == "" {
+		return ErrBookingContactRequired
+	}
+	if _, err := time.Parse("2006-01-02", booking.Date); err != nil {
+		return ErrInvalidBookingDate
+	}
+	return nil
+}
+
+// AnonymizedName replaces the customer's name once the retention job has
+// removed their details from a booking
+const AnonymizedName = "Anonymized"
+
+// Booking statuses
+const (
+	BookingStatusPending   = "pending"
+	BookingStatusConfirmed = "confirmed"
+	BookingStatusCompleted = "completed"
+	BookingStatusCanceled  = "canceled"
+)
+
+// BookingStatuses lists the valid booking statuses
+func BookingStatuses() []string {
+	return []string{
+		BookingStatusPending,
+		BookingStatusConfirmed,
+		BookingStatusCompleted,
+		BookingStatusCanceled,
+	}
+}
+
+// IsValidBookingStatus reports whether status is one of BookingStatuses
+func IsValidBookingStatus(status string) bool {
+	for _, s := range BookingStatuses() {
+		if s == status {
+			return true
+		}
+	}
+	return false
 }
 
 // CanArchiveBooking determines if a booking can be archived
@@ -60,3 +115,41 @@ func CanUnarchiveBooking(booking *Booking) bool {
 	// Can only unarchive bookings that are currently archived
 	return booking.Archived
 }
+
+// BookingWithoutPII is a booking without the customer's name, contact
+// details, location and notes. Webhooks receive it unless they opt in to
+// personal data.
+type BookingWithoutPII struct {
+	ID             int       `json:"id"`
+	Date           string    `json:"date"`
+	Time           string    `json:"time"`
+	People         int       `json:"people"`
+	CoffeeFlavors  []string  `json:"coffeeFlavors"`
+	MilkOptions    []string  `json:"milkOptions"`
+	Package        string    `json:"package"`
+	CreatedAt      time.Time `json:"createdAt"`
+	Archived       bool      `json:"archived"`
+	IsOutdoor      bool      `json:"isOutdoor"`
+	HasShade       bool      `json:"hasShade"`
+	Status         string    `json:"status"`
+	AssignedUserID *int      `json:"assignedUserId,omitempty"`
+}
+
+// WithoutPII returns the booking's fields that don't identify the customer
+func (b *Booking) WithoutPII() interface{} {
+	return &BookingWithoutPII{
+		ID:             b.ID,
+		Date:           b.Date,
+		Time:           b.Time,
+		People:         b.People,
+		CoffeeFlavors:  b.CoffeeFlavors,
+		MilkOptions:    b.MilkOptions,
+		Package:        b.Package,
+		CreatedAt:      b.CreatedAt,
+		Archived:       b.Archived,
+		IsOutdoor:      b.IsOutdoor,
+		HasShade:       b.HasShade,
+		Status:         b.Status,
+		AssignedUserID: b.AssignedUserID,
+	}
+}
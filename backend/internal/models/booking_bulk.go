@@ -0,0 +1,76 @@
+package models
+
+// Bulk booking actions
+const (
+	BulkActionArchive   = "archive"
+	BulkActionUnarchive = "unarchive"
+	BulkActionDelete    = "delete"
+	BulkActionSetStatus = "set_status"
+	BulkActionReassign  = "reassign"
+)
+
+// MaxBulkBookings is the most bookings one bulk operation may touch
+const MaxBulkBookings = 500
+
+// Outcome of a bulk action for a single booking
+const (
+	BulkResultChanged   = "changed"
+	BulkResultUnchanged = "unchanged"
+	BulkResultNotFound  = "not_found"
+)
+
+// BulkActions lists the valid bulk actions
+func BulkActions() []string {
+	return []string{
+		BulkActionArchive,
+		BulkActionUnarchive,
+		BulkActionDelete,
+		BulkActionSetStatus,
+		BulkActionReassign,
+	}
+}
+
+// BookingFilter selects bookings by their fields. Unset fields match every
+// booking; dates are inclusive YYYY-MM-DD bounds.
+type BookingFilter struct {
+	Archived       *bool  `json:"archived,omitempty"`
+	Status         string `json:"status,omitempty"`
+	DateFrom       string `json:"dateFrom,omitempty"`
+	DateTo         string `json:"dateTo,omitempty"`
+	AssignedUserID *int   `json:"assignedUserId,omitempty"`
+}
+
+// IsEmpty reports whether the filter would match every booking
+func (f *BookingFilter) IsEmpty() bool {
+	return f.Archived == nil && f.Status == "" && f.DateFrom == "" && f.DateTo == "" && f.AssignedUserID == nil
+}
+
+// BulkBookingRequest applies one action to the bookings listed in IDs or
+// matched by Filter. Status is the new status for set_status; AssignedUserID
+// the new assignee for reassign, where null unassigns.
+type BulkBookingRequest struct {
+	IDs            []int          `json:"ids,omitempty"`
+	Filter         *BookingFilter `json:"filter,omitempty"`
+	Action         string         `json:"action"`
+	Status         string         `json:"status,omitempty"`
+	AssignedUserID *int           `json:"assignedUserId,omitempty"`
+	DryRun         bool           `json:"dryRun"`
+}
+
+// BulkBookingResult is the outcome for one booking. Booking is its state
+// after the action, or before it for deletes.
+type BulkBookingResult struct {
+	ID      int      `json:"id"`
+	Result  string   `json:"result"`
+	Booking *Booking `json:"booking,omitempty"`
+}
+
+// BulkBookingResponse reports what a bulk operation changed, or would have
+// changed for a dry run
+type BulkBookingResponse struct {
+	Action  string              `json:"action"`
+	DryRun  bool                `json:"dryRun"`
+	Matched int                 `json:"matched"`
+	Changed int                 `json:"changed"`
+	Results []BulkBookingResult `json:"results"`
+}
@@ -0,0 +1,42 @@
+package models
+
+// MaxImportRows is the most bookings one import may contain
+const MaxImportRows = 5000
+
+// Outcome of importing a single row
+const (
+	ImportResultCreated   = "created"
+	ImportResultDuplicate = "duplicate"
+	ImportResultInvalid   = "invalid"
+)
+
+// BookingImportRequest imports the bookings in CSV, a file with a header
+// row. Mapping maps booking fields, by their JSON names, to the CSV column
+// holding each; fields left out are matched to a column with the same name
+// or export header. A dry run reports the outcome without saving anything.
+type BookingImportRequest struct {
+	CSV     string            `json:"csv"`
+	Mapping map[string]string `json:"mapping,omitempty"`
+	DryRun  bool              `json:"dryRun"`
+}
+
+// BookingImportResult is the outcome for one row of an import. Row is its
+// line in the file, counting the header as line 1. ID is the booking created,
+// or the existing booking a duplicate matches.
+type BookingImportResult struct {
+	Row    int      `json:"row"`
+	Result string   `json:"result"`
+	ID     int      `json:"id,omitempty"`
+	Errors []string `json:"errors,omitempty"`
+}
+
+// BookingImportResponse reports what an import created, or would have
+// created for a dry run
+type BookingImportResponse struct {
+	DryRun     bool                  `json:"dryRun"`
+	Rows       int                   `json:"rows"`
+	Created    int                   `json:"created"`
+	Duplicates int                   `json:"duplicates"`
+	Invalid    int                   `json:"invalid"`
+	Results    []BookingImportResult `json:"results"`
+}
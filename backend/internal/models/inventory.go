@@ -0,0 +1,107 @@
+package models
+
+import "time"
+
+// Ingredient is something kept in stock for making drinks. Stock is the
+// quantity on hand, in Unit.
+type Ingredient struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Unit      string    `json:"unit"`
+	Stock     float64   `json:"stock"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// IngredientInput is used for creating or updating ingredients
+type IngredientInput struct {
+	Name  string  `json:"name"`
+	Unit  string  `json:"unit"`
+	Stock float64 `json:"stock"`
+}
+
+// RecipeIngredient is the quantity of an ingredient in one serving of a menu
+// item. Name and Unit are filled in when recipes are read.
+type RecipeIngredient struct {
+	IngredientID int     `json:"ingredientId"`
+	Name         string  `json:"name,omitempty"`
+	Unit         string  `json:"unit,omitempty"`
+	Quantity     float64 `json:"quantity"`
+}
+
+// Recipe lists what goes into one serving of a menu item. A menu item
+// without ingredients has no recipe yet.
+type Recipe struct {
+	MenuItemID  int                `json:"menuItemId"`
+	Value       string             `json:"value"`
+	Label       string             `json:"label"`
+	Type        ItemType           `json:"type"`
+	Ingredients []RecipeIngredient `json:"ingredients"`
+}
+
+// RecipeInput replaces the ingredients of a menu item's recipe
+type RecipeInput struct {
+	Ingredients []RecipeIngredient `json:"ingredients"`
+}
+
+// PrepEvent is a booking on the prep sheet's date
+type PrepEvent struct {
+	BookingID     int      `json:"bookingId"`
+	Time          string   `json:"time"`
+	People        int      `json:"people"`
+	Location      string   `json:"location"`
+	Package       string   `json:"package"`
+	CoffeeFlavors []string `json:"coffeeFlavors"`
+	MilkOptions   []string `json:"milkOptions"`
+}
+
+// PrepItem is how many servings of a coffee flavor or milk option to
+// prepare. Each guest's servings are split evenly between the flavors, and
+// the milk options, their booking chose.
+type PrepItem struct {
+	Value     string   `json:"value"`
+	Label     string   `json:"label"`
+	Type      ItemType `json:"type"`
+	Servings  float64  `json:"servings"`
+	HasRecipe bool     `json:"hasRecipe"`
+}
+
+// IngredientNeed is the quantity of an ingredient the prep sheet's bookings
+// use. Committed is what bookings from today until the day before need
+// first; Remaining is the stock left after both, and Short is set when it
+// would go negative, with ToBuy the shortfall.
+type IngredientNeed struct {
+	IngredientID int     `json:"ingredientId"`
+	Name         string  `json:"name"`
+	Unit         string  `json:"unit"`
+	Required     float64 `json:"required"`
+	Committed    float64 `json:"committed"`
+	Stock        float64 `json:"stock"`
+	Remaining    float64 `json:"remaining"`
+	Short        bool    `json:"short"`
+	ToBuy        float64 `json:"toBuy"`
+}
+
+// MissingRecipe is a flavor or milk option booked that has no recipe.
+// MenuItemID is 0 for a value that isn't on the menu.
+type MissingRecipe struct {
+	MenuItemID int      `json:"menuItemId"`
+	Value      string   `json:"value"`
+	Label      string   `json:"label"`
+	Type       ItemType `json:"type"`
+}
+
+// PrepSheet is what to prepare, and buy, for the bookings on one date.
+// Shopping lists the ingredients that are short. MissingRecipes lists the
+// flavors and milk options booked that have no recipe, so aren't counted in
+// the ingredients.
+type PrepSheet struct {
+	Date           string           `json:"date"`
+	Bookings       int              `json:"bookings"`
+	Headcount      int              `json:"headcount"`
+	Events         []PrepEvent      `json:"events"`
+	Prep           []PrepItem       `json:"prep"`
+	Ingredients    []IngredientNeed `json:"ingredients"`
+	Shopping       []IngredientNeed `json:"shopping"`
+	MissingRecipes []MissingRecipe  `json:"missingRecipes"`
+}
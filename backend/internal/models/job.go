@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// JobState is a scheduled job's last run and lock, shared by every instance
+type JobState struct {
+	Name           string     `json:"name"`
+	LastRunAt      *time.Time `json:"lastRunAt,omitempty"`
+	LastDurationMS int64      `json:"lastDurationMs"`
+	LastResult     string     `json:"lastResult"`
+	LastError      string     `json:"lastError"`
+	LockedBy       string     `json:"lockedBy,omitempty"`
+	LockedUntil    *time.Time `json:"lockedUntil,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+}
+
+// JobRun is the outcome of one run of a scheduled job
+type JobRun struct {
+	StartedAt time.Time
+	Duration  time.Duration
+	Result    string
+	Error     string
+}
@@ -11,13 +11,53 @@ const (
 	MilkOption   ItemType = "milk_option"
 )
 
-// MenuItem represents a menu item (coffee flavor or milk option)
+// Dietary and allergen tags a menu item can carry
+const (
+	TagDairyFree  = "dairy_free"
+	TagNutFree    = "nut_free"
+	TagGlutenFree = "gluten_free"
+	TagSoyFree    = "soy_free"
+	TagVegan      = "vegan"
+)
+
+// MenuTags lists the tags a menu item can carry
+func MenuTags() []string {
+	return []string{TagDairyFree, TagNutFree, TagGlutenFree, TagSoyFree, TagVegan}
+}
+
+// IsValidMenuTag reports whether tag is one of MenuTags
+func IsValidMenuTag(tag string) bool {
+	for _, t := range MenuTags() {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// MenuItem represents a menu item (coffee flavor or milk option).
+// DisplayOrder is changed by reordering and the image by uploading one; both
+// are ignored when an item is created or updated. PriceModifier is added to
+// the price of a drink made with the item.
 type MenuItem struct {
-	ID        int       `json:"id,omitempty"`
-	Value     string    `json:"value" validate:"required"`
-	Label     string    `json:"label" validate:"required"`
-	Type      ItemType  `json:"type" validate:"required,oneof=coffee_flavor milk_option"`
-	Active    bool      `json:"active"`
-	CreatedAt time.Time `json:"createdAt,omitempty"`
-	UpdatedAt time.Time `json:"updatedAt,omitempty"`
+	ID            int       `json:"id,omitempty"`
+	Value         string    `json:"value" validate:"required"`
+	Label         string    `json:"label" validate:"required"`
+	Type          ItemType  `json:"type" validate:"required,oneof=coffee_flavor milk_option"`
+	Active        bool      `json:"active"`
+	DisplayOrder  int       `json:"displayOrder"`
+	Description   string    `json:"description"`
+	Tags          []string  `json:"tags"`
+	PriceModifier float64   `json:"priceModifier"`
+	ImageKey      string    `json:"-"`
+	ImageURL      string    `json:"imageUrl,omitempty"`
+	CreatedAt     time.Time `json:"createdAt,omitempty"`
+	UpdatedAt     time.Time `json:"updatedAt,omitempty"`
+}
+
+// MenuOrder lists menu items of one type in the order to show them. Items of
+// the type that aren't listed keep their order after the listed ones.
+type MenuOrder struct {
+	Type ItemType `json:"type"`
+	IDs  []int    `json:"ids"`
 }
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// PasswordResetToken is the server-side record of an emailed reset link
+type PasswordResetToken struct {
+	ID        int        `json:"id"`
+	UserID    int        `json:"userId"`
+	TokenHash string     `json:"-"`
+	CreatedAt time.Time  `json:"createdAt"`
+	ExpiresAt time.Time  `json:"expiresAt"`
+	UsedAt    *time.Time `json:"usedAt,omitempty"`
+}
+
+// IsUsable reports whether the token can still be redeemed
+func (t *PasswordResetToken) IsUsable(now time.Time) bool {
+	return t.UsedAt == nil && now.Before(t.ExpiresAt)
+}
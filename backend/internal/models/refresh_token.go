@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// RefreshToken is the server-side record of an issued refresh token.
+// Tokens issued by rotating one another share a FamilyID.
+type RefreshToken struct {
+	ID         int        `json:"id"`
+	JTIHash    string     `json:"-"`
+	FamilyID   string     `json:"familyId"`
+	UserID     int        `json:"userId"`
+	Device     string     `json:"device"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	ExpiresAt  time.Time  `json:"expiresAt"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+	ReplacedBy *int       `json:"-"`
+}
+
+// IsActive reports whether the token can still be exchanged for an access token
+func (t *RefreshToken) IsActive(now time.Time) bool {
+	return t.RevokedAt == nil && now.Before(t.ExpiresAt)
+}
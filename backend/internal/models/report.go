@@ -0,0 +1,100 @@
+package models
+
+// Report intervals. Weeks start on Monday.
+const (
+	ReportIntervalWeek  = "week"
+	ReportIntervalMonth = "month"
+)
+
+// ReportRange selects the bookings a report covers by their event date.
+// Both bounds are inclusive YYYY-MM-DD dates.
+type ReportRange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// VolumePeriod counts the bookings dated in one week or month. Headcount
+// leaves out canceled bookings.
+type VolumePeriod struct {
+	Start            string  `json:"start"`
+	Bookings         int     `json:"bookings"`
+	Headcount        int     `json:"headcount"`
+	Canceled         int     `json:"canceled"`
+	CancellationRate float64 `json:"cancellationRate"`
+}
+
+// VolumeReport is bookings and headcount per period, including periods with
+// no bookings
+type VolumeReport struct {
+	ReportRange
+	Interval string         `json:"interval"`
+	Periods  []VolumePeriod `json:"periods"`
+}
+
+// PackageRevenue is the estimated revenue from the bookings of one package,
+// priced at the package's current price. Priced is false when some of them
+// couldn't be priced, because the package no longer exists or its price has
+// no amount in it.
+type PackageRevenue struct {
+	Package   string  `json:"package"`
+	Bookings  int     `json:"bookings"`
+	Headcount int     `json:"headcount"`
+	Revenue   float64 `json:"revenue"`
+	Priced    bool    `json:"priced"`
+}
+
+// RevenueReport is revenue by package, leaving out canceled bookings
+type RevenueReport struct {
+	ReportRange
+	Total    float64          `json:"total"`
+	Packages []PackageRevenue `json:"packages"`
+}
+
+// Popularity is how many bookings chose a menu option, and what share of all
+// bookings that is
+type Popularity struct {
+	Value    string  `json:"value"`
+	Bookings int     `json:"bookings"`
+	Share    float64 `json:"share"`
+}
+
+// MenuReport ranks coffee flavors and milk options by the bookings choosing
+// them, leaving out canceled bookings
+type MenuReport struct {
+	ReportRange
+	Bookings      int          `json:"bookings"`
+	CoffeeFlavors []Popularity `json:"coffeeFlavors"`
+	MilkOptions   []Popularity `json:"milkOptions"`
+}
+
+// LeadTime describes the days between a booking being made and its event
+type LeadTime struct {
+	Bookings    int     `json:"bookings"`
+	AverageDays float64 `json:"averageDays"`
+	MedianDays  float64 `json:"medianDays"`
+	P90Days     float64 `json:"p90Days"`
+	MinDays     int     `json:"minDays"`
+	MaxDays     int     `json:"maxDays"`
+}
+
+// SummaryReport gives totals for the range. Everything but Bookings, Canceled
+// and CancellationRate leaves out canceled bookings.
+type SummaryReport struct {
+	ReportRange
+	Bookings         int      `json:"bookings"`
+	Canceled         int      `json:"canceled"`
+	CancellationRate float64  `json:"cancellationRate"`
+	Headcount        int      `json:"headcount"`
+	Outdoor          int      `json:"outdoor"`
+	OutdoorWithShade int      `json:"outdoorWithShade"`
+	Indoor           int      `json:"indoor"`
+	LeadTime         LeadTime `json:"leadTime"`
+}
+
+// Rate returns part as a fraction of total, or 0 when total is 0
+func Rate(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) / float64(total)
+}
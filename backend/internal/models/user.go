@@ -1,8 +1,35 @@
 package models
 
+import "time"
+
 type User struct {
-	ID       int    `json:"id"`
-	Username string `json:"username" validate:"required"`
-	Password string `json:"-" validate:"required"`    // Never expose in JSON
-	Role     string `json:"role" validate:"required"` // "admin" for full access
+	ID        int       `json:"id"`
+	Username  string    `json:"username" validate:"required"`
+	Email     string    `json:"email"`
+	Password  string    `json:"-" validate:"required"`    // Never expose in JSON
+	Role      string    `json:"role" validate:"required"` // one of the roles in auth.Roles()
+	CreatedAt time.Time `json:"createdAt,omitempty"`
+	UpdatedAt time.Time `json:"updatedAt,omitempty"`
+
+	// Login throttling state
+	FailedLoginAttempts int        `json:"failedLoginAttempts"`
+	LastFailedLoginAt   *time.Time `json:"lastFailedLoginAt,omitempty"`
+	LockedUntil         *time.Time `json:"lockedUntil,omitempty"`
+
+	// Two-factor authentication; the secret never leaves the server
+	TOTPEnabled bool   `json:"totpEnabled"`
+	TOTPSecret  string `json:"-"`
+}
+
+// IsLocked reports whether the account is temporarily locked at the given time
+func (u *User) IsLocked(now time.Time) bool {
+	return u.LockedUntil != nil && now.Before(*u.LockedUntil)
+}
+
+// UserInput is used for creating or updating admin users
+type UserInput struct {
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	Password string `json:"password,omitempty"` // only used on create
+	Role     string `json:"role"`
 }
@@ -0,0 +1,102 @@
+package models
+
+import "time"
+
+// Webhook events. A deleted booking is reported as canceled.
+const (
+	EventBookingCreated  = "booking.created"
+	EventBookingUpdated  = "booking.updated"
+	EventBookingArchived = "booking.archived"
+	EventBookingCanceled = "booking.canceled"
+	EventInquiryCreated  = "inquiry.created"
+
+	// EventWebhookTest is only sent by the admin test-fire endpoint
+	EventWebhookTest = "webhook.test"
+
+	// WebhookAllEvents subscribes a webhook to every event
+	WebhookAllEvents = "*"
+)
+
+// WebhookEvents lists the events a webhook can subscribe to
+func WebhookEvents() []string {
+	return []string{
+		EventBookingCreated,
+		EventBookingUpdated,
+		EventBookingArchived,
+		EventBookingCanceled,
+		EventInquiryCreated,
+	}
+}
+
+// IsValidWebhookEvent reports whether event can be used in a webhook's event filter
+func IsValidWebhookEvent(event string) bool {
+	if event == WebhookAllEvents {
+		return true
+	}
+	for _, e := range WebhookEvents() {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Webhook is a subscription that receives signed POSTs for the events it lists
+type Webhook struct {
+	ID          int      `json:"id"`
+	URL         string   `json:"url"`
+	Secret      string   `json:"-"` // only returned once, when the webhook is created
+	Events      []string `json:"events"`
+	Description string   `json:"description"`
+	Active      bool     `json:"active"`
+	// IncludePII opts in to customer names, contact details, locations and
+	// notes in event data
+	IncludePII bool      `json:"includePii"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+// Subscribes reports whether the webhook should receive event
+func (w *Webhook) Subscribes(event string) bool {
+	for _, e := range w.Events {
+		if e == event || e == WebhookAllEvents {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookInput is used for creating or updating webhooks. An empty secret
+// generates one on create and keeps the current one on update.
+type WebhookInput struct {
+	URL         string   `json:"url"`
+	Secret      string   `json:"secret,omitempty"`
+	Events      []string `json:"events"`
+	Description string   `json:"description"`
+	Active      bool     `json:"active"`
+	IncludePII  bool     `json:"includePii"`
+}
+
+// PersonalData is implemented by event data that holds customer details.
+// Webhooks without IncludePII receive WithoutPII instead.
+type PersonalData interface {
+	WithoutPII() interface{}
+}
+
+// WebhookDelivery records one attempt to deliver an event to a webhook
+type WebhookDelivery struct {
+	ID         int       `json:"id"`
+	WebhookID  int       `json:"webhookId"`
+	EventID    string    `json:"eventId"`
+	Event      string    `json:"event"`
+	Attempt    int       `json:"attempt"`
+	StatusCode *int      `json:"statusCode,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	DurationMs int64     `json:"durationMs"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// Succeeded reports whether the receiver answered with a 2xx status
+func (d *WebhookDelivery) Succeeded() bool {
+	return d.StatusCode != nil && *d.StatusCode >= 200 && *d.StatusCode < 300
+}
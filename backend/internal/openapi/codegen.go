@@ -0,0 +1,543 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"go/token"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// schema is the subset of JSON Schema the client generator understands
+type schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 string             `json:"type"`
+	Format               string             `json:"format"`
+	Description          string             `json:"description"`
+	Enum                 []string           `json:"enum"`
+	Items                *schema            `json:"items"`
+	Properties           map[string]*schema `json:"properties"`
+	Required             []string           `json:"required"`
+	AdditionalProperties *schema            `json:"additionalProperties"`
+}
+
+type parameter struct {
+	Ref      string  `json:"$ref"`
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required"`
+	Schema   *schema `json:"schema"`
+}
+
+type mediaType struct {
+	Schema *schema `json:"schema"`
+}
+
+type response struct {
+	Ref     string               `json:"$ref"`
+	Content map[string]mediaType `json:"content"`
+}
+
+type operation struct {
+	OperationID string      `json:"operationId"`
+	Summary     string      `json:"summary"`
+	Parameters  []parameter `json:"parameters"`
+	RequestBody *struct {
+		Required bool                 `json:"required"`
+		Content  map[string]mediaType `json:"content"`
+	} `json:"requestBody"`
+	Responses map[string]response `json:"responses"`
+
+	method, path string
+	pathParams   []parameter
+}
+
+type spec struct {
+	Paths      map[string]map[string]json.RawMessage `json:"paths"`
+	Components struct {
+		Schemas    map[string]*schema   `json:"schemas"`
+		Parameters map[string]parameter `json:"parameters"`
+		Responses  map[string]response  `json:"responses"`
+	} `json:"components"`
+}
+
+// GenerateClient writes Go types for the spec's component schemas and a
+// Client method per operation. The methods rely on the Client type, its do
+// helper and the upload type for file uploads, which the target package
+// implements by hand.
+func GenerateClient(pkg string) ([]byte, error) {
+	var doc spec
+	if err := json.Unmarshal(specJSON, &doc); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	g := generator{buf: &buf, schemas: doc.Components.Schemas}
+	names := make([]string, 0, len(g.schemas))
+	for name := range g.schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		g.writeType(name, g.schemas[name])
+	}
+
+	ops, err := doc.operations()
+	if err != nil {
+		return nil, err
+	}
+	for _, op := range ops {
+		// Event streams never end, so they need an SSE client rather than a
+		// method that reads the whole response
+		if op.streams() {
+			continue
+		}
+		if err := g.writeMethod(op); err != nil {
+			return nil, fmt.Errorf("%s: %w", op.OperationID, err)
+		}
+	}
+
+	// Import only what the generated code uses
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated by cmd/apigen from internal/openapi/openapi.yaml. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&src, "package %s\n\nimport (\n", pkg)
+	for _, imp := range []struct{ path, use string }{
+		{"context", "context."}, {"fmt", "fmt."}, {"io", "io.Reader"}, {"net/url", "url."}, {"time", "time."},
+	} {
+		if bytes.Contains(buf.Bytes(), []byte(imp.use)) {
+			fmt.Fprintf(&src, "%q\n", imp.path)
+		}
+	}
+	src.WriteString(")\n\n")
+	src.Write(buf.Bytes())
+
+	out, err := format.Source(src.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("generated code does not compile: %w", err)
+	}
+	return out, nil
+}
+
+// operations decodes every operation with references resolved, sorted by ID
+func (doc *spec) operations() ([]*operation, error) {
+	var ops []*operation
+	for path, item := range doc.Paths {
+		var shared []parameter
+		if raw, ok := item["parameters"]; ok {
+			if err := json.Unmarshal(raw, &shared); err != nil {
+				return nil, err
+			}
+		}
+
+		for method, raw := range item {
+			if !methods[method] {
+				continue
+			}
+			op := &operation{method: strings.ToUpper(method), path: path}
+			if err := json.Unmarshal(raw, op); err != nil {
+				return nil, fmt.Errorf("%s %s: %w", method, path, err)
+			}
+
+			var params []parameter
+			for _, p := range append(append([]parameter{}, shared...), op.Parameters...) {
+				if p.Ref != "" {
+					p = doc.Components.Parameters[refName(p.Ref)]
+				}
+				params = append(params, p)
+			}
+			op.Parameters = params
+
+			// Path parameters in the order they appear in the path
+			for _, segment := range strings.Split(path, "/") {
+				if name, ok := strings.CutPrefix(segment, "{"); ok {
+					name = strings.TrimSuffix(name, "}")
+					for _, p := range params {
+						if p.In == "path" && p.Name == name {
+							op.pathParams = append(op.pathParams, p)
+						}
+					}
+				}
+			}
+
+			for status, resp := range op.Responses {
+				if resp.Ref != "" {
+					op.Responses[status] = doc.Components.Responses[refName(resp.Ref)]
+				}
+			}
+			ops = append(ops, op)
+		}
+	}
+
+	sort.Slice(ops, func(i, j int) bool { return ops[i].OperationID < ops[j].OperationID })
+	return ops, nil
+}
+
+// streams reports whether op responds with a text/event-stream
+func (op *operation) streams() bool {
+	for _, resp := range op.Responses {
+		if _, ok := resp.Content["text/event-stream"]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// generator writes Go source for the component schemas and operations
+type generator struct {
+	buf     *bytes.Buffer
+	schemas map[string]*schema
+}
+
+// isStruct reports whether s refers to a component generated as a struct
+func (g *generator) isStruct(s *schema) bool {
+	if s == nil || s.Ref == "" {
+		return false
+	}
+	target := g.schemas[refName(s.Ref)]
+	return target != nil && target.Type == "object" && len(target.Properties) > 0
+}
+
+func (g *generator) writeType(name string, s *schema) {
+	buf := g.buf
+	writeComment(buf, s.Description)
+	switch {
+	case s.Type == "string" && len(s.Enum) > 0:
+		fmt.Fprintf(buf, "type %s string\n\n", name)
+		fmt.Fprintf(buf, "const (\n")
+		for _, value := range s.Enum {
+			fmt.Fprintf(buf, "%s%s %s = %q\n", name, enumName(value), name, value)
+		}
+		fmt.Fprintf(buf, ")\n\n")
+	case s.Type == "object" && len(s.Properties) > 0:
+		fmt.Fprintf(buf, "type %s struct {\n", name)
+		props := make([]string, 0, len(s.Properties))
+		for prop := range s.Properties {
+			props = append(props, prop)
+		}
+		sort.Strings(props)
+		for _, prop := range props {
+			p := s.Properties[prop]
+			required := contains(s.Required, prop)
+			writeComment(buf, p.Description)
+			tag := prop
+			if !required {
+				tag += ",omitempty"
+			}
+			fmt.Fprintf(buf, "%s %s `json:%q`\n", exportedName(prop), g.fieldType(p, required), tag)
+		}
+		fmt.Fprintf(buf, "}\n\n")
+	default:
+		fmt.Fprintf(buf, "type %s %s\n\n", name, goType(s))
+	}
+}
+
+// fieldType is goType, with pointers for optional timestamps and objects so
+// omitempty can leave them out
+func (g *generator) fieldType(s *schema, required bool) string {
+	t := goType(s)
+	if !required && (t == "time.Time" || g.isStruct(s)) {
+		return "*" + t
+	}
+	return t
+}
+
+func goType(s *schema) string {
+	if s == nil {
+		return "any"
+	}
+	if s.Ref != "" {
+		return refName(s.Ref)
+	}
+	switch s.Type {
+	case "string":
+		if s.Format == "date-time" {
+			return "time.Time"
+		}
+		return "string"
+	case "integer":
+		if s.Format == "int64" {
+			return "int64"
+		}
+		return "int"
+	case "number":
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "array":
+		return "[]" + goType(s.Items)
+	case "object":
+		if s.AdditionalProperties != nil {
+			return "map[string]" + goType(s.AdditionalProperties)
+		}
+		return "map[string]any"
+	}
+	return "any"
+}
+
+func (g *generator) writeMethod(op *operation) error {
+	buf := g.buf
+	name := exportedName(op.OperationID)
+
+	// Arguments: path parameters, then query parameters, then the body
+	args := []string{"ctx context.Context"}
+	for _, p := range op.pathParams {
+		args = append(args, fmt.Sprintf("%s %s", argName(p.Name), goType(p.Schema)))
+	}
+
+	var query []parameter
+	for _, p := range op.Parameters {
+		if p.In == "query" {
+			query = append(query, p)
+		}
+	}
+	if len(query) > 0 {
+		fmt.Fprintf(buf, "// %sParams holds the optional query parameters of %s\n", name, name)
+		fmt.Fprintf(buf, "type %sParams struct {\n", name)
+		for _, p := range query {
+			fmt.Fprintf(buf, "%s *%s\n", exportedName(p.Name), goType(p.Schema))
+		}
+		fmt.Fprintf(buf, "}\n\n")
+		args = append(args, fmt.Sprintf("params *%sParams", name))
+	}
+
+	bodyArg := "nil"
+	if op.RequestBody != nil {
+		if media, ok := op.RequestBody.Content["multipart/form-data"]; ok {
+			field, err := g.uploadField(media.Schema)
+			if err != nil {
+				return err
+			}
+			args = append(args, "filename string", "file io.Reader")
+			bodyArg = fmt.Sprintf("&upload{field: %q, filename: filename, file: file}", field)
+		} else {
+			media, ok := op.RequestBody.Content["application/json"]
+			if !ok {
+				return fmt.Errorf("request body must be application/json or multipart/form-data")
+			}
+			t := goType(media.Schema)
+			if !op.RequestBody.Required {
+				t = "*" + t
+			}
+			args = append(args, "body "+t)
+			bodyArg = "body"
+		}
+	}
+
+	result, isText, err := g.result(op)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(buf, "// %s calls %s %s", name, op.method, op.path)
+	if op.Summary != "" {
+		fmt.Fprintf(buf, ": %s", lowerFirst(op.Summary))
+	}
+	buf.WriteString("\n")
+
+	returns := "error"
+	if result != "" {
+		returns = fmt.Sprintf("(%s, error)", result)
+	}
+	fmt.Fprintf(buf, "func (c *Client) %s(%s) %s {\n", name, strings.Join(args, ", "), returns)
+
+	// Path with parameters escaped into their segments
+	var path []string
+	rest := op.path
+	for {
+		literal, param, found := strings.Cut(rest, "{")
+		if literal != "" {
+			path = append(path, fmt.Sprintf("%q", literal))
+		}
+		if !found {
+			break
+		}
+		param, rest, _ = strings.Cut(param, "}")
+		path = append(path, fmt.Sprintf("url.PathEscape(fmt.Sprint(%s))", argName(param)))
+		if rest == "" {
+			break
+		}
+	}
+	fmt.Fprintf(buf, "path := %s\n", strings.Join(path, " + "))
+
+	queryArg := "nil"
+	if len(query) > 0 {
+		queryArg = "query"
+		buf.WriteString("query := url.Values{}\nif params != nil {\n")
+		for _, p := range query {
+			field := exportedName(p.Name)
+			fmt.Fprintf(buf, "if params.%s != nil {\nquery.Set(%q, fmt.Sprint(*params.%s))\n}\n", field, p.Name, field)
+		}
+		buf.WriteString("}\n")
+	}
+
+	call := fmt.Sprintf("c.do(ctx, %q, path, %s, %s", op.method, queryArg, bodyArg)
+	switch {
+	case result == "":
+		fmt.Fprintf(buf, "return %s, nil)\n", call)
+	case isText:
+		fmt.Fprintf(buf, "var out string\nerr := %s, &out)\nreturn out, err\n", call)
+	case strings.HasPrefix(result, "*"):
+		fmt.Fprintf(buf, "var out %s\nif err := %s, &out); err != nil {\nreturn nil, err\n}\nreturn &out, nil\n",
+			strings.TrimPrefix(result, "*"), call)
+	default:
+		fmt.Fprintf(buf, "var out %s\nif err := %s, &out); err != nil {\nreturn nil, err\n}\nreturn out, nil\n", result, call)
+	}
+	buf.WriteString("}\n\n")
+	return nil
+}
+
+// uploadField returns the name of the file in a multipart form body, which
+// must hold a single binary field
+func (g *generator) uploadField(s *schema) (string, error) {
+	if s != nil && s.Ref != "" {
+		s = g.schemas[refName(s.Ref)]
+	}
+	if s == nil || len(s.Properties) != 1 {
+		return "", fmt.Errorf("multipart body must have a single file field")
+	}
+	for name, prop := range s.Properties {
+		if prop.Format != "binary" {
+			return "", fmt.Errorf("multipart field %s must be binary", name)
+		}
+		return name, nil
+	}
+	return "", nil
+}
+
+// result returns the Go type of the first successful response body, or ""
+// when it has none. Non-JSON bodies are returned as text.
+func (g *generator) result(op *operation) (string, bool, error) {
+	var statuses []string
+	for status := range op.Responses {
+		if strings.HasPrefix(status, "2") {
+			statuses = append(statuses, status)
+		}
+	}
+	if len(statuses) == 0 {
+		return "", false, fmt.Errorf("no success response")
+	}
+	sort.Strings(statuses)
+
+	content := op.Responses[statuses[0]].Content
+	if len(content) == 0 {
+		return "", false, nil
+	}
+	media, ok := content["application/json"]
+	if !ok {
+		return "string", true, nil
+	}
+
+	t := goType(media.Schema)
+	if g.isStruct(media.Schema) {
+		t = "*" + t
+	}
+	return t, false, nil
+}
+
+// initialisms are upper-cased in Go names, following Go naming conventions
+var initialisms = map[string]bool{
+	"api": true, "csrf": true, "id": true, "jwks": true, "png": true, "totp": true, "uri": true, "url": true,
+}
+
+// exportedName converts a camelCase, snake_case or colon separated name to
+// an exported Go identifier
+func exportedName(name string) string {
+	var words []string
+	var word []rune
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, string(word))
+			word = nil
+		}
+	}
+	for i, r := range name {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case unicode.IsUpper(r) && i > 0 && len(word) > 0 && !unicode.IsUpper(word[len(word)-1]):
+			flush()
+			word = append(word, r)
+		case unicode.IsLower(r) && len(word) >= 2 && unicode.IsUpper(word[len(word)-1]) && unicode.IsUpper(word[len(word)-2]):
+			// The last capital of a run starts the next word, as in APIDocs
+			last := word[len(word)-1]
+			word = word[:len(word)-1]
+			flush()
+			word = append(word, last, r)
+		default:
+			word = append(word, r)
+		}
+	}
+	flush()
+
+	var out strings.Builder
+	for _, w := range words {
+		if initialisms[strings.ToLower(w)] {
+			out.WriteString(strings.ToUpper(w))
+			continue
+		}
+		// Plural initialisms keep a lower-case s, as in IDs
+		if plural, ok := strings.CutSuffix(strings.ToLower(w), "s"); ok && initialisms[plural] {
+			out.WriteString(strings.ToUpper(plural) + "s")
+			continue
+		}
+		runes := []rune(strings.ToLower(w))
+		runes[0] = unicode.ToUpper(runes[0])
+		out.WriteString(string(runes))
+	}
+	return out.String()
+}
+
+// enumName names an enum constant after its value; the wildcard "*" is All
+func enumName(value string) string {
+	if value == "*" {
+		return "All"
+	}
+	return exportedName(value)
+}
+
+// argName converts a parameter name to an unexported Go identifier
+func argName(name string) string {
+	exported := exportedName(name)
+	if exported == strings.ToUpper(exported) {
+		exported = strings.ToLower(exported)
+	} else {
+		exported = lowerFirst(exported)
+	}
+	if token.IsKeyword(exported) {
+		exported += "Param"
+	}
+	return exported
+}
+
+func writeComment(buf *bytes.Buffer, text string) {
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		if line != "" {
+			fmt.Fprintf(buf, "// %s\n", line)
+		}
+	}
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	runes := []rune(s)
+	runes[0] = unicode.ToLower(runes[0])
+	return string(runes)
+}
+
+func refName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
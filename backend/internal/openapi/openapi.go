@@ -0,0 +1,123 @@
+package openapi
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// The spec is maintained as YAML for readability and served as JSON. It is
+// embedded, so a document that fails to parse is caught by the tests rather
+// than at runtime.
+//
+//go:embed openapi.yaml
+var specYAML []byte
+
+var specJSON = mustJSON(specYAML)
+
+func mustJSON(data []byte) []byte {
+	var doc any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		panic(fmt.Sprintf("openapi.yaml: %v", err))
+	}
+	out, err := json.Marshal(doc)
+	if err != nil {
+		panic(fmt.Sprintf("openapi.yaml: %v", err))
+	}
+	return out
+}
+
+// JSON returns the OpenAPI document
+func JSON() []byte {
+	return bytes.Clone(specJSON)
+}
+
+// SpecHandler serves the OpenAPI document
+func SpecHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(specJSON)
+}
+
+// docsPage renders the spec with Swagger UI from a pinned CDN release
+const docsPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Toasted Coffee Co API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5.18.2/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5.18.2/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: "openapi.json", dom_id: "#swagger-ui" });
+  </script>
+</body>
+</html>
+`
+
+// DocsHandler serves an interactive documentation page for the spec
+func DocsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write([]byte(docsPage))
+}
+
+// Operation is a method and path documented in the spec
+type Operation struct {
+	ID         string
+	Method     string
+	Path       string
+	Permission string // x-permission, for admin routes
+}
+
+type document struct {
+	Paths map[string]map[string]json.RawMessage `json:"paths"`
+}
+
+var methods = map[string]bool{
+	"get": true, "put": true, "post": true, "delete": true, "patch": true, "head": true, "options": true,
+}
+
+// Operations lists every operation in the spec, sorted by path and method
+func Operations() ([]Operation, error) {
+	var doc document
+	if err := json.Unmarshal(specJSON, &doc); err != nil {
+		return nil, err
+	}
+
+	var ops []Operation
+	for path, item := range doc.Paths {
+		for method, raw := range item {
+			if !methods[method] {
+				continue
+			}
+			var op struct {
+				OperationID string `json:"operationId"`
+				Permission  string `json:"x-permission"`
+			}
+			if err := json.Unmarshal(raw, &op); err != nil {
+				return nil, fmt.Errorf("%s %s: %w", method, path, err)
+			}
+			ops = append(ops, Operation{
+				ID:         op.OperationID,
+				Method:     strings.ToUpper(method),
+				Path:       path,
+				Permission: op.Permission,
+			})
+		}
+	}
+
+	sort.Slice(ops, func(i, j int) bool {
+		if ops[i].Path != ops[j].Path {
+			return ops[i].Path < ops[j].Path
+		}
+		return ops[i].Method < ops[j].Method
+	})
+	return ops, nil
+}
@@ -0,0 +1,103 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/openapi"
+)
+
+func TestSpecIsValidJSON(t *testing.T) {
+	var doc map[string]interface{}
+	if err := json.Unmarshal(openapi.JSON(), &doc); err != nil {
+		t.Fatalf("Spec is not valid JSON: %v", err)
+	}
+	if doc["openapi"] != "3.1.0" {
+		t.Errorf("Expected OpenAPI 3.1.0, got %v", doc["openapi"])
+	}
+
+	ops, err := openapi.Operations()
+	if err != nil {
+		t.Fatalf("Failed to list operations: %v", err)
+	}
+	seen := map[string]bool{}
+	for _, op := range ops {
+		if seen[op.ID] {
+			t.Errorf("Duplicate operationId %q", op.ID)
+		}
+		seen[op.ID] = true
+	}
+}
+
+func TestValidateResponse(t *testing.T) {
+	validator, err := openapi.NewValidator()
+	if err != nil {
+		t.Fatalf("Failed to load spec: %v", err)
+	}
+
+	jsonHeader := http.Header{"Content-Type": {"application/json"}}
+	textHeader := http.Header{"Content-Type": {"text/plain; charset=utf-8"}}
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		status      int
+		header      http.Header
+		body        string
+		expectError bool
+	}{
+		{
+			name:   "Matching body",
+			method: "POST", path: "/api/v1/bookings", status: http.StatusCreated, header: jsonHeader,
+			body: `{"id": 7, "message": "Booking created successfully"}`,
+		},
+		{
+			name:   "Missing required property",
+			method: "POST", path: "/api/v1/bookings", status: http.StatusCreated, header: jsonHeader,
+			body:        `{"message": "Booking created successfully"}`,
+			expectError: true,
+		},
+		{
+			name:   "Wrong type in referenced schema",
+			method: "GET", path: "/api/v1/menu", status: http.StatusOK, header: jsonHeader,
+			body:        `[{"id": 1, "value": "oat", "label": "Oat", "type": "tea", "active": true, "createdAt": "2026-01-01T00:00:00Z", "updatedAt": "2026-01-01T00:00:00Z"}]`,
+			expectError: true,
+		},
+		{
+			name:   "Shared text error response",
+			method: "GET", path: "/api/v1/bookings/{id}", status: http.StatusNotFound, header: textHeader,
+			body: "Booking not found\n",
+		},
+		{
+			name:   "Undocumented status",
+			method: "GET", path: "/api/v1/bookings/{id}", status: http.StatusTeapot, header: textHeader,
+			expectError: true,
+		},
+		{
+			name:   "Undocumented content type",
+			method: "GET", path: "/api/v1/bookings/{id}", status: http.StatusOK, header: textHeader,
+			body:        "ok",
+			expectError: true,
+		},
+		{
+			name:   "Body on a no-content response",
+			method: "DELETE", path: "/api/v1/bookings/{id}", status: http.StatusNoContent, header: jsonHeader,
+			body:        `{}`,
+			expectError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validator.ValidateResponse(tc.method, tc.path, tc.status, tc.header, []byte(tc.body))
+			if tc.expectError && err == nil {
+				t.Error("Expected a validation error")
+			}
+			if !tc.expectError && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package openapi
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// specURL is the resource name the spec is registered under for $ref resolution
+const specURL = "openapi.json"
+
+// Validator checks HTTP responses against the response schemas in the spec
+type Validator struct {
+	doc      map[string]any
+	compiler *jsonschema.Compiler
+
+	mu      sync.Mutex
+	schemas map[string]*jsonschema.Schema
+}
+
+// NewValidator loads the spec for response validation
+func NewValidator() (*Validator, error) {
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(specJSON))
+	if err != nil {
+		return nil, err
+	}
+
+	compiler := jsonschema.NewCompiler()
+	compiler.DefaultDraft(jsonschema.Draft2020)
+	compiler.AssertFormat()
+	if err := compiler.AddResource(specURL, doc); err != nil {
+		return nil, err
+	}
+
+	return &Validator{
+		doc:      doc.(map[string]any),
+		compiler: compiler,
+		schemas:  map[string]*jsonschema.Schema{},
+	}, nil
+}
+
+// ValidateResponse checks that the status code is documented for the
+// operation at method and path (the spec's path template, e.g.
+// "/api/v1/bookings/{id}"), and that the body matches its schema
+func (v *Validator) ValidateResponse(method, path string, status int, header http.Header, body []byte) error {
+	method = strings.ToLower(method)
+	pointer := []string{"paths", path, method, "responses", strconv.Itoa(status)}
+	response, ok := lookup(v.doc, pointer...).(map[string]any)
+	if !ok {
+		return fmt.Errorf("%s %s: status %d is not documented", strings.ToUpper(method), path, status)
+	}
+
+	// Shared responses are referenced from components
+	if ref, ok := response["$ref"].(string); ok {
+		pointer = strings.Split(strings.TrimPrefix(ref, "#/"), "/")
+		if response, ok = lookup(v.doc, pointer...).(map[string]any); !ok {
+			return fmt.Errorf("unresolved response %s", ref)
+		}
+	}
+
+	content, _ := response["content"].(map[string]any)
+	if len(content) == 0 {
+		if len(bytes.TrimSpace(body)) > 0 {
+			return fmt.Errorf("status %d should have no body, got %q", status, body)
+		}
+		return nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		return fmt.Errorf("invalid Content-Type %q", header.Get("Content-Type"))
+	}
+	if _, ok := content[mediaType]; !ok {
+		return fmt.Errorf("status %d: Content-Type %s is not documented", status, mediaType)
+	}
+	if mediaType != "application/json" {
+		return nil
+	}
+
+	schema, err := v.schema(append(pointer, "content", mediaType, "schema"))
+	if err != nil {
+		return err
+	}
+	instance, err := jsonschema.UnmarshalJSON(bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid JSON body: %w", err)
+	}
+	return schema.Validate(instance)
+}
+
+// schema compiles the schema at pointer, caching it for later responses
+func (v *Validator) schema(pointer []string) (*jsonschema.Schema, error) {
+	escaped := make([]string, len(pointer))
+	for i, token := range pointer {
+		escaped[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+	}
+	loc := specURL + "#/" + strings.Join(escaped, "/")
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if schema, ok := v.schemas[loc]; ok {
+		return schema, nil
+	}
+	schema, err := v.compiler.Compile(loc)
+	if err != nil {
+		return nil, err
+	}
+	v.schemas[loc] = schema
+	return schema, nil
+}
+
+// lookup walks doc along pointer, returning nil if any step is missing
+func lookup(doc any, pointer ...string) any {
+	for _, token := range pointer {
+		obj, ok := doc.(map[string]any)
+		if !ok {
+			return nil
+		}
+		doc = obj[token]
+	}
+	return doc
+}
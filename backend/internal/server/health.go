@@ -0,0 +1,147 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Check is a readiness dependency check, e.g. pinging the database
+type Check struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+// CheckResult is the outcome of one dependency check in the /readyz response
+type CheckResult struct {
+	Status   string `json:"status"`
+	Duration string `json:"duration"`
+	Error    string `json:"error,omitempty"`
+}
+
+// ReadinessReport is the /readyz response body
+type ReadinessReport struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+// Readiness tracks whether this instance should receive traffic. It starts
+// not ready, is set once the server is listening and is cleared as soon as
+// shutdown begins so load balancers drain the instance first. While set,
+// every dependency check must also pass.
+type Readiness struct {
+	ready   atomic.Bool
+	timeout time.Duration
+	checks  []Check
+}
+
+// NewReadiness creates a readiness probe running checks with a per-check timeout
+func NewReadiness(timeout time.Duration, checks ...Check) *Readiness {
+	return &Readiness{timeout: timeout, checks: checks}
+}
+
+// SetReady marks the instance ready or not ready
+func (r *Readiness) SetReady(ready bool) {
+	r.ready.Store(ready)
+}
+
+// Ready reports whether the instance accepts traffic
+func (r *Readiness) Ready() bool {
+	return r.ready.Load()
+}
+
+// Check runs every dependency check concurrently and reports the results.
+// The report is "ok" only if the instance is ready and all checks pass.
+func (r *Readiness) Check(ctx context.Context) ReadinessReport {
+	report := ReadinessReport{Status: "ok", Checks: make(map[string]CheckResult, len(r.checks))}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, check := range r.checks {
+		wg.Add(1)
+		go func(check Check) {
+			defer wg.Done()
+			result := r.run(ctx, check)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[check.Name] = result
+			if result.Status != "ok" {
+				report.Status = "unavailable"
+			}
+		}(check)
+	}
+	wg.Wait()
+
+	if !r.Ready() {
+		report.Status = "shutting_down"
+	}
+	return report
+}
+
+func (r *Readiness) run(ctx context.Context, check Check) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check.Run(ctx)
+	result := CheckResult{Status: "ok", Duration: time.Since(start).Round(time.Millisecond).String()}
+	if err == nil {
+		return result
+	}
+
+	// Probes are public, so details only go to the log
+	slog.WarnContext(ctx, "readiness check failed", "check", check.Name, "error", err)
+	result.Status = "fail"
+	result.Error = "unavailable"
+	if errors.Is(err, context.DeadlineExceeded) {
+		result.Error = "timed out"
+	}
+	return result
+}
+
+// livezHandler reports that the process is up. It checks no dependencies, so
+// an orchestrator only restarts the instance when it is actually wedged.
+func livezHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+}
+
+// readyzHandler reports whether the instance should receive traffic, with a
+// breakdown per dependency
+func readyzHandler(readiness *Readiness) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		report := readiness.Check(r.Context())
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		if report.Status != "ok" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(report)
+	}
+}
+
+// healthHandler is the original uptime endpoint, kept for existing monitors.
+// It follows the readiness flag but doesn't run dependency checks.
+func healthHandler(readiness *Readiness) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		status, code := "ok", http.StatusOK
+		if !readiness.Ready() {
+			status, code = "shutting_down", http.StatusServiceUnavailable
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":    status,
+			"timestamp": time.Now().Format(time.RFC3339),
+			"uptime":    time.Since(serviceStartTime).String(),
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package server_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/server"
+)
+
+func passingCheck(name string) server.Check {
+	return server.Check{Name: name, Run: func(ctx context.Context) error { return nil }}
+}
+
+func TestReadinessCheck(t *testing.T) {
+	failing := server.Check{Name: "database", Run: func(ctx context.Context) error {
+		return errors.New("connection refused")
+	}}
+	slow := server.Check{Name: "smtp", Run: func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}}
+
+	tests := []struct {
+		name           string
+		ready          bool
+		checks         []server.Check
+		expectedStatus string
+		expectedErrors map[string]string
+	}{
+		{
+			name:           "All checks pass",
+			ready:          true,
+			checks:         []server.Check{passingCheck("database"), passingCheck("migrations")},
+			expectedStatus: "ok",
+		},
+		{
+			name:           "Failing dependency",
+			ready:          true,
+			checks:         []server.Check{failing, passingCheck("migrations")},
+			expectedStatus: "unavailable",
+			expectedErrors: map[string]string{"database": "unavailable"},
+		},
+		{
+			name:           "Check times out",
+			ready:          true,
+			checks:         []server.Check{passingCheck("database"), slow},
+			expectedStatus: "unavailable",
+			expectedErrors: map[string]string{"smtp": "timed out"},
+		},
+		{
+			name:           "Shutting down",
+			ready:          false,
+			checks:         []server.Check{passingCheck("database")},
+			expectedStatus: "shutting_down",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			readiness := server.NewReadiness(20*time.Millisecond, tc.checks...)
+			readiness.SetReady(tc.ready)
+
+			report := readiness.Check(context.Background())
+
+			if report.Status != tc.expectedStatus {
+				t.Errorf("Expected status %q, got %q", tc.expectedStatus, report.Status)
+			}
+			if len(report.Checks) != len(tc.checks) {
+				t.Errorf("Expected %d check results, got %d", len(tc.checks), len(report.Checks))
+			}
+			for name, result := range report.Checks {
+				if expected := tc.expectedErrors[name]; result.Error != expected {
+					t.Errorf("Expected %s error %q, got %q", name, expected, result.Error)
+				}
+			}
+		})
+	}
+}
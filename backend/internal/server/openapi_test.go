@@ -0,0 +1,64 @@
+package server_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/auth"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/config"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/database"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/handlers"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/metrics"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/openapi"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/server"
+)
+
+// TestSpecCoversRoutes checks that every route the router serves is in the
+// OpenAPI spec and that the spec documents no route that doesn't exist
+func TestSpecCoversRoutes(t *testing.T) {
+	cfg := config.Default()
+	// Register the optional routes too
+	cfg.Features.DebugEndpoints = true
+	cfg.Metrics.Enabled = true
+
+	tokens, err := auth.NewTokenService(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create token service: %v", err)
+	}
+	h := handlers.NewHandlers(cfg, &database.Repositories{}, tokens, nil, nil, nil, "", handlers.AuthOptions{})
+	router := server.NewRouter(h, tokens, nil, cfg, server.NewReadiness(time.Second), metrics.New())
+
+	routes := map[string]bool{}
+	err = chi.Walk(router, func(method, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		routes[method+" "+route] = true
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to walk routes: %v", err)
+	}
+
+	ops, err := openapi.Operations()
+	if err != nil {
+		t.Fatalf("Failed to read spec: %v", err)
+	}
+	documented := map[string]bool{}
+	for _, op := range ops {
+		documented[op.Method+" "+op.Path] = true
+		if op.ID == "" {
+			t.Errorf("%s %s has no operationId", op.Method, op.Path)
+		}
+	}
+
+	for route := range routes {
+		if !documented[route] {
+			t.Errorf("Route %s is not in openapi.yaml", route)
+		}
+	}
+	for op := range documented {
+		if !routes[op] {
+			t.Errorf("openapi.yaml documents %s, which the router does not serve", op)
+		}
+	}
+}
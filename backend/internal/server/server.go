@@ -2,7 +2,7 @@ package server
 
 import (
 	"encoding/json"
-	"log"
+	"log/slog"
 	"net/http"
 	"strings"
 	"time"
@@ -10,131 +10,244 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/httprate"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/auth"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/blob"
 	"github.com/joshuagudgel/toasted-coffee/backend/internal/config"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/database"
 	"github.com/joshuagudgel/toasted-coffee/backend/internal/handlers"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/metrics"
 	custommiddleware "github.com/joshuagudgel/toasted-coffee/backend/internal/middleware"
-)
-
-const (
-	PublicReadLimit  = 100
-	PublicWriteLimit = 10
-	ContactLimit     = 5
-	AuthLimit        = 20
-	AdminLimit       = 200
-	HealthCheckLimit = 60
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/openapi"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/tracing"
 )
 
 var serviceStartTime = time.Now()
 
-func NewRouter(h *handlers.Handlers, cfg *config.Config) *chi.Mux {
+func NewRouter(h *handlers.Handlers, tokens *auth.TokenService, users database.UserRepositoryInterface,
+	cfg *config.Config, readiness *Readiness, m *metrics.Metrics) *chi.Mux {
 	mainRouter := chi.NewRouter()
+	mainRouter.Use(custommiddleware.RequestID)
+	mainRouter.Use(tracing.Middleware)
+	mainRouter.Use(m.Middleware)
+
+	limits := rateLimits{RateLimitConfig: cfg.RateLimits, metrics: m}
 
 	// Mount sub-routers for better organization
-	mainRouter.Mount("/", newMonitorRouter())
-	mainRouter.Mount("/api", newAPIRouter(h, cfg))
+	mainRouter.Mount("/", newMonitorRouter(cfg, readiness, limits))
+
+	// Scraped on the main port only when no separate metrics address is set
+	if cfg.Metrics.Enabled && cfg.Metrics.ListenAddr == "" {
+		mainRouter.Method(http.MethodGet, "/metrics", m.Handler(cfg.Metrics.Token))
+	}
+
+	// Public keys for verifying our JWTs
+	mainRouter.Get("/.well-known/jwks.json", h.Auth.JWKS)
+	mainRouter.Mount("/api", newAPIRouter(h, tokens, users, cfg, limits))
+
+	// Uploaded images, when they are kept on local disk
+	if cfg.Media.Backend == "local" {
+		media := http.StripPrefix("/media", blob.NewLocalStore(cfg.Media.Dir, cfg.Media.BaseURL))
+		mainRouter.With(limits.byIP("public_read", limits.PublicRead)).Get("/media/{key}", media.ServeHTTP)
+	}
 
 	return mainRouter
 }
 
-func newMonitorRouter() *chi.Mux {
+func newMonitorRouter(cfg *config.Config, readiness *Readiness, limits rateLimits) *chi.Mux {
 	router := chi.NewRouter()
+	router.Use(limits.byIP("health_check", limits.HealthCheck))
 
-	router.Get("/health", healthHandler)
-	router.Get("/ping-simple", pingSimpleHandler)
-	router.Get("/ping", pingHandler)
-	router.Get("/test-render", testRenderHandler)
+	router.Get("/livez", livezHandler)
+	router.Get("/readyz", readyzHandler(readiness))
+	router.Get("/health", healthHandler(readiness))
+
+	// Debug endpoints for checking the deployment by hand
+	if cfg.Features.DebugEndpoints {
+		router.Get("/ping-simple", pingSimpleHandler)
+		router.Get("/ping", pingHandler)
+		router.Get("/test-render", testRenderHandler)
+	}
 
 	return router
 }
 
-func newAPIRouter(h *handlers.Handlers, cfg *config.Config) *chi.Mux {
+func newAPIRouter(h *handlers.Handlers, tokens *auth.TokenService, users database.UserRepositoryInterface,
+	cfg *config.Config, limits rateLimits) *chi.Mux {
 	router := chi.NewRouter()
 
 	// Common middleware
-	router.Use(custommiddleware.SecureHTTPS)
-	router.Use(custommiddleware.SecurityHeaders)
-	router.Use(middleware.Logger)
+	router.Use(custommiddleware.SecureHTTPS(cfg.IsProduction()))
+	router.Use(custommiddleware.SecurityHeaders(cfg.IsProduction()))
+	router.Use(custommiddleware.RequestLogger)
 	router.Use(middleware.Recoverer)
-	router.Use(custommiddleware.CORS(cfg.AllowOrigins))
+	router.Use(custommiddleware.CORS(cfg.CORS.AllowOrigins, cfg.IsProduction()))
 
 	router.Route("/v1", func(r chi.Router) {
-		setupPublicRoutes(r, h)
-		setupAuthRoutes(r, h)
-		setupAdminRoutes(r, h)
+		setupPublicRoutes(r, h, limits)
+		setupAuthRoutes(r, h, limits)
+		setupAdminRoutes(r, h, tokens, users, limits)
 	})
 
 	return router
 }
 
-func setupPublicRoutes(r chi.Router, h *handlers.Handlers) {
+func setupPublicRoutes(r chi.Router, h *handlers.Handlers, limits rateLimits) {
 	// Public read-only endpoints
 	r.Group(func(r chi.Router) {
-		r.Use(httprate.LimitByIP(PublicReadLimit, 1*time.Minute))
+		r.Use(limits.byIP("public_read", limits.PublicRead))
 		r.Get("/menu", h.Menu.GetAll)
 		r.Get("/menu/{type}", h.Menu.GetByType)
 		r.Get("/packages", h.Package.GetAll)
+
+		// API description and its docs UI
+		r.Get("/openapi.json", openapi.SpecHandler)
+		r.Get("/docs", openapi.DocsHandler)
 	})
 
 	// Public write endpoints
 	r.Group(func(r chi.Router) {
-		r.Use(httprate.LimitByIP(PublicWriteLimit, 1*time.Minute))
+		r.Use(limits.byIP("public_write", limits.PublicWrite))
 		r.Post("/bookings", h.Booking.Create)
 	})
 
 	// Contact endpoint
-	r.With(httprate.LimitByIP(ContactLimit, 1*time.Minute)).
+	r.With(limits.byIP("contact", limits.Contact)).
 		Post("/contact", h.Contact.HandleInquiry)
 }
 
-func setupAuthRoutes(r chi.Router, h *handlers.Handlers) {
+func setupAuthRoutes(r chi.Router, h *handlers.Handlers, limits rateLimits) {
 	r.Group(func(r chi.Router) {
-		r.Use(httprate.LimitByIP(AuthLimit, 1*time.Minute))
+		r.Use(limits.byIP("auth", limits.Auth))
 		r.Post("/auth/login", h.Auth.Login)
 		r.Post("/auth/refresh", h.Auth.RefreshToken)
 		r.Post("/auth/logout", h.Auth.Logout)
+
+		// Second login step, and forced enrollment using the login challenge token
+		r.Post("/auth/2fa/verify", h.Auth.VerifyTwoFactor)
+		r.Post("/auth/2fa/setup", h.Auth.EnrollTwoFactor)
+		r.Post("/auth/2fa/setup/confirm", h.Auth.ConfirmTwoFactor)
+
+		// Forgotten password: request an emailed link, then redeem its token
+		r.Post("/auth/password/forgot", h.Auth.ForgotPassword)
+		r.Post("/auth/password/reset", h.Auth.ResetPassword)
+
+		// First-run setup, disabled once the initial admin exists
+		r.Get("/setup", h.Setup.Status)
+		r.Post("/setup", h.Setup.CreateAdmin)
 	})
 }
 
-func setupAdminRoutes(r chi.Router, h *handlers.Handlers) {
+func setupAdminRoutes(r chi.Router, h *handlers.Handlers, tokens *auth.TokenService,
+	users database.UserRepositoryInterface, limits rateLimits) {
 	r.Group(func(r chi.Router) {
-		r.Use(custommiddleware.JWTAuth)
-		r.Use(httprate.LimitByIP(AdminLimit, 1*time.Minute))
+		r.Use(custommiddleware.JWTAuth(tokens, users))
+		r.Use(custommiddleware.CSRFProtect)
+		r.Use(limits.byIP("admin", limits.Admin))
 
 		// Booking routes
-		r.Get("/bookings", h.Booking.GetAll)
-		r.Get("/bookings/{id}", h.Booking.GetByID)
-		r.Put("/bookings/{id}", h.Booking.Update)
-		r.Delete("/bookings/{id}", h.Booking.Delete)
-		r.Post("/bookings/{id}/archive", h.Booking.Archive)
-		r.Post("/bookings/{id}/unarchive", h.Booking.Unarchive)
+		r.With(requirePermission(auth.PermBookingsRead)).Get("/bookings", h.Booking.GetAll)
+		r.With(requirePermission(auth.PermBookingsRead)).Get("/bookings/export", h.Booking.Export)
+		r.With(requirePermission(auth.PermBookingsRead)).Get("/bookings/{id}", h.Booking.GetByID)
+		r.With(requirePermission(auth.PermBookingsWrite)).Post("/bookings/bulk", h.Booking.Bulk)
+		r.With(requirePermission(auth.PermBookingsWrite)).Post("/bookings/import", h.Booking.Import)
+		r.With(requirePermission(auth.PermBookingsWrite)).Put("/bookings/{id}", h.Booking.Update)
+		r.With(requirePermission(auth.PermBookingsDelete)).Delete("/bookings/{id}", h.Booking.Delete)
+		r.With(requirePermission(auth.PermBookingsWrite)).Post("/bookings/{id}/archive", h.Booking.Archive)
+		r.With(requirePermission(auth.PermBookingsWrite)).Post("/bookings/{id}/unarchive", h.Booking.Unarchive)
+
+		// Reports
+		r.With(requirePermission(auth.PermReportsRead)).Get("/reports/volume", h.Report.Volume)
+		r.With(requirePermission(auth.PermReportsRead)).Get("/reports/revenue", h.Report.Revenue)
+		r.With(requirePermission(auth.PermReportsRead)).Get("/reports/menu", h.Report.Menu)
+		r.With(requirePermission(auth.PermReportsRead)).Get("/reports/summary", h.Report.Summary)
+
+		// Live booking and inquiry changes for the dashboard
+		r.With(requirePermission(auth.PermBookingsRead)).Get("/events/stream", h.Events.Stream)
 
 		// Menu routes
-		r.Post("/menu", h.Menu.Create)
-		r.Put("/menu/{id}", h.Menu.Update)
-		r.Delete("/menu/{id}", h.Menu.Delete)
+		r.With(requirePermission(auth.PermMenuWrite)).Post("/menu", h.Menu.Create)
+		r.With(requirePermission(auth.PermMenuWrite)).Put("/menu/order", h.Menu.Reorder)
+		r.With(requirePermission(auth.PermMenuWrite)).Put("/menu/{id}", h.Menu.Update)
+		r.With(requirePermission(auth.PermMenuWrite)).Delete("/menu/{id}", h.Menu.Delete)
+		r.With(requirePermission(auth.PermMenuWrite)).Post("/menu/{id}/image", h.Menu.UploadImage)
+		r.With(requirePermission(auth.PermMenuWrite)).Delete("/menu/{id}/image", h.Menu.DeleteImage)
+
+		// Ingredients, recipes and the prep sheet
+		r.With(requirePermission(auth.PermBookingsRead)).Get("/ingredients", h.Inventory.GetIngredients)
+		r.With(requirePermission(auth.PermMenuWrite)).Post("/ingredients", h.Inventory.CreateIngredient)
+		r.With(requirePermission(auth.PermMenuWrite)).Put("/ingredients/{id}", h.Inventory.UpdateIngredient)
+		r.With(requirePermission(auth.PermMenuWrite)).Delete("/ingredients/{id}", h.Inventory.DeleteIngredient)
+		r.With(requirePermission(auth.PermBookingsRead)).Get("/recipes", h.Inventory.GetRecipes)
+		r.With(requirePermission(auth.PermMenuWrite)).Put("/recipes/{menuItemId}", h.Inventory.SetRecipe)
+		r.With(requirePermission(auth.PermBookingsRead)).Get("/prep-sheet", h.Inventory.PrepSheet)
 
 		// Package routes
-		r.Post("/packages", h.Package.Create)
-		r.Get("/packages/{id}", h.Package.GetByID)
-		r.Put("/packages/{id}", h.Package.Update)
-		r.Delete("/packages/{id}", h.Package.Delete)
+		r.With(requirePermission(auth.PermPackagesWrite)).Post("/packages", h.Package.Create)
+		r.With(requirePermission(auth.PermPackagesRead)).Get("/packages/{id}", h.Package.GetByID)
+		r.With(requirePermission(auth.PermPackagesWrite)).Put("/packages/{id}", h.Package.Update)
+		r.With(requirePermission(auth.PermPackagesWrite)).Delete("/packages/{id}", h.Package.Delete)
+
+		// User management routes
+		r.With(requirePermission(auth.PermUsersManage)).Get("/users", h.User.GetAll)
+		r.With(requirePermission(auth.PermUsersManage)).Get("/users/roles", h.User.Roles)
+		r.With(requirePermission(auth.PermUsersManage)).Get("/users/{id}", h.User.GetByID)
+		r.With(requirePermission(auth.PermUsersManage)).Post("/users", h.User.Create)
+		r.With(requirePermission(auth.PermUsersManage)).Put("/users/{id}", h.User.Update)
+		r.With(requirePermission(auth.PermUsersManage)).Delete("/users/{id}", h.User.Delete)
+		r.With(requirePermission(auth.PermUsersManage)).Post("/users/{id}/unlock", h.User.Unlock)
+		r.With(requirePermission(auth.PermUsersManage)).Post("/users/{id}/2fa/reset", h.User.ResetTwoFactor)
+		r.With(requirePermission(auth.PermUsersManage)).Get("/users/{id}/sessions", h.User.GetSessions)
+		r.With(requirePermission(auth.PermUsersManage)).Post("/users/{id}/sessions/revoke", h.User.RevokeSessions)
+
+		// Webhook subscriptions, test deliveries and the delivery log
+		r.With(requirePermission(auth.PermWebhooksManage)).Get("/webhooks", h.Webhook.GetAll)
+		r.With(requirePermission(auth.PermWebhooksManage)).Post("/webhooks", h.Webhook.Create)
+		r.With(requirePermission(auth.PermWebhooksManage)).Get("/webhooks/{id}", h.Webhook.GetByID)
+		r.With(requirePermission(auth.PermWebhooksManage)).Put("/webhooks/{id}", h.Webhook.Update)
+		r.With(requirePermission(auth.PermWebhooksManage)).Delete("/webhooks/{id}", h.Webhook.Delete)
+		r.With(requirePermission(auth.PermWebhooksManage)).Post("/webhooks/{id}/test", h.Webhook.Test)
+		r.With(requirePermission(auth.PermWebhooksManage)).Get("/webhooks/{id}/deliveries", h.Webhook.GetDeliveries)
+
+		// Effective configuration with secrets masked
+		r.With(requirePermission(auth.PermConfigRead)).Get("/config", h.Config.Get)
 
-		// Auth validation
+		// Auth validation and session management for the current user
 		r.Get("/auth/validate", h.Auth.ValidateToken)
+		r.Post("/auth/sessions/revoke", h.Auth.RevokeAllSessions)
+		r.Post("/auth/password", h.Auth.ChangePassword)
+
+		// Two-factor management for the current user
+		r.Get("/auth/2fa", h.Auth.TwoFactorStatus)
+		r.Post("/auth/2fa/enroll", h.Auth.EnrollTwoFactor)
+		r.Post("/auth/2fa/enroll/confirm", h.Auth.ConfirmTwoFactor)
+		r.Post("/auth/2fa/disable", h.Auth.DisableTwoFactor)
+		r.Post("/auth/2fa/recovery-codes", h.Auth.RegenerateRecoveryCodes)
 	})
 }
 
-// Monitor handler functions
-func healthHandler(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"status":    "ok",
-		"timestamp": time.Now().Format(time.RFC3339),
-		"uptime":    time.Since(serviceStartTime).String(),
-	})
+// rateLimits builds the per-IP limiter for each route group
+type rateLimits struct {
+	config.RateLimitConfig
+	metrics *metrics.Metrics
+}
+
+// byIP allows requestsPerMinute per client IP and counts rejections under group
+func (l rateLimits) byIP(group string, requestsPerMinute int) func(http.Handler) http.Handler {
+	return httprate.Limit(requestsPerMinute, 1*time.Minute,
+		httprate.WithKeyFuncs(httprate.KeyByIP),
+		httprate.WithLimitHandler(func(w http.ResponseWriter, r *http.Request) {
+			l.metrics.RateLimited(group)
+			http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+		}),
+	)
 }
 
+// requirePermission is shorthand for the permission middleware on a single admin route
+func requirePermission(perm auth.Permission) func(http.Handler) http.Handler {
+	return custommiddleware.RequirePermission(perm)
+}
+
+// Debug handler functions
 func pingSimpleHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "text/plain")
 	w.WriteHeader(http.StatusOK)
@@ -143,15 +256,9 @@ func pingSimpleHandler(w http.ResponseWriter, r *http.Request) {
 
 func pingHandler(w http.ResponseWriter, r *http.Request) {
 	requestTime := time.Now()
-	log.Printf("PING REQUEST: time=%v, ip=%s, user_agent=%s",
-		requestTime.Format(time.RFC3339),
-		r.RemoteAddr,
-		r.Header.Get("User-Agent"))
-
 	userAgent := r.Header.Get("User-Agent")
-	if strings.Contains(strings.ToLower(userAgent), "cron") {
-		log.Printf("PING: CRON JOB DETECTED - UserAgent: %s", userAgent)
-	}
+	slog.InfoContext(r.Context(), "ping received", "remote_addr", r.RemoteAddr, "user_agent", userAgent,
+		"cron", strings.Contains(strings.ToLower(userAgent), "cron"))
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
@@ -160,12 +267,12 @@ func pingHandler(w http.ResponseWriter, r *http.Request) {
 		"timestamp": requestTime.Format(time.RFC3339),
 	})
 
-	log.Printf("PING SUCCESS: Response sent in %v", time.Since(requestTime))
+	slog.DebugContext(r.Context(), "ping answered", "duration_ms", time.Since(requestTime).Milliseconds())
 }
 
 func testRenderHandler(w http.ResponseWriter, r *http.Request) {
-	log.Printf("RENDER TEST: Request received at %v", time.Now())
+	slog.InfoContext(r.Context(), "render test received")
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte("OK"))
-	log.Printf("RENDER TEST: Response sent")
+	slog.DebugContext(r.Context(), "render test answered")
 }
@@ -1,13 +1,21 @@
 package services
 
 import (
+	"context"
 	"crypto/tls"
 	"fmt"
-	"log"
-	"os"
+	"log/slog"
+	"net"
+	"strconv"
+	"sync"
 	"time"
 
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/config"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/metrics"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/tracing"
 	"github.com/microcosm-cc/bluemonday"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"gopkg.in/mail.v2"
 )
 
@@ -17,58 +25,116 @@ type EmailService struct {
 	from      string
 	to        string
 	sanitizer *bluemonday.Policy
-}
 
-// NewEmailService creates a new email service
-func NewEmailService() *EmailService {
-	// Read configuration from environment variables
-	smtpHost := os.Getenv("SMTP_HOST")
-	if smtpHost == "" {
-		log.Println("WARNING: SMTP host not set in environment variables")
-		smtpHost = "smtp.gmail.com" // Default to Gmail
-	}
+	// background tracks emails sent with Async so shutdown can wait for them
+	background sync.WaitGroup
 
-	smtpPort := 587 // Default SMTP port
+	metrics *metrics.Metrics
+}
 
-	smtpUser := os.Getenv("SMTP_USER")
-	if smtpUser == "" {
-		log.Println("WARNING: SMTP user not set in environment variables")
+// NewEmailService creates a new email service
+func NewEmailService(cfg config.MailConfig) *EmailService {
+	ctx := context.Background()
+	if cfg.SMTPUser == "" {
+		slog.WarnContext(ctx, "SMTP user not configured")
 	}
 
-	smtpPass := os.Getenv("SMTP_PASSWORD")
-	if smtpPass == "" {
-		log.Println("WARNING: SMTP password not set in environment variables")
+	if cfg.SMTPPassword == "" {
+		slog.WarnContext(ctx, "SMTP password not configured")
 	}
 
-	toEmail := os.Getenv("NOTIFICATION_EMAIL")
-	if toEmail == "" {
-		log.Println("WARNING: Notification email not set in environment variables")
+	if cfg.NotificationEmail == "" {
+		slog.WarnContext(ctx, "notification email not configured")
 	}
 
 	// Create the dialer
-	dialer := mail.NewDialer(smtpHost, smtpPort, smtpUser, smtpPass)
+	dialer := mail.NewDialer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUser, cfg.SMTPPassword)
 
 	// Configure TLS
 	dialer.TLSConfig = &tls.Config{
-		ServerName: smtpHost,
+		ServerName: cfg.SMTPHost,
 	}
 
 	sanitizer := bluemonday.StrictPolicy()
 	return &EmailService{
 		dialer:    dialer,
-		from:      smtpUser,
-		to:        toEmail,
+		from:      cfg.SMTPUser,
+		to:        cfg.NotificationEmail,
 		sanitizer: sanitizer,
 	}
 }
 
+// SetMetrics counts sent and failed emails in m
+func (s *EmailService) SetMetrics(m *metrics.Metrics) {
+	s.metrics = m
+}
+
+// send delivers m and records the outcome under kind. ctx only carries the
+// request ID for logging; a send is never cut short by a finished request.
+func (s *EmailService) send(ctx context.Context, kind string, m *mail.Message) error {
+	ctx, span := tracing.Tracer().Start(ctx, "email "+kind,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("email.kind", kind)),
+	)
+	defer span.End()
+
+	start := time.Now()
+	err := s.dialer.DialAndSend(m)
+	s.metrics.EmailSent(kind, err)
+	tracing.RecordError(span, err)
+
+	if err != nil {
+		slog.ErrorContext(ctx, "email send failed", "kind", kind, "error", err)
+	} else {
+		slog.InfoContext(ctx, "email sent", "kind", kind, "duration_ms", time.Since(start).Milliseconds())
+	}
+	return err
+}
+
+// Async sends an email in the background, e.g. so response times don't
+// reveal whether one went out. Drain waits for it on shutdown.
+func (s *EmailService) Async(send func()) {
+	s.background.Add(1)
+	go func() {
+		defer s.background.Done()
+		send()
+	}()
+}
+
+// Drain waits for emails started with Async to finish, or for ctx to expire
+func (s *EmailService) Drain(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.background.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("pending emails not sent: %w", ctx.Err())
+	}
+}
+
+// Ping checks that the SMTP server accepts TCP connections. It doesn't log in,
+// so it is cheap enough for readiness probes.
+func (s *EmailService) Ping(ctx context.Context) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(s.dialer.Host, strconv.Itoa(s.dialer.Port)))
+	if err != nil {
+		return fmt.Errorf("SMTP server unreachable: %w", err)
+	}
+	return conn.Close()
+}
+
 // sanitizeInput sanitizes user input to prevent XSS attacks
 func (s *EmailService) sanitizeInput(input string) string {
 	return s.sanitizer.Sanitize(input)
 }
 
 // SendBookingConfirmation sends an email notification for a successful booking
-func (s *EmailService) SendBookingConfirmation(bookingID int, name, date, time, location string, people int, pkg string) error {
+func (s *EmailService) SendBookingConfirmation(ctx context.Context, bookingID int, name, date, time, location string, people int, pkg string) error {
 	// Sanitize all user inputs
 	name = s.sanitizeInput(name)
 	date = s.sanitizeInput(date)
@@ -101,11 +167,11 @@ func (s *EmailService) SendBookingConfirmation(bookingID int, name, date, time,
     `, bookingID, name, date, time, location, people, pkg))
 
 	// Send the email
-	return s.dialer.DialAndSend(m)
+	return s.send(ctx, "booking_confirmation", m)
 }
 
 // SendBookingFailureAlert sends an email notification for a failed booking attempt
-func (s *EmailService) SendBookingFailureAlert(name, email, phone string, errorDetails string) error {
+func (s *EmailService) SendBookingFailureAlert(ctx context.Context, name, email, phone string, errorDetails string) error {
 	// Sanitize all user inputs
 	name = s.sanitizeInput(name)
 	email = s.sanitizeInput(email)
@@ -145,11 +211,66 @@ func (s *EmailService) SendBookingFailureAlert(name, email, phone string, errorD
     `, name, contactInfo, errorDetails))
 
 	// Send the email
-	return s.dialer.DialAndSend(m)
+	return s.send(ctx, "booking_failure_alert", m)
+}
+
+// SendAccountLockedAlert notifies the business, and the account holder when an
+// email is on file, that an admin account was locked after repeated failed logins
+func (s *EmailService) SendAccountLockedAlert(ctx context.Context, username, userEmail string, lockedUntil time.Time, remoteAddr string) error {
+	username = s.sanitizeInput(username)
+	remoteAddr = s.sanitizeInput(remoteAddr)
+
+	m := mail.NewMessage()
+
+	// Set headers
+	m.SetHeader("From", fmt.Sprintf("Toasted Coffee Co Support <%s>", s.from))
+	m.SetHeader("To", s.to)
+	if userEmail != "" {
+		m.SetHeader("Cc", userEmail)
+	}
+	m.SetHeader("Subject", fmt.Sprintf("SECURITY: Admin account %s locked", username))
+
+	// Set email body with HTML
+	m.SetBody("text/html", fmt.Sprintf(`
+        <h2>Admin Account Locked</h2>
+        <p>The admin account <strong>%s</strong> was temporarily locked after too many failed login attempts.</p>
+        <ul>
+            <li><strong>Locked until:</strong> %s</li>
+            <li><strong>Last attempt from:</strong> %s</li>
+        </ul>
+        <p>If this wasn't you, consider changing the password. An owner can unlock the account early from the admin dashboard.</p>
+    `, username, lockedUntil.Format("January 2, 2006 at 3:04 PM MST"), remoteAddr))
+
+	// Send the email
+	return s.send(ctx, "account_locked", m)
+}
+
+// SendPasswordReset emails an admin a link to choose a new password
+func (s *EmailService) SendPasswordReset(ctx context.Context, toEmail, username, resetURL string, expiresIn time.Duration) error {
+	username = s.sanitizeInput(username)
+
+	m := mail.NewMessage()
+
+	// Set headers
+	m.SetHeader("From", fmt.Sprintf("Toasted Coffee Co Support <%s>", s.from))
+	m.SetHeader("To", toEmail)
+	m.SetHeader("Subject", "Reset your Toasted Coffee Co admin password")
+
+	// Set email body with HTML
+	m.SetBody("text/html", fmt.Sprintf(`
+        <h2>Password Reset</h2>
+        <p>A password reset was requested for the admin account <strong>%s</strong>.</p>
+        <p><a href="%s">Choose a new password</a></p>
+        <p>This link expires in %d minutes and can only be used once.</p>
+        <p>If you didn't request this, you can ignore this email; your password won't change.</p>
+    `, username, resetURL, int(expiresIn.Minutes())))
+
+	// Send the email
+	return s.send(ctx, "password_reset", m)
 }
 
 // SendInquiry sends an email notification for customer inquiries or contact form submissions
-func (s *EmailService) SendInquiry(name, email, phone, message string) error {
+func (s *EmailService) SendInquiry(ctx context.Context, name, email, phone, message string) error {
 	// Sanitize all user inputs
 	name = s.sanitizeInput(name)
 	email = s.sanitizeInput(email)
@@ -159,7 +280,7 @@ func (s *EmailService) SendInquiry(name, email, phone, message string) error {
 	// Recover from panic
 	defer func() {
 		if r := recover(); r != nil {
-			log.Printf("RECOVERED from email panic: %v", r)
+			slog.ErrorContext(ctx, "recovered from email panic", "panic", r)
 		}
 	}()
 
@@ -198,5 +319,5 @@ func (s *EmailService) SendInquiry(name, email, phone, message string) error {
     `, name, contactInfo, message, time.Now().Format("January 2, 2006 at 3:04 PM")))
 
 	// Send the email
-	return s.dialer.DialAndSend(m)
+	return s.send(ctx, "inquiry", m)
 }
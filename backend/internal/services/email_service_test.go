@@ -1,11 +1,15 @@
 package services
 
 import (
+	"context"
 	"testing"
+	"time"
+
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/config"
 )
 
 func TestSanitizeInput(t *testing.T) {
-	emailService := NewEmailService()
+	emailService := NewEmailService(config.Default().Mail)
 
 	tests := []struct {
 		name     string
@@ -79,3 +83,29 @@ func TestSanitizeInput(t *testing.T) {
 		})
 	}
 }
+
+func TestDrainWaitsForAsyncEmails(t *testing.T) {
+	emailService := NewEmailService(config.Default().Mail)
+
+	release := make(chan struct{})
+	sent := false
+	emailService.Async(func() {
+		<-release
+		sent = true
+	})
+
+	// Drain gives up when the deadline passes first
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := emailService.Drain(ctx); err == nil {
+		t.Error("Expected Drain to time out while an email is pending")
+	}
+
+	close(release)
+	if err := emailService.Drain(context.Background()); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if !sent {
+		t.Error("Expected Drain to return after the email was sent")
+	}
+}
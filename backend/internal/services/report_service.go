@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/config"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/database"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
+)
+
+// ReportService serves the admin reports, keeping each one for the cache TTL
+// so dashboards polling the same range don't repeat the aggregate queries.
+// Reports are cached per instance; a booking change shows up once the cached
+// report expires.
+type ReportService struct {
+	repo database.ReportRepositoryInterface
+	ttl  time.Duration
+	now  func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]cachedReport
+}
+
+// cachedReport is a report and when it stops being served
+type cachedReport struct {
+	report  interface{}
+	expires time.Time
+}
+
+// NewReportService creates a new report service
+func NewReportService(repo database.ReportRepositoryInterface, cfg config.ReportsConfig) *ReportService {
+	return &ReportService{
+		repo:  repo,
+		ttl:   cfg.CacheTTL,
+		now:   time.Now,
+		cache: make(map[string]cachedReport),
+	}
+}
+
+// SetClock replaces the time source used to expire cached reports
+func (s *ReportService) SetClock(now func() time.Time) {
+	s.now = now
+}
+
+// CacheTTL returns how long reports are cached
+func (s *ReportService) CacheTTL() time.Duration {
+	return s.ttl
+}
+
+// Volume returns bookings and headcount per week or month
+func (s *ReportService) Volume(ctx context.Context, interval string, rng models.ReportRange) (*models.VolumeReport, error) {
+	return cached(s, "volume/"+interval+"/"+rng.From+"/"+rng.To, func() (*models.VolumeReport, error) {
+		return s.repo.Volume(ctx, interval, rng)
+	})
+}
+
+// Revenue returns estimated revenue by package
+func (s *ReportService) Revenue(ctx context.Context, rng models.ReportRange) (*models.RevenueReport, error) {
+	return cached(s, "revenue/"+rng.From+"/"+rng.To, func() (*models.RevenueReport, error) {
+		return s.repo.Revenue(ctx, rng)
+	})
+}
+
+// Menu returns the most popular coffee flavors and milk options
+func (s *ReportService) Menu(ctx context.Context, rng models.ReportRange) (*models.MenuReport, error) {
+	return cached(s, "menu/"+rng.From+"/"+rng.To, func() (*models.MenuReport, error) {
+		return s.repo.Menu(ctx, rng)
+	})
+}
+
+// Summary returns totals, the outdoor split, lead times and cancellations
+func (s *ReportService) Summary(ctx context.Context, rng models.ReportRange) (*models.SummaryReport, error) {
+	return cached(s, "summary/"+rng.From+"/"+rng.To, func() (*models.SummaryReport, error) {
+		return s.repo.Summary(ctx, rng)
+	})
+}
+
+// cached returns the report stored under key, or loads and stores it. Errors
+// are not cached.
+func cached[T any](s *ReportService, key string, load func() (*T, error)) (*T, error) {
+	if s.ttl <= 0 {
+		return load()
+	}
+
+	now := s.now()
+	s.mu.Lock()
+	entry, ok := s.cache[key]
+	s.mu.Unlock()
+	if ok && now.Before(entry.expires) {
+		return entry.report.(*T), nil
+	}
+
+	report, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for k, e := range s.cache {
+		if !now.Before(e.expires) {
+			delete(s.cache, k)
+		}
+	}
+	s.cache[key] = cachedReport{report: report, expires: now.Add(s.ttl)}
+	return report, nil
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/config"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
+)
+
+// countingReportRepo counts the report queries it runs
+type countingReportRepo struct {
+	calls int
+	err   error
+}
+
+func (r *countingReportRepo) Volume(ctx context.Context, interval string, rng models.ReportRange) (*models.VolumeReport, error) {
+	r.calls++
+	return &models.VolumeReport{ReportRange: rng, Interval: interval}, r.err
+}
+
+func (r *countingReportRepo) Revenue(ctx context.Context, rng models.ReportRange) (*models.RevenueReport, error) {
+	r.calls++
+	return &models.RevenueReport{ReportRange: rng}, r.err
+}
+
+func (r *countingReportRepo) Menu(ctx context.Context, rng models.ReportRange) (*models.MenuReport, error) {
+	r.calls++
+	return &models.MenuReport{ReportRange: rng}, r.err
+}
+
+func (r *countingReportRepo) Summary(ctx context.Context, rng models.ReportRange) (*models.SummaryReport, error) {
+	r.calls++
+	if r.err != nil {
+		return nil, r.err
+	}
+	return &models.SummaryReport{ReportRange: rng, Bookings: r.calls}, nil
+}
+
+func TestReportServiceCache(t *testing.T) {
+	june := models.ReportRange{From: "2026-06-01", To: "2026-06-30"}
+	july := models.ReportRange{From: "2026-07-01", To: "2026-07-31"}
+
+	tests := []struct {
+		name          string
+		ttl           time.Duration
+		err           error
+		run           func(s *ReportService, advance func(time.Duration))
+		expectedCalls int
+	}{
+		{
+			name: "Same range is served from the cache",
+			ttl:  time.Minute,
+			run: func(s *ReportService, advance func(time.Duration)) {
+				s.Summary(context.Background(), june)
+				advance(30 * time.Second)
+				s.Summary(context.Background(), june)
+			},
+			expectedCalls: 1,
+		},
+		{
+			name: "Each range and report is cached separately",
+			ttl:  time.Minute,
+			run: func(s *ReportService, advance func(time.Duration)) {
+				s.Summary(context.Background(), june)
+				s.Summary(context.Background(), july)
+				s.Menu(context.Background(), june)
+				s.Volume(context.Background(), models.ReportIntervalWeek, june)
+				s.Volume(context.Background(), models.ReportIntervalMonth, june)
+			},
+			expectedCalls: 5,
+		},
+		{
+			name: "Reports expire",
+			ttl:  time.Minute,
+			run: func(s *ReportService, advance func(time.Duration)) {
+				s.Summary(context.Background(), june)
+				advance(time.Minute)
+				s.Summary(context.Background(), june)
+			},
+			expectedCalls: 2,
+		},
+		{
+			name: "Caching disabled",
+			run: func(s *ReportService, advance func(time.Duration)) {
+				s.Summary(context.Background(), june)
+				s.Summary(context.Background(), june)
+			},
+			expectedCalls: 2,
+		},
+		{
+			name: "Errors are not cached",
+			ttl:  time.Minute,
+			err:  errors.New("connection lost"),
+			run: func(s *ReportService, advance func(time.Duration)) {
+				s.Summary(context.Background(), june)
+				s.Summary(context.Background(), june)
+			},
+			expectedCalls: 2,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &countingReportRepo{err: tc.err}
+			s := NewReportService(repo, config.ReportsConfig{CacheTTL: tc.ttl})
+			now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+			s.SetClock(func() time.Time { return now })
+
+			tc.run(s, func(d time.Duration) { now = now.Add(d) })
+
+			if repo.calls != tc.expectedCalls {
+				t.Errorf("Expected %d queries, got %d", tc.expectedCalls, repo.calls)
+			}
+		})
+	}
+}
+
+func TestReportServiceExpiredEntriesAreDropped(t *testing.T) {
+	s := NewReportService(&countingReportRepo{}, config.ReportsConfig{CacheTTL: time.Minute})
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	s.SetClock(func() time.Time { return now })
+
+	s.Summary(context.Background(), models.ReportRange{From: "2026-06-01", To: "2026-06-30"})
+	now = now.Add(2 * time.Minute)
+	s.Summary(context.Background(), models.ReportRange{From: "2026-07-01", To: "2026-07-31"})
+
+	if len(s.cache) != 1 {
+		t.Errorf("Expected only the fresh report to be cached, got %d", len(s.cache))
+	}
+}
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// ErrWebhookAddressBlocked is returned for webhook URLs, and refused
+// connections, that reach a loopback, private or link-local address
+var ErrWebhookAddressBlocked = errors.New("webhook URL must not point at a loopback, private or link-local address")
+
+// blockedPrefixes are special-purpose ranges netip doesn't classify: "this
+// network", carrier-grade NAT, IETF protocol assignments, benchmarking and
+// NAT64, which can embed any IPv4 address
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+	netip.MustParsePrefix("64:ff9b:1::/48"),
+}
+
+// resolveTimeout bounds the DNS lookup made when a webhook is saved
+const resolveTimeout = 3 * time.Second
+
+// isBlockedAddr reports whether webhooks may not connect to addr. This covers
+// cloud metadata endpoints such as 169.254.169.254, which are link-local.
+func isBlockedAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() || addr.IsUnspecified() || addr.IsLoopback() || addr.IsPrivate() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() ||
+		addr.IsMulticast() {
+		return true
+	}
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// refuseBlockedAddr is a net.Dialer Control function. It sees the address
+// actually being connected to, after DNS resolution, so a host that resolves
+// differently at delivery time than when it was saved is still refused.
+func refuseBlockedAddr(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil || isBlockedAddr(addr) {
+		return ErrWebhookAddressBlocked
+	}
+	return nil
+}
+
+// newWebhookTransport returns the transport deliveries are sent with. Unless
+// allowPrivate is set, every connection is checked by refuseBlockedAddr.
+// Proxies are never used, since the check must see the webhook's own address.
+func newWebhookTransport(allowPrivate bool) *http.Transport {
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	if !allowPrivate {
+		dialer.Control = refuseBlockedAddr
+	}
+	return &http.Transport{
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: time.Second,
+	}
+}
+
+// CheckURL returns ErrWebhookAddressBlocked if rawURL's host is, or resolves
+// to, an address deliveries may not reach. A host that doesn't resolve yet is
+// accepted; deliveries check the address again when they connect. A nil
+// service applies the default checks.
+func (s *WebhookService) CheckURL(ctx context.Context, rawURL string) error {
+	if s != nil && s.allowPrivate {
+		return nil
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	host := u.Hostname()
+
+	if addr, err := netip.ParseAddr(host); err == nil {
+		if isBlockedAddr(addr) {
+			return ErrWebhookAddressBlocked
+		}
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, resolveTimeout)
+	defer cancel()
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return nil
+	}
+	for _, addr := range addrs {
+		if isBlockedAddr(addr) {
+			return ErrWebhookAddressBlocked
+		}
+	}
+	return nil
+}
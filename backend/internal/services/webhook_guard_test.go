@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/config"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
+)
+
+func TestIsBlockedAddr(t *testing.T) {
+	tests := []struct {
+		addr    string
+		blocked bool
+	}{
+		{addr: "127.0.0.1", blocked: true},
+		{addr: "::1", blocked: true},
+		{addr: "10.1.2.3", blocked: true},
+		{addr: "172.16.0.1", blocked: true},
+		{addr: "192.168.1.10", blocked: true},
+		{addr: "169.254.169.254", blocked: true},
+		{addr: "::ffff:169.254.169.254", blocked: true},
+		{addr: "fd00:ec2::254", blocked: true},
+		{addr: "fe80::1", blocked: true},
+		{addr: "0.0.0.0", blocked: true},
+		{addr: "100.64.0.1", blocked: true},
+		{addr: "64:ff9b::a00:1", blocked: true},
+		{addr: "93.184.215.14", blocked: false},
+		{addr: "2606:2800:21f:cb07:6820:80da:af6b:8b2c", blocked: false},
+	}
+
+	for _, tc := range tests {
+		if blocked := isBlockedAddr(netip.MustParseAddr(tc.addr)); blocked != tc.blocked {
+			t.Errorf("isBlockedAddr(%s) = %v, expected %v", tc.addr, blocked, tc.blocked)
+		}
+	}
+}
+
+func TestWebhookCheckURL(t *testing.T) {
+	tests := []struct {
+		name         string
+		url          string
+		allowPrivate bool
+		expectedErr  error
+	}{
+		{name: "Cloud metadata", url: "http://169.254.169.254/latest/meta-data/", expectedErr: ErrWebhookAddressBlocked},
+		{name: "Private address", url: "https://10.0.0.5:8443/hooks", expectedErr: ErrWebhookAddressBlocked},
+		{name: "IPv6 loopback", url: "http://[::1]/hooks", expectedErr: ErrWebhookAddressBlocked},
+		{name: "Localhost", url: "http://localhost:8080/hooks", expectedErr: ErrWebhookAddressBlocked},
+		{name: "Public address", url: "https://93.184.215.14/hooks"},
+		{name: "Unresolvable host", url: "https://hooks.invalid/catch"},
+		{name: "Private networks allowed", url: "http://localhost:8080/hooks", allowPrivate: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			service := NewWebhookService(&fakeWebhookRepo{}, config.WebhookConfig{AllowPrivateNetworks: tc.allowPrivate})
+			if err := service.CheckURL(context.Background(), tc.url); !errors.Is(err, tc.expectedErr) {
+				t.Errorf("Expected %v, got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestWebhookDeliveryRefusesPrivateAddress(t *testing.T) {
+	var hits int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+	}))
+	defer server.Close()
+
+	// The default config blocks the loopback address the server listens on
+	repo := &fakeWebhookRepo{}
+	service := NewWebhookService(repo, config.Default().Webhooks)
+
+	hook := &models.Webhook{ID: 4, URL: server.URL, Secret: "whsec_test"}
+	delivery, err := service.Test(context.Background(), hook)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if delivery.Succeeded() || hits != 0 {
+		t.Fatalf("Expected the delivery to be refused before connecting, got %+v after %d requests", delivery, hits)
+	}
+	// The logged error doesn't reveal the address that was refused
+	if delivery.Error != ErrWebhookAddressBlocked.Error() {
+		t.Errorf("Expected %q, got %q", ErrWebhookAddressBlocked, delivery.Error)
+	}
+}
@@ -0,0 +1,326 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/config"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/database"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/metrics"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Headers sent with every webhook delivery. Receivers verify a delivery by
+// recomputing the signature over the timestamp and raw body with SignWebhook.
+const (
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookIDHeader        = "X-Webhook-ID"
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	WebhookSignatureHeader = "X-Webhook-Signature"
+)
+
+// WebhookPayload is the JSON body POSTed to webhooks. ID is shared by every
+// attempt to deliver the same event, so receivers can drop duplicates.
+type WebhookPayload struct {
+	ID        string      `json:"id"`
+	Event     string      `json:"event"`
+	CreatedAt time.Time   `json:"createdAt"`
+	Data      interface{} `json:"data"`
+}
+
+// webhookEvent is an event encoded once for all of its deliveries. When the
+// data holds personal data, withoutPII is the body for webhooks that haven't
+// opted in to it.
+type webhookEvent struct {
+	id         string
+	name       string
+	body       []byte
+	withoutPII []byte
+}
+
+// bodyFor returns the body delivered to hook
+func (ev *webhookEvent) bodyFor(hook *models.Webhook) []byte {
+	if ev.withoutPII != nil && !hook.IncludePII {
+		return ev.withoutPII
+	}
+	return ev.body
+}
+
+// WebhookService delivers events to webhook subscriptions, retrying failed
+// deliveries and logging every attempt
+type WebhookService struct {
+	repo         database.WebhookRepositoryInterface
+	client       *http.Client
+	maxAttempts  int
+	backoff      time.Duration
+	allowPrivate bool
+
+	// background tracks deliveries so shutdown can wait for them; closing
+	// stop cancels the retries still waiting for their turn
+	background sync.WaitGroup
+	stop       chan struct{}
+	stopOnce   sync.Once
+
+	metrics *metrics.Metrics
+}
+
+// NewWebhookService creates a new webhook service
+func NewWebhookService(repo database.WebhookRepositoryInterface, cfg config.WebhookConfig) *WebhookService {
+	return &WebhookService{
+		repo: repo,
+		client: &http.Client{
+			Transport: newWebhookTransport(cfg.AllowPrivateNetworks),
+			Timeout:   cfg.Timeout,
+			// A redirect is reported as a failed delivery rather than followed
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		maxAttempts:  cfg.MaxAttempts,
+		backoff:      cfg.RetryBackoff,
+		allowPrivate: cfg.AllowPrivateNetworks,
+		stop:         make(chan struct{}),
+	}
+}
+
+// SetMetrics counts delivery attempts in m
+func (s *WebhookService) SetMetrics(m *metrics.Metrics) {
+	s.metrics = m
+}
+
+// Emit delivers event to every active webhook subscribed to it in the
+// background. ctx only carries the request ID and trace for logging. A nil
+// service does nothing, so handlers work without webhooks configured.
+func (s *WebhookService) Emit(ctx context.Context, event string, data interface{}) {
+	if s == nil {
+		return
+	}
+
+	ev, err := newWebhookEvent(event, data)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to encode webhook event", "event", event, "error", err)
+		return
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	s.background.Add(1)
+	go func() {
+		defer s.background.Done()
+
+		hooks, err := s.repo.GetActiveForEvent(ctx, event)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to load webhooks", "event", event, "error", err)
+			return
+		}
+
+		for _, hook := range hooks {
+			s.background.Add(1)
+			go func(hook *models.Webhook) {
+				defer s.background.Done()
+				s.deliver(ctx, hook, ev)
+			}(hook)
+		}
+	}()
+}
+
+// Test sends a webhook.test event to hook once, whatever its event filter or
+// active flag, and returns the logged attempt
+func (s *WebhookService) Test(ctx context.Context, hook *models.Webhook) (*models.WebhookDelivery, error) {
+	ev, err := newWebhookEvent(models.EventWebhookTest, map[string]interface{}{
+		"webhookId": hook.ID,
+		"message":   "Test delivery from Toasted Coffee Co",
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.attempt(ctx, hook, ev, 1), nil
+}
+
+// Drain stops scheduling retries and waits for deliveries in progress to
+// finish, or for ctx to expire
+func (s *WebhookService) Drain(ctx context.Context) error {
+	s.stopOnce.Do(func() { close(s.stop) })
+
+	done := make(chan struct{})
+	go func() {
+		s.background.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("pending webhooks not delivered: %w", ctx.Err())
+	}
+}
+
+// deliver attempts ev until the webhook accepts it, maxAttempts is reached or
+// the service is drained
+func (s *WebhookService) deliver(ctx context.Context, hook *models.Webhook, ev *webhookEvent) {
+	wait := s.backoff
+	for attempt := 1; ; attempt++ {
+		if s.attempt(ctx, hook, ev, attempt).Succeeded() {
+			return
+		}
+		if attempt >= s.maxAttempts {
+			slog.WarnContext(ctx, "webhook delivery abandoned",
+				"webhook_id", hook.ID, "event", ev.name, "event_id", ev.id, "attempts", attempt)
+			return
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-s.stop:
+			timer.Stop()
+			slog.WarnContext(ctx, "webhook retries stopped by shutdown",
+				"webhook_id", hook.ID, "event", ev.name, "event_id", ev.id, "attempts", attempt)
+			return
+		}
+		wait *= 2
+	}
+}
+
+// attempt POSTs ev to hook once and records the outcome in the delivery log
+func (s *WebhookService) attempt(ctx context.Context, hook *models.Webhook, ev *webhookEvent, attempt int) *models.WebhookDelivery {
+	ctx, span := tracing.Tracer().Start(ctx, "webhook "+ev.name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("webhook.event", ev.name),
+			attribute.Int("webhook.id", hook.ID),
+			attribute.Int("webhook.attempt", attempt),
+		),
+	)
+	defer span.End()
+
+	delivery := &models.WebhookDelivery{
+		WebhookID: hook.ID,
+		EventID:   ev.id,
+		Event:     ev.name,
+		Attempt:   attempt,
+	}
+
+	start := time.Now()
+	status, err := s.post(ctx, hook, ev)
+	delivery.DurationMs = time.Since(start).Milliseconds()
+	if err != nil {
+		delivery.Error = err.Error()
+	} else {
+		delivery.StatusCode = &status
+		if !delivery.Succeeded() {
+			err = fmt.Errorf("webhook responded with status %d", status)
+		}
+	}
+
+	s.metrics.WebhookDelivered(ev.name, err == nil)
+	tracing.RecordError(span, err)
+	if err != nil {
+		slog.WarnContext(ctx, "webhook delivery failed",
+			"webhook_id", hook.ID, "event", ev.name, "attempt", attempt, "error", err)
+	} else {
+		slog.InfoContext(ctx, "webhook delivered",
+			"webhook_id", hook.ID, "event", ev.name, "attempt", attempt, "duration_ms", delivery.DurationMs)
+	}
+
+	if err := s.repo.LogDelivery(ctx, delivery); err != nil {
+		slog.ErrorContext(ctx, "failed to log webhook delivery", "webhook_id", hook.ID, "error", err)
+	}
+	return delivery
+}
+
+// post sends the signed request and returns the response status
+func (s *WebhookService) post(ctx context.Context, hook *models.Webhook, ev *webhookEvent) (int, error) {
+	body := ev.bodyFor(hook)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "ToastedCoffee-Webhooks/1.0")
+	req.Header.Set(WebhookEventHeader, ev.name)
+	req.Header.Set(WebhookIDHeader, ev.id)
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	req.Header.Set(WebhookSignatureHeader, "sha256="+SignWebhook(hook.Secret, timestamp, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		// Refused addresses are reported without the address, which the
+		// delivery log would otherwise reveal for internal hosts
+		if errors.Is(err, ErrWebhookAddressBlocked) {
+			return 0, ErrWebhookAddressBlocked
+		}
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// Drain a little of the body so the connection can be reused
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	return resp.StatusCode, nil
+}
+
+// SignWebhook returns the hex HMAC-SHA256 of "timestamp.body" keyed with secret
+func SignWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// GenerateWebhookSecret returns a random signing secret for a new webhook
+func GenerateWebhookSecret() (string, error) {
+	return randomHex("whsec_", 32)
+}
+
+func newWebhookEvent(event string, data interface{}) (*webhookEvent, error) {
+	id, err := randomHex("evt_", 16)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := WebhookPayload{
+		ID:        id,
+		Event:     event,
+		CreatedAt: time.Now().UTC(),
+		Data:      data,
+	}
+	ev := &webhookEvent{id: id, name: event}
+	if ev.body, err = json.Marshal(payload); err != nil {
+		return nil, err
+	}
+
+	if personal, ok := data.(models.PersonalData); ok {
+		payload.Data = personal.WithoutPII()
+		if ev.withoutPII, err = json.Marshal(payload); err != nil {
+			return nil, err
+		}
+	}
+	return ev, nil
+}
+
+func randomHex(prefix string, n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate random ID: %w", err)
+	}
+	return prefix + hex.EncodeToString(b), nil
+}
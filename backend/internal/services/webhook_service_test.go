@@ -0,0 +1,279 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/config"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/models"
+)
+
+// fakeWebhookRepo serves a fixed set of webhooks and records logged deliveries
+type fakeWebhookRepo struct {
+	hooks []*models.Webhook
+
+	mu         sync.Mutex
+	deliveries []*models.WebhookDelivery
+}
+
+func (r *fakeWebhookRepo) GetAll(ctx context.Context) ([]*models.Webhook, error) {
+	return r.hooks, nil
+}
+
+func (r *fakeWebhookRepo) GetActiveForEvent(ctx context.Context, event string) ([]*models.Webhook, error) {
+	var hooks []*models.Webhook
+	for _, hook := range r.hooks {
+		if hook.Active && hook.Subscribes(event) {
+			hooks = append(hooks, hook)
+		}
+	}
+	return hooks, nil
+}
+
+func (r *fakeWebhookRepo) GetByID(ctx context.Context, id int) (*models.Webhook, error) {
+	return nil, nil
+}
+
+func (r *fakeWebhookRepo) Create(ctx context.Context, hook *models.Webhook) error {
+	return nil
+}
+
+func (r *fakeWebhookRepo) Update(ctx context.Context, id int, hook *models.Webhook) error {
+	return nil
+}
+
+func (r *fakeWebhookRepo) Delete(ctx context.Context, id int) error {
+	return nil
+}
+
+func (r *fakeWebhookRepo) LogDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deliveries = append(r.deliveries, delivery)
+	return nil
+}
+
+func (r *fakeWebhookRepo) GetDeliveries(ctx context.Context, webhookID int, limit int) ([]*models.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.deliveries, nil
+}
+
+func TestWebhookDeliveryRetries(t *testing.T) {
+	tests := []struct {
+		name             string
+		statuses         []int // response to each attempt; the last one repeats
+		maxAttempts      int
+		expectedAttempts int
+		expectDelivered  bool
+	}{
+		{
+			name:             "Delivered first time",
+			statuses:         []int{http.StatusNoContent},
+			maxAttempts:      3,
+			expectedAttempts: 1,
+			expectDelivered:  true,
+		},
+		{
+			name:             "Delivered after retries",
+			statuses:         []int{http.StatusInternalServerError, http.StatusBadGateway, http.StatusOK},
+			maxAttempts:      5,
+			expectedAttempts: 3,
+			expectDelivered:  true,
+		},
+		{
+			name:             "Redirect is not followed",
+			statuses:         []int{http.StatusFound},
+			maxAttempts:      2,
+			expectedAttempts: 2,
+		},
+		{
+			name:             "Gives up after max attempts",
+			statuses:         []int{http.StatusServiceUnavailable},
+			maxAttempts:      3,
+			expectedAttempts: 3,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var requests []*http.Request
+			var bodies [][]byte
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				mu.Lock()
+				n := len(requests)
+				requests = append(requests, r)
+				bodies = append(bodies, body)
+				mu.Unlock()
+
+				status := tc.statuses[min(n, len(tc.statuses)-1)]
+				if status == http.StatusFound {
+					w.Header().Set("Location", "/elsewhere")
+				}
+				w.WriteHeader(status)
+			}))
+			defer server.Close()
+
+			repo := &fakeWebhookRepo{hooks: []*models.Webhook{
+				{ID: 1, URL: server.URL, Secret: "whsec_test", Events: []string{models.EventBookingCreated}, Active: true},
+				{ID: 2, URL: server.URL, Secret: "whsec_other", Events: []string{models.EventInquiryCreated}, Active: true},
+				{ID: 3, URL: server.URL, Secret: "whsec_off", Events: []string{models.WebhookAllEvents}, Active: false},
+			}}
+			service := NewWebhookService(repo, config.WebhookConfig{
+				Timeout:      time.Second,
+				MaxAttempts:  tc.maxAttempts,
+				RetryBackoff: time.Millisecond,
+				// httptest servers listen on loopback
+				AllowPrivateNetworks: true,
+			})
+
+			service.Emit(context.Background(), models.EventBookingCreated, &models.Booking{ID: 7, Name: "Ada"})
+			service.background.Wait()
+
+			if len(repo.deliveries) != tc.expectedAttempts {
+				t.Fatalf("Expected %d logged attempts, got %d", tc.expectedAttempts, len(repo.deliveries))
+			}
+			last := repo.deliveries[len(repo.deliveries)-1]
+			if last.Succeeded() != tc.expectDelivered {
+				t.Errorf("Expected delivered=%v, last attempt %+v", tc.expectDelivered, last)
+			}
+
+			for i, d := range repo.deliveries {
+				if d.WebhookID != 1 || d.Attempt != i+1 || d.EventID != repo.deliveries[0].EventID {
+					t.Errorf("Unexpected delivery log entry %+v", d)
+				}
+			}
+
+			// Every attempt is signed and carries the same payload
+			for i, r := range requests {
+				timestamp := r.Header.Get(WebhookTimestampHeader)
+				expected := "sha256=" + SignWebhook("whsec_test", timestamp, bodies[i])
+				if r.Header.Get(WebhookSignatureHeader) != expected {
+					t.Errorf("Attempt %d has signature %q, expected %q", i+1, r.Header.Get(WebhookSignatureHeader), expected)
+				}
+				if r.Header.Get(WebhookEventHeader) != models.EventBookingCreated {
+					t.Errorf("Unexpected event header %q", r.Header.Get(WebhookEventHeader))
+				}
+
+				var payload struct {
+					ID    string         `json:"id"`
+					Event string         `json:"event"`
+					Data  models.Booking `json:"data"`
+				}
+				if err := json.Unmarshal(bodies[i], &payload); err != nil {
+					t.Fatalf("Invalid payload: %v", err)
+				}
+				if payload.ID != r.Header.Get(WebhookIDHeader) || payload.Data.ID != 7 {
+					t.Errorf("Unexpected payload %s", bodies[i])
+				}
+			}
+		})
+	}
+}
+
+func TestWebhookPersonalDataOptIn(t *testing.T) {
+	var mu sync.Mutex
+	bodies := map[string]map[string]interface{}{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			Data map[string]interface{} `json:"data"`
+		}
+		json.NewDecoder(r.Body).Decode(&payload)
+		mu.Lock()
+		bodies[r.URL.Path] = payload.Data
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	repo := &fakeWebhookRepo{hooks: []*models.Webhook{
+		{ID: 1, URL: server.URL + "/default", Secret: "whsec_test", Events: []string{"*"}, Active: true},
+		{ID: 2, URL: server.URL + "/pii", Secret: "whsec_test", Events: []string{"*"}, Active: true, IncludePII: true},
+	}}
+	cfg := config.Default().Webhooks
+	cfg.AllowPrivateNetworks = true
+	service := NewWebhookService(repo, cfg)
+
+	service.Emit(context.Background(), models.EventBookingCreated, &models.Booking{
+		ID: 7, Name: "Ada", Email: "ada@example.com", Phone: "555-0100", Location: "12 Elm St",
+		Notes: "Gate code 1234", Date: "2026-05-01", People: 10,
+	})
+	service.background.Wait()
+
+	personal := []string{"name", "email", "phone", "location", "notes"}
+	if data := bodies["/default"]; data["id"] != float64(7) || data["date"] != "2026-05-01" {
+		t.Errorf("Expected the booking ID and event details by default, got %v", data)
+	}
+	for _, field := range personal {
+		if _, ok := bodies["/default"][field]; ok {
+			t.Errorf("Expected %s to be left out by default", field)
+		}
+		if _, ok := bodies["/pii"][field]; !ok {
+			t.Errorf("Expected %s with the personal data opt-in", field)
+		}
+	}
+}
+
+func TestWebhookTestIgnoresFilter(t *testing.T) {
+	var event string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		event = r.Header.Get(WebhookEventHeader)
+	}))
+	defer server.Close()
+
+	repo := &fakeWebhookRepo{}
+	cfg := config.Default().Webhooks
+	cfg.AllowPrivateNetworks = true
+	service := NewWebhookService(repo, cfg)
+
+	hook := &models.Webhook{ID: 4, URL: server.URL, Secret: "whsec_test", Events: []string{models.EventInquiryCreated}}
+	delivery, err := service.Test(context.Background(), hook)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !delivery.Succeeded() || event != models.EventWebhookTest {
+		t.Errorf("Expected a successful %s delivery, got %+v for %q", models.EventWebhookTest, delivery, event)
+	}
+	if len(repo.deliveries) != 1 {
+		t.Errorf("Expected the test delivery to be logged")
+	}
+}
+
+func TestWebhookDrainStopsRetries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	repo := &fakeWebhookRepo{hooks: []*models.Webhook{
+		{ID: 1, URL: server.URL, Secret: "whsec_test", Events: []string{models.WebhookAllEvents}, Active: true},
+	}}
+	service := NewWebhookService(repo, config.WebhookConfig{
+		Timeout:              time.Second,
+		MaxAttempts:          5,
+		RetryBackoff:         time.Hour,
+		AllowPrivateNetworks: true,
+	})
+
+	service.Emit(context.Background(), models.EventInquiryCreated, map[string]string{"name": "Ada"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := service.Drain(ctx); err != nil {
+		t.Fatalf("Expected Drain to cancel the pending retry, got %v", err)
+	}
+	if len(repo.deliveries) != 1 {
+		t.Errorf("Expected only the first attempt, got %d", len(repo.deliveries))
+	}
+
+	// Emitting on a nil service is a no-op
+	var disabled *WebhookService
+	disabled.Emit(context.Background(), models.EventBookingCreated, nil)
+}
@@ -0,0 +1,102 @@
+package tracing_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/config"
+	"github.com/joshuagudgel/toasted-coffee/backend/internal/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestMiddleware(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	router := chi.NewRouter()
+	router.Use(tracing.Middleware)
+	router.Get("/api/v1/bookings/{id}", func(w http.ResponseWriter, r *http.Request) {
+		// Spans started by handlers are children of the route span
+		_, span := tracing.Tracer().Start(r.Context(), "child")
+		span.End()
+		w.WriteHeader(http.StatusOK)
+	})
+	router.Get("/broken", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	const parentTraceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+
+	tests := []struct {
+		name         string
+		path         string
+		traceparent  string
+		expectedName string
+		expectError  bool
+	}{
+		{name: "Route pattern", path: "/api/v1/bookings/42", expectedName: "GET /api/v1/bookings/{id}"},
+		{name: "Server error", path: "/broken", expectedName: "GET /broken", expectError: true},
+		{name: "Unmatched route", path: "/nope", expectedName: "GET"},
+		{
+			name:         "Incoming trace parent",
+			path:         "/api/v1/bookings/42",
+			traceparent:  "00-" + parentTraceID + "-00f067aa0ba902b7-01",
+			expectedName: "GET /api/v1/bookings/{id}",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder.Reset()
+
+			req := httptest.NewRequest("GET", tc.path, nil)
+			if tc.traceparent != "" {
+				req.Header.Set("traceparent", tc.traceparent)
+			}
+			router.ServeHTTP(httptest.NewRecorder(), req)
+
+			var server sdktrace.ReadOnlySpan
+			for _, span := range recorder.Ended() {
+				if span.Name() != "child" {
+					server = span
+				}
+			}
+			if server == nil {
+				t.Fatal("Expected a server span")
+			}
+
+			if server.Name() != tc.expectedName {
+				t.Errorf("Expected span name %q, got %q", tc.expectedName, server.Name())
+			}
+			if isError := server.Status().Code == codes.Error; isError != tc.expectError {
+				t.Errorf("Expected error status = %v, got %v", tc.expectError, isError)
+			}
+			if tc.traceparent != "" && server.SpanContext().TraceID().String() != parentTraceID {
+				t.Errorf("Expected trace %s to be continued, got %s", parentTraceID, server.SpanContext().TraceID())
+			}
+			for _, span := range recorder.Ended() {
+				if span.Name() == "child" && span.Parent().SpanID() != server.SpanContext().SpanID() {
+					t.Error("Expected handler span to be a child of the route span")
+				}
+			}
+		})
+	}
+}
+
+func TestSetupDisabled(t *testing.T) {
+	shutdown, err := tracing.Setup(context.Background(), config.TracingConfig{Exporter: "none"}, "test")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("Unexpected shutdown error: %v", err)
+	}
+}